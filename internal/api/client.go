@@ -4,21 +4,47 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/porteden/cli/internal/apierr"
+	"github.com/porteden/cli/internal/debug"
 )
 
 type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	maxWait    time.Duration
+	wait       WaitFunc
+	limiter    *Limiter
+	onPage     func(PageProgress)
+	delegate   string
+
+	reauth     ReauthFunc
+	reauthOnce sync.Once
+	reauthed   bool
+}
+
+// ReauthFunc gets a new API key after the current one was rejected, or
+// returns an error to give up
+type ReauthFunc func() (string, error)
+
+// PageProgress reports the state of an auto-paginating fetch
+type PageProgress struct {
+	Resource string // "events", "emails" or "files"
+	Page     int    // Pages fetched so far
+	Fetched  int    // Items fetched so far
+	Total    int    // Total items reported by the API, 0 if unknown
+	Done     bool   // Set on the final report, including on error
 }
 
 func NewClient(apiKey string) *Client {
@@ -34,39 +60,99 @@ func NewClient(apiKey string) *Client {
 	}
 }
 
-// WithBaseURL sets a custom base URL (useful for testing)
-func (c *Client) WithBaseURL(baseURL string) *Client {
-	c.baseURL = baseURL
+// WithBaseTransport replaces the network transport beneath the auth and
+// logging layer, e.g. to serve requests from an in-process fake server
+func (c *Client) WithBaseTransport(rt http.RoundTripper) *Client {
+	if t, ok := c.httpClient.Transport.(*Transport); ok {
+		t.Base = rt
+	}
 	return c
 }
 
-func (c *Client) Get(path string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
+// WithUserAgentSuffix appends suffix to the User-Agent of every request
+func (c *Client) WithUserAgentSuffix(suffix string) *Client {
+	if t, ok := c.httpClient.Transport.(*Transport); ok {
+		t.UserAgentSuffix = suffix
+	}
+	return c
+}
 
-	resp, err := c.doWithRetry(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, err
+// WithRefreshToken authenticates with short-lived access tokens minted from
+// refreshToken instead of an API key. onRotate saves replacement refresh
+// tokens. Call after WithBaseURL.
+func (c *Client) WithRefreshToken(refreshToken string, onRotate func(string)) *Client {
+	if t, ok := c.httpClient.Transport.(*Transport); ok {
+		t.Tokens = NewTokenSource(c.baseURL, refreshToken, onRotate)
 	}
-	defer resp.Body.Close()
+	return c
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, apierr.ParseAPIError(resp)
+// WithDelegate makes calendar requests act on the calendars of another user
+// who has delegated access, e.g. an executive's calendar managed by an
+// assistant. Other requests are unaffected.
+func (c *Client) WithDelegate(email string) *Client {
+	c.delegate = email
+	return c
+}
+
+// delegatePath adds the delegation parameter to calendar request paths
+func (c *Client) delegatePath(path string) string {
+	if c.delegate == "" || !strings.HasPrefix(path, "/api/access/calendar/") {
+		return path
 	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "onBehalfOf=" + url.QueryEscape(c.delegate)
+}
 
-	return io.ReadAll(resp.Body)
+// WithReauth lets the client replace a rejected API key once: on the first
+// 401, fn is asked for a new key and the request is retried with it
+func (c *Client) WithReauth(fn ReauthFunc) *Client {
+	c.reauth = fn
+	return c
 }
 
-func (c *Client) Post(path string, data interface{}) ([]byte, error) {
-	body, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
+// reauthenticate runs the reauth callback once per client, reporting whether
+// a new key is in place. Concurrent callers wait for the first to finish.
+func (c *Client) reauthenticate() bool {
+	if c.reauth == nil {
+		return false
+	}
+	c.reauthOnce.Do(func() {
+		key, err := c.reauth()
+		if err != nil {
+			debug.Log("Re-authentication skipped: %v", err)
+			return
+		}
+		if t, ok := c.httpClient.Transport.(*Transport); ok {
+			t.SetAPIKey(key)
+		}
+		c.apiKey = key
+		c.reauthed = true
+	})
+	return c.reauthed
+}
+
+// request sends a request and returns the response body, or the API's error.
+// A rejected key may be replaced once, e.g. by signing in again: that happens
+// between attempts, outside their timeouts, as signing in can take a while.
+func (c *Client) request(method, path string, body []byte) ([]byte, error) {
+	data, err := c.attempt(method, path, body)
+	var apiErr *apierr.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized && c.reauthenticate() {
+		return c.attempt(method, path, body)
 	}
+	return data, err
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+// attempt sends a request once, with retries for transient failures
+func (c *Client) attempt(method, path string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute+c.maxWait)
 	defer cancel()
 
-	resp, err := c.doWithRetry(ctx, "POST", path, body)
+	resp, err := c.doWithRetry(ctx, method, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -79,43 +165,70 @@ func (c *Client) Post(path string, data interface{}) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-func (c *Client) Patch(path string, data interface{}) ([]byte, error) {
-	body, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
+// WithBaseURL sets a custom base URL (useful for testing)
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	c.baseURL = baseURL
+	return c
+}
+
+// WithMaxWait caps the total time spent waiting between retries. Retry-After
+// delays are honored in full up to this budget; beyond it the error response is
+// returned immediately. Zero keeps the default backoff behavior.
+func (c *Client) WithMaxWait(d time.Duration) *Client {
+	c.maxWait = d
+	return c
+}
+
+// WithWaitFunc replaces the sleep between retries, e.g. to display a countdown
+func (c *Client) WithWaitFunc(fn WaitFunc) *Client {
+	c.wait = fn
+	return c
+}
+
+// WithRateLimit spaces requests to stay within r, slowing down further when
+// the API responds with rate limiting
+func (c *Client) WithRateLimit(r Rate) *Client {
+	c.limiter = NewLimiter(r)
+	return c
+}
+
+// WithPageProgress registers a callback invoked after each page of a GetAll* fetch
+func (c *Client) WithPageProgress(fn func(PageProgress)) *Client {
+	c.onPage = fn
+	return c
+}
+
+// reportPage forwards pagination progress to the registered callback
+func (c *Client) reportPage(p PageProgress) {
+	if c.onPage != nil {
+		c.onPage(p)
 	}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
+func (c *Client) Get(path string) ([]byte, error) {
+	return c.request("GET", path, nil)
+}
 
-	resp, err := c.doWithRetry(ctx, "PATCH", path, body)
+func (c *Client) Post(path string, data interface{}) ([]byte, error) {
+	body, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 400 {
-		return nil, apierr.ParseAPIError(resp)
-	}
 
-	return io.ReadAll(resp.Body)
+	return c.request("POST", path, body)
 }
 
-func (c *Client) Delete(path string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
-
-	resp, err := c.doWithRetry(ctx, "DELETE", path, nil)
+func (c *Client) Patch(path string, data interface{}) ([]byte, error) {
+	body, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return nil, apierr.ParseAPIError(resp)
-	}
+	return c.request("PATCH", path, body)
+}
 
-	return io.ReadAll(resp.Body)
+func (c *Client) Delete(path string) ([]byte, error) {
+	return c.request("DELETE", path, nil)
 }
 
 // GetAuthStatus returns the current authentication status
@@ -154,6 +267,41 @@ func (c *Client) GetCalendars() (*CalendarsResponse, error) {
 	return &response, nil
 }
 
+// GetCalendarShares returns who a calendar is shared with
+func (c *Client) GetCalendarShares(calendarID int64) (*CalendarSharesResponse, error) {
+	body, err := c.Get(calendarSharesPath(calendarID))
+	if err != nil {
+		return nil, err
+	}
+
+	var response CalendarSharesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ShareCalendar grants a user access to a calendar, replacing any role they
+// already have
+func (c *Client) ShareCalendar(calendarID int64, req ShareCalendarRequest) (*CalendarShare, error) {
+	body, err := c.Post(calendarSharesPath(calendarID), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var share CalendarShare
+	if err := json.Unmarshal(body, &share); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &share, nil
+}
+
+func calendarSharesPath(calendarID int64) string {
+	return "/api/access/calendar/calendars/" + strconv.FormatInt(calendarID, 10) + "/shares"
+}
+
 // GetEvents returns events based on parameters
 func (c *Client) GetEvents(params EventParams) (*EventsResponse, error) {
 	v := url.Values{}
@@ -167,7 +315,9 @@ func (c *Client) GetEvents(params EventParams) (*EventsResponse, error) {
 	if params.CalendarID > 0 {
 		v.Set("calendarId", strconv.FormatInt(params.CalendarID, 10))
 	}
-	if params.Offset > 0 {
+	if params.PageToken != "" {
+		v.Set("pageToken", params.PageToken)
+	} else if params.Offset > 0 {
 		v.Set("offset", strconv.Itoa(params.Offset))
 	}
 	if params.IncludeCancelled {
@@ -179,6 +329,12 @@ func (c *Client) GetEvents(params EventParams) (*EventsResponse, error) {
 	if params.Attendees != "" {
 		v.Set("attendees", params.Attendees)
 	}
+	if params.ConnectionID > 0 {
+		v.Set("connectionId", strconv.FormatInt(params.ConnectionID, 10))
+	}
+	if params.SyncToken != "" {
+		v.Set("syncToken", params.SyncToken)
+	}
 
 	body, err := c.Get("/api/access/calendar/events?" + v.Encode())
 	if err != nil {
@@ -193,9 +349,14 @@ func (c *Client) GetEvents(params EventParams) (*EventsResponse, error) {
 	return &response, nil
 }
 
-// GetEvent returns a single event by ID
-func (c *Client) GetEvent(eventID string) (*SingleEventResponse, error) {
+// GetEvent returns a single event by ID. expand asks for extended fields
+// (ExpandAttendees, ExpandAttachments, ExpandRecurrence) that providers may
+// otherwise leave out.
+func (c *Client) GetEvent(eventID string, expand ...string) (*SingleEventResponse, error) {
 	path := "/api/access/calendar/events/" + url.PathEscape(eventID)
+	if len(expand) > 0 {
+		path += "?" + url.Values{"expand": {strings.Join(expand, ",")}}.Encode()
+	}
 	body, err := c.Get(path)
 	if err != nil {
 		return nil, err
@@ -240,10 +401,15 @@ func (c *Client) UpdateEvent(eventID string, req UpdateEventRequest) (*Event, er
 	return &event, nil
 }
 
-// DeleteEvent deletes a calendar event
-func (c *Client) DeleteEvent(eventID string, notifyAttendees bool) (*DeleteEventResponse, error) {
+// DeleteEvent deletes a calendar event. For an instance of a recurring
+// event, scope picks which instances go (see ScopeThisInstance); empty
+// deletes just this one.
+func (c *Client) DeleteEvent(eventID string, notifyAttendees bool, scope string) (*DeleteEventResponse, error) {
 	v := url.Values{}
 	v.Set("notifyAttendees", strconv.FormatBool(notifyAttendees))
+	if scope != "" {
+		v.Set("scope", scope)
+	}
 
 	path := "/api/access/calendar/events/" + url.PathEscape(eventID) + "?" + v.Encode()
 	body, err := c.Delete(path)
@@ -259,10 +425,11 @@ func (c *Client) DeleteEvent(eventID string, notifyAttendees bool) (*DeleteEvent
 	return &response, nil
 }
 
-// RespondToEvent responds to an event invitation
-func (c *Client) RespondToEvent(eventID, status string) (*Event, error) {
+// RespondToEvent responds to an event invitation, with an optional comment
+// for the organizer
+func (c *Client) RespondToEvent(eventID, status, comment string) (*Event, error) {
 	path := "/api/access/calendar/events/" + url.PathEscape(eventID) + "/respond"
-	body, err := c.Post(path, map[string]string{"status": status})
+	body, err := c.Post(path, RespondEventRequest{Status: status, Comment: comment})
 	if err != nil {
 		return nil, err
 	}
@@ -309,7 +476,9 @@ func (c *Client) GetEventsByContact(params EventsByContactParams) (*EventsRespon
 		v.Set("name", params.Name)
 	}
 	v.Set("limit", strconv.Itoa(params.Limit))
-	if params.Offset > 0 {
+	if params.PageToken != "" {
+		v.Set("pageToken", params.PageToken)
+	} else if params.Offset > 0 {
 		v.Set("offset", strconv.Itoa(params.Offset))
 	}
 
@@ -367,6 +536,12 @@ func (c *Client) GetEmails(params EmailParams) (*EmailsResponse, error) {
 	if params.PageToken != "" {
 		v.Set("pageToken", params.PageToken)
 	}
+	if params.Mailbox != "" {
+		v.Set("mailbox", params.Mailbox)
+	}
+	if params.SyncToken != "" {
+		v.Set("syncToken", params.SyncToken)
+	}
 
 	body, err := c.Get("/api/access/email/messages?" + v.Encode())
 	if err != nil {
@@ -381,38 +556,66 @@ func (c *Client) GetEmails(params EmailParams) (*EmailsResponse, error) {
 	return &response, nil
 }
 
-// GetAllEmails fetches all emails by auto-paginating through results
-func (c *Client) GetAllEmails(params EmailParams) (*EmailsResponse, error) {
-	var allEmails []Email
-	var accessInfo string
+// ForEachEmailsPage auto-paginates through email results, calling fn with each
+// page as it arrives. Stops early if fn returns an error. Reports whether more
+// results remain after hitting the page cap.
+func (c *Client) ForEachEmailsPage(params EmailParams, fn func(*EmailsResponse) error) (hasMore bool, err error) {
 	const maxPages = 100
 
+	progress := PageProgress{Resource: "emails"}
+	defer func() {
+		progress.Done = true
+		c.reportPage(progress)
+	}()
+
 	for page := 0; page < maxPages; page++ {
 		resp, err := c.GetEmails(params)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		allEmails = append(allEmails, resp.Emails...)
-		accessInfo = resp.AccessInfo
+		progress.Page, progress.Fetched, progress.Total = page+1, progress.Fetched+len(resp.Emails), resp.TotalCount
+		c.reportPage(progress)
+
+		if err := fn(resp); err != nil {
+			return false, err
+		}
 
 		if !resp.HasMore || resp.NextPageToken == "" {
-			return &EmailsResponse{
-				Emails:     allEmails,
-				TotalCount: len(allEmails),
-				AccessInfo: accessInfo,
-			}, nil
+			return false, nil
 		}
 
 		params.PageToken = resp.NextPageToken
 	}
 
-	// Safety: return what we have after hitting page limit
+	// Safety: stop after hitting page limit
+	return true, nil
+}
+
+// GetAllEmails fetches all emails by auto-paginating through results
+func (c *Client) GetAllEmails(params EmailParams) (*EmailsResponse, error) {
+	var allEmails []Email
+	var deleted []string
+	var accessInfo, syncToken string
+
+	hasMore, err := c.ForEachEmailsPage(params, func(resp *EmailsResponse) error {
+		allEmails = append(allEmails, resp.Emails...)
+		deleted = append(deleted, resp.DeletedIDs...)
+		accessInfo = resp.AccessInfo
+		syncToken = resp.NextSyncToken
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &EmailsResponse{
-		Emails:     allEmails,
-		TotalCount: len(allEmails),
-		HasMore:    true,
-		AccessInfo: accessInfo,
+		Emails:        allEmails,
+		TotalCount:    len(allEmails),
+		HasMore:       hasMore,
+		NextSyncToken: syncToken,
+		DeletedIDs:    deleted,
+		AccessInfo:    accessInfo,
 	}, nil
 }
 
@@ -441,9 +644,76 @@ func (c *Client) GetEmail(emailID string, includeBody bool) (*SingleEmailRespons
 	return &response, nil
 }
 
+// FetchEmailBodies fills in the body of each email using up to workers
+// concurrent requests. Emails are updated in place; the first error is returned
+// after in-flight requests finish.
+func (c *Client) FetchEmailBodies(emails []Email, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(workers, len(emails)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := c.GetEmail(emails[i].ID, true)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to fetch body of %s: %w", emails[i].ID, err)
+					}
+				} else {
+					emails[i].Body = resp.Email.Body
+					emails[i].BodyType = resp.Email.BodyType
+					if len(resp.Email.Attachments) > 0 {
+						emails[i].Attachments = resp.Email.Attachments
+					}
+				}
+				done++
+				c.reportPage(PageProgress{Resource: "message bodies", Fetched: done, Total: len(emails)})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range emails {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	c.reportPage(PageProgress{Resource: "message bodies", Fetched: done, Total: len(emails), Done: true})
+	return firstErr
+}
+
+// GetAttachment downloads the raw content of an email attachment
+func (c *Client) GetAttachment(emailID, attachmentID string) ([]byte, error) {
+	path := "/api/access/email/messages/" + url.PathEscape(emailID) + "/attachments/" + url.PathEscape(attachmentID)
+	return c.Get(path)
+}
+
 // GetThread returns all messages in a thread by ID
-func (c *Client) GetThread(threadID string) (*ThreadResponse, error) {
+func (c *Client) GetThread(threadID string, includeBodies bool) (*ThreadResponse, error) {
 	path := "/api/access/email/threads/" + threadID
+	if !includeBodies {
+		path += "?includeBody=false"
+	}
 	body, err := c.Get(path)
 	if err != nil {
 		return nil, err
@@ -523,32 +793,34 @@ func (c *Client) ModifyEmail(emailID string, req ModifyEmailRequest) error {
 	return err
 }
 
-// Put sends a PUT request with JSON body
-func (c *Client) Put(path string, data interface{}) ([]byte, error) {
-	body, err := json.Marshal(data)
+// SetAutoReply turns the mailbox's automatic reply on or off
+func (c *Client) SetAutoReply(settings AutoReplySettings) (*AutoReplySettings, error) {
+	body, err := c.Put("/api/access/email/auto-reply", settings)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer cancel()
+	var result AutoReplySettings
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
 
-	resp, err := c.doWithRetry(ctx, "PUT", path, body)
+	return &result, nil
+}
+
+// Put sends a PUT request with JSON body
+func (c *Client) Put(path string, data interface{}) ([]byte, error) {
+	body, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return nil, apierr.ParseAPIError(resp)
-	}
-
-	return io.ReadAll(resp.Body)
+	return c.request("PUT", path, body)
 }
 
 // PostRaw sends a POST request with a raw byte body and specified Content-Type
 func (c *Client) PostRaw(path string, body []byte, contentType string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute+c.maxWait)
 	defer cancel()
 
 	var bodyReader io.Reader
@@ -634,39 +906,61 @@ func (c *Client) GetDriveFiles(params DriveListParams) (*DriveFilesResponse, err
 	return &response, nil
 }
 
-// GetAllDriveFiles fetches all drive files by auto-paginating (safety cap: 50 pages)
-func (c *Client) GetAllDriveFiles(params DriveListParams) (*DriveFilesResponse, error) {
-	var allFiles []DriveFile
-	var accessInfo *string
-	var authWarnings []string
+// ForEachDriveFilesPage auto-paginates through drive files (safety cap: 50
+// pages), calling fn with each page as it arrives. Stops early if fn returns an
+// error. Reports whether more results remain after hitting the page cap.
+func (c *Client) ForEachDriveFilesPage(params DriveListParams, fn func(*DriveFilesResponse) error) (hasMore bool, err error) {
 	const maxPages = 50
 
+	progress := PageProgress{Resource: "files"}
+	defer func() {
+		progress.Done = true
+		c.reportPage(progress)
+	}()
+
 	for page := 0; page < maxPages; page++ {
 		resp, err := c.GetDriveFiles(params)
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		allFiles = append(allFiles, resp.Files...)
-		accessInfo = resp.AccessInfo
-		authWarnings = resp.AuthWarnings
+		progress.Page, progress.Fetched = page+1, progress.Fetched+len(resp.Files)
+		c.reportPage(progress)
+
+		if err := fn(resp); err != nil {
+			return false, err
+		}
 
 		if !resp.HasMore || resp.NextPageToken == nil || *resp.NextPageToken == "" {
-			return &DriveFilesResponse{
-				Files:        allFiles,
-				HasMore:      false,
-				AccessInfo:   accessInfo,
-				AuthWarnings: authWarnings,
-			}, nil
+			return false, nil
 		}
 
 		params.PageToken = *resp.NextPageToken
 	}
 
 	// Safety cap reached
+	return true, nil
+}
+
+// GetAllDriveFiles fetches all drive files by auto-paginating (safety cap: 50 pages)
+func (c *Client) GetAllDriveFiles(params DriveListParams) (*DriveFilesResponse, error) {
+	var allFiles []DriveFile
+	var accessInfo *string
+	var authWarnings []string
+
+	hasMore, err := c.ForEachDriveFilesPage(params, func(resp *DriveFilesResponse) error {
+		allFiles = append(allFiles, resp.Files...)
+		accessInfo = resp.AccessInfo
+		authWarnings = resp.AuthWarnings
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &DriveFilesResponse{
 		Files:        allFiles,
-		HasMore:      true,
+		HasMore:      hasMore,
 		AccessInfo:   accessInfo,
 		AuthWarnings: authWarnings,
 	}, nil
@@ -935,44 +1229,108 @@ func (c *Client) AppendSheetRows(fileID string, req AppendSheetRowsRequest) (*Dr
 	return &result, nil
 }
 
-// GetAllEvents fetches all events by auto-paginating through results
-func (c *Client) GetAllEvents(params EventParams) (*EventsResponse, error) {
-	var allEvents []Event
-	offset := 0
-	var accessInfo string
-	var calEmail string
+// ForEachEventsPage auto-paginates through event results, calling fn with each
+// page as it arrives. Stops early if fn returns an error.
+func (c *Client) ForEachEventsPage(params EventParams, fn func(*EventsResponse) error) error {
+	return c.forEachEventsPage("events", func(offset int, pageToken string) (*EventsResponse, error) {
+		params.Offset, params.PageToken = offset, pageToken
+		return c.GetEvents(params)
+	}, fn)
+}
+
+// ForEachEventsByContactPage is ForEachEventsPage for events by contact
+func (c *Client) ForEachEventsByContactPage(params EventsByContactParams, fn func(*EventsResponse) error) error {
+	return c.forEachEventsPage("events", func(offset int, pageToken string) (*EventsResponse, error) {
+		params.Offset, params.PageToken = offset, pageToken
+		return c.GetEventsByContact(params)
+	}, fn)
+}
+
+// forEachEventsPage drives pagination for event listings. It follows the
+// page token when the API returns one, so events added or removed mid-fetch
+// don't shift later pages, and falls back to offsets otherwise.
+func (c *Client) forEachEventsPage(resource string, fetch func(offset int, pageToken string) (*EventsResponse, error), fn func(*EventsResponse) error) error {
+	offset, pageToken := 0, ""
+
+	progress := PageProgress{Resource: resource}
+	defer func() {
+		progress.Done = true
+		c.reportPage(progress)
+	}()
 
 	for {
-		params.Offset = offset
-		resp, err := c.GetEvents(params)
+		resp, err := fetch(offset, pageToken)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		allEvents = append(allEvents, resp.Events...)
-		accessInfo = resp.AccessInfo
-		calEmail = resp.CurrentUserCalendarEmail
+		progress.Page++
+		progress.Fetched += len(resp.Events)
+		if resp.Meta != nil {
+			progress.Total = resp.Meta.TotalCount
+		}
+		c.reportPage(progress)
 
-		if resp.Meta == nil || !resp.Meta.HasMore {
-			// Build final response with aggregated data
-			finalMeta := &Meta{
-				Count:      len(allEvents),
-				TotalCount: len(allEvents),
-			}
-			if resp.Meta != nil {
-				finalMeta.From = resp.Meta.From
-				finalMeta.To = resp.Meta.To
-				finalMeta.Timestamp = resp.Meta.Timestamp
-			}
-			return &EventsResponse{
-				RequestID:                resp.RequestID,
-				Events:                   allEvents,
-				Meta:                     finalMeta,
-				AccessInfo:               accessInfo,
-				CurrentUserCalendarEmail: calEmail,
-			}, nil
+		if err := fn(resp); err != nil {
+			return err
+		}
+
+		if resp.Meta == nil || !resp.Meta.HasMore || resp.Meta.Count == 0 {
+			return nil
 		}
 
 		offset += resp.Meta.Count
+		pageToken = resp.Meta.NextPageToken
 	}
 }
+
+// GetAllEvents fetches all events by auto-paginating through results
+func (c *Client) GetAllEvents(params EventParams) (*EventsResponse, error) {
+	return collectEvents(func(fn func(*EventsResponse) error) error {
+		return c.ForEachEventsPage(params, fn)
+	})
+}
+
+// GetAllEventsByContact fetches all events by contact by auto-paginating
+func (c *Client) GetAllEventsByContact(params EventsByContactParams) (*EventsResponse, error) {
+	return collectEvents(func(fn func(*EventsResponse) error) error {
+		return c.ForEachEventsByContactPage(params, fn)
+	})
+}
+
+// collectEvents aggregates every page into a single response
+func collectEvents(forEach func(func(*EventsResponse) error) error) (*EventsResponse, error) {
+	var allEvents []Event
+	var deleted []string
+	var last *EventsResponse
+
+	err := forEach(func(resp *EventsResponse) error {
+		allEvents = append(allEvents, resp.Events...)
+		deleted = append(deleted, resp.DeletedIDs...)
+		last = resp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Build final response with aggregated data
+	finalMeta := &Meta{
+		Count:      len(allEvents),
+		TotalCount: len(allEvents),
+	}
+	if last.Meta != nil {
+		finalMeta.From = last.Meta.From
+		finalMeta.To = last.Meta.To
+		finalMeta.Timestamp = last.Meta.Timestamp
+		finalMeta.NextSyncToken = last.Meta.NextSyncToken
+	}
+	return &EventsResponse{
+		RequestID:                last.RequestID,
+		Events:                   allEvents,
+		DeletedIDs:               deleted,
+		Meta:                     finalMeta,
+		AccessInfo:               last.AccessInfo,
+		CurrentUserCalendarEmail: last.CurrentUserCalendarEmail,
+	}, nil
+}
@@ -1,23 +1,43 @@
-package api
+package api_test
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/apierr"
+	"github.com/porteden/cli/internal/fakeserver"
 )
 
-// getTestClient returns a client configured for integration testing.
-// Skips the test if PE_API_KEY is not set.
-func getTestClient(t *testing.T) *Client {
-	apiKey := os.Getenv("PE_API_KEY")
-	if apiKey == "" {
-		t.Skip("PE_API_KEY not set, skipping integration test")
+// getTestClient returns a client configured for integration testing. It talks
+// to the live API when PE_API_KEY is set, and to the in-repo fake server
+// otherwise. The fake server is returned so tests can make stronger assertions
+// against its known data; it is nil when running live.
+func getTestClient(t *testing.T) (*api.Client, *fakeserver.Server) {
+	if apiKey := os.Getenv("PE_API_KEY"); apiKey != "" {
+		return api.NewClient(apiKey), nil
 	}
-	return NewClient(apiKey)
+
+	fake := fakeserver.New()
+	fake.APIKey = "test-key"
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	noWait := func(context.Context, time.Duration, int) error { return nil }
+	return api.NewClient("test-key").WithBaseURL(srv.URL).WithWaitFunc(noWait), fake
 }
 
 func TestAuthStatus(t *testing.T) {
-	client := getTestClient(t)
+	client, _ := getTestClient(t)
 
 	status, err := client.GetAuthStatus()
 	if err != nil {
@@ -38,7 +58,7 @@ func TestAuthStatus(t *testing.T) {
 }
 
 func TestGetCalendars(t *testing.T) {
-	client := getTestClient(t)
+	client, _ := getTestClient(t)
 
 	resp, err := client.GetCalendars()
 	if err != nil {
@@ -63,13 +83,13 @@ func TestGetCalendars(t *testing.T) {
 }
 
 func TestGetEventsToday(t *testing.T) {
-	client := getTestClient(t)
+	client, _ := getTestClient(t)
 
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	params := EventParams{
+	params := api.EventParams{
 		From:  startOfDay,
 		To:    endOfDay,
 		Limit: 50,
@@ -91,13 +111,13 @@ func TestGetEventsToday(t *testing.T) {
 }
 
 func TestGetEventsWeek(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfWeek := startOfDay.Add(7 * 24 * time.Hour)
 
-	params := EventParams{
+	params := api.EventParams{
 		From:  startOfDay,
 		To:    endOfWeek,
 		Limit: 50,
@@ -108,18 +128,22 @@ func TestGetEventsWeek(t *testing.T) {
 		t.Fatalf("GetEvents failed: %v", err)
 	}
 
+	if fake != nil && len(resp.Events) == 0 {
+		t.Error("Expected the fake server's sample events this week")
+	}
+
 	t.Logf("Found %d event(s) this week", len(resp.Events))
 }
 
 func TestGetEventsDateRange(t *testing.T) {
-	client := getTestClient(t)
+	client, _ := getTestClient(t)
 
 	// Test a specific date range (current month)
 	now := time.Now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	endOfMonth := startOfMonth.AddDate(0, 1, 0)
 
-	params := EventParams{
+	params := api.EventParams{
 		From:  startOfMonth,
 		To:    endOfMonth,
 		Limit: 100,
@@ -138,13 +162,13 @@ func TestGetEventsDateRange(t *testing.T) {
 }
 
 func TestSearchViaEvents(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfMonth := startOfDay.AddDate(0, 1, 0)
 
-	params := EventParams{
+	params := api.EventParams{
 		From:  startOfDay,
 		To:    endOfMonth,
 		Limit: 50,
@@ -156,13 +180,24 @@ func TestSearchViaEvents(t *testing.T) {
 		t.Fatalf("GetEvents with query failed: %v", err)
 	}
 
+	if fake != nil {
+		if len(resp.Events) == 0 {
+			t.Error("Expected at least one event matching 'meeting'")
+		}
+		for _, e := range resp.Events {
+			if !strings.Contains(strings.ToLower(e.Title+" "+e.Description+" "+e.Location), "meeting") {
+				t.Errorf("Event %q doesn't match 'meeting'", e.Title)
+			}
+		}
+	}
+
 	t.Logf("Found %d event(s) matching 'meeting'", len(resp.Events))
 }
 
 func TestGetEventsByContact(t *testing.T) {
-	client := getTestClient(t)
+	client, _ := getTestClient(t)
 
-	params := EventsByContactParams{
+	params := api.EventsByContactParams{
 		Email: "test@example.com",
 		Limit: 50,
 	}
@@ -176,33 +211,74 @@ func TestGetEventsByContact(t *testing.T) {
 }
 
 func TestRespondToEvent_NotFound(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
 	// Test with a non-existent event ID - should return an error
-	_, err := client.RespondToEvent("999999", "accepted")
+	_, err := client.RespondToEvent("999999", "accepted", "")
 	if err == nil {
 		t.Fatal("Expected error for non-existent event, got nil")
 	}
+	if fake != nil {
+		assertStatus(t, err, 404)
+	}
 
 	t.Logf("Got expected error for non-existent event: %v", err)
 }
 
 func TestGetEvent_NotFound(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
 	// Test with a non-existent event ID - should return an error
 	_, err := client.GetEvent("999999")
 	if err == nil {
 		t.Fatal("Expected error for non-existent event, got nil")
 	}
+	if fake != nil {
+		assertStatus(t, err, 404)
+	}
 
 	t.Logf("Got expected error for non-existent event: %v", err)
 }
 
+func TestGetEvent_Expand(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("creates an event")
+	}
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	created, err := client.CreateEvent(api.CreateEventRequest{
+		CalendarID: fakeserver.WorkCalendarID,
+		Summary:    "Weekly review",
+		From:       start,
+		To:         start.Add(time.Hour),
+		Recurrence: []string{"RRULE:FREQ=WEEKLY"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	plain, err := client.GetEvent(created.ID)
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	if len(plain.Event.Recurrence) != 0 {
+		t.Errorf("Recurrence returned without expand: %v", plain.Event.Recurrence)
+	}
+
+	expanded, err := client.GetEvent(created.ID, api.ExpandRecurrence)
+	if err != nil {
+		t.Fatalf("GetEvent with expand failed: %v", err)
+	}
+	if len(expanded.Event.Recurrence) != 1 || expanded.Event.Recurrence[0] != "RRULE:FREQ=WEEKLY" {
+		t.Errorf("Recurrence = %v, want the rule", expanded.Event.Recurrence)
+	}
+}
+
 func TestUpdateEvent_NotFound(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
-	req := UpdateEventRequest{
+	req := api.UpdateEventRequest{
 		Summary: "Test Update",
 	}
 
@@ -210,29 +286,35 @@ func TestUpdateEvent_NotFound(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error for non-existent event, got nil")
 	}
+	if fake != nil {
+		assertStatus(t, err, 404)
+	}
 
 	t.Logf("Got expected error for non-existent event: %v", err)
 }
 
 func TestDeleteEvent_NotFound(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
-	_, err := client.DeleteEvent("999999", true)
+	_, err := client.DeleteEvent("999999", true, "")
 	if err == nil {
 		t.Fatal("Expected error for non-existent event, got nil")
 	}
+	if fake != nil {
+		assertStatus(t, err, 404)
+	}
 
 	t.Logf("Got expected error for non-existent event: %v", err)
 }
 
 func TestGetFreeBusy(t *testing.T) {
-	client := getTestClient(t)
+	client, _ := getTestClient(t)
 
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfWeek := startOfDay.Add(7 * 24 * time.Hour)
 
-	params := FreeBusyParams{
+	params := api.FreeBusyParams{
 		From: startOfDay,
 		To:   endOfWeek,
 	}
@@ -249,13 +331,13 @@ func TestGetFreeBusy(t *testing.T) {
 }
 
 func TestGetAllEvents(t *testing.T) {
-	client := getTestClient(t)
+	client, fake := getTestClient(t)
 
 	now := time.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfWeek := startOfDay.Add(7 * 24 * time.Hour)
 
-	params := EventParams{
+	params := api.EventParams{
 		From:  startOfDay,
 		To:    endOfWeek,
 		Limit: 10, // Small limit to test pagination
@@ -266,5 +348,618 @@ func TestGetAllEvents(t *testing.T) {
 		t.Fatalf("GetAllEvents failed: %v", err)
 	}
 
+	if fake != nil {
+		params.Limit = 100
+		single, err := client.GetEvents(params)
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		if len(single.Events) <= 10 {
+			t.Fatalf("Expected more than one page of sample events, got %d", len(single.Events))
+		}
+		if len(resp.Events) != len(single.Events) {
+			t.Errorf("GetAllEvents returned %d event(s), want %d", len(resp.Events), len(single.Events))
+		}
+	}
+
 	t.Logf("GetAllEvents returned %d event(s)", len(resp.Events))
 }
+
+func TestForEachEventsPageInsertMidFetch(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("creates events in the fake server's sample data")
+	}
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	params := api.EventParams{From: startOfDay, To: startOfDay.AddDate(0, 0, 7), Limit: 5}
+
+	seen := make(map[string]bool)
+	pages := 0
+	err := client.ForEachEventsPage(params, func(resp *api.EventsResponse) error {
+		pages++
+		if pages == 1 {
+			// An event before the page boundary would shift offset-based pages
+			_, err := client.CreateEvent(api.CreateEventRequest{
+				CalendarID: 1001,
+				Summary:    "Added mid-fetch",
+				From:       startOfDay.Add(time.Minute),
+				To:         startOfDay.Add(time.Hour),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, e := range resp.Events {
+			if seen[e.ID] {
+				t.Errorf("Event %s returned twice", e.ID)
+			}
+			seen[e.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachEventsPage failed: %v", err)
+	}
+	if pages < 2 {
+		t.Fatalf("Expected several pages, got %d", pages)
+	}
+}
+
+func TestGetAllEmails(t *testing.T) {
+	client, fake := getTestClient(t)
+
+	resp, err := client.GetAllEmails(api.EmailParams{Limit: 5})
+	if err != nil {
+		t.Fatalf("GetAllEmails failed: %v", err)
+	}
+
+	if fake != nil {
+		seen := make(map[string]bool)
+		for _, e := range resp.Emails {
+			if seen[e.ID] {
+				t.Errorf("Email %s returned twice", e.ID)
+			}
+			seen[e.ID] = true
+		}
+		if len(resp.Emails) <= 5 {
+			t.Errorf("Expected several pages of sample emails, got %d", len(resp.Emails))
+		}
+	}
+
+	t.Logf("GetAllEmails returned %d email(s)", len(resp.Emails))
+}
+
+func TestGetThread(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires the fake server's sample data")
+	}
+
+	emails, err := client.GetEmails(api.EmailParams{Subject: "Q3 planning", Limit: 1})
+	if err != nil {
+		t.Fatalf("GetEmails failed: %v", err)
+	}
+	if len(emails.Emails) != 1 {
+		t.Fatalf("Expected 1 email, got %d", len(emails.Emails))
+	}
+
+	thread, err := client.GetThread(emails.Emails[0].ThreadID, true)
+	if err != nil {
+		t.Fatalf("GetThread failed: %v", err)
+	}
+	if thread.MessageCount != 2 || len(thread.Messages) != 2 {
+		t.Errorf("Expected 2 messages in thread, got %d", len(thread.Messages))
+	}
+	if !thread.Messages[0].ReceivedAt.Before(thread.Messages[1].ReceivedAt) {
+		t.Error("Expected thread messages oldest first")
+	}
+	if thread.Messages[0].Body == "" {
+		t.Error("Expected message bodies")
+	}
+
+	headers, err := client.GetThread(emails.Emails[0].ThreadID, false)
+	if err != nil {
+		t.Fatalf("GetThread without bodies failed: %v", err)
+	}
+	if len(headers.Messages) != 2 || headers.Messages[0].Body != "" || headers.Messages[0].Subject == "" {
+		t.Errorf("Expected headers without bodies, got %+v", headers.Messages)
+	}
+}
+
+func TestGetEmailRaw(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires the fake server's sample data")
+	}
+
+	emails, err := client.GetEmails(api.EmailParams{Subject: "Q3 planning", Limit: 1})
+	if err != nil || len(emails.Emails) != 1 {
+		t.Fatalf("GetEmails failed: %v", err)
+	}
+	raw, err := client.GetEmailRaw(emails.Emails[0].ID)
+	if err != nil {
+		t.Fatalf("GetEmailRaw failed: %v", err)
+	}
+	headers, err := api.ParseHeaders(raw)
+	if err != nil {
+		t.Fatalf("ParseHeaders failed: %v", err)
+	}
+	found := map[string]int{}
+	for _, h := range headers {
+		found[h.Name]++
+	}
+	if found["Received"] < 2 || found["Message-ID"] != 1 || found["Authentication-Results"] != 1 {
+		t.Errorf("Expected the Received chain, Message-ID and authentication results, got %+v", headers)
+	}
+}
+
+func TestEventLifecycle(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("creates and deletes events; runs against the fake server only")
+	}
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	created, err := client.CreateEvent(api.CreateEventRequest{
+		CalendarID: fakeserver.WorkCalendarID,
+		Summary:    "Lifecycle test",
+		From:       start,
+		To:         start.Add(30 * time.Minute),
+		Attendees:  []string{fakeserver.UserEmail},
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	updated, err := client.UpdateEvent(created.ID, api.UpdateEventRequest{Location: "Room 1"})
+	if err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	if updated.Location != "Room 1" || updated.Title != "Lifecycle test" {
+		t.Errorf("Unexpected event after update: %+v", updated)
+	}
+
+	responded, err := client.RespondToEvent(created.ID, "accepted", "Joining 10 minutes late")
+	if err != nil {
+		t.Fatalf("RespondToEvent failed: %v", err)
+	}
+	if len(responded.Attendees) != 1 || responded.Attendees[0].Comment != "Joining 10 minutes late" {
+		t.Errorf("Response comment not recorded: %+v", responded.Attendees)
+	}
+
+	if _, err := client.DeleteEvent(created.ID, false, ""); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	_, err = client.GetEvent(created.ID)
+	assertStatus(t, err, 404)
+}
+
+func TestRecurringSeriesScope(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("changes a recurring series; runs against the fake server only")
+	}
+
+	window := api.EventParams{Limit: 100, Query: "standup", From: time.Now().AddDate(0, 0, -10), To: time.Now().AddDate(0, 0, 17)}
+	standups := func() []api.Event {
+		resp, err := client.GetAllEvents(window)
+		if err != nil {
+			t.Fatalf("GetAllEvents failed: %v", err)
+		}
+		return resp.Events
+	}
+	series := standups()
+	if len(series) < 10 || series[0].SeriesID == "" {
+		t.Fatalf("Expected a seeded standup series, got %d events", len(series))
+	}
+
+	// From the middle on, an hour later in a new room
+	mid := series[len(series)/2]
+	later := mid.StartUtc.Add(time.Hour)
+	if _, err := client.UpdateEvent(mid.ID, api.UpdateEventRequest{Location: "Room 4", From: &later, To: ptr(mid.EndUtc.Add(time.Hour)), Scope: api.ScopeThisAndFollowing}); err != nil {
+		t.Fatalf("UpdateEvent failed: %v", err)
+	}
+	for i, e := range standups() {
+		moved := i >= len(series)/2
+		if (e.Location == "Room 4") != moved || e.StartUtc.Equal(series[i].StartUtc.Add(time.Hour)) != moved {
+			t.Errorf("Instance %d (%s): location %q, start %v; moved = %v", i, e.ID, e.Location, e.StartUtc, moved)
+		}
+	}
+
+	// The default leaves the rest of the series alone
+	if _, err := client.DeleteEvent(series[0].ID, false, ""); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	if n := len(standups()); n != len(series)-1 {
+		t.Errorf("Expected %d instances after deleting one, got %d", len(series)-1, n)
+	}
+	resp, err := client.DeleteEvent(mid.ID, false, api.ScopeEntireSeries)
+	if err != nil {
+		t.Fatalf("DeleteEvent of the series failed: %v", err)
+	}
+	if n := len(standups()); n != 0 {
+		t.Errorf("Expected the series to be gone, %d instances left (%s)", n, resp.Message)
+	}
+
+	// A one-off event has no series to widen the change to
+	lunch, err := client.GetAllEvents(api.EventParams{Limit: 10, Query: "Lunch with Sam", From: window.From, To: window.To})
+	if err != nil || len(lunch.Events) == 0 {
+		t.Fatalf("GetAllEvents = %v, %v", lunch, err)
+	}
+	_, err = client.UpdateEvent(lunch.Events[0].ID, api.UpdateEventRequest{Location: "Cafe", Scope: api.ScopeEntireSeries})
+	assertStatus(t, err, 400)
+}
+
+func TestEventReminders(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("creates and changes events; runs against the fake server only")
+	}
+
+	start := time.Now().Add(72 * time.Hour).Truncate(time.Hour)
+	reminders := &api.EventReminders{Overrides: []api.Reminder{{Method: api.ReminderPopup, Minutes: 10}, {Method: api.ReminderEmail, Minutes: 1440}}}
+	created, err := client.CreateEvent(api.CreateEventRequest{
+		CalendarID: fakeserver.WorkCalendarID,
+		Summary:    "Reminded",
+		From:       start,
+		To:         start.Add(time.Hour),
+		Reminders:  reminders,
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	got, err := client.GetEvent(created.ID)
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	if r := got.Event.Reminders; r == nil || r.UseDefault || len(r.Overrides) != 2 || r.Overrides[1] != reminders.Overrides[1] {
+		t.Errorf("Reminders = %+v, want %+v", r, reminders)
+	}
+
+	// An empty list of overrides turns reminders off; useDefault restores them
+	for _, want := range []api.EventReminders{{}, {UseDefault: true}} {
+		updated, err := client.UpdateEvent(created.ID, api.UpdateEventRequest{Reminders: ptr(want)})
+		if err != nil {
+			t.Fatalf("UpdateEvent failed: %v", err)
+		}
+		if r := updated.Reminders; r == nil || r.UseDefault != want.UseDefault || len(r.Overrides) != 0 {
+			t.Errorf("Reminders after update = %+v, want %+v", r, want)
+		}
+	}
+
+	_, err = client.UpdateEvent(created.ID, api.UpdateEventRequest{Reminders: &api.EventReminders{Overrides: []api.Reminder{{Method: "sms", Minutes: 5}}}})
+	assertStatus(t, err, 400)
+}
+
+func TestSyncTokens(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("changes events and emails; runs against the fake server only")
+	}
+
+	window := api.EventParams{Limit: 5, From: time.Now().AddDate(0, 0, -7), To: time.Now().AddDate(0, 0, 14)}
+	full, err := client.GetAllEvents(window)
+	if err != nil {
+		t.Fatalf("GetAllEvents failed: %v", err)
+	}
+	if full.Meta.NextSyncToken == "" || len(full.Events) == 0 {
+		t.Fatalf("Expected events and a sync token, got %d events, token %q", len(full.Events), full.Meta.NextSyncToken)
+	}
+
+	// Only changes since the token come back, with deletions listed by ID
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	created, err := client.CreateEvent(api.CreateEventRequest{CalendarID: fakeserver.WorkCalendarID, Summary: "Delta", From: start, To: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	gone := full.Events[0].ID
+	if _, err := client.DeleteEvent(gone, false, ""); err != nil {
+		t.Fatalf("DeleteEvent failed: %v", err)
+	}
+	window.SyncToken = full.Meta.NextSyncToken
+	delta, err := client.GetAllEvents(window)
+	if err != nil {
+		t.Fatalf("GetAllEvents with sync token failed: %v", err)
+	}
+	if len(delta.Events) != 1 || delta.Events[0].ID != created.ID {
+		t.Errorf("Expected only the created event, got %+v", delta.Events)
+	}
+	if len(delta.DeletedIDs) != 1 || delta.DeletedIDs[0] != gone {
+		t.Errorf("Expected %s to be reported deleted, got %v", gone, delta.DeletedIDs)
+	}
+	if delta.Meta.NextSyncToken == "" || delta.Meta.NextSyncToken == window.SyncToken {
+		t.Errorf("Expected a new sync token, got %q", delta.Meta.NextSyncToken)
+	}
+
+	emails, err := client.GetAllEmails(api.EmailParams{Limit: 5})
+	if err != nil {
+		t.Fatalf("GetAllEmails failed: %v", err)
+	}
+	read := true
+	if err := client.ModifyEmail(emails.Emails[0].ID, api.ModifyEmailRequest{MarkAsRead: &read}); err != nil {
+		t.Fatalf("ModifyEmail failed: %v", err)
+	}
+	changed, err := client.GetAllEmails(api.EmailParams{Limit: 5, SyncToken: emails.NextSyncToken})
+	if err != nil {
+		t.Fatalf("GetAllEmails with sync token failed: %v", err)
+	}
+	if len(changed.Emails) != 1 || changed.Emails[0].ID != emails.Emails[0].ID {
+		t.Errorf("Expected only the modified email, got %d", len(changed.Emails))
+	}
+
+	fake.ExpireSyncTokens()
+	_, err = client.GetEmails(api.EmailParams{SyncToken: changed.NextSyncToken})
+	if !api.IsSyncTokenExpired(err) {
+		t.Errorf("Expected an expired sync token error, got %v", err)
+	}
+}
+
+func TestRetryOnRateLimit(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires fault injection on the fake server")
+	}
+
+	fake.InjectError("/api/access/calendar/calendars", 429, 2)
+	if _, err := client.GetCalendars(); err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+
+	fake.InjectError("/api/access/calendar/calendars", 429, 10)
+	_, err := client.GetCalendars()
+	if err == nil {
+		t.Fatal("Expected error after exhausting retries, got nil")
+	}
+}
+
+func TestConnectionReuse(t *testing.T) {
+	fake := fakeserver.New()
+	fake.APIKey = "test-key"
+	srv := httptest.NewUnstartedServer(fake)
+	var conns atomic.Int32
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	noWait := func(context.Context, time.Duration, int) error { return nil }
+	client := api.NewClient("test-key").WithBaseURL(srv.URL).WithWaitFunc(noWait)
+
+	// Retried error responses must not cost a connection either
+	fake.InjectError("/api/access/email/messages", 503, 2)
+	if _, err := client.GetAllEmails(api.EmailParams{Limit: 2}); err != nil {
+		t.Fatalf("GetAllEmails failed: %v", err)
+	}
+	other := api.NewClient("test-key").WithBaseURL(srv.URL)
+	if _, err := other.GetCalendars(); err != nil {
+		t.Fatalf("GetCalendars failed: %v", err)
+	}
+
+	if n := conns.Load(); n != 1 {
+		t.Errorf("opened %d connections, want 1 shared by every page and client", n)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	fake := fakeserver.New()
+	fake.APIKey = "test-key"
+	var encodings []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	client := api.NewClient("test-key").WithBaseURL(srv.URL)
+
+	// The fake server gzips JSON, so this only parses if it was decoded
+	if resp, err := client.GetEmails(api.EmailParams{Limit: 5}); err != nil || len(resp.Emails) == 0 {
+		t.Fatalf("GetEmails = %v, %v", resp, err)
+	}
+
+	large := api.SendEmailRequest{
+		To:      []api.Participant{{Email: "priya@example.com"}},
+		Subject: "Report",
+		Body:    strings.Repeat("Quarterly numbers, line by line. ", 2000),
+	}
+	if _, err := client.SendEmail(large); err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+	small := api.SendEmailRequest{To: large.To, Subject: "Hi", Body: "Short"}
+	if _, err := client.SendEmail(small); err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+	if want := []string{"", "gzip", ""}; strings.Join(encodings, ",") != strings.Join(want, ",") {
+		t.Errorf("request encodings = %q, want %q", encodings, want)
+	}
+
+	// A server that rejects compressed bodies gets them plain, then and after
+	fake.RejectCompressedRequests = true
+	encodings = nil
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendEmail(large); err != nil {
+			t.Fatalf("SendEmail after rejection failed: %v", err)
+		}
+	}
+	if want := []string{"gzip", "", ""}; strings.Join(encodings, ",") != strings.Join(want, ",") {
+		t.Errorf("request encodings = %q, want %q", encodings, want)
+	}
+}
+
+func TestUserAgentSuffix(t *testing.T) {
+	fake := fakeserver.New()
+	fake.APIKey = "test-key"
+	var agent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent = r.Header.Get("User-Agent")
+		fake.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := api.NewClient("test-key").WithBaseURL(srv.URL).WithUserAgentSuffix("nightly-sync-job")
+	if _, err := client.GetCalendars(); err != nil {
+		t.Fatalf("GetCalendars failed: %v", err)
+	}
+	if !strings.HasPrefix(agent, "PortEden-CLI/") || !strings.HasSuffix(agent, ") nightly-sync-job") {
+		t.Errorf("User-Agent = %q, want PortEden-CLI/{version} ({os}; {arch}) nightly-sync-job", agent)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	client, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires fault injection on the fake server")
+	}
+
+	fake.InjectError("/api/access/calendar/calendars", 503, 10)
+	if _, err := client.GetCalendars(); !api.IsTransient(err) {
+		t.Errorf("Expected a server error to be transient, got %v", err)
+	}
+
+	if _, err := client.GetEvent("evt_missing"); err == nil || api.IsTransient(err) {
+		t.Errorf("Expected a not found error not to be transient, got %v", err)
+	}
+
+	srv := httptest.NewServer(fake)
+	srv.Close()
+	noWait := func(context.Context, time.Duration, int) error { return nil }
+	offline := api.NewClient("test-key").WithBaseURL(srv.URL).WithWaitFunc(noWait)
+	if _, err := offline.GetCalendars(); !api.IsTransient(err) {
+		t.Errorf("Expected a network error to be transient, got %v", err)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	_, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires the fake server")
+	}
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	_, err := api.NewClient("wrong-key").WithBaseURL(srv.URL).GetAuthStatus()
+	assertStatus(t, err, 401)
+}
+
+func TestReauthOnUnauthenticated(t *testing.T) {
+	_, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires the fake server")
+	}
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	calls := 0
+	client := api.NewClient("revoked-key").WithBaseURL(srv.URL).WithReauth(func() (string, error) {
+		calls++
+		return fake.APIKey, nil
+	})
+	if _, err := client.GetAuthStatus(); err != nil {
+		t.Fatalf("GetAuthStatus after re-authentication failed: %v", err)
+	}
+	if _, err := client.GetCalendars(); err != nil {
+		t.Fatalf("GetCalendars with the new key failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected one re-authentication, got %d", calls)
+	}
+
+	// Giving up keeps the original error
+	declined := api.NewClient("revoked-key").WithBaseURL(srv.URL).WithReauth(func() (string, error) {
+		return "", errors.New("declined")
+	})
+	_, err := declined.GetAuthStatus()
+	assertStatus(t, err, 401)
+}
+
+func TestReauthWithConcurrentRequests(t *testing.T) {
+	_, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires the fake server")
+	}
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	var calls atomic.Int32
+	client := api.NewClient("revoked-key").WithBaseURL(srv.URL).WithReauth(func() (string, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond) // Signing in takes a while
+		return fake.APIKey, nil
+	})
+
+	// Requests in flight while the key is replaced all get through with it
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetCalendars()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetCalendars failed: %v", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected one re-authentication, got %d", n)
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	_, fake := getTestClient(t)
+	if fake == nil {
+		t.Skip("requires the fake server")
+	}
+	fake.RefreshToken = "rt_initial"
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	var rotated []string
+	client := api.NewClient("").WithBaseURL(srv.URL).WithRefreshToken("rt_initial", func(token string) {
+		rotated = append(rotated, token)
+	})
+	if _, err := client.GetAuthStatus(); err != nil {
+		t.Fatalf("GetAuthStatus with an access token failed: %v", err)
+	}
+	if _, err := client.GetCalendars(); err != nil {
+		t.Fatalf("GetCalendars with a cached access token failed: %v", err)
+	}
+	if len(rotated) != 1 || rotated[0] != fake.RefreshToken {
+		t.Errorf("Expected one rotation to %q, got %v", fake.RefreshToken, rotated)
+	}
+
+	// The replaced refresh token no longer works
+	stale := api.NewClient("").WithBaseURL(srv.URL).WithRefreshToken("rt_initial", nil)
+	_, err := stale.GetAuthStatus()
+	assertStatus(t, err, 401)
+}
+
+// assertStatus checks that err is an API error with the given HTTP status
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var apiErr *apierr.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected API error with status %d, got %v", status, err)
+	}
+	if apiErr.StatusCode != status {
+		t.Errorf("Expected status %d, got %d (%v)", status, apiErr.StatusCode, apiErr)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
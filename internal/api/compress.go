@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/porteden/cli/internal/debug"
+)
+
+// compressMinSize is the smallest JSON request body worth compressing;
+// below it the gzip overhead outweighs the bytes saved
+const compressMinSize = 32 << 10
+
+// compressor asks for gzip-encoded responses and decodes them, and sends
+// large JSON request bodies gzip-encoded. It sits beneath the recorder, so
+// fixtures hold plain bodies. Brotli isn't offered: the standard library
+// has no decoder for it.
+type compressor struct {
+	Base http.RoundTripper
+	// uploadsRejected is set once the server answers a compressed request
+	// with 415; later requests are sent uncompressed
+	uploadsRejected atomic.Bool
+}
+
+func newCompressor(base http.RoundTripper) *compressor {
+	return &compressor{Base: base}
+}
+
+func (c *compressor) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Setting Accept-Encoding ourselves turns off the transport's implicit
+	// gzip handling, so responses are decoded below
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	var plain []byte
+	if c.shouldCompress(req) {
+		var err error
+		if plain, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		setBody(req, gzipBytes(plain), "gzip")
+		debug.Log("Compressed request body from %d to %d bytes", len(plain), req.ContentLength)
+	}
+
+	resp, err := c.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if plain != nil && resp.StatusCode == http.StatusUnsupportedMediaType {
+		debug.Log("Server rejected a compressed request body; sending uncompressed from now on")
+		drainAndClose(resp.Body)
+		c.uploadsRejected.Store(true)
+		setBody(req, plain, "")
+		if resp, err = c.Base.RoundTrip(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		resp.Body = &gzipReader{body: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// shouldCompress reports whether req carries a JSON body large enough to
+// be worth compressing. Uploads of files are left alone, since they are
+// usually compressed already.
+func (c *compressor) shouldCompress(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody &&
+		req.ContentLength >= compressMinSize &&
+		req.Header.Get("Content-Encoding") == "" &&
+		strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") &&
+		!c.uploadsRejected.Load()
+}
+
+// setBody replaces req's body with data sent with the given Content-Encoding
+func setBody(req *http.Request, data []byte, encoding string) {
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+	if encoding == "" {
+		req.Header.Del("Content-Encoding")
+	} else {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+}
+
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+	return buf.Bytes()
+}
+
+// gzipReader decodes a gzip response body. The gzip header is read on the
+// first Read, so empty bodies (HEAD, 204) don't fail.
+type gzipReader struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (g *gzipReader) Read(p []byte) (int, error) {
+	if g.zr == nil && g.err == nil {
+		g.zr, g.err = gzip.NewReader(g.body)
+	}
+	if g.err != nil {
+		return 0, g.err
+	}
+	return g.zr.Read(p)
+}
+
+func (g *gzipReader) Close() error {
+	return g.body.Close()
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/porteden/cli/internal/apierr"
+)
+
+// PingResult reports how a single request to the API went. Durations are
+// zero for phases that didn't happen, e.g. DNS on a reused connection.
+type PingResult struct {
+	BaseURL    string
+	RemoteAddr string
+	Status     int
+	Latency    time.Duration
+	DNS        time.Duration
+	Connect    time.Duration
+	TLS        time.Duration
+	FirstByte  time.Duration
+	TLSInfo    *TLSInfo
+	// Version is the API version negotiated for the request, if reported
+	Version *VersionInfo
+	// Auth is the key's status when the request was authorized
+	Auth *AuthStatusResponse
+	// NetworkError is set when the API couldn't be reached at all
+	NetworkError string
+	// APIError is set when the API answered with an error
+	APIError *apierr.APIError
+}
+
+// TLSInfo describes the connection's TLS session
+type TLSInfo struct {
+	Version     string    `json:"version"`
+	CipherSuite string    `json:"cipherSuite"`
+	ServerName  string    `json:"serverName"`
+	Issuer      string    `json:"issuer,omitempty"`
+	NotAfter    time.Time `json:"notAfter,omitempty"`
+}
+
+// BaseURL returns the API address the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Ping makes one request to the auth status endpoint, without retries, and
+// times each phase of it
+func (c *Client) Ping(ctx context.Context) *PingResult {
+	result := &PingResult{BaseURL: c.baseURL}
+
+	var start, dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { result.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { result.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			result.TLS = time.Since(tlsStart)
+			if err == nil {
+				result.TLSInfo = newTLSInfo(state)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Conn != nil {
+				result.RemoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+		GotFirstResponseByte: func() { result.FirstByte = time.Since(start) },
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), "GET", c.baseURL+"/api/auth/token/status", nil)
+	if err != nil {
+		result.NetworkError = err.Error()
+		return result
+	}
+
+	start = time.Now()
+	resp, err := c.httpClient.Do(req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		// The API may have answered the token refresh, e.g. rejecting it
+		var apiErr *apierr.APIError
+		if errors.As(err, &apiErr) {
+			result.Status = apiErr.StatusCode
+			result.APIError = apiErr
+			return result
+		}
+		result.NetworkError = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	result.Version = LastVersionInfo()
+	if resp.StatusCode >= 400 {
+		result.APIError = apierr.ParseAPIError(resp)
+		return result
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		var status AuthStatusResponse
+		if json.Unmarshal(body, &status) == nil {
+			result.Auth = &status
+		}
+	}
+	return result
+}
+
+func newTLSInfo(state tls.ConnectionState) *TLSInfo {
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		info.NotAfter = cert.NotAfter
+		info.Issuer = cert.Issuer.CommonName
+		if len(cert.Issuer.Organization) > 0 {
+			info.Issuer = cert.Issuer.Organization[0]
+		}
+	}
+	return info
+}
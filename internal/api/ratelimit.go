@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate is a request budget such as 10 per minute
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.N, r.Per)
+}
+
+// interval is the spacing between requests at this rate
+func (r Rate) interval() time.Duration {
+	return r.Per / time.Duration(r.N)
+}
+
+// rateUnits are the named periods ParseRate accepts after the slash
+var rateUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "second": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour,
+}
+
+// ParseRate parses "10/min", "2/s", "500/hour" or "5/30s"
+func ParseRate(s string) (Rate, error) {
+	count, unit, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid rate %q (use e.g. 10/min)", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: count must be a positive number", s)
+	}
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	per, ok := rateUnits[unit]
+	if !ok {
+		if per, err = time.ParseDuration(unit); err != nil || per <= 0 {
+			return Rate{}, fmt.Errorf("invalid rate %q: period must be s, min, hour or a duration", s)
+		}
+	}
+	return Rate{N: n, Per: per}, nil
+}
+
+// maxSlowdown caps how far rate-limit responses can stretch the interval
+const maxSlowdown = 8
+
+// Limiter spaces requests evenly at a Rate. When the API still rate-limits,
+// Backoff holds every request until the server's retry delay has passed and
+// widens the spacing, which eases back to the configured rate as requests
+// succeed.
+type Limiter struct {
+	mu       sync.Mutex
+	base     time.Duration // Spacing at the configured rate
+	interval time.Duration // Current spacing, widened after rate limiting
+	next     time.Time     // Earliest start of the next request
+}
+
+// NewLimiter creates a limiter for the given rate
+func NewLimiter(r Rate) *Limiter {
+	return &Limiter{base: r.interval(), interval: r.interval()}
+}
+
+// Wait blocks until the next request may start
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, start.Sub(now), 0)
+}
+
+// Backoff records a rate-limit response: nothing starts before delay has
+// passed, and requests are spaced further apart
+func (l *Limiter) Backoff(delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.interval = min(l.interval*2, l.base*maxSlowdown)
+	if resume := time.Now().Add(delay); resume.After(l.next) {
+		l.next = resume
+	}
+}
+
+// Success moves the spacing a step back toward the configured rate
+func (l *Limiter) Success() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.interval = l.base + (l.interval-l.base)*3/4
+}
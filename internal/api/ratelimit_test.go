@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration // Interval between requests
+		err  bool
+	}{
+		{in: "10/min", want: 6 * time.Second},
+		{in: "2/s", want: 500 * time.Millisecond},
+		{in: "500/hour", want: 7200 * time.Millisecond},
+		{in: "5/30s", want: 6 * time.Second},
+		{in: "10", err: true},
+		{in: "0/min", err: true},
+		{in: "10/fortnight", err: true},
+	}
+	for _, tt := range tests {
+		r, err := ParseRate(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseRate(%q) = %v, want error", tt.in, r)
+			}
+			continue
+		}
+		if err != nil || r.interval() != tt.want {
+			t.Errorf("ParseRate(%q) interval = %v, %v; want %v", tt.in, r.interval(), err, tt.want)
+		}
+	}
+}
+
+func TestLimiterBackoffAndRecovery(t *testing.T) {
+	l := NewLimiter(Rate{N: 10, Per: time.Second})
+
+	l.Backoff(2 * time.Second)
+	if l.interval != 200*time.Millisecond {
+		t.Errorf("interval after backoff = %v, want 200ms", l.interval)
+	}
+	if until := time.Until(l.next); until < time.Second {
+		t.Errorf("next request in %v, want about 2s", until)
+	}
+	for i := 0; i < 10; i++ {
+		l.Backoff(0)
+	}
+	if l.interval != 800*time.Millisecond {
+		t.Errorf("interval = %v, want capped at 800ms", l.interval)
+	}
+
+	for i := 0; i < 50; i++ {
+		l.Success()
+	}
+	if l.interval > 101*time.Millisecond {
+		t.Errorf("interval after successes = %v, want back near 100ms", l.interval)
+	}
+}
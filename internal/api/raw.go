@@ -0,0 +1,56 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MessageHeader is one header field of a raw message, in the order it appears
+type MessageHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// GetEmailRaw downloads an email as the original RFC 822 message, with every
+// header the provider received
+func (c *Client) GetEmailRaw(emailID string) ([]byte, error) {
+	path := "/api/access/email/messages/" + url.PathEscape(emailID) + "/raw"
+	return c.Get(path)
+}
+
+// ParseHeaders returns the header fields of a raw message in order, with
+// folded lines joined. Repeated fields such as Received are all kept.
+func ParseHeaders(raw []byte) ([]MessageHeader, error) {
+	var headers []MessageHeader
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "" {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(headers) == 0 {
+				return nil, fmt.Errorf("malformed message: starts with a continuation line")
+			}
+			last := &headers[len(headers)-1]
+			last.Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("malformed header line %q", line)
+		}
+		headers = append(headers, MessageHeader{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("malformed message: no headers")
+	}
+	return headers, nil
+}
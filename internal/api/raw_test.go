@@ -0,0 +1,38 @@
+package api
+
+import "testing"
+
+func TestParseHeaders(t *testing.T) {
+	raw := "Received: by mx.example.com;\r\n" +
+		"        Thu, 15 Oct 2026 16:05:00 +0000\r\n" +
+		"Received: from mail.example.org\r\n" +
+		"Authentication-Results: mx.example.com;\r\n\tspf=pass;\r\n\tdkim=pass\r\n" +
+		"Subject: Hello: world\r\n" +
+		"\r\n" +
+		"Body: not a header\r\n"
+
+	headers, err := ParseHeaders([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MessageHeader{
+		{"Received", "by mx.example.com; Thu, 15 Oct 2026 16:05:00 +0000"},
+		{"Received", "from mail.example.org"},
+		{"Authentication-Results", "mx.example.com; spf=pass; dkim=pass"},
+		{"Subject", "Hello: world"},
+	}
+	if len(headers) != len(want) {
+		t.Fatalf("got %d headers, want %d: %+v", len(headers), len(want), headers)
+	}
+	for i := range want {
+		if headers[i] != want[i] {
+			t.Errorf("header %d = %+v, want %+v", i, headers[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"", "  folded first\r\n", "no colon here\r\n"} {
+		if _, err := ParseHeaders([]byte(bad)); err == nil {
+			t.Errorf("ParseHeaders(%q) should fail", bad)
+		}
+	}
+}
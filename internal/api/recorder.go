@@ -0,0 +1,259 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/porteden/cli/internal/debug"
+)
+
+// Recorder is a VCR-style RoundTripper. In record mode it passes requests
+// through and saves each sanitized request/response pair as a JSON fixture in
+// Dir; in replay mode it serves responses from those fixtures without any
+// network access. Enable with PE_RECORD=<dir> or PE_REPLAY=<dir>.
+type Recorder struct {
+	Base   http.RoundTripper
+	Dir    string
+	Replay bool
+}
+
+// ErrNoFixture is returned in replay mode when a request has no recording
+var ErrNoFixture = errors.New("no recorded response")
+
+// Fixture is one recorded request/response pair
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"` // Path and query, without host
+	Body   string `json:"body,omitempty"`
+}
+
+type FixtureResponse struct {
+	Status       int               `json:"status"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Body         string            `json:"body"`
+	BodyEncoding string            `json:"bodyEncoding,omitempty"` // "base64" for binary bodies
+}
+
+// recordedHeaders are the response headers worth keeping; everything else
+// (cookies, tracing IDs, dates) is dropped to keep fixtures stable and clean
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// sensitiveParam matches query parameters whose values must not be recorded
+var sensitiveParam = regexp.MustCompile(`(?i)^(token|key|api_?key|secret|password)$`)
+
+// sensitiveField matches JSON body fields whose values must not be recorded,
+// such as the tokens exchanged at auth/token/refresh. Paging and sync tokens
+// (nextPageToken, syncToken) are left alone: replays depend on them.
+var sensitiveField = regexp.MustCompile(`(?i)^(token|(access|refresh|id)_?token|api_?key|(client_?)?secret|password)$`)
+
+var nonSlugChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// newRecorderFromEnv wraps base with a Recorder when PE_RECORD or PE_REPLAY is set
+func newRecorderFromEnv(base http.RoundTripper) http.RoundTripper {
+	if dir := os.Getenv("PE_REPLAY"); dir != "" {
+		return &Recorder{Base: base, Dir: dir, Replay: true}
+	}
+	if dir := os.Getenv("PE_RECORD"); dir != "" {
+		return &Recorder{Base: base, Dir: dir}
+	}
+	return base
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	// Named after the redacted body, so a replay matches whatever the secrets
+	// are. Request headers, Authorization included, are never recorded.
+	recordedBody := redactBody(reqBody)
+	path := filepath.Join(r.Dir, FixtureName(req.Method, sanitizedURL(req), recordedBody))
+
+	if r.Replay {
+		return r.replay(req, path)
+	}
+
+	resp, err := r.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	fixture := Fixture{
+		Request: FixtureRequest{Method: req.Method, URL: sanitizedURL(req), Body: string(recordedBody)},
+		Response: FixtureResponse{
+			Status:  resp.StatusCode,
+			Headers: map[string]string{},
+		},
+	}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			fixture.Response.Headers[h] = v
+		}
+	}
+	if utf8.Valid(respBody) {
+		fixture.Response.Body = string(redactBody(respBody))
+	} else {
+		fixture.Response.Body = base64.StdEncoding.EncodeToString(respBody)
+		fixture.Response.BodyEncoding = "base64"
+	}
+
+	if err := writeFixture(path, fixture); err != nil {
+		debug.Log("Failed to record fixture: %v", err)
+	} else {
+		debug.Log("Recorded %s %s -> %s", req.Method, fixture.Request.URL, path)
+	}
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s %s (expected %s)", ErrNoFixture, req.Method, sanitizedURL(req), path)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	body := []byte(fixture.Response.Body)
+	if fixture.Response.BodyEncoding == "base64" {
+		if body, err = base64.StdEncoding.DecodeString(fixture.Response.Body); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+		}
+	}
+
+	header := http.Header{}
+	for k, v := range fixture.Response.Headers {
+		header.Set(k, v)
+	}
+	debug.Log("Replayed %s %s from %s", req.Method, fixture.Request.URL, path)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// FixtureName derives a stable, readable file name for a request, e.g.
+// "GET_api_access_calendar_events_3f2a9c1d.json"
+func FixtureName(method, url string, body []byte) string {
+	sum := sha256.Sum256(append([]byte(method+" "+url+"\n"), body...))
+
+	path, _, _ := strings.Cut(url, "?")
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(path, "_"), "_")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	return method + "_" + slug + "_" + hex.EncodeToString(sum[:4]) + ".json"
+}
+
+// sanitizedURL returns the request path and query with sensitive parameter
+// values redacted and parameters sorted
+func sanitizedURL(req *http.Request) string {
+	q := req.URL.Query()
+	if len(q) == 0 {
+		return req.URL.Path
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if sensitiveParam.MatchString(k) {
+			q[k] = []string{"REDACTED"}
+		}
+	}
+	return req.URL.Path + "?" + q.Encode()
+}
+
+// redactBody returns a JSON body with the values of sensitive fields, at any
+// depth, replaced. Other bodies are returned as they are.
+func redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || !redactValue(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactValue replaces sensitive fields in a decoded JSON value, reporting
+// whether there were any
+func redactValue(v interface{}) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if _, ok := field.(string); ok && sensitiveField.MatchString(k) {
+				v[k] = "REDACTED"
+				redacted = true
+			} else if redactValue(field) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactValue(item) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// writeFixture saves a fixture readable only by the user: even redacted,
+// recordings hold the account's mail and calendar data
+func writeFixture(path string, f Fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"data":[{"id":1,"name":"Work","isPrimary":true}]}`))
+	}))
+
+	t.Setenv("PE_RECORD", dir)
+	recorded, err := NewClient("secret-key").WithBaseURL(srv.URL).GetCalendars()
+	srv.Close()
+	if err != nil {
+		t.Fatalf("recording GetCalendars failed: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 fixture, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, secret := range []string{"secret-key", "session=abc"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("fixture contains %q", secret)
+		}
+	}
+
+	// The server is gone, so this only succeeds if served from the fixture
+	t.Setenv("PE_RECORD", "")
+	t.Setenv("PE_REPLAY", dir)
+	replayed, err := NewClient("other-key").WithBaseURL(srv.URL).GetCalendars()
+	if err != nil {
+		t.Fatalf("replaying GetCalendars failed: %v", err)
+	}
+	if len(replayed.Data) != 1 || replayed.Data[0].Name != recorded.Data[0].Name {
+		t.Errorf("replayed %+v, recorded %+v", replayed.Data, recorded.Data)
+	}
+
+	if _, err := NewClient("k").WithBaseURL(srv.URL).GetEmails(EmailParams{}); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("expected missing-fixture error, got %v", err)
+	}
+}
+
+func TestRecorderRedactsTokenRefresh(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/auth/token/refresh" {
+			_, _ = w.Write([]byte(`{"accessToken":"at-secret","expiresIn":900,"refreshToken":"rt-rotated"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer at-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":"UNAUTHENTICATED"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":1,"name":"Work"}],"nextPageToken":"page-2"}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("PE_RECORD", dir)
+	if _, err := NewClient("").WithBaseURL(srv.URL).WithRefreshToken("rt-initial", nil).GetCalendars(); err != nil {
+		t.Fatalf("recording GetCalendars failed: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 fixtures, got %d", len(files))
+	}
+	var all string
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s has mode %o, want 600", filepath.Base(f), perm)
+		}
+		data, _ := os.ReadFile(f)
+		all += string(data)
+	}
+	for _, secret := range []string{"rt-initial", "rt-rotated", "at-secret"} {
+		if strings.Contains(all, secret) {
+			t.Errorf("fixtures contain %q", secret)
+		}
+	}
+	if !strings.Contains(all, "page-2") {
+		t.Error("paging token was redacted")
+	}
+
+	// A different refresh token still replays the redacted exchange
+	t.Setenv("PE_RECORD", "")
+	t.Setenv("PE_REPLAY", dir)
+	if _, err := NewClient("").WithBaseURL(srv.URL).WithRefreshToken("rt-other", nil).GetCalendars(); err != nil {
+		t.Errorf("replaying GetCalendars failed: %v", err)
+	}
+}
@@ -3,12 +3,15 @@ package api
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
+	"github.com/porteden/cli/internal/apierr"
 	"github.com/porteden/cli/internal/debug"
 )
 
@@ -18,6 +21,37 @@ const (
 	maxBackoff     = 30 * time.Second
 )
 
+// statusError is a retryable HTTP status that was still failing when retries
+// ran out
+type statusError int
+
+func (e statusError) Error() string { return fmt.Sprintf("HTTP %d", int(e)) }
+
+// IsTransient reports whether err is a network failure or server error that
+// may succeed later, rather than a request the API rejected
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrNoFixture) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *apierr.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	var status statusError
+	if errors.As(err, &status) {
+		return status >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// IsSyncTokenExpired reports whether the API rejected a sync token as too
+// old or unknown. The caller should drop the token and fetch everything again.
+func IsSyncTokenExpired(err error) bool {
+	var apiErr *apierr.APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == 410 || apiErr.Code == "SYNC_TOKEN_EXPIRED")
+}
+
 // isRetryable checks if the response status code is retryable
 func isRetryable(statusCode int) bool {
 	switch statusCode {
@@ -48,21 +82,47 @@ func getRetryAfter(resp *http.Response) time.Duration {
 	return 0
 }
 
+// WaitFunc blocks for d before a retry, returning early with ctx.Err() if the
+// context is cancelled. statusCode is 0 when retrying after a network error.
+type WaitFunc func(ctx context.Context, d time.Duration, statusCode int) error
+
+// sleep is the default WaitFunc
+func sleep(ctx context.Context, d time.Duration, _ int) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
 // doWithRetry executes a request with automatic retries for transient errors
 // IMPORTANT: Accept []byte instead of io.Reader - io.Reader is consumed on first attempt
 // and subsequent retries would send empty bodies!
 func (c *Client) doWithRetry(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
 	var lastErr error
+	var lastStatus int
+	var waited time.Duration
 	backoff := initialBackoff
 
+	wait := c.wait
+	if wait == nil {
+		wait = sleep
+	}
+
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			debug.Log("Retry attempt %d/%d after %v", attempt, maxRetries, backoff)
 
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
+			if err := wait(ctx, backoff, lastStatus); err != nil {
+				return nil, err
+			}
+			waited += backoff
+		}
+
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
 			}
 		}
 
@@ -72,7 +132,7 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body []by
 			bodyReader = bytes.NewReader(body)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+c.delegatePath(path), bodyReader)
 		if err != nil {
 			return nil, err
 		}
@@ -83,27 +143,59 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body []by
 		// Note: Transport handles Authorization and logging via RoundTrip
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			// A missing fixture won't appear on retry
+			if errors.Is(err, ErrNoFixture) {
+				return nil, err
+			}
+			// Neither will a rejected refresh token
+			var apiErr *apierr.APIError
+			if errors.As(err, &apiErr) {
+				return nil, apiErr
+			}
 			// Network errors are retryable
 			lastErr = err
+			lastStatus = 0
 			backoff = min(backoff*2, maxBackoff)
 			continue
 		}
 
 		// Success or non-retryable error
 		if !isRetryable(resp.StatusCode) {
+			if c.limiter != nil && resp.StatusCode < 400 {
+				c.limiter.Success()
+			}
 			return resp, nil
 		}
 
-		// Retryable error - close body and prepare for retry
-		resp.Body.Close()
-		lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
-
-		// Respect Retry-After header if present
-		if retryAfter := getRetryAfter(resp); retryAfter > 0 {
+		// Respect Retry-After header if present. With a max wait configured the
+		// server's delay is honored in full, as long as it fits the budget.
+		retryAfter := getRetryAfter(resp)
+		switch {
+		case retryAfter > 0 && c.maxWait > 0:
+			backoff = retryAfter
+		case retryAfter > 0:
 			backoff = min(retryAfter, maxBackoff)
-		} else {
+		default:
 			backoff = min(backoff*2, maxBackoff)
 		}
+
+		// Hold every throttled request, not just this one, until the server
+		// is ready again
+		if c.limiter != nil && resp.StatusCode == http.StatusTooManyRequests {
+			c.limiter.Backoff(backoff)
+		}
+
+		// Hand the error response back rather than block past --max-wait, so the
+		// caller reports the real API error (e.g. RATE_LIMITED)
+		if c.maxWait > 0 && (attempt == maxRetries || waited+backoff > c.maxWait) {
+			debug.Log("Not retrying: waiting %v would exceed max wait of %v", backoff, c.maxWait)
+			return resp, nil
+		}
+
+		// Retryable error - close body and prepare for retry
+		drainAndClose(resp.Body)
+		lastErr = statusError(resp.StatusCode)
+		lastStatus = resp.StatusCode
 	}
 
 	return nil, fmt.Errorf("request failed after %d retries: %w", maxRetries, lastErr)
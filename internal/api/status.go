@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultStatusURL is the PortEden status page, overridable with PE_STATUS_URL
+const defaultStatusURL = "https://status.porteden.com"
+
+// StatusURL returns the address of the PortEden status page
+func StatusURL() string {
+	if u := os.Getenv("PE_STATUS_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return defaultStatusURL
+}
+
+// ServiceStatus is the status page summary: the overall state, each
+// component, and unresolved incidents and maintenance
+type ServiceStatus struct {
+	Page struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"page"`
+	Status struct {
+		// Indicator is none, minor, major, critical or maintenance
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Components            []ServiceComponent `json:"components"`
+	Incidents             []Incident         `json:"incidents"`
+	ScheduledMaintenances []Incident         `json:"scheduled_maintenances"`
+}
+
+// ServiceComponent is one part of the service, e.g. "Calendar API"
+type ServiceComponent struct {
+	Name string `json:"name"`
+	// Status is operational, degraded_performance, partial_outage,
+	// major_outage or under_maintenance
+	Status string `json:"status"`
+}
+
+// Incident is an ongoing incident or scheduled maintenance
+type Incident struct {
+	Name       string             `json:"name"`
+	Status     string             `json:"status"`
+	Impact     string             `json:"impact"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	ShortLink  string             `json:"shortlink,omitempty"`
+	Updates    []IncidentUpdate   `json:"incident_updates,omitempty"`
+	Components []ServiceComponent `json:"components,omitempty"`
+}
+
+// IncidentUpdate is a message posted on an incident, newest first
+type IncidentUpdate struct {
+	Status    string    `json:"status"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Operational reports whether nothing is wrong
+func (s *ServiceStatus) Operational() bool {
+	return (s.Status.Indicator == "" || s.Status.Indicator == "none") && len(s.Incidents) == 0
+}
+
+// GetServiceStatus fetches the status page summary. The status page is a
+// separate service, so the request carries no API key and isn't retried.
+func (c *Client) GetServiceStatus(ctx context.Context) (*ServiceStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", StatusURL()+"/api/v2/summary.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.httpClient
+	if t, ok := c.httpClient.Transport.(*Transport); ok && (t.APIKey() != "" || t.Tokens != nil) {
+		anon := &Transport{Base: t.Base, UserAgentSuffix: t.UserAgentSuffix}
+		httpClient = &http.Client{Transport: anon, Timeout: c.httpClient.Timeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not reach the status page: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the status page answered HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var status ServiceStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse the status page: %w", err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/porteden/cli/internal/apierr"
+	"github.com/porteden/cli/internal/debug"
+)
+
+// tokenExpiryMargin renews access tokens this long before they expire, so a
+// token doesn't run out while a request is in flight
+const tokenExpiryMargin = 30 * time.Second
+
+// TokenSource mints short-lived access tokens from a refresh token, reusing
+// each until shortly before it expires. A leaked access token is only good
+// for minutes; the refresh token never leaves the token endpoint.
+type TokenSource struct {
+	baseURL string
+	// onRotate saves a new refresh token when the server rotates it
+	onRotate func(refreshToken string)
+
+	mu           sync.Mutex
+	refreshToken string
+	accessToken  string
+	expiry       time.Time
+}
+
+// NewTokenSource returns a token source minting access tokens from the API at
+// baseURL. onRotate, if set, is called with each replacement refresh token.
+func NewTokenSource(baseURL, refreshToken string, onRotate func(string)) *TokenSource {
+	return &TokenSource{baseURL: baseURL, refreshToken: refreshToken, onRotate: onRotate}
+}
+
+// tokenResponse is the response of the token refresh endpoint
+type tokenResponse struct {
+	AccessToken  string `json:"accessToken"`
+	ExpiresIn    int    `json:"expiresIn"`              // Seconds
+	RefreshToken string `json:"refreshToken,omitempty"` // Set when the refresh token was rotated
+}
+
+// Token returns a valid access token, minting a new one through rt when the
+// cached one is missing or about to expire. A rejected refresh token is
+// returned as an *apierr.APIError.
+func (ts *TokenSource) Token(ctx context.Context, rt http.RoundTripper) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.accessToken != "" && time.Until(ts.expiry) > tokenExpiryMargin {
+		return ts.accessToken, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"refreshToken": ts.refreshToken})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", ts.baseURL+"/api/auth/token/refresh", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Version", APIVersion)
+
+	debug.Log("Refreshing access token")
+	resp, err := (&http.Client{Transport: rt, Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", apierr.ParseAPIError(resp)
+	}
+
+	var tokens tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil || tokens.AccessToken == "" {
+		return "", fmt.Errorf("failed to refresh access token: invalid response")
+	}
+	ts.accessToken = tokens.AccessToken
+	ts.expiry = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	if tokens.RefreshToken != "" && tokens.RefreshToken != ts.refreshToken {
+		ts.refreshToken = tokens.RefreshToken
+		if ts.onRotate != nil {
+			ts.onRotate(tokens.RefreshToken)
+		}
+	}
+	return ts.accessToken, nil
+}
+
+// Invalidate drops the cached access token, e.g. after the API rejected it
+func (ts *TokenSource) Invalidate() {
+	ts.mu.Lock()
+	ts.accessToken = ""
+	ts.mu.Unlock()
+}
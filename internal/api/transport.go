@@ -4,8 +4,12 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/porteden/cli/internal/config"
@@ -14,25 +18,89 @@ import (
 
 // Transport implements http.RoundTripper with automatic auth and logging
 type Transport struct {
-	Base   http.RoundTripper
-	APIKey string
+	Base http.RoundTripper
+	// Tokens, when set, supplies short-lived access tokens in place of APIKey
+	Tokens *TokenSource
+	// UserAgentSuffix is appended to the User-Agent, e.g. to tell automations
+	// sharing a key apart in server logs
+	UserAgentSuffix string
+
+	mu     sync.RWMutex // guards apiKey, which may be replaced mid-run
+	apiKey string
+}
+
+var (
+	sharedOnce      sync.Once
+	sharedTransport *http.Transport
+)
+
+// SharedTransport returns the network transport behind every client, so
+// pages of a long fetch and concurrent requests reuse warm connections
+// instead of paying for a TCP and TLS handshake each time. HTTP/2 is
+// negotiated when the server offers it, multiplexing requests over one
+// connection; idle HTTP/1.1 connections are kept for up to 32 requests in
+// parallel (the highest --concurrency is 8, plus pagination and retries).
+func SharedTransport() *http.Transport {
+	sharedOnce.Do(func() {
+		dialer := &net.Dialer{Timeout: 15 * time.Second, KeepAlive: 30 * time.Second}
+		sharedTransport = &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          64,
+			MaxIdleConnsPerHost:   32,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		}
+	})
+	return sharedTransport
 }
 
 func NewTransport(apiKey string) *Transport {
 	return &Transport{
-		Base:   http.DefaultTransport,
-		APIKey: apiKey,
+		Base:   newRecorderFromEnv(newCompressor(SharedTransport())),
+		apiKey: apiKey,
 	}
 }
 
+// APIKey returns the key requests are sent with
+func (t *Transport) APIKey() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.apiKey
+}
+
+// SetAPIKey replaces the key, e.g. after signing in again. Requests already
+// on their way keep the old one.
+func (t *Transport) SetAPIKey(key string) {
+	t.mu.Lock()
+	t.apiKey = key
+	t.mu.Unlock()
+}
+
 func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Add authorization header
-	req.Header.Set("Authorization", "Bearer "+t.APIKey)
+	if t.Tokens != nil {
+		token, err := t.Tokens.Token(req.Context(), t.Base)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if key := t.APIKey(); key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
 
 	// Add User-Agent header for version tracking
-	// Format: PortEden-CLI/{version} ({os}; {arch})
-	req.Header.Set("User-Agent", fmt.Sprintf("PortEden-CLI/%s (%s; %s)",
-		config.Version, runtime.GOOS, runtime.GOARCH))
+	// Format: PortEden-CLI/{version} ({os}; {arch}) {suffix}
+	userAgent := fmt.Sprintf("PortEden-CLI/%s (%s; %s)", config.Version, runtime.GOOS, runtime.GOARCH)
+	if t.UserAgentSuffix != "" {
+		userAgent += " " + t.UserAgentSuffix
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	// Ask for the API version this CLI was written against
+	req.Header.Set("X-API-Version", APIVersion)
 
 	// Add request ID for tracing
 	requestID := randomHex(4)
@@ -43,8 +111,15 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	// Log request in verbose mode
+	// Log request in verbose mode, with whether it got a warm connection
 	debug.LogRequest(req, requestID)
+	if debug.Verbose {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				debug.Log("[%s] Connection: reused=%t idle=%v", requestID, info.Reused, info.IdleTime)
+			},
+		}))
+	}
 	start := time.Now()
 
 	// Execute request
@@ -54,12 +129,40 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
+	// A rejected access token is minted anew on the next request
+	if t.Tokens != nil && resp.StatusCode == http.StatusUnauthorized {
+		t.Tokens.Invalidate()
+	}
+
+	// Echo the request ID on the response so errors can report it
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+	if resp.Header.Get("X-Request-ID") == "" {
+		resp.Header.Set("X-Request-ID", requestID)
+	}
+
 	// Log response in verbose mode
 	debug.LogResponse(resp, requestID, time.Since(start))
+	recordVersion(resp)
 
 	return resp, nil
 }
 
+// ValidUserAgentSuffix reports whether s can be sent in a User-Agent header:
+// printable ASCII only, at most 128 characters
+func ValidUserAgentSuffix(s string) bool {
+	if len(s) > 128 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 // NewHTTPClient creates an http.Client with the custom transport
 func NewHTTPClient(apiKey string) *http.Client {
 	return &http.Client{
@@ -68,6 +171,14 @@ func NewHTTPClient(apiKey string) *http.Client {
 	}
 }
 
+// drainAndClose reads what's left of a response body that won't be used,
+// up to a limit, so its connection goes back to the pool rather than being
+// torn down
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, 64<<10)
+	body.Close()
+}
+
 func randomHex(n int) string {
 	b := make([]byte, n)
 	_, _ = rand.Read(b)
@@ -4,24 +4,27 @@ import "time"
 
 // Meta contains response metadata
 type Meta struct {
-	Count       int       `json:"count,omitempty"`
-	Offset      int       `json:"offset,omitempty"`
-	HasMore     bool      `json:"hasMore,omitempty"`
-	TotalCount  int       `json:"totalCount,omitempty"`
-	Truncated   bool      `json:"truncated,omitempty"`
-	ExecutionMs int       `json:"execution_ms,omitempty"`
-	From        time.Time `json:"from,omitempty"`
-	To          time.Time `json:"to,omitempty"`
-	Timestamp   time.Time `json:"timestamp,omitempty"`
+	Count         int       `json:"count,omitempty"`
+	Offset        int       `json:"offset,omitempty"`
+	HasMore       bool      `json:"hasMore,omitempty"`
+	TotalCount    int       `json:"totalCount,omitempty"`
+	NextPageToken string    `json:"nextPageToken,omitempty"`
+	NextSyncToken string    `json:"nextSyncToken,omitempty"` // On the last page; pass as EventParams.SyncToken
+	Truncated     bool      `json:"truncated,omitempty"`
+	ExecutionMs   int       `json:"execution_ms,omitempty"`
+	From          time.Time `json:"from,omitempty"`
+	To            time.Time `json:"to,omitempty"`
+	Timestamp     time.Time `json:"timestamp,omitempty"`
 }
 
 // EventsResponse is the response type for calendar events
 type EventsResponse struct {
-	RequestID                string  `json:"request_id,omitempty"`
-	Events                   []Event `json:"events"`
-	Meta                     *Meta   `json:"meta,omitempty"`
-	AccessInfo               string  `json:"accessInfo,omitempty"`
-	CurrentUserCalendarEmail string  `json:"currentUserCalendarEmail,omitempty"`
+	RequestID                string   `json:"request_id,omitempty"`
+	Events                   []Event  `json:"events"`
+	DeletedIDs               []string `json:"deletedIds,omitempty"` // With a sync token: events deleted since
+	Meta                     *Meta    `json:"meta,omitempty"`
+	AccessInfo               string   `json:"accessInfo,omitempty"`
+	CurrentUserCalendarEmail string   `json:"currentUserCalendarEmail,omitempty"`
 }
 
 // SingleEventResponse is the response type for a single event GET
@@ -39,11 +42,12 @@ type CalendarsResponse struct {
 
 // AuthStatusResponse is the response for auth status endpoint
 type AuthStatusResponse struct {
-	Email        string    `json:"email"`
-	OperatorName string    `json:"operatorName"`
-	KeyID        int       `json:"keyId"`
-	KeyTitle     string    `json:"keyTitle,omitempty"`
-	CreatedAt    time.Time `json:"createdAt"`
+	Email        string     `json:"email"`
+	OperatorName string     `json:"operatorName"`
+	KeyID        int        `json:"keyId"`
+	KeyTitle     string     `json:"keyTitle,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"` // Unset for keys that don't expire
 }
 
 // Event represents a calendar event
@@ -67,6 +71,57 @@ type Event struct {
 	JoinUrl          string     `json:"joinUrl,omitempty"`
 	Labels           []string   `json:"labels,omitempty"`
 	IsRecurringEvent bool       `json:"isRecurringEvent,omitempty"`
+	SeriesID         string     `json:"seriesId,omitempty"`     // the recurring series this is an instance of
+	EventType        string     `json:"eventType,omitempty"`    // default or outOfOffice
+	Transparency     string     `json:"transparency,omitempty"` // opaque (busy) or transparent (free)
+	UpdatedUtc       time.Time  `json:"updatedUtc,omitempty"`   // last change, used to detect conflicts
+	// Reminders are the user's notifications before the event starts
+	Reminders *EventReminders `json:"reminders,omitempty"`
+
+	// Extended fields, which some providers only return when asked for with
+	// GetEvent's expand
+	Recurrence  []string          `json:"recurrence,omitempty"`
+	Attachments []EventAttachment `json:"attachments,omitempty"`
+}
+
+// Event expansions for GetEvent
+const (
+	ExpandAttendees   = "attendees"
+	ExpandAttachments = "attachments"
+	ExpandRecurrence  = "recurrence"
+)
+
+// Scopes for changing or deleting an instance of a recurring event
+const (
+	ScopeThisInstance     = "thisInstance"
+	ScopeThisAndFollowing = "thisAndFollowing"
+	ScopeEntireSeries     = "entireSeries"
+)
+
+// Reminder methods
+const (
+	ReminderPopup = "popup"
+	ReminderEmail = "email"
+)
+
+// Reminder is a notification sent some minutes before an event starts
+type Reminder struct {
+	Method  string `json:"method"` // popup or email
+	Minutes int    `json:"minutes"`
+}
+
+// EventReminders are either the calendar's default reminders or the event's
+// own; UseDefault false with no overrides means no reminders at all
+type EventReminders struct {
+	UseDefault bool       `json:"useDefault"`
+	Overrides  []Reminder `json:"overrides,omitempty"`
+}
+
+// EventAttachment is a file attached to an event
+type EventAttachment struct {
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	MimeType string `json:"mimeType,omitempty"`
 }
 
 // Attendee represents an event attendee
@@ -76,6 +131,8 @@ type Attendee struct {
 	DisplayName    string `json:"displayName,omitempty"` // Alias
 	Response       string `json:"response,omitempty"`
 	ResponseStatus string `json:"responseStatus,omitempty"` // Alias
+	// Comment is the note sent with the attendee's response
+	Comment string `json:"comment,omitempty"`
 }
 
 // Calendar represents a calendar
@@ -88,9 +145,31 @@ type Calendar struct {
 	IsPrimary       bool      `json:"isPrimary"`
 	IsOperatorOwner bool      `json:"isOperatorOwner,omitempty"`
 	OwnerEmail      string    `json:"ownerEmail,omitempty"`
+	ConnectionID    int64     `json:"connectionId,omitempty"`
 	LastSyncedAt    time.Time `json:"lastSyncedAt,omitempty"`
 }
 
+// CalendarShare is one entry of a calendar's access list
+type CalendarShare struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`           // owner, writer, reader or freeBusyReader
+	Type  string `json:"type,omitempty"` // user, group or domain
+}
+
+// CalendarSharesResponse lists who a calendar is shared with
+type CalendarSharesResponse struct {
+	CalendarID int64           `json:"calendarId"`
+	Shares     []CalendarShare `json:"shares"`
+	AccessInfo string          `json:"accessInfo,omitempty"`
+}
+
+// ShareCalendarRequest grants a user access to a calendar
+type ShareCalendarRequest struct {
+	Email            string `json:"email"`
+	Role             string `json:"role"`
+	SendNotification *bool  `json:"sendNotification,omitempty"`
+}
+
 // EventParams holds parameters for event queries
 type EventParams struct {
 	From             time.Time
@@ -98,9 +177,18 @@ type EventParams struct {
 	CalendarID       int64
 	Limit            int
 	Offset           int
+	PageToken        string // from Meta.NextPageToken; takes precedence over Offset
 	Query            string // keyword search (q parameter)
 	Attendees        string // comma-separated attendee emails
 	IncludeCancelled bool
+	ConnectionID     int64  // only events from this connected account
+	SyncToken        string // from Meta.NextSyncToken; only events changed since are returned
+}
+
+// RespondEventRequest represents a response to an event invitation
+type RespondEventRequest struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment,omitempty"`
 }
 
 // CreateEventRequest represents a request to create an event
@@ -114,6 +202,15 @@ type CreateEventRequest struct {
 	IsAllDay    bool      `json:"isAllDay,omitempty"`
 	Attendees   []string  `json:"attendees,omitempty"`
 	Recurrence  []string  `json:"recurrence,omitempty"`
+	// EventType "outOfOffice" marks the event as time away
+	EventType    string `json:"eventType,omitempty"`
+	Transparency string `json:"transparency,omitempty"`
+	// DeclineNewInvitations declines invitations that overlap an out-of-office event
+	DeclineNewInvitations bool `json:"declineNewInvitations,omitempty"`
+	// ConnectionID picks the connected account to create the event with
+	ConnectionID *int64 `json:"connectionId,omitempty"`
+	// Reminders replace the calendar's default reminders when set
+	Reminders *EventReminders `json:"reminders,omitempty"`
 }
 
 // UpdateEventRequest represents a request to update an event (PATCH)
@@ -127,14 +224,20 @@ type UpdateEventRequest struct {
 	AddAttendees      []string   `json:"addAttendees,omitempty"`
 	RemoveAttendees   []string   `json:"removeAttendees,omitempty"`
 	SendNotifications *bool      `json:"sendNotifications,omitempty"`
+	// Reminders replace the event's reminders when set
+	Reminders *EventReminders `json:"reminders,omitempty"`
+	// Scope applies the change to one instance of a recurring event (the
+	// default), to it and the instances after it, or to the entire series
+	Scope string `json:"scope,omitempty"`
 }
 
 // EventsByContactParams holds parameters for events by-contact queries
 type EventsByContactParams struct {
-	Email  string // Partial email matching (case-insensitive)
-	Name   string // Partial name/display name matching (case-insensitive)
-	Limit  int
-	Offset int
+	Email     string // Partial email matching (case-insensitive)
+	Name      string // Partial name/display name matching (case-insensitive)
+	Limit     int
+	Offset    int
+	PageToken string // from Meta.NextPageToken; takes precedence over Offset
 }
 
 // FreeBusyResponse is the response type for free/busy queries
@@ -155,6 +258,7 @@ type BusyPeriod struct {
 	StartUtc        time.Time `json:"startUtc"`
 	EndUtc          time.Time `json:"endUtc"`
 	DurationMinutes int       `json:"durationMinutes"`
+	Holiday         string    `json:"holiday,omitempty"` // set by --skip-holidays
 }
 
 // FreeBusyParams holds parameters for free/busy queries
@@ -174,11 +278,13 @@ type DeleteEventResponse struct {
 
 // EmailsResponse is the response type for email list/search operations
 type EmailsResponse struct {
-	Emails        []Email `json:"emails"`
-	TotalCount    int     `json:"totalCount,omitempty"`
-	HasMore       bool    `json:"hasMore,omitempty"`
-	NextPageToken string  `json:"nextPageToken,omitempty"`
-	AccessInfo    string  `json:"accessInfo,omitempty"`
+	Emails        []Email  `json:"emails"`
+	TotalCount    int      `json:"totalCount,omitempty"`
+	HasMore       bool     `json:"hasMore,omitempty"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+	NextSyncToken string   `json:"nextSyncToken,omitempty"` // On the last page; pass as EmailParams.SyncToken
+	DeletedIDs    []string `json:"deletedIds,omitempty"`    // With a sync token: emails deleted since
+	AccessInfo    string   `json:"accessInfo,omitempty"`
 }
 
 // SingleEmailResponse wraps a single email with access info
@@ -191,6 +297,7 @@ type SingleEmailResponse struct {
 type Email struct {
 	ID             string        `json:"id"`
 	ThreadID       string        `json:"threadId,omitempty"`
+	InReplyTo      string        `json:"inReplyTo,omitempty"` // ID of the message this one replies to
 	Subject        string        `json:"subject,omitempty"`
 	From           *Participant  `json:"from,omitempty"`
 	To             []Participant `json:"to,omitempty"`
@@ -250,6 +357,8 @@ type EmailParams struct {
 	Limit         int
 	IncludeBody   bool
 	PageToken     string
+	Mailbox       string
+	SyncToken     string // from EmailsResponse.NextSyncToken; only emails changed since are returned
 }
 
 // SendEmailRequest represents a request to send a new email
@@ -260,23 +369,35 @@ type SendEmailRequest struct {
 	Subject      string        `json:"subject"`
 	Body         string        `json:"body"`
 	BodyType     string        `json:"bodyType,omitempty"`
+	TextBody     string        `json:"textBody,omitempty"` // Plain text alternative to an HTML body
 	Importance   string        `json:"importance,omitempty"`
 	ConnectionID *int64        `json:"connectionId,omitempty"`
+	Mailbox      string        `json:"mailbox,omitempty"`
+
+	RequestReadReceipt     bool `json:"requestReadReceipt,omitempty"`
+	RequestDeliveryReceipt bool `json:"requestDeliveryReceipt,omitempty"`
 }
 
 // ReplyEmailRequest represents a request to reply to an email
 type ReplyEmailRequest struct {
-	Body     string `json:"body"`
-	BodyType string `json:"bodyType,omitempty"`
-	ReplyAll bool   `json:"replyAll,omitempty"`
+	Body     string        `json:"body"`
+	BodyType string        `json:"bodyType,omitempty"`
+	TextBody string        `json:"textBody,omitempty"` // Plain text alternative to an HTML body
+	ReplyAll bool          `json:"replyAll,omitempty"`
+	CC       []Participant `json:"cc,omitempty"`
+	BCC      []Participant `json:"bcc,omitempty"`
+	Mailbox  string        `json:"mailbox,omitempty"`
 }
 
 // ForwardEmailRequest represents a request to forward an email
 type ForwardEmailRequest struct {
 	To       []Participant `json:"to"`
 	CC       []Participant `json:"cc,omitempty"`
+	BCC      []Participant `json:"bcc,omitempty"`
 	Body     string        `json:"body,omitempty"`
 	BodyType string        `json:"bodyType,omitempty"`
+	TextBody string        `json:"textBody,omitempty"` // Plain text alternative to an HTML body
+	Mailbox  string        `json:"mailbox,omitempty"`
 }
 
 // ModifyEmailRequest represents a request to modify email properties
@@ -294,6 +415,15 @@ type EmailActionResponse struct {
 	ErrorMessage string `json:"errorMessage,omitempty"`
 }
 
+// AutoReplySettings is the mailbox's automatic reply (vacation responder)
+type AutoReplySettings struct {
+	Enabled bool       `json:"enabled"`
+	Subject string     `json:"subject,omitempty"`
+	Message string     `json:"message,omitempty"`
+	Start   *time.Time `json:"start,omitempty"`
+	End     *time.Time `json:"end,omitempty"`
+}
+
 // ==================== DRIVE TYPES ====================
 
 // DriveUser represents a file owner or collaborator
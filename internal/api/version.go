@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIVersion is the API version this CLI is written against, sent with every
+// request as X-API-Version
+const APIVersion = "2026-01"
+
+// VersionInfo is what the API said about the version it served a request with
+type VersionInfo struct {
+	Requested  string     `json:"requested"`
+	Negotiated string     `json:"negotiated,omitempty"`
+	Supported  []string   `json:"supported,omitempty"`
+	Deprecated bool       `json:"deprecated"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+}
+
+var (
+	versionMu   sync.Mutex
+	lastVersion *VersionInfo
+)
+
+// LastVersionInfo returns the version details of the latest API response, or
+// nil when no response carried any
+func LastVersionInfo() *VersionInfo {
+	versionMu.Lock()
+	defer versionMu.Unlock()
+	if lastVersion == nil {
+		return nil
+	}
+	info := *lastVersion
+	return &info
+}
+
+// recordVersion reads the version headers of an API response:
+//
+//	X-API-Version: the version the request was served with
+//	X-API-Supported-Versions: comma-separated versions the API accepts
+//	Deprecation: set when the served version is deprecated (RFC 9745)
+//	Sunset: when the served version stops working (RFC 8594)
+func recordVersion(resp *http.Response) {
+	h := resp.Header
+	negotiated := h.Get("X-API-Version")
+	supported := h.Get("X-API-Supported-Versions")
+	deprecation := h.Get("Deprecation")
+	if negotiated == "" && supported == "" && deprecation == "" {
+		return
+	}
+
+	info := &VersionInfo{
+		Requested:  APIVersion,
+		Negotiated: negotiated,
+		Deprecated: deprecation != "" && !strings.EqualFold(deprecation, "false"),
+	}
+	for _, v := range strings.Split(supported, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			info.Supported = append(info.Supported, v)
+		}
+	}
+	// A version missing from the supported list is on its way out even
+	// without a Deprecation header
+	if len(info.Supported) > 0 && !slices.Contains(info.Supported, APIVersion) {
+		info.Deprecated = true
+	}
+	if sunset, err := http.ParseTime(h.Get("Sunset")); err == nil {
+		info.Sunset = &sunset
+	}
+
+	versionMu.Lock()
+	lastVersion = info
+	versionMu.Unlock()
+}
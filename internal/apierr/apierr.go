@@ -10,10 +10,11 @@ import (
 // APIError represents an error response from the API
 type APIError struct {
 	StatusCode   int    `json:"-"`
-	Code         string `json:"code,omitempty"`         // Backend error code (ACCESS_DENIED, NOT_FOUND, etc.)
-	ErrorMessage string `json:"error,omitempty"`        // Legacy error field
-	Message      string `json:"message,omitempty"`      // Detailed error message
+	Code         string `json:"code,omitempty"`    // Backend error code (ACCESS_DENIED, NOT_FOUND, etc.)
+	ErrorMessage string `json:"error,omitempty"`   // Legacy error field
+	Message      string `json:"message,omitempty"` // Detailed error message
 	Details      string `json:"details,omitempty"`
+	RequestID    string `json:"requestId,omitempty"`
 }
 
 func (e *APIError) Error() string {
@@ -23,10 +24,45 @@ func (e *APIError) Error() string {
 	return e.ErrorMessage
 }
 
-// ParseAPIError extracts error details from an HTTP response.
+// ErrorCode returns the backend error code, deriving one from the HTTP status when the
+// response body didn't include it
+func (e *APIError) ErrorCode() string {
+	if e.Code != "" {
+		return e.Code
+	}
+	switch {
+	case e.StatusCode == 400 || e.StatusCode == 422:
+		return "VALIDATION"
+	case e.StatusCode == 401:
+		return "UNAUTHENTICATED"
+	case e.StatusCode == 403:
+		return "ACCESS_DENIED"
+	case e.StatusCode == 404:
+		return "NOT_FOUND"
+	case e.StatusCode == 409:
+		return "CONFLICT"
+	case e.StatusCode == 429:
+		return "RATE_LIMITED"
+	case e.StatusCode >= 500:
+		return "SERVER_ERROR"
+	default:
+		return "API_ERROR"
+	}
+}
+
+// ParseAPIError extracts error details from an HTTP response. The request ID
+// falls back to the X-Request-ID response header when the body has none.
 // NOTE: This function does NOT close resp.Body - caller is responsible for closing.
 // This allows the caller to use defer resp.Body.Close() consistently.
 func ParseAPIError(resp *http.Response) *APIError {
+	apiErr := parseBody(resp)
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	return apiErr
+}
+
+func parseBody(resp *http.Response) *APIError {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return &APIError{
@@ -58,8 +94,8 @@ func UserFriendlyError(err *APIError) string {
 		return "Not found. The requested resource doesn't exist."
 	case 429:
 		return "Rate limited. Please wait a moment and try again."
-	case 500, 502, 503:
-		return "Server error. Please try again later."
+	case 500, 502, 503, 504:
+		return "Server error. Please try again later. If it keeps failing, run 'porteden status' to check for ongoing incidents."
 	default:
 		if err.Message != "" {
 			return err.Message
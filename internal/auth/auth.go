@@ -14,13 +14,14 @@ import (
 	"time"
 
 	"github.com/pkg/browser"
+	"github.com/porteden/cli/internal/api"
 )
 
 const (
 	baseURL = "https://cliv1b.porteden.com"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = &http.Client{Transport: api.SharedTransport(), Timeout: 30 * time.Second}
 
 type LoginResponse struct {
 	SessionToken string    `json:"sessionToken"`
@@ -28,99 +29,165 @@ type LoginResponse struct {
 	LoginURL     string    `json:"loginUrl"`
 	ExpiresAt    time.Time `json:"expiresAt"`
 	Message      string    `json:"message"`
+	// UserCode is shown on the login page, so the user can check it's their session
+	UserCode string `json:"userCode,omitempty"`
 }
 
 type PollResponse struct {
-	Status string  `json:"status"`
-	ApiKey *string `json:"apiKey,omitempty"`
-	Error  *string `json:"error,omitempty"`
+	Status       string  `json:"status"`
+	ApiKey       *string `json:"apiKey,omitempty"`
+	RefreshToken *string `json:"refreshToken,omitempty"`
+	Error        *string `json:"error,omitempty"`
 }
 
 // LoginProgress reports login progress to the caller.
 type LoginProgress struct {
+	// NoBrowser skips opening the browser; the user opens the URL themselves.
+	NoBrowser bool
 	// OnBrowserOpen is called when the browser is about to open, with the fallback URL.
 	OnBrowserOpen func(loginURL string)
+	// OnLoginURL is called instead of OnBrowserOpen with NoBrowser, with the
+	// URL to open and the code the login page shows, if any.
+	OnLoginURL func(loginURL, userCode string)
 	// OnWaiting is called when polling starts.
 	OnWaiting func()
 }
 
 // Login authenticates via browser and stores the API key for the given profile.
+// With ssoOrg set, sign-in goes through that organization's SSO provider.
 // If progress is nil, no progress messages are printed.
-func Login(profile, operatorID, keyTitle string, progress *LoginProgress) (string, error) {
+func Login(profile, operatorID, keyTitle, ssoOrg string, progress *LoginProgress) (string, error) {
 	if profile == "" {
 		profile = "default"
 	}
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer cancel()
-
-	// 1. Initiate login session
 	reqBody := map[string]interface{}{}
+	if ssoOrg != "" {
+		reqBody["ssoOrganization"] = ssoOrg
+	}
 	if operatorID != "" {
 		reqBody["operatorId"] = operatorID
 	}
 	if keyTitle != "" {
 		reqBody["keyTitle"] = keyTitle
 	}
+	result, err := login(reqBody, progress)
+	if err != nil {
+		return "", err
+	}
+	if result.ApiKey == nil {
+		return "", fmt.Errorf("no API key in response")
+	}
+	apiKey := *result.ApiKey
+
+	// Store API key securely
+	if err := StoreAPIKey(apiKey, profile); err != nil {
+		return "", fmt.Errorf("failed to store API key: %w", err)
+	}
 
+	return apiKey, nil
+}
+
+// LoginOAuth authenticates via browser and stores a refresh token for the
+// given profile instead of an API key. Requests then use short-lived access
+// tokens minted from it. ssoOrg is as for Login.
+func LoginOAuth(profile, ssoOrg string, progress *LoginProgress) (string, error) {
+	if profile == "" {
+		profile = "default"
+	}
+
+	reqBody := map[string]interface{}{"grantType": "refresh_token"}
+	if ssoOrg != "" {
+		reqBody["ssoOrganization"] = ssoOrg
+	}
+	result, err := login(reqBody, progress)
+	if err != nil {
+		return "", err
+	}
+	if result.RefreshToken == nil {
+		return "", fmt.Errorf("no refresh token in response")
+	}
+	refreshToken := *result.RefreshToken
+
+	if err := StoreRefreshToken(refreshToken, profile); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return refreshToken, nil
+}
+
+// login runs the browser login flow and returns the completed poll response
+func login(reqBody map[string]interface{}, progress *LoginProgress) (*PollResponse, error) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// 1. Initiate login session. Without a browser the server adds a code
+	// to confirm on the login page, since the URL may be opened elsewhere.
+	if progress != nil && progress.NoBrowser {
+		reqBody["noBrowser"] = true
+	}
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to encode request: %w", err)
+		return nil, fmt.Errorf("failed to encode request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/auth/token/login", bytes.NewReader(jsonBody))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("could not connect to PortEden. Please check your internet connection and try again")
+		return nil, fmt.Errorf("could not connect to PortEden. Please check your internet connection and try again")
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("could not read server response. Please try again")
+		return nil, fmt.Errorf("could not read server response. Please try again")
 	}
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return "", fmt.Errorf("too many login attempts. Please wait a minute and try again")
+		return nil, fmt.Errorf("too many login attempts. Please wait a minute and try again")
+	}
+	if org, ok := reqBody["ssoOrganization"].(string); ok {
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, fmt.Errorf("no organization %q with single sign-on. Check the name with your administrator", org)
+		case http.StatusBadRequest, http.StatusForbidden:
+			return nil, fmt.Errorf("single sign-on is not enabled for %q. Ask your administrator, or sign in without --sso", org)
+		}
 	}
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("could not start login session. Please try again later")
+		return nil, fmt.Errorf("could not start login session. Please try again later")
 	}
 
 	var loginResp LoginResponse
 	if err := json.Unmarshal(body, &loginResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// 2. Open browser
-	if progress != nil && progress.OnBrowserOpen != nil {
-		progress.OnBrowserOpen(loginResp.LoginURL)
+	// 2. Open browser, or leave it to the user
+	if progress != nil && progress.NoBrowser {
+		if progress.OnLoginURL != nil {
+			progress.OnLoginURL(loginResp.LoginURL, loginResp.UserCode)
+		}
+	} else {
+		if progress != nil && progress.OnBrowserOpen != nil {
+			progress.OnBrowserOpen(loginResp.LoginURL)
+		}
+		_ = browser.OpenURL(loginResp.LoginURL)
 	}
-	_ = browser.OpenURL(loginResp.LoginURL)
 
 	// 3. Poll for completion
 	if progress != nil && progress.OnWaiting != nil {
 		progress.OnWaiting()
 	}
-	apiKey, err := pollForCompletion(ctx, loginResp.SessionToken, loginResp.PollSecret, loginResp.ExpiresAt)
-	if err != nil {
-		return "", err
-	}
-
-	// 4. Store API key securely
-	if err := StoreAPIKey(apiKey, profile); err != nil {
-		return "", fmt.Errorf("failed to store API key: %w", err)
-	}
-
-	return apiKey, nil
+	return pollForCompletion(ctx, loginResp.SessionToken, loginResp.PollSecret, loginResp.ExpiresAt)
 }
 
-func pollForCompletion(ctx context.Context, sessionToken, pollSecret string, expiresAt time.Time) (string, error) {
+func pollForCompletion(ctx context.Context, sessionToken, pollSecret string, expiresAt time.Time) (*PollResponse, error) {
 	// Build poll URL with proper encoding
 	pollURL := fmt.Sprintf("%s/api/auth/token/poll/%s?secret=%s",
 		baseURL,
@@ -140,9 +207,9 @@ func pollForCompletion(ctx context.Context, sessionToken, pollSecret string, exp
 	defer initialDelay.Stop()
 	select {
 	case <-ctx.Done():
-		return "", fmt.Errorf("login cancelled by user")
+		return nil, fmt.Errorf("login cancelled by user")
 	case <-timer.C:
-		return "", fmt.Errorf("login timed out")
+		return nil, fmt.Errorf("login timed out")
 	case <-initialDelay.C:
 	}
 
@@ -152,9 +219,9 @@ func pollForCompletion(ctx context.Context, sessionToken, pollSecret string, exp
 	for {
 		select {
 		case <-ctx.Done():
-			return "", fmt.Errorf("login cancelled by user")
+			return nil, fmt.Errorf("login cancelled by user")
 		case <-timer.C:
-			return "", fmt.Errorf("login timed out")
+			return nil, fmt.Errorf("login timed out")
 		case <-ticker.C:
 			resp, err := httpClient.Get(pollURL)
 			if err != nil {
@@ -170,10 +237,10 @@ func pollForCompletion(ctx context.Context, sessionToken, pollSecret string, exp
 
 			if resp.StatusCode != http.StatusOK {
 				if resp.StatusCode == http.StatusNotFound {
-					return "", fmt.Errorf("login session expired. Please try again")
+					return nil, fmt.Errorf("login session expired. Please try again")
 				}
 				if resp.StatusCode == http.StatusTooManyRequests {
-					return "", fmt.Errorf("too many login attempts. Please wait a minute and try again")
+					return nil, fmt.Errorf("too many login attempts. Please wait a minute and try again")
 				}
 				if resp.StatusCode >= 500 {
 					continue // Retry server errors
@@ -191,20 +258,17 @@ func pollForCompletion(ctx context.Context, sessionToken, pollSecret string, exp
 
 			switch pollResp.Status {
 			case "completed":
-				if pollResp.ApiKey != nil {
-					return *pollResp.ApiKey, nil
-				}
-				return "", fmt.Errorf("no API key in response")
+				return &pollResp, nil
 			case "expired":
-				return "", fmt.Errorf("login session expired")
+				return nil, fmt.Errorf("login session expired")
 			case "failed":
 				msg := "authentication failed"
 				if pollResp.Error != nil {
 					msg = *pollResp.Error
 				}
-				return "", errors.New(msg)
+				return nil, errors.New(msg)
 			case "invalid_secret":
-				return "", fmt.Errorf("invalid poll secret - session may be compromised")
+				return nil, fmt.Errorf("invalid poll secret - session may be compromised")
 			}
 		}
 	}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package auth
+
+// lockFile is a no-op where file locks aren't available; writes are still
+// atomic, but parallel invocations may drop each other's changes
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
@@ -6,14 +6,34 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
+	"time"
 )
 
-const credentialsFile = "credentials.json"
+const (
+	credentialsFile = "credentials.json"
+	// credentialsLock is locked while credentials are changed, so parallel
+	// invocations (say a cron job and an interactive shell) take turns
+	credentialsLock = "credentials.lock"
+)
 
 // credentialStore is the on-disk JSON format.
 type credentialStore struct {
-	ActiveProfile string            `json:"active_profile"`
-	Profiles      map[string]string `json:"profiles"`
+	ActiveProfile string             `json:"active_profile"`
+	Profiles      map[string]string  `json:"profiles"`
+	Keys          map[string]KeyInfo `json:"keys,omitempty"`
+	// RefreshTokens hold the credentials of profiles signed in with --oauth
+	RefreshTokens map[string]string `json:"refresh_tokens,omitempty"`
+}
+
+// KeyInfo is what the API last reported about a profile's key, kept so the
+// CLI can warn before the key expires
+type KeyInfo struct {
+	KeyID     int        `json:"key_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CheckedAt time.Time  `json:"checked_at"`
+	WarnedAt  *time.Time `json:"warned_at,omitempty"`
 }
 
 var store *credentialStore
@@ -59,23 +79,64 @@ func loadStore() error {
 	return nil
 }
 
-func saveStore() error {
+// storeMu serializes updates within this process; the file lock only
+// coordinates with other processes
+var storeMu sync.Mutex
+
+// updateStore applies change to the credentials currently on disk and saves
+// them. Reloading under the lock means a profile stored by another invocation
+// since this one started is kept rather than overwritten.
+func updateStore(change func(s *credentialStore)) error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
 	dir, err := configDir()
 	if err != nil {
 		return err
 	}
-
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	unlock, err := lockFile(filepath.Join(dir, credentialsLock))
+	if err != nil {
+		return fmt.Errorf("failed to lock credentials file: %w", err)
+	}
+	defer unlock()
+
+	if err := loadStore(); err != nil {
+		return err
+	}
+	change(store)
+	return saveStore(dir)
+}
+
+// saveStore writes the credentials to a temporary file and renames it into
+// place, so readers never see a partly written file
+func saveStore(dir string) error {
 	data, err := json.MarshalIndent(store, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to encode credentials: %w", err)
 	}
 
-	path := filepath.Join(dir, credentialsFile)
-	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+	tmp, err := os.CreateTemp(dir, credentialsFile+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, credentialsFile)); err != nil {
 		return fmt.Errorf("failed to write credentials file: %w", err)
 	}
 	return nil
@@ -104,8 +165,47 @@ func StoreAPIKey(apiKey, profile string) error {
 	if profile == "" {
 		profile = "default"
 	}
-	store.Profiles[profile] = apiKey
-	return saveStore()
+	return updateStore(func(s *credentialStore) {
+		s.Profiles[profile] = apiKey
+		delete(s.Keys, profile)
+		delete(s.RefreshTokens, profile)
+	})
+}
+
+// StoreRefreshToken stores a refresh token for a profile, replacing any API
+// key it had. Also used to save rotated tokens.
+func StoreRefreshToken(refreshToken, profile string) error {
+	if err := ensureStore(); err != nil {
+		return err
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return updateStore(func(s *credentialStore) {
+		if s.RefreshTokens == nil {
+			s.RefreshTokens = make(map[string]string)
+		}
+		if _, hadKey := s.Profiles[profile]; hadKey {
+			delete(s.Profiles, profile)
+			delete(s.Keys, profile)
+		}
+		s.RefreshTokens[profile] = refreshToken
+	})
+}
+
+// GetRefreshToken retrieves the refresh token of a profile signed in with --oauth
+func GetRefreshToken(profile string) (string, error) {
+	if err := ensureStore(); err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = GetActiveProfile()
+	}
+	token, ok := store.RefreshTokens[profile]
+	if !ok || token == "" {
+		return "", fmt.Errorf("no refresh token found for profile %q", profile)
+	}
+	return token, nil
 }
 
 // GetAPIKey retrieves the API key for a profile, checking PE_API_KEY first.
@@ -131,7 +231,7 @@ func GetStoredAPIKey(profile string) (string, error) {
 	return key, nil
 }
 
-// DeleteAPIKey removes the API key for a profile.
+// DeleteAPIKey removes the API key or refresh token for a profile.
 func DeleteAPIKey(profile string) error {
 	if err := ensureStore(); err != nil {
 		return err
@@ -139,8 +239,33 @@ func DeleteAPIKey(profile string) error {
 	if profile == "" {
 		profile = "default"
 	}
-	delete(store.Profiles, profile)
-	return saveStore()
+	return updateStore(func(s *credentialStore) {
+		delete(s.Profiles, profile)
+		delete(s.Keys, profile)
+		delete(s.RefreshTokens, profile)
+	})
+}
+
+// GetKeyInfo returns the stored details of a profile's key, if any
+func GetKeyInfo(profile string) (KeyInfo, bool) {
+	if store == nil {
+		return KeyInfo{}, false
+	}
+	info, ok := store.Keys[profile]
+	return info, ok
+}
+
+// SetKeyInfo stores the details of a profile's key
+func SetKeyInfo(profile string, info KeyInfo) error {
+	if err := ensureStore(); err != nil {
+		return err
+	}
+	return updateStore(func(s *credentialStore) {
+		if s.Keys == nil {
+			s.Keys = make(map[string]KeyInfo)
+		}
+		s.Keys[profile] = info
+	})
 }
 
 // GetActiveProfile returns the currently active profile name.
@@ -156,8 +281,9 @@ func SetActiveProfile(profile string) error {
 	if err := ensureStore(); err != nil {
 		return err
 	}
-	store.ActiveProfile = profile
-	return saveStore()
+	return updateStore(func(s *credentialStore) {
+		s.ActiveProfile = profile
+	})
 }
 
 // ListProfiles returns all stored profile names and the active profile.
@@ -168,6 +294,11 @@ func ListProfiles() (profiles []string, activeProfile string, err error) {
 	for name := range store.Profiles {
 		profiles = append(profiles, name)
 	}
+	for name := range store.RefreshTokens {
+		if _, ok := store.Profiles[name]; !ok {
+			profiles = append(profiles, name)
+		}
+	}
 	sort.Strings(profiles)
 	return profiles, store.ActiveProfile, nil
 }
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+)
+
+// TestStoreHelper stores one profile when run as a child process by
+// TestStoreKeepsParallelUpdates
+func TestStoreHelper(t *testing.T) {
+	profile := os.Getenv("PORTEDEN_TEST_STORE_PROFILE")
+	if profile == "" {
+		t.Skip("only run as a child process")
+	}
+	if err := InitStore(); err != nil {
+		t.Fatal(err)
+	}
+	if err := StoreAPIKey("key-"+profile, profile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreKeepsParallelUpdates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(profile string) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestStoreHelper$")
+			cmd.Env = append(os.Environ(), "HOME="+home, "PORTEDEN_TEST_STORE_PROFILE="+profile)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s: %v\n%s", profile, err, out)
+			}
+		}(fmt.Sprintf("p%d", i))
+	}
+	wg.Wait()
+
+	if err := loadStore(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		profile := fmt.Sprintf("p%d", i)
+		if got := store.Profiles[profile]; got != "key-"+profile {
+			t.Errorf("profile %s has key %q, want it kept", profile, got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	events := []api.Event{
+		{
+			ID: "evt_1", Title: "Review; budget, Q3", Description: "Line one\nLine two",
+			StartUtc: start, EndUtc: start.Add(time.Hour), Status: "confirmed",
+			Organizer: "alex@example.com",
+			Attendees: []api.Attendee{{Email: "priya@example.com", Name: "Priya Shah", Response: "accepted"}},
+		},
+		{ID: "evt_2", Title: strings.Repeat("é", 60), StartUtc: start, EndUtc: start.AddDate(0, 0, 1), AllDay: true},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, "Work", events); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Work\r\n",
+		"UID:evt_1@porteden\r\n",
+		"DTSTART:20260302T150000Z\r\n",
+		`SUMMARY:Review\; budget\, Q3` + "\r\n",
+		`DESCRIPTION:Line one\nLine two` + "\r\n",
+		"STATUS:CONFIRMED\r\n",
+		`ATTENDEE;CN="Priya Shah";PARTSTAT=ACCEPTED:mailto:priya@example.com` + "\r\n",
+		"DTSTART;VALUE=DATE:20260302\r\n",
+		"DTEND;VALUE=DATE:20260303\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+strings.Repeat("é", 60)+"\r\n") {
+		t.Error("folding should not split UTF-8 sequences")
+	}
+}
+
+func TestWriteFreeBusyICS(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	busy := []api.BusyPeriod{
+		{StartUtc: at(14, 0), EndUtc: at(15, 0)},
+		{StartUtc: at(9, 0), EndUtc: at(10, 0)},
+		{StartUtc: at(9, 30), EndUtc: at(11, 0)},
+		{StartUtc: at(11, 0), EndUtc: at(11, 30)},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFreeBusyICS(&buf, "Alex", day, day.AddDate(0, 0, 1), busy); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"X-WR-CALNAME:Alex\r\n",
+		"BEGIN:VFREEBUSY\r\n",
+		"DTSTART:20260302T000000Z\r\nDTEND:20260303T000000Z\r\n",
+		"FREEBUSY;FBTYPE=BUSY:20260302T090000Z/20260302T113000Z\r\n",
+		"FREEBUSY;FBTYPE=BUSY:20260302T140000Z/20260302T150000Z\r\n",
+		"SUMMARY:Busy\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 2 {
+		t.Errorf("got %d busy events, want 2 after merging", n)
+	}
+}
+
+func TestWriteMbox(t *testing.T) {
+	emails := []api.Email{{
+		ID:       "msg_1",
+		Subject:  "Café plans",
+		From:     &api.Participant{Email: "sam@example.com", Name: "Sam Okafor"},
+		To:       []api.Participant{{Email: "alex@example.com"}},
+		Body:     "Hi,\nFrom now on we meet at noon.\n>From the team",
+		SentAt:   time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+		IsRead:   true,
+		BodyType: "text",
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteMbox(&buf, emails); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"From sam@example.com Mon Mar  2 09:30:00 2026\n",
+		"Message-ID: <msg_1@porteden>\n",
+		`From: "Sam Okafor" <sam@example.com>` + "\n",
+		"Subject: =?utf-8?q?Caf=C3=A9_plans?=\n",
+		"\n>From now on we meet at noon.\n",
+		"\n>>From the team",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\r") {
+		t.Error("mbox output should use LF line endings")
+	}
+}
+
+func TestWriterAndOpen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backup")
+	w, err := Create(dir, NewManifest(time.Time{}, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := File{Path: "calendar/1001.json", Kind: KindCalendar, Format: FormatJSON, Count: 1, CalendarID: 1001}
+	if err := w.AddJSON(f, []api.Event{{ID: "evt_1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Create(dir, NewManifest(time.Time{}, time.Now())); err == nil {
+		t.Error("Create should refuse a directory that already holds an export")
+	}
+
+	m, base, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 1 || m.Files[0].Size == 0 || len(m.Files[0].SHA256) != 64 {
+		t.Fatalf("unexpected manifest files: %+v", m.Files)
+	}
+	if _, err := Open(base, m.Files[0]); err != nil {
+		t.Errorf("Open: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "calendar", "1001.json"), []byte("[]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(base, m.Files[0]); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Open of an edited file: got %v, want ErrChecksum", err)
+	}
+}
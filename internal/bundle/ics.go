@@ -0,0 +1,182 @@
+package bundle
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/freetime"
+)
+
+const (
+	icsDateTime = "20060102T150405Z"
+	icsDate     = "20060102"
+)
+
+// WriteICS writes events as an iCalendar (RFC 5545) file named after the
+// calendar, so it can be imported into other calendar apps
+func WriteICS(w io.Writer, calendarName string, events []api.Event) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//PortEden//porteden CLI//EN")
+	line("CALSCALE", "GREGORIAN")
+	if calendarName != "" {
+		line("X-WR-CALNAME", escapeText(calendarName))
+	}
+	stamp := time.Now().UTC().Format(icsDateTime)
+	for _, e := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.ID+"@porteden")
+		line("DTSTAMP", stamp)
+		if e.AllDay || e.IsAllDay {
+			line("DTSTART;VALUE=DATE", e.StartUtc.UTC().Format(icsDate))
+			line("DTEND;VALUE=DATE", e.EndUtc.UTC().Format(icsDate))
+		} else {
+			line("DTSTART", e.StartUtc.UTC().Format(icsDateTime))
+			line("DTEND", e.EndUtc.UTC().Format(icsDateTime))
+		}
+		title := e.Title
+		if title == "" {
+			title = e.Summary
+		}
+		line("SUMMARY", escapeText(title))
+		if e.Description != "" {
+			line("DESCRIPTION", escapeText(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escapeText(e.Location))
+		}
+		if e.JoinUrl != "" {
+			line("URL", e.JoinUrl)
+		}
+		if status := strings.ToUpper(e.Status); status == "CONFIRMED" || status == "TENTATIVE" || status == "CANCELLED" {
+			line("STATUS", status)
+		}
+		if e.Transparency == "transparent" {
+			line("TRANSP", "TRANSPARENT")
+		}
+		if e.Organizer != "" {
+			line("ORGANIZER", "mailto:"+e.Organizer)
+		}
+		for _, a := range e.Attendees {
+			params := ""
+			if name := attendeeName(a); name != "" {
+				params += ";CN=" + quoteParam(name)
+			}
+			if status := partStat(a); status != "" {
+				params += ";PARTSTAT=" + status
+			}
+			line("ATTENDEE"+params, "mailto:"+a.Email)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// WriteFreeBusyICS writes busy periods between from and to as an iCalendar
+// file without any event details. Overlapping periods are merged. Besides
+// the VFREEBUSY component, each period is also written as an opaque "Busy"
+// event, since most calendar apps only show events from a subscription.
+func WriteFreeBusyICS(w io.Writer, calendarName string, from, to time.Time, busy []api.BusyPeriod) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	periods := freetime.Merge(busy)
+	stamp := time.Now().UTC().Format(icsDateTime)
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//PortEden//porteden CLI//EN")
+	line("CALSCALE", "GREGORIAN")
+	if calendarName != "" {
+		line("X-WR-CALNAME", escapeText(calendarName))
+	}
+	line("BEGIN", "VFREEBUSY")
+	line("UID", "freebusy@porteden")
+	line("DTSTAMP", stamp)
+	line("DTSTART", from.UTC().Format(icsDateTime))
+	line("DTEND", to.UTC().Format(icsDateTime))
+	for _, p := range periods {
+		line("FREEBUSY;FBTYPE=BUSY", p.StartUtc.UTC().Format(icsDateTime)+"/"+p.EndUtc.UTC().Format(icsDateTime))
+	}
+	line("END", "VFREEBUSY")
+	for _, p := range periods {
+		line("BEGIN", "VEVENT")
+		line("UID", "busy-"+p.StartUtc.UTC().Format(icsDateTime)+"@porteden")
+		line("DTSTAMP", stamp)
+		line("DTSTART", p.StartUtc.UTC().Format(icsDateTime))
+		line("DTEND", p.EndUtc.UTC().Format(icsDateTime))
+		line("SUMMARY", "Busy")
+		line("CLASS", "PRIVATE")
+		line("TRANSP", "OPAQUE")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func attendeeName(a api.Attendee) string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.DisplayName
+}
+
+// partStat maps an attendee's response to its iCalendar participation status
+func partStat(a api.Attendee) string {
+	response := a.Response
+	if response == "" {
+		response = a.ResponseStatus
+	}
+	switch response {
+	case "accepted":
+		return "ACCEPTED"
+	case "declined":
+		return "DECLINED"
+	case "tentative":
+		return "TENTATIVE"
+	case "needsAction":
+		return "NEEDS-ACTION"
+	}
+	return ""
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// quoteParam quotes a parameter value, which may not contain double quotes
+func quoteParam(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
+
+// writeFolded writes a content line, folding it into lines of at most 75
+// octets without splitting a UTF-8 sequence
+func writeFolded(w *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,170 @@
+// Package bundle reads and writes export bundles: a directory of JSON, ICS
+// and mbox files described by a manifest, used for backups and restores.
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// ManifestFile is the manifest's name inside a bundle directory
+	ManifestFile = "manifest.json"
+
+	manifestVersion = 1
+)
+
+// File kinds and formats listed in a manifest
+const (
+	KindCalendar = "calendar"
+	KindEmail    = "email"
+
+	FormatJSON = "json"
+	FormatICS  = "ics"
+	FormatMbox = "mbox"
+)
+
+// Manifest describes the contents of a bundle
+type Manifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Profile    string    `json:"profile,omitempty"`
+	CLIVersion string    `json:"cliVersion,omitempty"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	Files      []File    `json:"files"`
+}
+
+// File is one data file of a bundle. Path is relative to the bundle
+// directory.
+type File struct {
+	Path         string `json:"path"`
+	Kind         string `json:"kind"`
+	Format       string `json:"format"`
+	Count        int    `json:"count"`
+	Size         int64  `json:"size"`
+	SHA256       string `json:"sha256"`
+	CalendarID   int64  `json:"calendarId,omitempty"`
+	CalendarName string `json:"calendarName,omitempty"`
+}
+
+// NewManifest starts the manifest of a bundle covering since to until
+func NewManifest(since, until time.Time) *Manifest {
+	return &Manifest{Version: manifestVersion, ExportedAt: time.Now().UTC(), Since: since, Until: until}
+}
+
+// Writer creates the files of a bundle in a directory
+type Writer struct {
+	Dir      string
+	Manifest *Manifest
+}
+
+// Create prepares dir for a new bundle. It refuses a directory that already
+// holds one, so an earlier export is never overwritten.
+func Create(dir string, m *Manifest) (*Writer, error) {
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil {
+		return nil, fmt.Errorf("%s already contains an export; choose another directory", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return &Writer{Dir: dir, Manifest: m}, nil
+}
+
+// Add writes a data file with write and lists it in the manifest. Files are
+// private to the user since they hold mail and calendar contents.
+func (w *Writer) Add(f File, write func(io.Writer) error) error {
+	path := filepath.Join(w.Dir, filepath.FromSlash(f.Path))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	h := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(out, h)}
+	if err := write(counter); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	f.Size, f.SHA256 = counter.n, hex.EncodeToString(h.Sum(nil))
+	w.Manifest.Files = append(w.Manifest.Files, f)
+	return nil
+}
+
+// AddJSON writes v as indented JSON
+func (w *Writer) AddJSON(f File, v interface{}) error {
+	return w.Add(f, func(out io.Writer) error {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	})
+}
+
+// Close writes the manifest, which marks the bundle complete
+func (w *Writer) Close() error {
+	data, err := json.MarshalIndent(w.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(w.Dir, ManifestFile), append(data, '\n'), 0600)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ReadManifest loads a bundle's manifest from its path, or from the
+// directory holding it
+func ReadManifest(path string) (*Manifest, string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, ManifestFile)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if m.Version != manifestVersion {
+		return nil, "", fmt.Errorf("unsupported export version %d", m.Version)
+	}
+	return &m, filepath.Dir(path), nil
+}
+
+// ErrChecksum is returned by Open when a file no longer matches the manifest
+var ErrChecksum = errors.New("file does not match the manifest checksum")
+
+// Open reads a data file of the bundle in dir, checking it against the
+// manifest so a damaged or edited file is never restored
+func Open(dir string, f File) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f.Path)))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != f.SHA256 {
+		return nil, fmt.Errorf("%s: %w", f.Path, ErrChecksum)
+	}
+	return data, nil
+}
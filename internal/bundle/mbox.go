@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// fromLine matches body lines that mboxrd escapes with an extra ">"
+var fromLine = regexp.MustCompile(`(?m)^(>*From )`)
+
+// WriteMbox writes emails in mboxrd format, readable by most mail clients.
+// Bodies are quoted-printable; attachments are not included.
+func WriteMbox(w io.Writer, emails []api.Email) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range emails {
+		date := e.SentAt
+		if date.IsZero() {
+			date = e.ReceivedAt
+		}
+		sender := "MAILER-DAEMON"
+		if e.From != nil && e.From.Email != "" {
+			sender = e.From.Email
+		}
+		bw.WriteString("From " + sender + " " + date.UTC().Format(time.ANSIC) + "\n")
+
+		header := func(name, value string) {
+			if value != "" {
+				bw.WriteString(name + ": " + value + "\n")
+			}
+		}
+		header("Message-ID", "<"+e.ID+"@porteden>")
+		if e.InReplyTo != "" {
+			header("In-Reply-To", "<"+e.InReplyTo+"@porteden>")
+		}
+		header("Date", date.Format(time.RFC1123Z))
+		if e.From != nil {
+			header("From", address(*e.From))
+		}
+		header("To", addressList(e.To))
+		header("Cc", addressList(e.CC))
+		header("Bcc", addressList(e.BCC))
+		header("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+		header("X-PortEden-Thread-ID", e.ThreadID)
+		header("X-PortEden-Labels", strings.Join(e.Labels, ", "))
+		if !e.IsRead {
+			header("Status", "O")
+		} else {
+			header("Status", "RO")
+		}
+		header("MIME-Version", "1.0")
+		contentType := "text/plain"
+		if e.BodyType == "html" {
+			contentType = "text/html"
+		}
+		header("Content-Type", contentType+"; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		bw.WriteString("\n")
+
+		body := e.Body
+		if body == "" {
+			body = e.BodyPreview
+		}
+		var encoded bytes.Buffer
+		qp := quotedprintable.NewWriter(&encoded)
+		qp.Write([]byte(body))
+		qp.Close()
+		text := strings.ReplaceAll(encoded.String(), "\r\n", "\n")
+		bw.WriteString(fromLine.ReplaceAllString(text, ">$1"))
+		bw.WriteString("\n\n")
+	}
+	return bw.Flush()
+}
+
+func address(p api.Participant) string {
+	return (&mail.Address{Name: p.Name, Address: p.Email}).String()
+}
+
+func addressList(ps []api.Participant) string {
+	out := make([]string, len(ps))
+	for i, p := range ps {
+		out[i] = address(p)
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,148 @@
+// Package cache stores offline data (such as the search index) under
+// ~/.config/porteden/cache. Files are private to the user, written atomically
+// so an interrupted write never leaves a corrupt cache behind, and optionally
+// encrypted at rest with a key held in the OS keyring.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/config"
+)
+
+// ErrNotFound is returned by Read when the cache entry does not exist
+var ErrNotFound = errors.New("cache entry not found")
+
+// Dir returns the cache directory
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache"), nil
+}
+
+// Read returns the contents of a cache entry
+func Read(name string) ([]byte, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	if isEncrypted(data) {
+		return decrypt(data)
+	}
+	return data, nil
+}
+
+// Write atomically replaces a cache entry
+func Write(name string, data []byte) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	if EncryptionEnabled() {
+		if data, err = encrypt(data); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a cache entry
+func Remove(name string) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove cache entry: %w", err)
+	}
+	return nil
+}
+
+// Entry describes a cache file
+type Entry struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	Encrypted bool      `json:"encrypted"`
+}
+
+// List returns the entries in the cache directory
+func List() ([]Entry, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || strings.Contains(f.Name(), ".tmp-") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			Name:      f.Name(),
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			Encrypted: fileEncrypted(filepath.Join(dir, f.Name())),
+		})
+	}
+	return entries, nil
+}
+
+// fileEncrypted checks an entry's header without reading the whole file
+func fileEncrypted(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, len(encryptedMagic))
+	n, _ := io.ReadFull(f, head)
+	return isEncrypted(head[:n])
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/porteden/cli/internal/config"
+)
+
+// encryptedMagic prefixes encrypted cache entries. Entries without it are
+// plaintext, so caches written before encryption was enabled stay readable.
+var encryptedMagic = []byte("PECACHE1")
+
+// markerFile records that cache encryption is enabled. It lives in the config
+// directory so purging the cache keeps the setting.
+const markerFile = "cache-encrypted"
+
+// ErrUndecryptable means an entry was encrypted with a key that is no longer
+// available (e.g. the keyring item was deleted)
+var ErrUndecryptable = errors.New("cache entry cannot be decrypted; run 'porteden cache purge' to reset it")
+
+// EncryptionEnabled reports whether new cache writes are encrypted
+func EncryptionEnabled() bool {
+	dir, err := config.Dir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, markerFile))
+	return err == nil
+}
+
+// EnableEncryption turns on encryption and re-writes every existing entry
+// encrypted. Returns the number of entries migrated.
+func EnableEncryption() (int, error) {
+	if _, err := encryptionKey(); err != nil {
+		return 0, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, markerFile), nil, 0600); err != nil {
+		return 0, fmt.Errorf("failed to enable encryption: %w", err)
+	}
+
+	entries, err := List()
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, e := range entries {
+		if e.Encrypted {
+			continue
+		}
+		data, err := Read(e.Name)
+		if err != nil {
+			return migrated, err
+		}
+		if err := Write(e.Name, data); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
+
+func encrypt(plain []byte) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, encryptedMagic...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, encryptedMagic), nil
+}
+
+func decrypt(data []byte) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(encryptedMagic):]
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrUndecryptable
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], encryptedMagic)
+	if err != nil {
+		return nil, ErrUndecryptable
+	}
+	return plain, nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := encryptionKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/porteden/cli/internal/config"
+)
+
+// useKey makes the process use key for the test, instead of the keyring
+func useKey(t *testing.T, key []byte) {
+	t.Helper()
+	keyMu.Lock()
+	old := cachedKey
+	cachedKey = key
+	keyMu.Unlock()
+	t.Cleanup(func() {
+		keyMu.Lock()
+		cachedKey = old
+		keyMu.Unlock()
+	})
+}
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	useKey(t, testKey(1))
+	plain := []byte(`{"subject":"Quarterly report"}`)
+
+	data, err := encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(data) {
+		t.Fatalf("encrypted data lacks the %q prefix", encryptedMagic)
+	}
+	if bytes.Contains(data, plain) {
+		t.Fatal("encrypted data contains the plaintext")
+	}
+
+	got, err := decrypt(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("decrypt = %q, want %q", got, plain)
+	}
+}
+
+func TestDecryptRejectsTamperedData(t *testing.T) {
+	useKey(t, testKey(1))
+	data, err := encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)-1] ^= 0xff
+	if _, err := decrypt(tampered); !errors.Is(err, ErrUndecryptable) {
+		t.Errorf("tampered: err = %v, want ErrUndecryptable", err)
+	}
+
+	if _, err := decrypt(encryptedMagic); !errors.Is(err, ErrUndecryptable) {
+		t.Errorf("truncated: err = %v, want ErrUndecryptable", err)
+	}
+
+	useKey(t, testKey(2))
+	if _, err := decrypt(data); !errors.Is(err, ErrUndecryptable) {
+		t.Errorf("wrong key: err = %v, want ErrUndecryptable", err)
+	}
+}
+
+func TestReadLegacyPlaintext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useKey(t, testKey(1))
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte(`{"version":1}`)
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), plain, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read("index.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Read = %q, want %q", got, plain)
+	}
+}
+
+func TestWriteReadEncrypted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useKey(t, testKey(1))
+	if _, err := EnableEncryption(); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := []byte(`{"version":1}`)
+	if err := Write("index.json", plain); err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := Dir()
+	raw, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncrypted(raw) {
+		t.Error("entry was written without encryption")
+	}
+
+	got, err := Read("index.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Read = %q, want %q", got, plain)
+	}
+}
+
+func TestKeyFileCreatedPrivate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	key, err := keyFileGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 32 {
+		t.Fatalf("key is %d bytes, want 32", len(key))
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, keyFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("key file mode = %o, want 600", perm)
+	}
+
+	again, err := keyFileGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, key) {
+		t.Error("second read returned a different key")
+	}
+}
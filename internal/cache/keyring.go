@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/porteden/cli/internal/config"
+	"github.com/porteden/cli/internal/debug"
+)
+
+const (
+	keyringService = "porteden-cli"
+	keyringAccount = "cache-key"
+	keyFileName    = "cache.key"
+)
+
+// keyMu guards cachedKey, the key once read, so the keyring tool runs at most
+// once per process
+var (
+	keyMu     sync.Mutex
+	cachedKey []byte
+)
+
+// encryptionKey returns the 256-bit cache key, creating one on first use. The
+// key lives in the OS keyring (macOS Keychain, or the Secret Service via
+// secret-tool on Linux). Where no keyring is available it falls back to a
+// private key file in the config directory.
+func encryptionKey() ([]byte, error) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	if cachedKey != nil {
+		return cachedKey, nil
+	}
+	key, err := loadEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	cachedKey = key
+	return key, nil
+}
+
+func loadEncryptionKey() ([]byte, error) {
+	key, err := keyringGet()
+	switch {
+	case err == nil:
+		return key, nil
+	case errors.Is(err, errNoKeyring):
+		debug.Log("Keyring unavailable, using key file: %v", err)
+		return keyFileGet()
+	case !errors.Is(err, errNoKey):
+		// A locked or failing keyring may still hold the key: making a new
+		// one would replace it and lose everything encrypted with it
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := keyringSet(key); err != nil {
+		debug.Log("Could not store key in keyring, using key file: %v", err)
+		return keyFileGet()
+	}
+	return key, nil
+}
+
+var (
+	// errNoKey means the keyring works but holds no cache key yet
+	errNoKey = errors.New("no cache key in keyring")
+	// errNoKeyring means there is no keyring to keep the key in
+	errNoKeyring = errors.New("no keyring")
+)
+
+func keyringGet() ([]byte, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	case "linux", "freebsd", "openbsd":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, fmt.Errorf("%w: secret-tool not found", errNoKeyring)
+		}
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	default:
+		return nil, fmt.Errorf("%w: no keyring support on %s", errNoKeyring, runtime.GOOS)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if keyringItemMissing(exitErr) {
+				return nil, errNoKey
+			}
+			return nil, fmt.Errorf("keyring lookup failed: %v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("keyring lookup failed: %w", err)
+	}
+	value := strings.TrimSpace(string(out))
+	if value == "" {
+		return nil, errNoKey
+	}
+	return decodeKey(value)
+}
+
+// keyringItemMissing reports whether a lookup failed only because there is
+// no such item: security exits 44 (errSecItemNotFound), and secret-tool exits
+// 1 without printing an error
+func keyringItemMissing(err *exec.ExitError) bool {
+	if runtime.GOOS == "darwin" {
+		return err.ExitCode() == 44
+	}
+	return err.ExitCode() == 1 && len(bytes.TrimSpace(err.Stderr)) == 0
+}
+
+// keyringSet stores the key, passing it on stdin so it never shows in the
+// process list
+func keyringSet(key []byte) error {
+	value := hex.EncodeToString(key)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// -w last makes security prompt for the password, and then again to
+		// confirm it
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", keyringAccount, "-w")
+		cmd.Stdin = strings.NewReader(value + "\n" + value + "\n")
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label=PortEden CLI cache key", "service", keyringService, "account", keyringAccount)
+		cmd.Stdin = strings.NewReader(value)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// keyFileGet reads or creates the fallback key file
+func keyFileGet() ([]byte, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, keyFileName)
+
+	if data, err := os.ReadFile(path); err == nil {
+		return decodeKey(strings.TrimSpace(string(data)))
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save cache key: %w", err)
+	}
+	return key, nil
+}
+
+func decodeKey(value string) ([]byte, error) {
+	key, err := hex.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid cache key")
+	}
+	return key, nil
+}
@@ -0,0 +1,600 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/config"
+	"github.com/porteden/cli/internal/extract"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+// attachmentJob is one attachment queued for download
+type attachmentJob struct {
+	email      api.Email
+	attachment api.Attachment
+}
+
+// attachmentNameData is the data available to --name-template
+type attachmentNameData struct {
+	Date    string // Received date, YYYY-MM-DD
+	Subject string
+	From    string // Sender email
+	Name    string // Original attachment file name
+	Ext     string // Extension of Name, including the dot
+	EmailID string
+	Index   int // 1-based position in the download list
+}
+
+// savedAttachment describes a downloaded file
+type savedAttachment struct {
+	EmailID      string `json:"emailId"`
+	AttachmentID string `json:"attachmentId"`
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Size         int    `json:"size"`
+}
+
+var emailAttachmentsCmd = &cobra.Command{
+	Use:   "attachments [emailId...]",
+	Short: "Download attachments",
+	Long: `Download attachments from specific emails, a thread, or a search.
+
+Downloads run concurrently into --output-dir (default: the downloads.dir
+config setting, else the current directory), with one subfolder per thread
+named after its subject. Use --flat (or downloads.flat) to skip the subfolders.
+File names come from --name-template, a Go template with the fields .Date,
+.Subject, .From, .Name, .Ext, .EmailID and .Index. Existing files are never
+overwritten unless --overwrite is set; a numeric suffix is added instead.
+
+Examples:
+  porteden email attachments <emailId>
+  porteden email attachments --thread <threadId> --output-dir ./invoices --flat
+  porteden email attachments -q "invoice" --after 2026-01-01 --name-template '{{.Date}}-{{.Subject}}-{{.Name}}'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threadID, _ := cmd.Flags().GetString("thread")
+		query, _ := cmd.Flags().GetString("query")
+		from, _ := cmd.Flags().GetString("from")
+		afterStr, _ := cmd.Flags().GetString("after")
+		limit, _ := cmd.Flags().GetInt("limit")
+		dir := downloadDir(cmd)
+		flat, _ := cmd.Flags().GetBool("flat")
+		if !cmd.Flags().Changed("flat") {
+			flat = userConfig.Bool("downloads.flat")
+		}
+		nameTemplate, _ := cmd.Flags().GetString("name-template")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		includeInline, _ := cmd.Flags().GetBool("include-inline")
+
+		searching := query != "" || from != "" || afterStr != ""
+		if len(args) == 0 && threadID == "" && !searching {
+			return fmt.Errorf("specify email IDs, --thread, or a search (-q, --from, --after)")
+		}
+
+		tmpl, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid --name-template: %w", err)
+		}
+
+		ids := make([]string, len(args))
+		for i, arg := range args {
+			if ids[i], err = resolveEmailID(cmd, arg); err != nil {
+				return err
+			}
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		emails, err := collectAttachmentEmails(client, ids, threadID, query, from, afterStr, limit)
+		if err != nil {
+			return err
+		}
+
+		var jobs []attachmentJob
+		for _, e := range emails {
+			for _, a := range e.Attachments {
+				if a.IsInline && !includeInline {
+					continue
+				}
+				jobs = append(jobs, attachmentJob{email: e, attachment: a})
+			}
+		}
+		if len(jobs) == 0 {
+			fmt.Fprintln(os.Stderr, "No attachments found.")
+			return nil
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+
+		var folders map[string]string
+		if !flat {
+			folders = threadFolderNames(jobs)
+		}
+
+		saved, err := downloadAttachments(client, jobs, dir, folders, tmpl, concurrency, overwrite)
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(saved, output.FormatJSON, output.PrintOptions{})
+		} else {
+			for _, s := range saved {
+				fmt.Println(s.Path)
+			}
+			fmt.Fprintf(os.Stderr, "Downloaded %d of %d attachment(s) to %s\n", len(saved), len(jobs), dir)
+		}
+		return formatError(err)
+	},
+}
+
+var emailAttachmentCmd = &cobra.Command{
+	Use:   "attachment <emailId> <attachmentId>",
+	Short: "Download or preview one attachment",
+	Long: `Download a single attachment, or with --preview print the start of its
+text to decide whether it's worth downloading.
+
+The attachment can be given by ID or by file name. Text is extracted from
+plain text, CSV, HTML, Word (.docx) and PDF files. PDF text is best effort:
+scanned pages and some embedded fonts have no extractable text.
+
+Examples:
+  porteden email attachment <emailId> att_1
+  porteden email attachment <emailId> report.pdf --output-dir ~/Downloads
+  porteden email attachment <emailId> att_1 --preview
+  porteden email attachment <emailId> data.csv --preview --lines 100`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preview, _ := cmd.Flags().GetBool("preview")
+		lines, _ := cmd.Flags().GetInt("lines")
+		overwrite, _ := cmd.Flags().GetBool("overwrite")
+
+		emailID, err := resolveEmailID(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		resp, err := client.GetEmail(emailID, false)
+		if err != nil {
+			return formatError(err)
+		}
+		att, err := findAttachment(resp.Email, args[1])
+		if err != nil {
+			return err
+		}
+
+		if !preview {
+			dir := downloadDir(cmd)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			tmpl := template.Must(template.New("name").Parse("{{.Name}}"))
+			saved, err := downloadAttachments(client, []attachmentJob{{email: resp.Email, attachment: att}}, dir, nil, tmpl, 1, overwrite)
+			if err != nil {
+				return err
+			}
+			if getOutputFormat(cmd) == output.FormatJSON {
+				output.PrintWithOptions(saved[0], output.FormatJSON, output.PrintOptions{})
+			} else {
+				fmt.Println(saved[0].Path)
+			}
+			return nil
+		}
+
+		data, err := client.GetAttachment(emailID, att.ID)
+		if err != nil {
+			return formatError(err)
+		}
+		text, err := extract.Text(att.Name, att.ContentType, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", att.Name, err)
+		}
+		all := strings.Split(strings.TrimRight(text, "\n"), "\n")
+		shown := all
+		if lines > 0 && len(shown) > lines {
+			shown = shown[:lines]
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(attachmentPreview{
+				EmailID:      emailID,
+				AttachmentID: att.ID,
+				Name:         att.Name,
+				Text:         strings.Join(shown, "\n"),
+				Lines:        len(all),
+				Truncated:    len(shown) < len(all),
+			}, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		for _, l := range shown {
+			fmt.Println(l)
+		}
+		if len(shown) < len(all) {
+			fmt.Fprintln(os.Stderr, output.ColorGray(fmt.Sprintf("... %d more line(s). Use --lines to see more, or download it without --preview.", len(all)-len(shown))))
+		}
+		return nil
+	},
+}
+
+// attachmentPreview is the JSON output of 'email attachment --preview'
+type attachmentPreview struct {
+	EmailID      string `json:"emailId"`
+	AttachmentID string `json:"attachmentId"`
+	Name         string `json:"name"`
+	Text         string `json:"text"`
+	Lines        int    `json:"lines"` // Lines in the whole text
+	Truncated    bool   `json:"truncated"`
+}
+
+// findAttachment looks up an email's attachment by ID, then by file name
+func findAttachment(e api.Email, ref string) (api.Attachment, error) {
+	for _, a := range e.Attachments {
+		if a.ID == ref {
+			return a, nil
+		}
+	}
+	for _, a := range e.Attachments {
+		if strings.EqualFold(a.Name, ref) {
+			return a, nil
+		}
+	}
+
+	if len(e.Attachments) == 0 {
+		return api.Attachment{}, fmt.Errorf("email %s has no attachments", e.ID)
+	}
+	names := make([]string, len(e.Attachments))
+	for i, a := range e.Attachments {
+		names[i] = fmt.Sprintf("%s (%s)", a.ID, a.Name)
+	}
+	return api.Attachment{}, fmt.Errorf("email %s has no attachment %q; it has %s", e.ID, ref, strings.Join(names, ", "))
+}
+
+// collectAttachmentEmails resolves the emails to download from, with
+// attachment metadata filled in
+func collectAttachmentEmails(client *api.Client, ids []string, threadID, query, from, afterStr string, limit int) ([]api.Email, error) {
+	var emails []api.Email
+
+	for _, id := range ids {
+		resp, err := client.GetEmail(id, false)
+		if err != nil {
+			return nil, formatError(err)
+		}
+		emails = append(emails, resp.Email)
+	}
+
+	if threadID != "" {
+		thread, err := client.GetThread(threadID, false)
+		if err != nil {
+			return nil, formatError(err)
+		}
+		emails = append(emails, thread.Messages...)
+	}
+
+	if query != "" || from != "" || afterStr != "" {
+		hasAttachment := true
+		params := api.EmailParams{
+			Query:         query,
+			From:          from,
+			HasAttachment: &hasAttachment,
+			Limit:         min(limit, 50),
+		}
+		if afterStr != "" {
+			after, err := parseDateTime(afterStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --after: %w", err)
+			}
+			params.After = after
+		}
+
+		var found []api.Email
+		_, err := client.ForEachEmailsPage(params, func(resp *api.EmailsResponse) error {
+			found = append(found, resp.Emails...)
+			if len(found) >= limit {
+				return errStopPaging
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopPaging) {
+			return nil, formatError(err)
+		}
+		if len(found) > limit {
+			found = found[:limit]
+		}
+
+		// Listings may omit attachment metadata
+		for _, e := range found {
+			if len(e.Attachments) == 0 && e.HasAttachments {
+				resp, err := client.GetEmail(e.ID, false)
+				if err != nil {
+					return nil, formatError(err)
+				}
+				e = resp.Email
+			}
+			emails = append(emails, e)
+		}
+	}
+
+	return emails, nil
+}
+
+// errStopPaging ends a ForEach*Page loop early without reporting an error
+var errStopPaging = errors.New("stop paging")
+
+// pagingError is the error of a ForEach*Page loop, or nil if it was ended
+// early with errStopPaging or by a streamer's output limit
+func pagingError(err error) error {
+	if errors.Is(err, errStopPaging) || errors.Is(err, output.ErrOutputLimit) {
+		return nil
+	}
+	return err
+}
+
+// downloadDir resolves where downloads go: --output-dir, then downloads.dir
+// from the config file, then the current directory
+func downloadDir(cmd *cobra.Command) string {
+	if cmd.Flags().Changed("output-dir") {
+		dir, _ := cmd.Flags().GetString("output-dir")
+		return dir
+	}
+	if dir := userConfig.String("downloads.dir"); dir != "" {
+		return config.ExpandHome(dir)
+	}
+	return "."
+}
+
+// threadFolderNames maps each thread in jobs to a subfolder name derived from
+// its subject, falling back to the thread ID. Threads whose subjects collide
+// get their ID appended so their files never mix.
+func threadFolderNames(jobs []attachmentJob) map[string]string {
+	folders := map[string]string{}
+	used := map[string]string{} // folder name -> thread key
+	for _, job := range jobs {
+		key := threadKey(job.email)
+		if _, ok := folders[key]; ok {
+			continue
+		}
+		name := sanitizeFileName(threadSubject(job.email.Subject))
+		if name == "" {
+			name = sanitizeFileName(key)
+		}
+		if other, ok := used[strings.ToLower(name)]; ok && other != key {
+			name = sanitizeFileName(name + " " + key)
+		}
+		used[strings.ToLower(name)] = key
+		folders[key] = name
+	}
+	return folders
+}
+
+// threadKey identifies the thread an email belongs to; emails without a thread
+// ID are treated as their own thread
+func threadKey(e api.Email) string {
+	if e.ThreadID != "" {
+		return e.ThreadID
+	}
+	return e.ID
+}
+
+// threadSubject strips reply and forward prefixes so every message in a thread
+// maps to the same folder
+func threadSubject(subject string) string {
+	for {
+		trimmed := strings.TrimSpace(subject)
+		lower := strings.ToLower(trimmed)
+		cut := false
+		for _, prefix := range []string{"re:", "fw:", "fwd:", "aw:", "wg:"} {
+			if strings.HasPrefix(lower, prefix) {
+				trimmed, cut = trimmed[len(prefix):], true
+				break
+			}
+		}
+		if !cut {
+			return trimmed
+		}
+		subject = trimmed
+	}
+}
+
+// downloadAttachments fetches jobs concurrently, writing each to a unique path
+// in dir, inside the job's thread folder when folders is non-nil. Returns the
+// files saved and the first error encountered.
+func downloadAttachments(client *api.Client, jobs []attachmentJob, dir string, folders map[string]string, tmpl *template.Template, workers int, overwrite bool) ([]savedAttachment, error) {
+	paths := make([]string, len(jobs))
+	reserved := map[string]bool{}
+	for i, job := range jobs {
+		name, err := attachmentFileName(tmpl, job, i+1)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, folders[threadKey(job.email)], name)
+		if !overwrite {
+			path = uniquePath(path, reserved)
+		}
+		reserved[path] = true
+		paths[i] = path
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		done     int
+	)
+	results := make([]*savedAttachment, len(jobs))
+	queue := make(chan int)
+
+	for w := 0; w < min(max(workers, 1), len(jobs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				job := jobs[i]
+				data, err := client.GetAttachment(job.email.ID, job.attachment.ID)
+				if err == nil {
+					if err = os.MkdirAll(filepath.Dir(paths[i]), 0755); err == nil {
+						err = os.WriteFile(paths[i], data, 0644)
+					}
+				}
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to download %s: %w", job.attachment.Name, formatError(err))
+					}
+				} else {
+					results[i] = &savedAttachment{
+						EmailID:      job.email.ID,
+						AttachmentID: job.attachment.ID,
+						Name:         job.attachment.Name,
+						Path:         paths[i],
+						Size:         len(data),
+					}
+				}
+				done++
+				progress.Status(fmt.Sprintf("Downloading attachments %s %d/%d",
+					progress.Bar(done, len(jobs), 20), done, len(jobs)))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	progress.Clear()
+
+	saved := []savedAttachment{}
+	for _, r := range results {
+		if r != nil {
+			saved = append(saved, *r)
+		}
+	}
+	return saved, firstErr
+}
+
+// attachmentFileName renders the name template for a job. Field values are
+// sanitized; literal slashes in the template create subdirectories.
+func attachmentFileName(tmpl *template.Template, job attachmentJob, index int) (string, error) {
+	from := ""
+	if job.email.From != nil {
+		from = job.email.From.Email
+	}
+	date := ""
+	if !job.email.ReceivedAt.IsZero() {
+		date = job.email.ReceivedAt.Local().Format("2006-01-02")
+	}
+	name := job.attachment.Name
+	if name == "" {
+		name = "attachment-" + job.attachment.ID
+	}
+
+	data := attachmentNameData{
+		Date:    date,
+		Subject: sanitizeFileName(job.email.Subject),
+		From:    sanitizeFileName(from),
+		Name:    sanitizeFileName(name),
+		Ext:     sanitizeFileName(filepath.Ext(name)),
+		EmailID: sanitizeFileName(job.email.ID),
+		Index:   index,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid --name-template: %w", err)
+	}
+
+	result := filepath.Clean(buf.String())
+	if result == "." || filepath.IsAbs(result) || result == ".." || strings.HasPrefix(result, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("--name-template produced an invalid path: %q", buf.String())
+	}
+	return result, nil
+}
+
+// sanitizeFileName replaces characters that are unsafe in file names
+func sanitizeFileName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r < 32, strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, s)
+	s = strings.TrimSpace(s)
+	// At most 100 bytes, cut between runes
+	if len(s) > 100 {
+		cut := 100
+		for !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut]
+	}
+	return strings.Trim(s, ".")
+}
+
+// uniquePath appends " (2)", " (3)", ... before the extension until the path
+// is neither on disk nor already claimed by this run
+func uniquePath(path string, reserved map[string]bool) string {
+	taken := func(p string) bool {
+		if reserved[p] {
+			return true
+		}
+		_, err := os.Stat(p)
+		return err == nil
+	}
+	if !taken(path) {
+		return path
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		candidate := base + " (" + strconv.Itoa(n) + ")" + ext
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+func init() {
+	emailAttachmentsCmd.Flags().String("thread", "", "Download attachments from every message in a thread")
+	emailAttachmentsCmd.Flags().StringP("query", "q", "", "Search emails with attachments")
+	emailAttachmentsCmd.Flags().String("from", "", "Search by sender email")
+	emailAttachmentsCmd.Flags().String("after", "", "Search emails after date (YYYY-MM-DD or datetime)")
+	emailAttachmentsCmd.Flags().Int("limit", 50, "Maximum emails to search")
+	emailAttachmentsCmd.Flags().String("output-dir", "", "Directory to save attachments in (default: downloads.dir config, else current directory)")
+	emailAttachmentsCmd.Flags().Bool("flat", false, "Save all files directly in the output directory, without per-thread subfolders")
+	emailAttachmentsCmd.Flags().String("name-template", "{{.Name}}", "File name template (fields: .Date .Subject .From .Name .Ext .EmailID .Index)")
+	emailAttachmentsCmd.Flags().Int("concurrency", 4, "Parallel downloads")
+	emailAttachmentsCmd.Flags().Bool("overwrite", false, "Overwrite existing files instead of adding a numeric suffix")
+	emailAttachmentsCmd.Flags().Bool("include-inline", false, "Include inline attachments (e.g. signature images)")
+
+	emailAttachmentCmd.Flags().Bool("preview", false, "Print the start of the attachment's text instead of downloading it")
+	emailAttachmentCmd.Flags().Int("lines", 40, "Lines to print with --preview (0 for all)")
+	emailAttachmentCmd.Flags().String("output-dir", "", "Directory to save the attachment in (default: downloads.dir config, else current directory)")
+	emailAttachmentCmd.Flags().Bool("overwrite", false, "Overwrite an existing file instead of adding a numeric suffix")
+
+	emailCmd.AddCommand(emailAttachmentsCmd)
+	emailCmd.AddCommand(emailAttachmentCmd)
+}
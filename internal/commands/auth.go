@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"regexp"
 
 	"github.com/porteden/cli/internal/api"
 	"github.com/porteden/cli/internal/auth"
 	"github.com/porteden/cli/internal/debug"
+	"github.com/porteden/cli/internal/i18n"
 	"github.com/porteden/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +27,9 @@ func requireStore() error {
 	return auth.InitStore()
 }
 
+// ssoSlugPattern matches organization slugs accepted by --sso
+var ssoSlugPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
 var authCmd = &cobra.Command{
 	Use:   "auth",
 	Short: "Authentication commands",
@@ -41,8 +46,22 @@ Two authentication methods:
   1. Browser OAuth (default): Opens browser for secure login
   2. Direct token: Pass --token flag for non-interactive setup (CI/automation)
 
+On a remote machine or over SSH, use --no-browser: the login URL is printed
+to open in a browser anywhere, along with a code to check on the login page.
+
+Organizations with single sign-on use --sso with the organization's slug,
+as given by their administrator. Sign-in then goes through the company's
+identity provider.
+
+With --oauth, the browser login stores a refresh token instead of a
+long-lived API key. Each run mints an access token that expires within
+minutes, so a leaked token is of little use.
+
 Examples:
   porteden auth login                    # Browser OAuth
+  porteden auth login --oauth            # Short-lived access tokens
+  porteden auth login --no-browser       # Print the URL instead (SSH)
+  porteden auth login --sso acme         # Organization single sign-on
   porteden auth login --token pe_xxx     # Direct token
   porteden auth login --profile work     # Named profile`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,7 +74,24 @@ Examples:
 
 		token, _ := cmd.Flags().GetString("token")
 		keyTitle, _ := cmd.Flags().GetString("title")
+		oauth, _ := cmd.Flags().GetBool("oauth")
+		noBrowser, _ := cmd.Flags().GetBool("no-browser")
+		sso, _ := cmd.Flags().GetString("sso")
 		profileName := getProfile(cmd)
+		if oauth && token != "" {
+			return fmt.Errorf("--oauth signs in through the browser and can't be combined with --token")
+		}
+		if noBrowser && token != "" {
+			return fmt.Errorf("--no-browser applies to browser login and can't be combined with --token")
+		}
+		if cmd.Flags().Changed("sso") {
+			if token != "" {
+				return fmt.Errorf("--sso signs in through your organization and can't be combined with --token")
+			}
+			if !ssoSlugPattern.MatchString(sso) {
+				return fmt.Errorf("invalid --sso %q: use the organization's slug, e.g. acme-corp", sso)
+			}
+		}
 
 		// Delete existing key before re-authenticating
 		if existingKey, err := auth.GetStoredAPIKey(profileName); err == nil && existingKey != "" {
@@ -77,7 +113,7 @@ Examples:
 		}
 
 		// Browser OAuth wizard flow
-		if _, err := runLoginWizard(profileName, keyTitle); err != nil {
+		if _, err := runLoginWizard(profileName, keyTitle, sso, oauth, noBrowser); err != nil {
 			return err
 		}
 		return nil
@@ -89,13 +125,21 @@ var statusCmd = &cobra.Command{
 	Short: "Show authentication status",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		profileName := getProfile(cmd)
-		apiKey, err := auth.GetAPIKey(profileName)
-		if err != nil {
-			fmt.Printf("Not authenticated (profile: %s). Run 'porteden auth login' to authenticate.\n", profileName)
-			return nil
+		var client *api.Client
+		oauth := false
+		if isDemo(cmd) {
+			client = newDemoClient()
+		} else if oauthClient, ok := newOAuthClient(profileName); ok {
+			client, oauth = oauthClient, true
+		} else {
+			apiKey, err := auth.GetAPIKey(profileName)
+			if err != nil {
+				fmt.Printf("Not authenticated (profile: %s). Run 'porteden auth login' to authenticate.\n", profileName)
+				return nil
+			}
+			client = newAPIClient(apiKey)
 		}
 
-		client := api.NewClient(apiKey)
 		status, err := client.GetAuthStatus()
 		if err != nil {
 			return err
@@ -109,6 +153,20 @@ var statusCmd = &cobra.Command{
 			fmt.Printf("Key title: %s\n", status.KeyTitle)
 		}
 		fmt.Printf("Key created: %s\n", status.CreatedAt.Format("2006-01-02"))
+		if oauth {
+			// The expiry is the access token's, minutes away by design
+			fmt.Println("Credentials: refresh token with short-lived access tokens")
+			return nil
+		}
+		if status.ExpiresAt != nil {
+			fmt.Printf("Key expires: %s\n", status.ExpiresAt.Format("2006-01-02"))
+		}
+		if !isDemo(cmd) && os.Getenv("PE_API_KEY") == "" {
+			rememberKeyInfo(profileName, status)
+		}
+		if notice := keyExpiryNotice(profileName, status.ExpiresAt); notice != "" && !quietMode {
+			fmt.Fprintln(os.Stderr, output.ColorYellow(notice))
+		}
 		return nil
 	},
 }
@@ -173,12 +231,14 @@ var logoutCmd = &cobra.Command{
 		}
 
 		profileName := getProfile(cmd)
-		apiKey, err := auth.GetAPIKey(profileName)
-		if err != nil {
-			return fmt.Errorf("not authenticated (profile: %s)", profileName)
+		client, ok := newOAuthClient(profileName)
+		if !ok {
+			apiKey, err := auth.GetAPIKey(profileName)
+			if err != nil {
+				return fmt.Errorf("%w (profile: %s)", errNotAuthenticated, profileName)
+			}
+			client = newAPIClient(apiKey)
 		}
-
-		client := api.NewClient(apiKey)
 		if err := client.Logout(); err != nil {
 			fmt.Printf("Warning: failed to revoke API key on server: %v\n", err)
 		}
@@ -193,22 +253,31 @@ var logoutCmd = &cobra.Command{
 }
 
 // runLoginWizard runs the full interactive login wizard with banner, steps, and completion.
-// Returns the API key on success.
-func runLoginWizard(profileName, keyTitle string) (string, error) {
+// Returns the API key on success, or the refresh token when oauth is set.
+// With noBrowser the login URL is printed for the user to open instead, and
+// with ssoOrg set sign-in goes through that organization's SSO.
+func runLoginWizard(profileName, keyTitle, ssoOrg string, oauth, noBrowser bool) (string, error) {
 	totalSteps := 2
-	if auth.IsInteractiveTerminal() {
+	if auth.IsInteractiveTerminal() && !oauth {
 		totalSteps = 3 // includes export step
 	}
 
 	// Banner & welcome
 	output.PrintBanner()
-	fmt.Println("  Let's connect your PortEden account.")
-	fmt.Println(output.ColorGray("  We'll open your browser to sign in securely."))
+	fmt.Println("  " + i18n.T("Let's connect your PortEden account."))
+	if noBrowser {
+		fmt.Println(output.ColorGray("  " + i18n.T("You'll open a sign-in link in any browser.")))
+	} else {
+		fmt.Println(output.ColorGray("  " + i18n.T("We'll open your browser to sign in securely.")))
+	}
+	if ssoOrg != "" {
+		fmt.Println(output.ColorGray("  " + i18n.T("Signing in with the single sign-on of %s.", ssoOrg)))
+	}
 	fmt.Println()
 
 	// "Press Enter to continue" for interactive terminals
 	if auth.IsInteractiveTerminal() {
-		fmt.Print(output.ColorGray("  Press Enter to continue..."))
+		fmt.Print(output.ColorGray("  " + i18n.T("Press Enter to continue...")))
 		if _, err := bufio.NewReader(os.Stdin).ReadBytes('\n'); err != nil {
 			debug.Log("Failed to read stdin input: %v", err)
 		}
@@ -216,27 +285,56 @@ func runLoginWizard(profileName, keyTitle string) (string, error) {
 	}
 
 	// Step 1: Open browser
-	output.PrintStep(1, totalSteps, "Opening browser...")
+	if noBrowser {
+		output.PrintStep(1, totalSteps, i18n.T("Starting sign-in..."))
+	} else {
+		output.PrintStep(1, totalSteps, i18n.T("Opening browser..."))
+	}
 	progress := &auth.LoginProgress{
+		NoBrowser: noBrowser,
 		OnBrowserOpen: func(loginURL string) {
-			output.PrintInfo("If it doesn't open, visit: " + loginURL)
+			output.PrintInfo(i18n.T("If it doesn't open, visit: ") + loginURL)
+		},
+		OnLoginURL: func(loginURL, userCode string) {
+			// Printed plain on their own lines so they copy cleanly
+			output.PrintInfo(i18n.T("Open this link in any browser:"))
+			fmt.Printf("        %s\n", loginURL)
+			if userCode != "" {
+				output.PrintInfo(i18n.T("Check that the page shows this code:"))
+				fmt.Printf("        %s\n", userCode)
+			}
 		},
 		OnWaiting: func() {
 			fmt.Println()
-			output.PrintStep(2, totalSteps, "Waiting for browser authentication... "+output.ColorGray("Please complete sign-in in your browser."))
+			output.PrintStep(2, totalSteps, i18n.T("Waiting for browser authentication... ")+output.ColorGray(i18n.T("Please complete sign-in in your browser.")))
 		},
 	}
 
-	apiKey, err := auth.Login(profileName, "", keyTitle, progress)
+	if oauth {
+		refreshToken, err := auth.LoginOAuth(profileName, ssoOrg, progress)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\n  %s %v\n", output.ColorRed(output.Symbols("✗")), err)
+			return "", fmt.Errorf("login failed")
+		}
+		// The refresh token stays in the credential store; there is no key
+		// to show or export
+		fmt.Println()
+		output.PrintSuccess(i18n.T("Authenticated successfully!"))
+		output.PrintInfo("Requests will use short-lived access tokens")
+		output.PrintCompletion(profileName)
+		return refreshToken, nil
+	}
+
+	apiKey, err := auth.Login(profileName, "", keyTitle, ssoOrg, progress)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "\n  %s %v\n", output.ColorRed("✗"), err)
+		fmt.Fprintf(os.Stderr, "\n  %s %v\n", output.ColorRed(output.Symbols("✗")), err)
 		return "", fmt.Errorf("login failed")
 	}
 
 	fmt.Println()
-	output.PrintSuccess("Authenticated successfully!")
+	output.PrintSuccess(i18n.T("Authenticated successfully!"))
 	fmt.Println()
-	fmt.Println("  Your API key:")
+	fmt.Println("  " + i18n.T("Your API key:"))
 	fmt.Println()
 	fmt.Printf("    %s\n", output.ColorBold(apiKey))
 	fmt.Println()
@@ -246,7 +344,7 @@ func runLoginWizard(profileName, keyTitle string) (string, error) {
 	// Step 3: Export (interactive only)
 	if auth.IsInteractiveTerminal() {
 		fmt.Println()
-		output.PrintStep(3, totalSteps, "Additional setup")
+		output.PrintStep(3, totalSteps, i18n.T("Additional setup"))
 		dest := auth.PromptExportDestination(os.Stdin, os.Stdout)
 		if dest != auth.ExportNone {
 			if err := auth.ExportAPIKey(apiKey, dest); err != nil {
@@ -263,6 +361,9 @@ func runLoginWizard(profileName, keyTitle string) (string, error) {
 func init() {
 	loginCmd.Flags().String("token", "", "API key for direct authentication (non-interactive)")
 	loginCmd.Flags().String("title", "", "Title for the API key (e.g., 'Work Laptop')")
+	loginCmd.Flags().Bool("oauth", false, "Store a refresh token and use short-lived access tokens instead of an API key")
+	loginCmd.Flags().String("sso", "", "Sign in through the single sign-on of this organization (its slug)")
+	loginCmd.Flags().Bool("no-browser", false, "Print the login URL instead of opening a browser (for SSH and remote shells)")
 	authCmd.AddCommand(loginCmd)
 	authCmd.AddCommand(statusCmd)
 	authCmd.AddCommand(listProfilesCmd)
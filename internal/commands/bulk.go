@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/picker"
+	"github.com/spf13/cobra"
+)
+
+// rateFlagUsage describes --rate on commands that send many requests
+const rateFlagUsage = "Throttle requests to this rate, e.g. 10/min or 1/s"
+
+// applyRateFlag throttles the client to the --rate flag, if set
+func applyRateFlag(cmd *cobra.Command, client *api.Client) error {
+	rateStr, _ := cmd.Flags().GetString("rate")
+	if rateStr == "" {
+		return nil
+	}
+	rate, err := api.ParseRate(rateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --rate: %w", err)
+	}
+	client.WithRateLimit(rate)
+	return nil
+}
+
+// runBulk applies an action to items chosen in the multi-select picker. It
+// asks once for confirmation, continues past individual failures and ends
+// with a summary.
+func runBulk(action, noun string, items []picker.Item, fn func(id string) error) error {
+	what := fmt.Sprintf("%d %s", len(items), noun)
+	if len(items) != 1 {
+		what += "s"
+	}
+
+	fmt.Println()
+	for _, it := range items {
+		fmt.Println("  " + it.Label)
+	}
+	fmt.Println()
+	if !confirm(fmt.Sprintf("%s %s?", action, what)) {
+		fmt.Println(i18n.T("Cancelled. No changes made."))
+		return nil
+	}
+
+	failed := 0
+	for _, it := range items {
+		if err := fn(it.ID); err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "  %s %s: %v\n", output.ColorRed(output.Symbols("✗")), it.Label, formatError(err))
+			continue
+		}
+		output.PrintSuccess(it.Label)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %s failed", failed, what)
+	}
+	fmt.Printf("\nDone: %s\n", what)
+	return nil
+}
@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/porteden/cli/internal/cache"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// cacheItem is one cached file shown by cache status
+type cacheItem struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	Encrypted bool      `json:"encrypted"`
+}
+
+// cacheStatus is the JSON shape of cache status
+type cacheStatus struct {
+	Items      []cacheItem `json:"items"`
+	TotalBytes int64       `json:"totalBytes"`
+	Encryption bool        `json:"encryption"`
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage local caches",
+}
+
+var cacheStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show local cache usage",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := listCacheItems()
+		if err != nil {
+			return err
+		}
+
+		status := cacheStatus{Items: items, Encryption: cache.EncryptionEnabled()}
+		for _, it := range items {
+			status.TotalBytes += it.Size
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(status, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+
+		if len(items) == 0 {
+			fmt.Println("Cache is empty.")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		output.TableHeader(w, "NAME", "TYPE", "SIZE", "MODIFIED", "ENCRYPTED")
+		for _, it := range items {
+			encrypted := ""
+			if it.Encrypted {
+				encrypted = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				it.Name, it.Type, output.FormatBytes(it.Size), output.FormatLocalTime(it.ModTime), encrypted)
+		}
+		w.Flush()
+
+		encryption := "off (run 'porteden cache encrypt')"
+		if status.Encryption {
+			encryption = "on"
+		}
+		fmt.Printf("\nTotal: %s in %d file(s). Encryption: %s\n", output.FormatBytes(status.TotalBytes), len(items), encryption)
+		return nil
+	},
+}
+
+var cachePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete cached data",
+	Long: `Delete cached data, optionally only entries of a type or older than an age.
+The email outbox and queued calendar changes are only purged with --type outbox
+or --type sync, since they haven't been sent yet. The secret behind calendar
+publish URLs is only purged with --type publish.
+
+Examples:
+  porteden cache purge
+  porteden cache purge --older-than 30d
+  porteden cache purge --type index`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThanStr, _ := cmd.Flags().GetString("older-than")
+		types, _ := cmd.Flags().GetStringSlice("type")
+
+		var olderThan time.Duration
+		if olderThanStr != "" {
+			d, err := parseAge(olderThanStr)
+			if err != nil {
+				return fmt.Errorf("invalid --older-than: %w", err)
+			}
+			olderThan = d
+		}
+
+		items, err := listCacheItems()
+		if err != nil {
+			return err
+		}
+
+		var removed int
+		var freed int64
+		for _, it := range items {
+			if len(types) > 0 && !slices.Contains(types, it.Type) {
+				continue
+			}
+			// Queued emails and changes haven't been sent yet, and the publish
+			// secret is in subscribers' URLs, so they only go when asked for
+			if (it.Type == "outbox" || it.Type == "sync" || it.Type == "publish") && !slices.Contains(types, it.Type) {
+				continue
+			}
+			if olderThan > 0 && time.Since(it.ModTime) < olderThan {
+				continue
+			}
+			if err := os.Remove(it.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %w", it.Name, err)
+			}
+			removed++
+			freed += it.Size
+		}
+
+		fmt.Printf("Removed %d file(s), freed %s\n", removed, output.FormatBytes(freed))
+		return nil
+	},
+}
+
+var cacheEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt local caches at rest",
+	Long: `Encrypt the offline cache (such as the search index) with AES-256-GCM.
+
+The key is stored in the OS keyring: the macOS Keychain, or the Secret Service
+(via secret-tool) on Linux. Where no keyring is available it falls back to
+~/.config/porteden/cache.key (mode 0600). Existing entries are migrated, and
+all future cache writes are encrypted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		migrated, err := cache.EnableEncryption()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cache encryption enabled (%d existing entries encrypted)\n", migrated)
+		return nil
+	},
+}
+
+// listCacheItems gathers every cache file: the offline cache directory plus
+// the update check timestamp
+func listCacheItems() ([]cacheItem, error) {
+	var items []cacheItem
+
+	if info, err := os.Stat(version.CheckCacheFile()); err == nil {
+		items = append(items, cacheItem{
+			Name:    filepath.Base(version.CheckCacheFile()),
+			Type:    "version-check",
+			Path:    version.CheckCacheFile(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	entries, err := cache.List()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := cache.Dir()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		items = append(items, cacheItem{
+			Name:      e.Name,
+			Type:      cacheEntryType(e.Name),
+			Path:      filepath.Join(dir, e.Name),
+			Size:      e.Size,
+			ModTime:   e.ModTime,
+			Encrypted: e.Encrypted,
+		})
+	}
+	return items, nil
+}
+
+// cacheEntryType classifies a cache file by its name
+func cacheEntryType(name string) string {
+	switch {
+	case strings.HasPrefix(name, "index-"):
+		return "index"
+	case strings.HasPrefix(name, "refs-"):
+		return "refs"
+	case strings.HasPrefix(name, "calendars-"):
+		return "calendars"
+	case strings.HasPrefix(name, "recipients-"):
+		return "recipients"
+	case strings.HasPrefix(name, outboxQueue+"-"):
+		return "outbox"
+	case strings.HasPrefix(name, syncQueue+"-"):
+		return "sync"
+	case strings.HasPrefix(name, "publish-"):
+		return "publish"
+	default:
+		return "other"
+	}
+}
+
+// parseAge parses durations with day and week units ("30d", "2w") as well as
+// anything time.ParseDuration accepts
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("expected a number before %q", suffix)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+func init() {
+	cachePurgeCmd.Flags().String("older-than", "", "Only delete entries older than this (e.g. 30d, 2w, 12h)")
+	cachePurgeCmd.Flags().StringSlice("type", nil, "Only delete entries of these types: index, refs, calendars, recipients, outbox, sync, version-check, other")
+
+	cacheCmd.AddCommand(cacheStatusCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+	cacheCmd.AddCommand(cacheEncryptCmd)
+}
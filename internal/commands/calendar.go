@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -9,9 +10,12 @@ import (
 	"time"
 
 	"github.com/porteden/cli/internal/api"
-	"github.com/porteden/cli/internal/apierr"
 	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/debug"
+	"github.com/porteden/cli/internal/i18n"
 	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/progress"
+	"github.com/porteden/cli/internal/refs"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +38,7 @@ var calendarsCmd = &cobra.Command{
 		if err != nil {
 			return formatError(err)
 		}
+		saveCalendarList(cmd, calendars.Data)
 
 		output.PrintWithOptions(calendars, getOutputFormat(cmd), output.PrintOptions{
 			Compact: IsCompactMode(),
@@ -51,36 +56,92 @@ Examples:
   porteden calendar events --today
   porteden calendar events --tomorrow
   porteden calendar events --week
+  porteden calendar events --week-of 2026-03-02
+  porteden calendar events --month=2026-03
+  porteden calendar events --quarter
   porteden calendar events --days 7
+  porteden calendar events --last-week
+  porteden calendar events --days -14
   porteden calendar events --from 2026-02-01 --to 2026-02-28
+  porteden calendar events --from 2026-02-01        # Everything from then on
+  porteden calendar events --to 2026-02-28          # From now until then
   porteden calendar events -q "budget review"
-  porteden calendar events -q "meeting" --attendees "finance@example.com,cfo@example.com"`,
+  porteden calendar events -q "meeting" --attendees "finance@example.com,cfo@example.com"
+  porteden calendar events --preset one-on-ones
+  porteden calendar events --days 30 --order desc
+  porteden calendar events --week --hide-declined
+  porteden calendar events --days 14 --organized-by-me --meetings-only
+  porteden calendar events --week --awaiting-my-response
+  porteden calendar events --days 14 --needs-rsvp-from cfo@example.com
+  porteden calendar events --week --since-token=          # Full listing plus a sync token
+  porteden calendar events --week --since-token <token>   # Only what changed since
+
+With --since-token, only events created or changed since the token was
+issued are listed, and deleted event IDs are reported. Repeat the same
+filters each time; the next token is printed on stderr (in meta with -j).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyPreset(cmd); err != nil {
+			return err
+		}
+
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
 
-		params, err := buildEventParams(cmd)
+		params, err := buildEventParams(cmd, client)
+		if err != nil {
+			return err
+		}
+		order, err := listOrder(cmd)
 		if err != nil {
 			return err
 		}
+		token, syncing, err := sinceToken(cmd)
+		if err != nil {
+			return err
+		}
+		params.SyncToken = token
+
+		if stream, _ := cmd.Flags().GetBool("stream"); stream {
+			s := newStreamer(cmd, client)
+			defer s.Close()
+			var seen []api.Event
+			defer func() {
+				rememberEvents(cmd, seen)
+				rememberListing(cmd, refs.KindEvent, eventIDs(seen))
+			}()
+			return formatError(pagingError(client.ForEachEventsPage(params, func(resp *api.EventsResponse) error {
+				resp.Events = filterEvents(cmd, resp.Events, resp.CurrentUserCalendarEmail)
+				s.Page(resp)
+				seen = append(seen, resp.Events...)
+				return s.Stop()
+			})))
+		}
 
 		fetchAll, _ := cmd.Flags().GetBool("all")
 		var events *api.EventsResponse
 
-		if fetchAll {
+		if fetchAll || syncing {
 			events, err = client.GetAllEvents(params)
 		} else {
 			events, err = client.GetEvents(params)
 		}
 		if err != nil {
-			return formatError(err)
+			return syncTokenError(err)
 		}
+		events.Events = filterEvents(cmd, events.Events, events.CurrentUserCalendarEmail)
+		sortEvents(events.Events, order)
+		rememberEvents(cmd, events.Events)
+		rememberListing(cmd, refs.KindEvent, eventIDs(events.Events))
 
 		output.PrintWithOptions(events, getOutputFormat(cmd), output.PrintOptions{
-			Compact: IsCompactMode(),
+			Compact:   IsCompactMode(),
+			Highlight: searchQuery(cmd),
 		})
+		if syncing && events.Meta != nil {
+			printSyncState(cmd, "events", events.DeletedIDs, events.Meta.NextSyncToken)
+		}
 		return nil
 	},
 }
@@ -88,15 +149,36 @@ Examples:
 var eventCmd = &cobra.Command{
 	Use:   "event <eventId>",
 	Short: "Get a single event",
-	Args:  cobra.ExactArgs(1),
+	Long: `Get a single event.
+
+Some calendar providers leave out attendees, attachments and recurrence rules
+unless asked. --expand requests them.
+
+Examples:
+  porteden calendar event <eventId>
+  porteden calendar event <eventId> --expand attendees,attachments,recurrence`,
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		eventID := args[0]
+		expand, _ := cmd.Flags().GetStringSlice("expand")
+		for i, x := range expand {
+			expand[i] = strings.ToLower(strings.TrimSpace(x))
+			switch expand[i] {
+			case api.ExpandAttendees, api.ExpandAttachments, api.ExpandRecurrence:
+			default:
+				return fmt.Errorf("invalid --expand %q: use attendees, attachments or recurrence", x)
+			}
+		}
+
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
+		eventID, err := eventIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
 
-		resp, err := client.GetEvent(eventID)
+		resp, err := client.GetEvent(eventID, expand...)
 		if err != nil {
 			return formatError(err)
 		}
@@ -111,13 +193,16 @@ var eventCmd = &cobra.Command{
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create an event",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyTemplate(cmd)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
 
-		calendarID, _ := cmd.Flags().GetInt64("calendar")
+		calendarRef, _ := cmd.Flags().GetString("calendar")
 		summary, _ := cmd.Flags().GetString("summary")
 		fromStr, _ := cmd.Flags().GetString("from")
 		toStr, _ := cmd.Flags().GetString("to")
@@ -133,10 +218,37 @@ var createCmd = &cobra.Command{
 			return fmt.Errorf("invalid start time: %w", err)
 		}
 
-		endTime, err := time.Parse(time.RFC3339, toStr)
+		duration, hasDuration, err := eventDuration(cmd)
 		if err != nil {
-			return fmt.Errorf("invalid end time: %w", err)
+			return err
+		}
+		if rule, ok, err := repeatRule(cmd, eventLocation(startTime)); err != nil {
+			return err
+		} else if ok {
+			recurrence = append(recurrence, rule)
+		}
+		reminders, err := reminderFlags(cmd)
+		if err != nil {
+			return err
+		}
+		var endTime time.Time
+		switch {
+		case hasDuration:
+			endTime = startTime.Add(duration)
+		case toStr == "":
+			return fmt.Errorf("give the end of the event with --to or --duration")
+		default:
+			if endTime, err = time.Parse(time.RFC3339, toStr); err != nil {
+				return fmt.Errorf("invalid end time: %w", err)
+			}
+		}
+
+		// Offline, a queued event's calendar name is resolved when it's replayed
+		calendarID, err := resolveCalendarID(cmd, client, calendarRef)
+		if useQueue, _ := cmd.Flags().GetBool("queue"); err != nil && !(useQueue && api.IsTransient(err)) {
+			return err
 		}
+		checkRecipientTypos(cmd, plainEmails(attendees))
 
 		req := api.CreateEventRequest{
 			CalendarID:  calendarID,
@@ -148,12 +260,22 @@ var createCmd = &cobra.Command{
 			IsAllDay:    allDay,
 			Attendees:   attendees,
 			Recurrence:  recurrence,
+			Reminders:   reminders,
+		}
+		if cmd.Flags().Changed("connection-id") {
+			connID, _ := cmd.Flags().GetInt64("connection-id")
+			req.ConnectionID = &connID
 		}
 
 		event, err := client.CreateEvent(req)
 		if err != nil {
-			return formatError(err)
+			queued := queuedCreate{Event: req}
+			if calendarID == 0 {
+				queued.Calendar = calendarRef
+			}
+			return queueFailedChange(cmd, syncCreate, "", syncSummary(syncCreate, "", summary), queued, err)
 		}
+		rememberRecipients(cmd, plainAddresses(attendees))
 
 		fmt.Printf("Event created successfully (ID: %s)\n", event.ID)
 		output.PrintWithOptions(event, getOutputFormat(cmd), output.PrintOptions{
@@ -168,20 +290,38 @@ var updateCmd = &cobra.Command{
 	Short: "Update an existing event",
 	Long: `Update an existing calendar event. All fields are optional.
 
+For an occurrence of a recurring event, only that occurrence changes unless
+--this-and-following or --entire-series is given. New times then move each
+occurrence by as much as the one given.
+
 Examples:
   porteden calendar update <eventId> --summary "New Title"
   porteden calendar update <eventId> --from "2026-02-10T10:00:00Z" --to "2026-02-10T11:00:00Z"
+  porteden calendar update <eventId> --duration 45m       # Keep the start, change the length
   porteden calendar update <eventId> --add-attendees "new@example.com"
-  porteden calendar update <eventId> --remove-attendees "old@example.com" --notify`,
-	Args: cobra.ExactArgs(1),
+  porteden calendar update <eventId> --remove-attendees "old@example.com" --notify
+  porteden calendar update <eventId> --reminder 10m,email:1d
+  porteden calendar update <eventId> --location "Room 4" --this-and-following`,
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		eventID := args[0]
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
+		eventID, err := eventIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
 
-		req := api.UpdateEventRequest{}
+		scope, err := seriesScope(cmd)
+		if err != nil {
+			return err
+		}
+		reminders, err := reminderFlags(cmd)
+		if err != nil {
+			return err
+		}
+		req := api.UpdateEventRequest{Scope: scope, Reminders: reminders}
 
 		if cmd.Flags().Changed("summary") {
 			req.Summary, _ = cmd.Flags().GetString("summary")
@@ -208,12 +348,28 @@ Examples:
 			}
 			req.To = &t
 		}
+		if duration, ok, err := eventDuration(cmd); err != nil {
+			return err
+		} else if ok {
+			// Without a new start, the length counts from the current one
+			start := req.From
+			if start == nil {
+				resp, err := client.GetEvent(eventID)
+				if err != nil {
+					return formatError(err)
+				}
+				start = &resp.Event.StartUtc
+			}
+			end := start.Add(duration)
+			req.To = &end
+		}
 		if cmd.Flags().Changed("all-day") {
 			allDay, _ := cmd.Flags().GetBool("all-day")
 			req.IsAllDay = &allDay
 		}
 		if cmd.Flags().Changed("add-attendees") {
 			req.AddAttendees, _ = cmd.Flags().GetStringSlice("add-attendees")
+			checkRecipientTypos(cmd, plainEmails(req.AddAttendees))
 		}
 		if cmd.Flags().Changed("remove-attendees") {
 			req.RemoveAttendees, _ = cmd.Flags().GetStringSlice("remove-attendees")
@@ -225,8 +381,9 @@ Examples:
 
 		event, err := client.UpdateEvent(eventID, req)
 		if err != nil {
-			return formatError(err)
+			return queueFailedChange(cmd, syncUpdate, eventID, syncSummary(syncUpdate, eventID, ""), req, err)
 		}
+		rememberRecipients(cmd, plainAddresses(req.AddAttendees))
 
 		fmt.Printf("Event updated successfully (ID: %s)\n", event.ID)
 		output.PrintWithOptions(event, getOutputFormat(cmd), output.PrintOptions{
@@ -241,12 +398,17 @@ var deleteCmd = &cobra.Command{
 	Short: "Delete an event",
 	Long: `Delete a calendar event.
 
+Without an event ID on a terminal, pick any number of events to delete at once.
+For an occurrence of a recurring event, only that occurrence is deleted unless
+--this-and-following or --entire-series is given.
+
 Examples:
   porteden calendar delete <eventId>
-  porteden calendar delete <eventId> --no-notify`,
-	Args: cobra.ExactArgs(1),
+  porteden calendar delete <eventId> --no-notify
+  porteden calendar delete <eventId> --entire-series
+  porteden calendar delete                      # Choose events interactively`,
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		eventID := args[0]
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
@@ -254,10 +416,34 @@ Examples:
 
 		noNotify, _ := cmd.Flags().GetBool("no-notify")
 		notifyAttendees := !noNotify
+		scope, err := seriesScope(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			items, err := eventChoices(client)
+			if err != nil {
+				return err
+			}
+			chosen, err := pickMany(cmd, "Delete events>", items)
+			if err != nil {
+				return err
+			}
+			return runBulk("Delete", "event", chosen, func(id string) error {
+				_, err := client.DeleteEvent(id, notifyAttendees, scope)
+				return err
+			})
+		}
 
-		resp, err := client.DeleteEvent(eventID, notifyAttendees)
+		eventID, err := eventIDArg(cmd, client, args)
 		if err != nil {
-			return formatError(err)
+			return err
+		}
+
+		resp, err := client.DeleteEvent(eventID, notifyAttendees, scope)
+		if err != nil {
+			return queueFailedChange(cmd, syncDelete, eventID, syncSummary(syncDelete, eventID, ""), queuedDelete{NotifyAttendees: notifyAttendees, Scope: scope}, err)
 		}
 
 		fmt.Printf("Event deleted: %s\n", resp.Message)
@@ -265,17 +451,107 @@ Examples:
 	},
 }
 
+// addCreateFlags registers the flags of calendar create, which calendar
+// template apply shares
+func addCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("calendar", "", "Calendar ID or name (required)")
+	cmd.Flags().String("summary", "", "Event title (required)")
+	cmd.Flags().String("from", "", "Start time (required)")
+	cmd.Flags().String("to", "", "End time (or give --duration)")
+	cmd.Flags().String("duration", "", durationUsage)
+	cmd.Flags().String("description", "", "Event description")
+	cmd.Flags().String("location", "", "Event location")
+	cmd.Flags().StringSlice("attendees", nil, "Attendee emails")
+	_ = cmd.RegisterFlagCompletionFunc("attendees", completeRecipients)
+	cmd.Flags().Bool("all-day", false, "Create all-day event")
+	cmd.Flags().StringSlice("recurrence", nil, "RRULE recurrence patterns")
+	addRepeatFlags(cmd)
+	addReminderFlags(cmd)
+	cmd.Flags().Int64("connection-id", 0, "Specific connection to create the event with")
+	cmd.Flags().Bool("queue", false, queueFlagUsage)
+	_ = cmd.MarkFlagRequired("calendar")
+	_ = cmd.MarkFlagRequired("summary")
+	_ = cmd.MarkFlagRequired("from")
+}
+
+// durationUsage describes --duration on calendar create and update
+const durationUsage = "Length of the event instead of --to, e.g. 45m, 1h30m or 2d"
+
+// eventDuration returns the --duration of an event being created or
+// updated, which replaces --to. Days and weeks ("2d") are accepted too.
+func eventDuration(cmd *cobra.Command) (time.Duration, bool, error) {
+	if !cmd.Flags().Changed("duration") {
+		return 0, false, nil
+	}
+	if cmd.Flags().Changed("to") {
+		return 0, false, fmt.Errorf("--duration cannot be combined with --to")
+	}
+	value, _ := cmd.Flags().GetString("duration")
+	d, err := parseAge(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid --duration %q: %w", value, err)
+	}
+	if d <= 0 {
+		return 0, false, fmt.Errorf("--duration must be positive")
+	}
+	return d, true, nil
+}
+
+// addSeriesScopeFlags registers the flags that pick which occurrences of a
+// recurring event an update or delete covers
+func addSeriesScopeFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().Bool("this-instance", false, verb+" only this occurrence of a recurring event (default)")
+	cmd.Flags().Bool("this-and-following", false, verb+" this occurrence and every one after it")
+	cmd.Flags().Bool("entire-series", false, verb+" every occurrence of the recurring event")
+}
+
+// seriesScope returns the API scope chosen with --this-instance,
+// --this-and-following or --entire-series, or "" for the API's default of
+// just the given occurrence
+func seriesScope(cmd *cobra.Command) (string, error) {
+	scope := ""
+	for flag, value := range map[string]string{
+		"this-instance":      api.ScopeThisInstance,
+		"this-and-following": api.ScopeThisAndFollowing,
+		"entire-series":      api.ScopeEntireSeries,
+	} {
+		if set, _ := cmd.Flags().GetBool(flag); !set {
+			continue
+		}
+		if scope != "" {
+			return "", fmt.Errorf("use only one of --this-instance, --this-and-following and --entire-series")
+		}
+		scope = value
+	}
+	return scope, nil
+}
+
+// respondVerbs phrase the bulk confirmation for each response status
+var respondVerbs = map[string]string{
+	"accepted":  "Accept",
+	"declined":  "Decline",
+	"tentative": "Tentatively accept",
+}
+
 var respondCmd = &cobra.Command{
 	Use:   "respond <eventId> <status>",
 	Short: "Respond to an event invitation",
 	Long: `Respond to an event invitation with one of:
   - accepted
   - declined
-  - tentative`,
-	Args: cobra.ExactArgs(2),
+  - tentative
+
+Without an event ID on a terminal, pick any number of events to respond to at once.
+--message sends a note to the organizer along with the response.
+
+Examples:
+  porteden calendar respond <eventId> accepted
+  porteden calendar respond <eventId> tentative --message "Joining 10 minutes late"
+  porteden calendar respond declined            # Choose events interactively`,
+	Args: pickableArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		eventID := args[0]
-		status := args[1]
+		status := args[len(args)-1]
+		message, _ := cmd.Flags().GetString("message")
 
 		// Validate status
 		validStatuses := map[string]bool{
@@ -291,8 +567,27 @@ var respondCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if len(args) == 1 {
+			items, err := eventChoices(client)
+			if err != nil {
+				return err
+			}
+			chosen, err := pickMany(cmd, "Respond to events>", items)
+			if err != nil {
+				return err
+			}
+			return runBulk(respondVerbs[status], "event", chosen, func(id string) error {
+				_, err := client.RespondToEvent(id, status, message)
+				return err
+			})
+		}
+
+		eventID, err := eventIDArg(cmd, client, args[:1])
+		if err != nil {
+			return err
+		}
 
-		event, err := client.RespondToEvent(eventID, status)
+		event, err := client.RespondToEvent(eventID, status, message)
 		if err != nil {
 			return formatError(err)
 		}
@@ -314,7 +609,8 @@ Examples:
   porteden calendar freebusy --today
   porteden calendar freebusy --week
   porteden calendar freebusy --from 2026-02-05 --to 2026-02-12
-  porteden calendar freebusy --week --calendars 123,456`,
+  porteden calendar freebusy --week --calendars 123,456
+  porteden calendar freebusy --week --skip-holidays US`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getClient(cmd)
 		if err != nil {
@@ -322,12 +618,20 @@ Examples:
 		}
 
 		// Reuse buildEventParams for time range parsing
-		eventParams, err := buildEventParams(cmd)
+		eventParams, err := buildEventParams(cmd, client)
 		if err != nil {
 			return err
 		}
+		if eventParams.To.IsZero() {
+			return fmt.Errorf("free/busy needs the end of the range: add --to")
+		}
 
-		calendars, _ := cmd.Flags().GetString("calendars")
+		calendarRefs, _ := cmd.Flags().GetString("calendars")
+		calendars, err := resolveCalendarList(cmd, client, calendarRefs)
+		if err != nil {
+			return err
+		}
+		region, _ := cmd.Flags().GetString("skip-holidays")
 
 		params := api.FreeBusyParams{
 			From:      eventParams.From,
@@ -339,6 +643,11 @@ Examples:
 		if err != nil {
 			return formatError(err)
 		}
+		if region != "" {
+			if err := addHolidayBlocks(resp, region, params.From, params.To); err != nil {
+				return err
+			}
+		}
 
 		output.PrintWithOptions(resp, getOutputFormat(cmd), output.PrintOptions{
 			Compact: IsCompactMode(),
@@ -387,17 +696,30 @@ Examples:
 			Offset: offset,
 		}
 
+		if stream, _ := cmd.Flags().GetBool("stream"); stream {
+			s := newStreamer(cmd, client)
+			defer s.Close()
+			var seen []api.Event
+			defer func() { rememberEvents(cmd, seen) }()
+			return formatError(pagingError(client.ForEachEventsByContactPage(params, func(resp *api.EventsResponse) error {
+				s.Page(resp)
+				seen = append(seen, resp.Events...)
+				return s.Stop()
+			})))
+		}
+
 		fetchAll, _ := cmd.Flags().GetBool("all")
 		var events *api.EventsResponse
 
 		if fetchAll {
-			events, err = getAllEventsByContact(client, params)
+			events, err = client.GetAllEventsByContact(params)
 		} else {
 			events, err = client.GetEventsByContact(params)
 		}
 		if err != nil {
 			return formatError(err)
 		}
+		rememberEvents(cmd, events.Events)
 
 		output.PrintWithOptions(events, getOutputFormat(cmd), output.PrintOptions{
 			Compact: IsCompactMode(),
@@ -406,90 +728,63 @@ Examples:
 	},
 }
 
-// getAllEventsByContact fetches all events by contact by auto-paginating
-func getAllEventsByContact(client *api.Client, params api.EventsByContactParams) (*api.EventsResponse, error) {
-	var allEvents []api.Event
-	offset := 0
-	var accessInfo string
-	var calEmail string
-
-	for {
-		params.Offset = offset
-		resp, err := client.GetEventsByContact(params)
-		if err != nil {
-			return nil, err
-		}
-
-		allEvents = append(allEvents, resp.Events...)
-		accessInfo = resp.AccessInfo
-		calEmail = resp.CurrentUserCalendarEmail
-
-		if resp.Meta == nil || !resp.Meta.HasMore {
-			finalMeta := &api.Meta{
-				Count:      len(allEvents),
-				TotalCount: len(allEvents),
-			}
-			if resp.Meta != nil {
-				finalMeta.From = resp.Meta.From
-				finalMeta.To = resp.Meta.To
-				finalMeta.Timestamp = resp.Meta.Timestamp
-			}
-			return &api.EventsResponse{
-				RequestID:                resp.RequestID,
-				Events:                   allEvents,
-				Meta:                     finalMeta,
-				AccessInfo:               accessInfo,
-				CurrentUserCalendarEmail: calEmail,
-			}, nil
-		}
-
-		offset += resp.Meta.Count
-	}
-}
-
 func init() {
-	// Time filter flags (used by events and freebusy)
-	for _, cmd := range []*cobra.Command{eventsCmd, freebusyCmd} {
+	// Time filter flags (used by events, freebusy and count)
+	for _, cmd := range []*cobra.Command{eventsCmd, freebusyCmd, calendarCountCmd, calendarDeleteManyCmd, calendarFreeCmd} {
 		cmd.Flags().Bool("today", false, "Show today's events")
 		cmd.Flags().Bool("tomorrow", false, "Show tomorrow's events")
-		cmd.Flags().Bool("week", false, "Show this week's events")
-		cmd.Flags().Int("days", 0, "Show events for the next N days")
-		cmd.Flags().String("from", "", "Start date (YYYY-MM-DD or datetime)")
-		cmd.Flags().String("to", "", "End date (YYYY-MM-DD or datetime)")
+		cmd.Flags().Bool("yesterday", false, "Show yesterday's events")
+		cmd.Flags().Bool("week", false, "Show this week's events (see calendar.week_starts)")
+		cmd.Flags().Bool("last-week", false, "Show last week's events")
+		cmd.Flags().String("week-of", "", "Show the week containing this date (YYYY-MM-DD)")
+		cmd.Flags().String("month", "", "Show a month: --month for this one, --month=YYYY-MM for another")
+		cmd.Flags().String("quarter", "", "Show a quarter: --quarter for this one, --quarter=Qn-YYYY for another")
+		cmd.Flags().Lookup("month").NoOptDefVal = currentPeriod
+		cmd.Flags().Lookup("quarter").NoOptDefVal = currentPeriod
+		cmd.Args = periodArgs
+		cmd.Flags().Int("days", 0, "Show events for the next N days (negative: the last N days)")
+		cmd.Flags().String("from", "", "Start date (YYYY-MM-DD or datetime); without --to the range has no end")
+		cmd.Flags().String("to", "", "End date (YYYY-MM-DD or datetime); without --from the range starts now")
+	}
+	for _, cmd := range []*cobra.Command{eventsCmd, freebusyCmd} {
 		cmd.Flags().Int("limit", 50, "Maximum events to return")
 		cmd.Flags().Int("offset", 0, "Skip first N events (pagination)")
 		cmd.Flags().Bool("all", false, "Fetch all pages")
 	}
 
 	// Events-specific flags
-	eventsCmd.Flags().Int64("calendar", 0, "Filter by calendar ID")
+	eventsCmd.Flags().String("calendar", "", "Filter by calendar (ID or name)")
+	eventsCmd.Flags().Int64("connection-id", 0, "Only show events from this connected account")
+	eventsCmd.Flags().String("since-token", "", sinceTokenUsage)
+	eventsCmd.Flags().Bool("stream", false, "Fetch all pages, printing each page as it arrives (NDJSON with --json)")
 	eventsCmd.Flags().Bool("include-cancelled", false, "Include cancelled events (default: false)")
 	eventsCmd.Flags().StringP("query", "q", "", "Keyword search in title, description, location")
 	eventsCmd.Flags().String("attendees", "", "Comma-separated attendee emails to filter by")
+	eventsCmd.Flags().Bool("meetings-only", false, "Only show events with other attendees")
+	eventsCmd.Flags().Bool("organized-by-me", false, "Only show events you organize")
+	eventsCmd.Flags().Bool("awaiting-my-response", false, "Only show invitations you haven't replied to")
+	eventsCmd.Flags().String("needs-rsvp-from", "", "Only show events where this attendee hasn't replied")
+	eventsCmd.Flags().Bool("hide-declined", false, "Leave out events you declined (default from calendar.hide_declined)")
+	eventsCmd.Flags().String("preset", "", "Apply the filters.<name> preset from the config file")
+	eventsCmd.Flags().String("order", orderAsc, "Sort by start time: asc (soonest first) or desc")
+	_ = eventsCmd.RegisterFlagCompletionFunc("preset", presetNames)
 
 	// Freebusy-specific flags
-	freebusyCmd.Flags().String("calendars", "", "Comma-separated calendar IDs")
+	freebusyCmd.Flags().String("calendars", "", "Comma-separated calendar IDs or names")
+	freebusyCmd.Flags().String("skip-holidays", "", "Mark public holidays in this region (US, GB, CA, DE, FR) as busy")
+	_ = freebusyCmd.RegisterFlagCompletionFunc("skip-holidays", holidayRegions)
 
 	// By-contact flags (no time filters in v2 API)
 	byContactCmd.Flags().String("name", "", "Filter by contact name (partial match, case-insensitive)")
 	byContactCmd.Flags().Int("limit", 50, "Maximum events to return")
 	byContactCmd.Flags().Int("offset", 0, "Skip first N events (pagination)")
 	byContactCmd.Flags().Bool("all", false, "Fetch all pages")
+	byContactCmd.Flags().Bool("stream", false, "Fetch all pages, printing each page as it arrives (NDJSON with --json)")
 
 	// Create flags
-	createCmd.Flags().Int64("calendar", 0, "Calendar ID (required)")
-	createCmd.Flags().String("summary", "", "Event title (required)")
-	createCmd.Flags().String("from", "", "Start time (required)")
-	createCmd.Flags().String("to", "", "End time (required)")
-	createCmd.Flags().String("description", "", "Event description")
-	createCmd.Flags().String("location", "", "Event location")
-	createCmd.Flags().StringSlice("attendees", nil, "Attendee emails")
-	createCmd.Flags().Bool("all-day", false, "Create all-day event")
-	createCmd.Flags().StringSlice("recurrence", nil, "RRULE recurrence patterns")
-	_ = createCmd.MarkFlagRequired("calendar")
-	_ = createCmd.MarkFlagRequired("summary")
-	_ = createCmd.MarkFlagRequired("from")
-	_ = createCmd.MarkFlagRequired("to")
+	addCreateFlags(createCmd)
+	createCmd.Flags().String("template", "", "Fill in unset flags from this event template")
+	_ = createCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
 
 	// Update flags
 	updateCmd.Flags().String("summary", "", "New event title")
@@ -497,13 +792,29 @@ func init() {
 	updateCmd.Flags().String("location", "", "New location")
 	updateCmd.Flags().String("from", "", "New start time (RFC3339)")
 	updateCmd.Flags().String("to", "", "New end time (RFC3339)")
+	updateCmd.Flags().String("duration", "", durationUsage)
 	updateCmd.Flags().Bool("all-day", false, "Set as all-day event")
 	updateCmd.Flags().StringSlice("add-attendees", nil, "Emails to add as attendees")
+	_ = updateCmd.RegisterFlagCompletionFunc("add-attendees", completeRecipients)
 	updateCmd.Flags().StringSlice("remove-attendees", nil, "Emails to remove from attendees")
 	updateCmd.Flags().Bool("notify", true, "Send notifications to attendees")
+	addReminderFlags(updateCmd)
+	updateCmd.Flags().Bool("default-reminders", false, "Use the calendar's default reminders again")
+	updateCmd.Flags().Bool("queue", false, queueFlagUsage)
+	addSeriesScopeFlags(updateCmd, "Change")
 
 	// Delete flags
 	deleteCmd.Flags().Bool("no-notify", false, "Don't send cancellation notifications")
+	deleteCmd.Flags().Bool("queue", false, queueFlagUsage)
+	addSeriesScopeFlags(deleteCmd, "Delete")
+
+	// Event flags
+	eventCmd.Flags().StringSlice("expand", nil, "Extended fields to request: attendees, attachments, recurrence")
+
+	// Respond flags
+	respondCmd.Flags().String("message", "", "Note to the organizer sent with the response")
+
+	calendarCmd.PersistentFlags().String("as", "", "Act on the calendars of someone who delegated access to you (their email)")
 
 	calendarCmd.AddCommand(calendarsCmd)
 	calendarCmd.AddCommand(eventsCmd)
@@ -516,30 +827,64 @@ func init() {
 	calendarCmd.AddCommand(freebusyCmd)
 }
 
-// Helper function to get API client.
+// delegateAs is the calendar owner named by --as, for error messages
+var delegateAs string
+
+// Helper function to get API client, acting for the --as calendar owner when set.
 // If not authenticated and running in an interactive terminal, offers to run the setup wizard.
 func getClient(cmd *cobra.Command) (*api.Client, error) {
+	as, _ := cmd.Flags().GetString("as")
+	if as != "" && !strings.Contains(as, "@") {
+		return nil, fmt.Errorf("invalid --as %q: use the email address of the calendar owner", as)
+	}
+	client, err := getAccountClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if as != "" {
+		delegateAs = as
+		client.WithDelegate(as)
+	}
+	return client, nil
+}
+
+// getAccountClient returns a client for the signed-in account
+func getAccountClient(cmd *cobra.Command) (*api.Client, error) {
+	if isDemo(cmd) {
+		return newDemoClient(), nil
+	}
+
 	profileName := getProfile(cmd)
+	if client, ok := newOAuthClient(profileName); ok {
+		return client, nil
+	}
 	apiKey, err := auth.GetAPIKey(profileName)
 	if err == nil {
-		return api.NewClient(apiKey), nil
+		client := newAPIClient(apiKey)
+		// A stored key that gets rejected can be replaced without starting over
+		if os.Getenv("PE_API_KEY") == "" {
+			warnKeyExpiry(profileName, client)
+			if auth.IsInteractiveTerminal() {
+				client.WithReauth(func() (string, error) { return reauthenticate(profileName) })
+			}
+		}
+		return client, nil
 	}
 
 	// Non-interactive: return plain error
 	if !auth.IsInteractiveTerminal() {
-		return nil, fmt.Errorf("not authenticated. Run 'porteden auth login' to authenticate")
+		return nil, fmt.Errorf("%w. Run 'porteden auth login' to authenticate", errNotAuthenticated)
 	}
 
 	// Interactive: offer setup wizard
 	output.PrintBanner()
-	fmt.Println("  No account configured yet.")
-	fmt.Print("  Would you like to set up now? " + output.ColorGray("[Y/n]: "))
+	fmt.Println("  " + i18n.T("No account configured yet."))
+	fmt.Print("  " + i18n.T("Would you like to set up now?") + " " + output.ColorGray(i18n.T("[Y/n]")+": "))
 
 	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
-	choice := strings.TrimSpace(strings.ToLower(line))
-	if choice != "" && choice != "y" && choice != "yes" {
+	if choice := strings.TrimSpace(line); choice != "" && !i18n.Yes(choice) {
 		fmt.Println()
-		return nil, fmt.Errorf("not authenticated. Run 'porteden auth login' to authenticate")
+		return nil, fmt.Errorf("%w. Run 'porteden auth login' to authenticate", errNotAuthenticated)
 	}
 	fmt.Println()
 
@@ -548,16 +893,84 @@ func getClient(cmd *cobra.Command) (*api.Client, error) {
 		return nil, err
 	}
 
-	wizardKey, err := runLoginWizard(profileName, "")
+	wizardKey, err := runLoginWizard(profileName, "", "", false, false)
 	if err != nil {
 		return nil, err
 	}
 
-	return api.NewClient(wizardKey), nil
+	return newAPIClient(wizardKey), nil
+}
+
+// newOAuthClient returns a client using the profile's refresh token from
+// 'auth login --oauth', if it has one. PE_API_KEY takes precedence.
+func newOAuthClient(profileName string) (*api.Client, bool) {
+	if os.Getenv("PE_API_KEY") != "" {
+		return nil, false
+	}
+	refreshToken, err := auth.GetRefreshToken(profileName)
+	if err != nil {
+		return nil, false
+	}
+	return newAPIClient("").WithRefreshToken(refreshToken, func(rotated string) {
+		if err := auth.StoreRefreshToken(rotated, profileName); err != nil {
+			debug.Log("Failed to save rotated refresh token: %v", err)
+		}
+	}), true
+}
+
+// reauthenticate offers to sign in again after the API rejected the stored
+// key, returning the new key
+func reauthenticate(profileName string) (string, error) {
+	progress.Clear()
+	fmt.Fprintln(os.Stderr, output.ColorYellow(i18n.T("The API key for profile '%s' was rejected.", profileName)))
+	fmt.Fprint(os.Stderr, i18n.T("Sign in again and retry?")+" "+output.ColorGray(i18n.T("[Y/n]")+": "))
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if choice := strings.TrimSpace(line); choice != "" && !i18n.Yes(choice) {
+		return "", errors.New("declined")
+	}
+	fmt.Fprintln(os.Stderr)
+	return runLoginWizard(profileName, "", "", false, false)
+}
+
+// newAPIClient creates a client with the global request options applied
+func newAPIClient(apiKey string) *api.Client {
+	return api.NewClient(apiKey).
+		WithMaxWait(maxWait).
+		WithUserAgentSuffix(uaSuffix).
+		WithWaitFunc(retryCountdown).
+		WithPageProgress(showPageProgress)
+}
+
+// showPageProgress displays a page counter on stderr during --all fetches, with
+// a progress bar when the API reports a total
+func showPageProgress(p api.PageProgress) {
+	if p.Done {
+		progress.Clear()
+		return
+	}
+	if p.Total > p.Fetched {
+		status := fmt.Sprintf("Fetching %s %s %d/%d", p.Resource, progress.Bar(p.Fetched, p.Total, 20), p.Fetched, p.Total)
+		if p.Page > 0 {
+			status += fmt.Sprintf(" (page %d)", p.Page)
+		}
+		progress.Status(status)
+		return
+	}
+	progress.Status(fmt.Sprintf("Fetching %s... page %d, %d so far", p.Resource, p.Page, p.Fetched))
+}
+
+// retryCountdown shows the remaining wait before a retry on interactive terminals
+func retryCountdown(ctx context.Context, d time.Duration, statusCode int) error {
+	format := "Request failed, retrying in %s..."
+	if statusCode == 429 {
+		format = "Rate limited by the API, retrying in %s..."
+	}
+	return progress.Countdown(ctx, d, format)
 }
 
 // Helper function to build event parameters from flags
-func buildEventParams(cmd *cobra.Command) (api.EventParams, error) {
+func buildEventParams(cmd *cobra.Command, client *api.Client) (api.EventParams, error) {
 	params := api.EventParams{
 		Limit: 50,
 	}
@@ -574,9 +987,17 @@ func buildEventParams(cmd *cobra.Command) (api.EventParams, error) {
 
 	// Get calendar ID (only supported by events endpoint)
 	if cmd.Flags().Changed("calendar") {
-		if calID, _ := cmd.Flags().GetInt64("calendar"); calID > 0 {
-			params.CalendarID = calID
+		calendarRef, _ := cmd.Flags().GetString("calendar")
+		calID, err := resolveCalendarID(cmd, client, calendarRef)
+		if err != nil {
+			return params, err
 		}
+		params.CalendarID = calID
+	}
+
+	// Get connection ID (only for events endpoint)
+	if cmd.Flags().Changed("connection-id") {
+		params.ConnectionID, _ = cmd.Flags().GetInt64("connection-id")
 	}
 
 	// Get includeCancelled (only for events endpoint)
@@ -598,7 +1019,12 @@ func buildEventParams(cmd *cobra.Command) (api.EventParams, error) {
 	now := time.Now()
 	today, _ := cmd.Flags().GetBool("today")
 	tomorrow, _ := cmd.Flags().GetBool("tomorrow")
+	yesterday, _ := cmd.Flags().GetBool("yesterday")
 	week, _ := cmd.Flags().GetBool("week")
+	lastWeek, _ := cmd.Flags().GetBool("last-week")
+	weekOf, _ := cmd.Flags().GetString("week-of")
+	month, _ := cmd.Flags().GetString("month")
+	quarter, _ := cmd.Flags().GetString("quarter")
 	days, _ := cmd.Flags().GetInt("days")
 	fromStr, _ := cmd.Flags().GetString("from")
 	toStr, _ := cmd.Flags().GetString("to")
@@ -614,21 +1040,62 @@ func buildEventParams(cmd *cobra.Command) (api.EventParams, error) {
 	} else if tomorrow {
 		params.From = startOfDay(now.AddDate(0, 0, 1))
 		params.To = params.From.AddDate(0, 0, 1)
+	} else if yesterday {
+		params.From = startOfDay(now.AddDate(0, 0, -1))
+		params.To = startOfDay(now)
 	} else if week {
-		params.From = startOfDay(now)
+		params.From = startOfWeek(startOfDay(now))
 		params.To = params.From.AddDate(0, 0, 7)
+	} else if lastWeek {
+		params.To = startOfWeek(startOfDay(now))
+		params.From = params.To.AddDate(0, 0, -7)
+	} else if weekOf != "" {
+		day, err := time.ParseInLocation("2006-01-02", weekOf, now.Location())
+		if err != nil {
+			return params, fmt.Errorf("invalid --week-of %q: use YYYY-MM-DD", weekOf)
+		}
+		params.From = startOfWeek(day)
+		params.To = params.From.AddDate(0, 0, 7)
+	} else if month != "" {
+		var err error
+		if params.From, err = monthStart(month, now); err != nil {
+			return params, err
+		}
+		params.To = params.From.AddDate(0, 1, 0)
+	} else if quarter != "" {
+		var err error
+		if params.From, err = quarterStart(quarter, now); err != nil {
+			return params, err
+		}
+		params.To = params.From.AddDate(0, 3, 0)
 	} else if days > 0 {
 		params.From = startOfDay(now)
 		params.To = params.From.AddDate(0, 0, days)
-	} else if fromStr != "" && toStr != "" {
+	} else if days < 0 {
+		// The last N days, ending with today
+		params.To = startOfDay(now).AddDate(0, 0, 1)
+		params.From = params.To.AddDate(0, 0, days)
+	} else if fromStr != "" || toStr != "" {
+		// A range given by one end is open: without --from it starts now,
+		// without --to it has no end
+		params.From = now
 		var err error
-		params.From, err = parseDateTime(fromStr)
-		if err != nil {
-			return params, fmt.Errorf("invalid from date: %w", err)
+		if fromStr != "" {
+			if params.From, err = parseDateTime(fromStr); err != nil {
+				return params, fmt.Errorf("invalid from date: %w", err)
+			}
 		}
-		params.To, err = parseDateTime(toStr)
-		if err != nil {
-			return params, fmt.Errorf("invalid to date: %w", err)
+		if toStr != "" {
+			if params.To, err = parseDateTime(toStr); err != nil {
+				return params, fmt.Errorf("invalid to date: %w", err)
+			}
+		}
+		if fromStr != "" && toStr != "" {
+			if err := checkDateRange("from", "to", fromStr, toStr, params.From, params.To); err != nil {
+				return params, err
+			}
+		} else if toStr != "" && !params.To.After(params.From) {
+			return params, fmt.Errorf("--to %s has already passed: add --from to list events before it", toStr)
 		}
 	} else {
 		// Default: next 7 days
@@ -639,25 +1106,54 @@ func buildEventParams(cmd *cobra.Command) (api.EventParams, error) {
 	return params, nil
 }
 
-// Helper function to parse date/datetime strings
-func parseDateTime(s string) (time.Time, error) {
-	// Try RFC3339 first (full datetime)
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
-		return t, nil
+// startOfWeek returns the first day of the week containing day: Monday (as
+// in ISO weeks) or Sunday, following calendar.week_starts
+func startOfWeek(day time.Time) time.Time {
+	first := time.Monday
+	if userConfig.String("calendar.week_starts") == "sunday" {
+		first = time.Sunday
 	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) - int(first) + 7) % 7))
+}
+
+// currentPeriod is the value of --month and --quarter given without one
+const currentPeriod = "current"
 
-	// Try date only (YYYY-MM-DD)
-	if t, err := time.Parse("2006-01-02", s); err == nil {
-		return t, nil
+// periodArgs rejects positional arguments, pointing out that a --month or
+// --quarter value has to be joined with "=" since the value is optional
+func periodArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	for _, name := range []string{"month", "quarter"} {
+		if v, _ := cmd.Flags().GetString(name); v == currentPeriod && cmd.Flags().Changed(name) {
+			return fmt.Errorf("unexpected argument %q: write --%s=%s to pick a %s", args[0], name, args[0], name)
+		}
 	}
+	return fmt.Errorf("unexpected argument %q", args[0])
+}
 
-	return time.Time{}, fmt.Errorf("invalid date format (use YYYY-MM-DD or RFC3339)")
+// monthStart returns the first day of a month given as YYYY-MM, or of the
+// month containing now for currentPeriod
+func monthStart(s string, now time.Time) (time.Time, error) {
+	if s == currentPeriod {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), nil
+	}
+	t, err := time.ParseInLocation("2006-01", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --month %q: use YYYY-MM", s)
+	}
+	return t, nil
 }
 
-// Helper function to format API errors
-func formatError(err error) error {
-	if apiErr, ok := err.(*apierr.APIError); ok {
-		return errors.New(apierr.UserFriendlyError(apiErr))
+// quarterStart returns the first day of a quarter given as Qn-YYYY, or of
+// the quarter containing now for currentPeriod
+func quarterStart(s string, now time.Time) (time.Time, error) {
+	q, year := (int(now.Month())-1)/3+1, now.Year()
+	if s != currentPeriod {
+		if _, err := fmt.Sscanf(strings.ToUpper(s), "Q%d-%d", &q, &year); err != nil || q < 1 || q > 4 || year < 1000 {
+			return time.Time{}, fmt.Errorf("invalid --quarter %q: use Qn-YYYY, e.g. Q2-2026", s)
+		}
 	}
-	return err
+	return time.Date(year, time.Month(3*q-2), 1, 0, 0, 0, 0, now.Location()), nil
 }
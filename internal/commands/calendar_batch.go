@@ -0,0 +1,384 @@
+package commands
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// batchColumns are the recognized CSV headers; the first three are required
+var batchColumns = []string{"summary", "start", "end", "attendees", "location", "recurrence", "description", "calendar", "all_day"}
+
+// batchRow is one validated CSV row, ready to create
+type batchRow struct {
+	Line int                    `json:"row"`
+	Req  api.CreateEventRequest `json:"event"`
+	// Calendar is a calendar name, resolved to Req.CalendarID before creating
+	Calendar string `json:"calendar,omitempty"`
+}
+
+// batchResult is the outcome of creating one row
+type batchResult struct {
+	Row     int    `json:"row"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"` // created or failed
+	EventID string `json:"eventId,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+var createBatchCmd = &cobra.Command{
+	Use:   "create-batch <file.csv>",
+	Short: "Create events from a CSV file",
+	Long: `Create calendar events from a CSV file with a header row.
+
+Columns (case-insensitive, any order):
+  summary      Event title (required)
+  start        Start time (required)
+  end          End time, or a duration after start such as 30m or 1h30m (required)
+  attendees    Attendee emails separated by ";" or spaces
+  location     Event location
+  recurrence   RRULE lines separated by "|", e.g. RRULE:FREQ=WEEKLY;COUNT=4
+  description  Event description
+  calendar     Calendar ID or name (defaults to --calendar, then the primary calendar)
+  all_day      true for an all-day event; start and end are then dates
+
+Times are RFC3339 (2026-02-10T15:00:00Z) or local "2026-02-10 15:00" in the
+output timezone. Every row is validated before anything is created, and the
+events are previewed for confirmation. Use "-" to read the CSV from stdin.
+
+Examples:
+  porteden calendar create-batch events.csv --dry-run
+  porteden calendar create-batch events.csv --calendar 12345 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		calendarRef, _ := cmd.Flags().GetString("calendar")
+		jsonOutput := getOutputFormat(cmd) == output.FormatJSON
+
+		// From here on, errors are about the file's contents, not the invocation
+		cmd.SilenceUsage = true
+
+		var in io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open CSV: %w", err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		rows, err := parseBatchCSV(in)
+		if err != nil {
+			return err
+		}
+
+		if dryRun && jsonOutput {
+			output.PrintWithOptions(rows, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		if !jsonOutput {
+			printBatchPreview(rows)
+		}
+		if dryRun {
+			fmt.Printf("\nDry run: %d event(s) valid, nothing created.\n", len(rows))
+			return nil
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		if err := applyRateFlag(cmd, client); err != nil {
+			return err
+		}
+
+		calendarID, err := resolveCalendarID(cmd, client, calendarRef)
+		if err != nil {
+			return err
+		}
+		for i := range rows {
+			if rows[i].Calendar == "" {
+				continue
+			}
+			if rows[i].Req.CalendarID, err = resolveCalendarID(cmd, client, rows[i].Calendar); err != nil {
+				return fmt.Errorf("row %d: %w", rows[i].Line, err)
+			}
+		}
+		if calendarID == 0 && needsDefaultCalendar(rows) {
+			if calendarID, err = primaryCalendarID(client); err != nil {
+				return err
+			}
+		}
+
+		if !yes && auth.IsInteractiveTerminal() {
+			fmt.Println()
+			if !confirm(i18n.T("Create %d event(s)?", len(rows))) {
+				fmt.Println(i18n.T("Cancelled. No events created."))
+				return nil
+			}
+		}
+
+		results := createBatch(client, rows, calendarID, !jsonOutput)
+		if jsonOutput {
+			output.PrintWithOptions(results, output.FormatJSON, output.PrintOptions{})
+		}
+
+		failed := 0
+		for _, r := range results {
+			if r.Status == "failed" {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d events failed", failed, len(results))
+		}
+		if !jsonOutput {
+			fmt.Printf("\nCreated %d event(s)\n", len(results))
+		}
+		return nil
+	},
+}
+
+// parseBatchCSV reads and validates every row, reporting all problems at once
+// so nothing is created from a partly broken file
+func parseBatchCSV(in io.Reader) ([]batchRow, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("CSV is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	cols := make(map[string]int)
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		name = strings.ReplaceAll(name, "-", "_")
+		if !slices.Contains(batchColumns, name) {
+			return nil, fmt.Errorf("unknown column %q (expected %s)", h, strings.Join(batchColumns, ", "))
+		}
+		cols[name] = i
+	}
+	for _, required := range batchColumns[:3] {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+
+	var rows []batchRow
+	var problems []string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		req, err := batchRequest(field)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("  row %d: %v", line, err))
+			continue
+		}
+		row := batchRow{Line: line, Req: req}
+		if v := field("calendar"); v != "" && req.CalendarID == 0 {
+			row.Calendar = v
+		}
+		rows = append(rows, row)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d invalid row(s), nothing created:\n%s", len(problems), strings.Join(problems, "\n"))
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("CSV has no event rows")
+	}
+	return rows, nil
+}
+
+// batchRequest builds the create request for one row
+func batchRequest(field func(string) string) (api.CreateEventRequest, error) {
+	req := api.CreateEventRequest{
+		Summary:     field("summary"),
+		Location:    field("location"),
+		Description: field("description"),
+	}
+	if req.Summary == "" {
+		return req, errors.New("summary is empty")
+	}
+
+	if v := field("all_day"); v != "" {
+		allDay, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, fmt.Errorf("all_day %q is not true or false", v)
+		}
+		req.IsAllDay = allDay
+	}
+
+	start, err := parseBatchTime(field("start"), req.IsAllDay)
+	if err != nil {
+		return req, fmt.Errorf("start: %w", err)
+	}
+	req.From = start
+
+	endStr := field("end")
+	if d, derr := time.ParseDuration(endStr); derr == nil {
+		req.To = start.Add(d)
+	} else if req.To, err = parseBatchTime(endStr, req.IsAllDay); err != nil {
+		return req, fmt.Errorf("end: %w", err)
+	}
+	if !req.To.After(req.From) {
+		return req, errors.New("end must be after start")
+	}
+
+	// Calendar names are resolved once there is a client
+	if id, err := strconv.ParseInt(field("calendar"), 10, 64); err == nil {
+		if id <= 0 {
+			return req, fmt.Errorf("calendar %q is not a calendar ID", field("calendar"))
+		}
+		req.CalendarID = id
+	}
+
+	for _, a := range strings.FieldsFunc(field("attendees"), func(r rune) bool {
+		return r == ';' || r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}) {
+		if !strings.Contains(a, "@") {
+			return req, fmt.Errorf("attendee %q is not an email address", a)
+		}
+		req.Attendees = append(req.Attendees, a)
+	}
+
+	for _, rule := range strings.FieldsFunc(field("recurrence"), func(r rune) bool { return r == '|' || r == '\n' }) {
+		rule = strings.TrimSpace(rule)
+		upper := strings.ToUpper(rule)
+		if !strings.HasPrefix(upper, "RRULE:") && !strings.HasPrefix(upper, "EXDATE") && !strings.HasPrefix(upper, "RDATE") {
+			return req, fmt.Errorf("recurrence %q must start with RRULE:, EXDATE or RDATE", rule)
+		}
+		req.Recurrence = append(req.Recurrence, rule)
+	}
+	return req, nil
+}
+
+// parseBatchTime accepts RFC3339, or a local date and time in the output
+// timezone. All-day events take a date.
+func parseBatchTime(s string, allDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("missing")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	layouts := []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+	if allDay {
+		layouts = []string{"2006-01-02"}
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, output.GetOutputLocation()); err == nil {
+			return t, nil
+		}
+	}
+	if allDay {
+		return time.Time{}, fmt.Errorf("%q is not a date (YYYY-MM-DD)", s)
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time (use RFC3339 or YYYY-MM-DD HH:MM)", s)
+}
+
+// needsDefaultCalendar reports whether any row relies on the default calendar
+func needsDefaultCalendar(rows []batchRow) bool {
+	for _, r := range rows {
+		if r.Req.CalendarID == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func printBatchPreview(rows []batchRow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	output.TableHeader(w, "ROW", "START", "END", "SUMMARY", "ATTENDEES", "REPEATS")
+	for _, r := range rows {
+		layout := "2006-01-02 15:04"
+		if r.Req.IsAllDay {
+			layout = "2006-01-02"
+		}
+		repeats := ""
+		if len(r.Req.Recurrence) > 0 {
+			repeats = "yes"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n",
+			r.Line,
+			r.Req.From.In(output.GetOutputLocation()).Format(layout),
+			r.Req.To.In(output.GetOutputLocation()).Format(layout),
+			truncateRunes(r.Req.Summary, 40),
+			len(r.Req.Attendees),
+			repeats,
+		)
+	}
+	w.Flush()
+}
+
+// createBatch creates the rows in order, reporting each one as it finishes
+func createBatch(client *api.Client, rows []batchRow, calendarID int64, report bool) []batchResult {
+	results := make([]batchResult, 0, len(rows))
+	for _, r := range rows {
+		req := r.Req
+		if req.CalendarID == 0 {
+			req.CalendarID = calendarID
+		}
+		res := batchResult{Row: r.Line, Summary: req.Summary}
+
+		event, err := client.CreateEvent(req)
+		if err != nil {
+			res.Status, res.Error = "failed", formatError(err).Error()
+			if report {
+				fmt.Fprintf(os.Stderr, "  %s row %d %s: %s\n", output.ColorRed(output.Symbols("✗")), r.Line, req.Summary, res.Error)
+			}
+		} else {
+			res.Status, res.EventID = "created", event.ID
+			if report {
+				output.PrintSuccess(fmt.Sprintf("row %d %s (ID: %s)", r.Line, req.Summary, event.ID))
+			}
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func init() {
+	createBatchCmd.Flags().String("calendar", "", "Default calendar ID or name for rows without one (default: primary calendar)")
+	createBatchCmd.Flags().Bool("dry-run", false, "Validate and preview without creating events")
+	createBatchCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	createBatchCmd.Flags().String("rate", "", rateFlagUsage)
+
+	calendarCmd.AddCommand(createBatchCmd)
+}
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// deleteManyResult is the outcome of deleting one matched event
+type deleteManyResult struct {
+	EventID string `json:"eventId"`
+	Title   string `json:"title"`
+	Start   string `json:"start"`
+	Status  string `json:"status"` // deleted, failed or matched (with --dry-run)
+	Error   string `json:"error,omitempty"`
+}
+
+var calendarDeleteManyCmd = &cobra.Command{
+	Use:   "delete-many",
+	Short: "Delete every event matching a search",
+	Long: `Delete all events in a time range that match --query or --attendees. The
+matching events are listed and confirmed first, then deleted one at a time;
+a failure doesn't stop the rest, and a summary follows.
+
+Takes the same time range flags as 'calendar events' (default: the next 7 days).
+Occurrences of recurring events are deleted one by one, leaving the rest of
+their series. Without a terminal to confirm on, --yes is required.
+
+Examples:
+  porteden calendar delete-many -q "standup" --from 2026-03-01 --to 2026-03-15 --dry-run
+  porteden calendar delete-many -q "Focus time" --month=2026-03 --calendar Work
+  porteden calendar delete-many --attendees old-vendor@example.com --days 90 --yes --no-notify`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, _ := cmd.Flags().GetString("query")
+		attendees, _ := cmd.Flags().GetString("attendees")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		noNotify, _ := cmd.Flags().GetBool("no-notify")
+		jsonOutput := getOutputFormat(cmd) == output.FormatJSON
+
+		// An empty search would match every event in the range
+		if query == "" && attendees == "" {
+			return fmt.Errorf("give --query or --attendees to choose the events to delete")
+		}
+		if !dryRun && !yes && !auth.IsInteractiveTerminal() {
+			return fmt.Errorf("deleting without a terminal to confirm on needs --yes")
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		if err := applyRateFlag(cmd, client); err != nil {
+			return err
+		}
+		params, err := buildEventParams(cmd, client)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		params.Limit = 100
+
+		resp, err := client.GetAllEvents(params)
+		if err != nil {
+			return formatError(err)
+		}
+		events := resp.Events
+		if len(events) == 0 {
+			if jsonOutput {
+				output.PrintWithOptions([]deleteManyResult{}, output.FormatJSON, output.PrintOptions{})
+				return nil
+			}
+			fmt.Println("No matching events.")
+			return nil
+		}
+
+		items := eventPickerItems(events)
+		if dryRun {
+			if jsonOutput {
+				results := make([]deleteManyResult, len(events))
+				for i, e := range events {
+					results[i] = newDeleteManyResult(e, "matched")
+				}
+				output.PrintWithOptions(results, output.FormatJSON, output.PrintOptions{})
+				return nil
+			}
+			for _, it := range items {
+				fmt.Println("  " + it.Label)
+			}
+			fmt.Printf("\nDry run: %d event(s) match, nothing deleted.\n", len(events))
+			return nil
+		}
+
+		if !yes {
+			fmt.Println()
+			for _, it := range items {
+				fmt.Println("  " + it.Label)
+			}
+			fmt.Println()
+			if !confirm(i18n.T("Delete %d event(s)?", len(events))) {
+				fmt.Println(i18n.T("Cancelled. No changes made."))
+				return nil
+			}
+		}
+
+		results := make([]deleteManyResult, 0, len(events))
+		failed := 0
+		for i, e := range events {
+			res := newDeleteManyResult(e, "deleted")
+			progress := fmt.Sprintf("[%d/%d] %s", i+1, len(events), items[i].Label)
+			if _, err := client.DeleteEvent(e.ID, !noNotify, ""); err != nil {
+				failed++
+				res.Status, res.Error = "failed", formatError(err).Error()
+				if !jsonOutput {
+					fmt.Fprintf(os.Stderr, "  %s %s: %s\n", output.ColorRed(output.Symbols("✗")), progress, res.Error)
+				}
+			} else if !jsonOutput {
+				output.PrintSuccess(progress)
+			}
+			results = append(results, res)
+		}
+
+		if jsonOutput {
+			output.PrintWithOptions(results, output.FormatJSON, output.PrintOptions{})
+		} else {
+			fmt.Printf("\nDeleted %d of %d event(s)", len(events)-failed, len(events))
+			if failed > 0 {
+				fmt.Printf(", %d failed", failed)
+			}
+			fmt.Println()
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d events failed", failed, len(events))
+		}
+		return nil
+	},
+}
+
+func newDeleteManyResult(e api.Event, status string) deleteManyResult {
+	title := e.Title
+	if title == "" {
+		title = e.Summary
+	}
+	return deleteManyResult{
+		EventID: e.ID,
+		Title:   title,
+		Start:   output.GetLocalStart(e.StartLocal, e.StartUtc),
+		Status:  status,
+	}
+}
+
+func init() {
+	calendarDeleteManyCmd.Flags().StringP("query", "q", "", "Keyword search in title, description, location")
+	calendarDeleteManyCmd.Flags().String("attendees", "", "Comma-separated attendee emails to match")
+	calendarDeleteManyCmd.Flags().String("calendar", "", "Only delete from this calendar (ID or name)")
+	calendarDeleteManyCmd.Flags().Bool("dry-run", false, "List the matching events without deleting them")
+	calendarDeleteManyCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	calendarDeleteManyCmd.Flags().Bool("no-notify", false, "Don't send cancellation notifications")
+	calendarDeleteManyCmd.Flags().String("rate", "", rateFlagUsage)
+
+	calendarCmd.AddCommand(calendarDeleteManyCmd)
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var calendarDuplicateCmd = &cobra.Command{
+	Use:     "duplicate <eventId>",
+	Aliases: []string{"clone"},
+	Short:   "Create a copy of an event at another time",
+	Long: `Create a new event with the title, description, location, attendees and length
+of an existing one, starting at --from. The copy goes into the same calendar
+unless --calendar picks another. A copy of a recurring event's occurrence is a
+single event.
+
+Attendees are invited to the copy as new; their responses to the original
+aren't carried over.
+
+Examples:
+  porteden calendar duplicate <eventId> --from 2026-02-17T10:00:00Z
+  porteden calendar duplicate <eventId> --from "2026-02-17 10:00" --calendar Personal
+  porteden calendar duplicate <eventId> --from 2026-02-17T10:00:00Z --duration 30m --no-attendees`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromStr, _ := cmd.Flags().GetString("from")
+		calendarRef, _ := cmd.Flags().GetString("calendar")
+		summary, _ := cmd.Flags().GetString("summary")
+		noAttendees, _ := cmd.Flags().GetBool("no-attendees")
+
+		start, err := parseDateTime(fromStr)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %w", err)
+		}
+		duration, hasDuration, err := eventDuration(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		eventID, err := eventIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.GetEvent(eventID, api.ExpandAttendees)
+		if err != nil {
+			return formatError(err)
+		}
+		orig := resp.Event
+
+		calendarID := orig.CalendarID
+		if calendarRef != "" {
+			if calendarID, err = resolveCalendarID(cmd, client, calendarRef); err != nil {
+				return err
+			}
+		}
+		if !hasDuration {
+			duration = orig.EndUtc.Sub(orig.StartUtc)
+		}
+		if summary == "" {
+			summary = orig.Title
+		}
+		if summary == "" {
+			summary = orig.Summary
+		}
+
+		req := api.CreateEventRequest{
+			CalendarID:   calendarID,
+			Summary:      summary,
+			Description:  orig.Description,
+			Location:     orig.Location,
+			From:         start,
+			To:           start.Add(duration),
+			IsAllDay:     orig.AllDay || orig.IsAllDay,
+			Transparency: orig.Transparency,
+		}
+		if !noAttendees {
+			req.Attendees = copiedAttendees(orig, resp.CurrentUserCalendarEmail)
+		}
+
+		event, err := client.CreateEvent(req)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Event created successfully (ID: %s)\n", event.ID)
+		output.PrintWithOptions(event, getOutputFormat(cmd), output.PrintOptions{
+			Compact: IsCompactMode(),
+		})
+		return nil
+	},
+}
+
+// copiedAttendees lists the attendees of e to invite to a copy, leaving out
+// the user, who becomes the copy's organizer
+func copiedAttendees(e api.Event, self string) []string {
+	var attendees []string
+	for _, a := range e.Attendees {
+		if a.Email == "" || strings.EqualFold(a.Email, self) {
+			continue
+		}
+		attendees = append(attendees, a.Email)
+	}
+	return attendees
+}
+
+func init() {
+	calendarDuplicateCmd.Flags().String("from", "", "Start time of the copy (required)")
+	calendarDuplicateCmd.Flags().String("duration", "", "Length of the copy (default: same as the original), e.g. 45m or 1h30m")
+	calendarDuplicateCmd.Flags().String("calendar", "", "Calendar ID or name for the copy (default: the original's)")
+	calendarDuplicateCmd.Flags().String("summary", "", "Title of the copy (default: the original's)")
+	calendarDuplicateCmd.Flags().Bool("no-attendees", false, "Don't invite the original's attendees")
+	_ = calendarDuplicateCmd.MarkFlagRequired("from")
+
+	calendarCmd.AddCommand(calendarDuplicateCmd)
+}
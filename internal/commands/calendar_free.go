@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/freetime"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// freeSlotsResult is the JSON output of 'calendar free'
+type freeSlotsResult struct {
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	MinMinutes int             `json:"minMinutes"`
+	Slots      []freetime.Slot `json:"slots"`
+}
+
+var calendarFreeCmd = &cobra.Command{
+	Use:   "free",
+	Short: "Find free time slots",
+	Long: `List the gaps between busy times in your calendars, merged across all of
+them (or those given with --calendars), that are at least --min long.
+
+Only working hours count: 09:00-17:00, Monday to Friday, in the output
+timezone, unless changed with calendar.work_hours and calendar.work_days in
+the config file or --hours and --work-days. --any-time drops the limit.
+
+Takes the same time range flags as 'calendar events' (default: the next 7
+days). Time already past isn't free.
+
+Examples:
+  porteden calendar free --week --min 30m
+  porteden calendar free --days 3 --min 1h --calendars Work,Personal
+  porteden calendar free --tomorrow --hours 08:00-12:00
+  porteden calendar free --week --work-days mon,tue,thu --skip-holidays US
+  porteden calendar free --from 2026-03-02 --to 2026-03-07 --any-time -j`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		minStr, _ := cmd.Flags().GetString("min")
+		minLength, err := parseAge(minStr)
+		if err != nil || minLength <= 0 {
+			return fmt.Errorf("invalid --min %q: use a length such as 30m or 1h", minStr)
+		}
+		hours, err := workingHours(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		eventParams, err := buildEventParams(cmd, client)
+		if err != nil {
+			return err
+		}
+		if eventParams.To.IsZero() {
+			return fmt.Errorf("free time needs the end of the range: add --to")
+		}
+		from, to := eventParams.From, eventParams.To
+		if now := time.Now(); from.Before(now) {
+			from = now.Truncate(time.Minute)
+		}
+
+		calendarRefs, _ := cmd.Flags().GetString("calendars")
+		calendars, err := resolveCalendarList(cmd, client, calendarRefs)
+		if err != nil {
+			return err
+		}
+
+		result := freeSlotsResult{From: from, To: to, MinMinutes: int(minLength.Minutes()), Slots: []freetime.Slot{}}
+		if to.After(from) {
+			resp, err := client.GetFreeBusy(api.FreeBusyParams{From: from, To: to, Calendars: calendars})
+			if err != nil {
+				return formatError(err)
+			}
+			if region, _ := cmd.Flags().GetString("skip-holidays"); region != "" {
+				if err := addHolidayBlocks(resp, region, from, to); err != nil {
+					return err
+				}
+			}
+			var busy []api.BusyPeriod
+			for _, c := range resp.Calendars {
+				busy = append(busy, c.Busy...)
+			}
+			if slots := freetime.Free(busy, from, to, hours, output.GetOutputLocation(), minLength); slots != nil {
+				result.Slots = slots
+			}
+		}
+
+		switch getOutputFormat(cmd) {
+		case output.FormatJSON:
+			output.PrintWithOptions(result, output.FormatJSON, output.PrintOptions{})
+			return nil
+		case output.FormatPlain:
+			for _, s := range result.Slots {
+				fmt.Printf("%s\t%s\t%d\n", output.FormatLocalTime(s.StartUtc), output.FormatLocalTime(s.EndUtc), s.DurationMinutes)
+			}
+			return nil
+		}
+		if len(result.Slots) == 0 {
+			fmt.Printf("No free slots of %s or more.\n", output.FormatMinutes(result.MinMinutes))
+			return nil
+		}
+		loc := output.GetOutputLocation()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		output.TableHeader(w, "START", "END", "LENGTH")
+		for _, s := range result.Slots {
+			start, end := s.StartUtc.In(loc), s.EndUtc.In(loc)
+			endFormat := "15:04"
+			if end.YearDay() != start.YearDay() || end.Year() != start.Year() {
+				endFormat = "Mon 2006-01-02 15:04"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", start.Format("Mon 2006-01-02 15:04"), end.Format(endFormat), output.FormatMinutes(s.DurationMinutes))
+		}
+		return w.Flush()
+	},
+}
+
+// workingHours returns the hours free time is looked for in, from the
+// config file and --hours/--work-days, or nil with --any-time
+func workingHours(cmd *cobra.Command) (*freetime.WorkingHours, error) {
+	anyTime, _ := cmd.Flags().GetBool("any-time")
+	if anyTime {
+		if cmd.Flags().Changed("hours") || cmd.Flags().Changed("work-days") {
+			return nil, fmt.Errorf("--any-time cannot be combined with --hours or --work-days")
+		}
+		return nil, nil
+	}
+
+	hours := freetime.DefaultWorkingHours
+	if v := userConfig.String("calendar.work_hours"); v != "" {
+		if err := hours.ParseHours(v); err != nil {
+			return nil, fmt.Errorf("calendar.work_hours: %w", err)
+		}
+	}
+	if days := userConfig.List("calendar.work_days"); len(days) > 0 {
+		if err := hours.ParseDays(days); err != nil {
+			return nil, fmt.Errorf("calendar.work_days: %w", err)
+		}
+	}
+	if cmd.Flags().Changed("hours") {
+		v, _ := cmd.Flags().GetString("hours")
+		if err := hours.ParseHours(v); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.Flags().Changed("work-days") {
+		days, _ := cmd.Flags().GetStringSlice("work-days")
+		if err := hours.ParseDays(days); err != nil {
+			return nil, err
+		}
+	}
+	return &hours, nil
+}
+
+func init() {
+	calendarFreeCmd.Flags().String("min", "30m", "Shortest slot to list, e.g. 30m or 1h")
+	calendarFreeCmd.Flags().String("calendars", "", "Comma-separated calendar IDs or names (default: all)")
+	calendarFreeCmd.Flags().String("hours", "", "Working hours to look in, e.g. 09:00-17:00 (default from calendar.work_hours)")
+	calendarFreeCmd.Flags().StringSlice("work-days", nil, "Working days to look in, e.g. mon,tue,wed (default from calendar.work_days)")
+	calendarFreeCmd.Flags().Bool("any-time", false, "Look outside working hours and on every day too")
+	calendarFreeCmd.Flags().String("skip-holidays", "", "Treat public holidays in this region (US, GB, CA, DE, FR) as busy")
+	_ = calendarFreeCmd.RegisterFlagCompletionFunc("skip-holidays", holidayRegions)
+
+	calendarCmd.AddCommand(calendarFreeCmd)
+}
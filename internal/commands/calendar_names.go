@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/cache"
+	"github.com/porteden/cli/internal/debug"
+	"github.com/spf13/cobra"
+)
+
+// calendarsCacheTTL is how long the calendar list used to resolve names is
+// reused before it is fetched again
+const calendarsCacheTTL = time.Hour
+
+// calendarList is the cached calendar list of a profile
+type calendarList struct {
+	FetchedAt time.Time      `json:"fetchedAt"`
+	Calendars []api.Calendar `json:"calendars"`
+}
+
+// calendarsCacheFile is the cache entry holding a profile's calendar list
+func calendarsCacheFile(profile string) string {
+	return "calendars-" + profile + ".json"
+}
+
+// errNoCalendar is returned by matchCalendar when no calendar matches
+var errNoCalendar = errors.New("no matching calendar")
+
+// resolveCalendarID turns a calendar ID, name or unique name prefix into the
+// calendar's ID. Numbers are taken as IDs without a lookup. Names match
+// case-insensitively against the cached calendar list, which is fetched again
+// when it is stale or the name isn't in it.
+func resolveCalendarID(cmd *cobra.Command, client *api.Client, ref string) (int64, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return 0, nil
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		if id <= 0 {
+			return 0, fmt.Errorf("invalid calendar ID %q (see 'porteden calendar calendars')", ref)
+		}
+		return id, nil
+	}
+
+	calendars, fresh := loadCalendarList(cmd)
+	if !fresh {
+		var err error
+		if calendars, err = fetchCalendarList(cmd, client); err != nil {
+			return 0, err
+		}
+	}
+	cal, err := matchCalendar(calendars, ref)
+	if errors.Is(err, errNoCalendar) && fresh {
+		// The calendar may have been created or renamed since the list was cached
+		if calendars, err = fetchCalendarList(cmd, client); err != nil {
+			return 0, err
+		}
+		cal, err = matchCalendar(calendars, ref)
+	}
+	if errors.Is(err, errNoCalendar) {
+		return 0, fmt.Errorf("no calendar named %q (see 'porteden calendar calendars')", ref)
+	}
+	if err != nil {
+		return 0, err
+	}
+	debug.Log("Resolved calendar %q to %d (%s)", ref, cal.ID, cal.Name)
+	return cal.ID, nil
+}
+
+// matchCalendar finds the calendar whose name is ref, or failing that the one
+// calendar whose name starts with ref
+func matchCalendar(calendars []api.Calendar, ref string) (*api.Calendar, error) {
+	var prefixed []api.Calendar
+	for i, c := range calendars {
+		if strings.EqualFold(c.Name, ref) {
+			return &calendars[i], nil
+		}
+		if strings.HasPrefix(strings.ToLower(c.Name), strings.ToLower(ref)) {
+			prefixed = append(prefixed, c)
+		}
+	}
+
+	switch len(prefixed) {
+	case 0:
+		return nil, errNoCalendar
+	case 1:
+		return &prefixed[0], nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "calendar name %q matches %d calendars:", ref, len(prefixed))
+	for _, c := range prefixed {
+		fmt.Fprintf(&b, "\n  %d  %s", c.ID, c.Name)
+	}
+	return nil, errors.New(b.String())
+}
+
+// loadCalendarList returns the cached calendar list and whether it is recent
+// enough to use. Delegated (--as) calendars are never cached.
+func loadCalendarList(cmd *cobra.Command) ([]api.Calendar, bool) {
+	if delegateAs != "" {
+		return nil, false
+	}
+	data, err := cache.Read(calendarsCacheFile(getProfile(cmd)))
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			debug.Log("Failed to read cached calendars: %v", err)
+		}
+		return nil, false
+	}
+	var list calendarList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, false
+	}
+	return list.Calendars, time.Since(list.FetchedAt) < calendarsCacheTTL
+}
+
+// fetchCalendarList gets the calendar list from the API and caches it
+func fetchCalendarList(cmd *cobra.Command, client *api.Client) ([]api.Calendar, error) {
+	resp, err := client.GetCalendars()
+	if err != nil {
+		return nil, formatError(err)
+	}
+	saveCalendarList(cmd, resp.Data)
+	return resp.Data, nil
+}
+
+// saveCalendarList caches a freshly fetched calendar list. Failures only cost
+// a lookup next time, so they're logged rather than returned.
+func saveCalendarList(cmd *cobra.Command, calendars []api.Calendar) {
+	if delegateAs != "" {
+		return
+	}
+	data, err := json.Marshal(calendarList{FetchedAt: time.Now(), Calendars: calendars})
+	if err == nil {
+		err = cache.Write(calendarsCacheFile(getProfile(cmd)), data)
+	}
+	if err != nil {
+		debug.Log("Failed to cache calendars: %v", err)
+	}
+}
+
+// resolveCalendarList resolves a comma-separated list of calendar IDs and
+// names into comma-separated IDs
+func resolveCalendarList(cmd *cobra.Command, client *api.Client, list string) (string, error) {
+	if strings.TrimSpace(list) == "" {
+		return "", nil
+	}
+	var ids []string
+	for _, ref := range strings.Split(list, ",") {
+		id, err := resolveCalendarID(cmd, client, ref)
+		if err != nil {
+			return "", err
+		}
+		if id != 0 {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+	}
+	return strings.Join(ids, ","), nil
+}
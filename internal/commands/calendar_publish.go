@@ -0,0 +1,286 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/bundle"
+	"github.com/porteden/cli/internal/cache"
+	"github.com/porteden/cli/internal/debug"
+	"github.com/spf13/cobra"
+)
+
+var calendarPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Serve your calendar as an ICS feed others can subscribe to",
+	Long: `Serve an iCalendar feed of the coming days over HTTP, so teammates can
+subscribe to it from their calendar app. The feed is rebuilt from the API when
+it is older than --refresh, and runs until interrupted.
+
+With --freebusy-only the feed holds only busy blocks, without titles,
+attendees or any other detail. Without it, full events are published.
+
+The feed is served at a secret path, printed on start. The secret is kept for
+the profile, so the URL stays the same across restarts; --new-secret replaces
+it, which cuts off everyone subscribed to the old URL. Other paths return 404.
+
+Examples:
+  porteden calendar publish --listen :8089 --freebusy-only
+  porteden calendar publish --freebusy-only --days 60 --calendars Work,Travel
+  porteden calendar publish --listen 127.0.0.1:8089 --refresh 15m
+  porteden calendar publish --freebusy-only --new-secret`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		freeBusyOnly, _ := cmd.Flags().GetBool("freebusy-only")
+		days, _ := cmd.Flags().GetInt("days")
+		refresh, _ := cmd.Flags().GetDuration("refresh")
+		newSecret, _ := cmd.Flags().GetBool("new-secret")
+		calendarRefs, _ := cmd.Flags().GetString("calendars")
+
+		if days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+		if refresh < time.Minute {
+			return fmt.Errorf("--refresh must be at least 1m")
+		}
+		host, port, err := net.SplitHostPort(listen)
+		if err != nil {
+			return fmt.Errorf("invalid --listen %q: use host:port or :port", listen)
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		calendars, err := resolveCalendarList(cmd, client, calendarRefs)
+		if err != nil {
+			return err
+		}
+		secret, err := publishSecret(getProfile(cmd), newSecret)
+		if err != nil {
+			return err
+		}
+
+		feed := &publishedFeed{refresh: refresh, build: func() ([]byte, error) {
+			from := time.Now().Truncate(time.Hour)
+			to := from.AddDate(0, 0, days)
+			if freeBusyOnly {
+				return freeBusyFeed(client, calendars, from, to)
+			}
+			return eventsFeed(client, calendars, from, to)
+		}}
+		// Fail now rather than on the first subscriber's request
+		if _, _, err := feed.get(); err != nil {
+			return formatError(err)
+		}
+
+		ln, err := net.Listen("tcp", listen)
+		if err != nil {
+			return err
+		}
+		path := "/" + secret + "/calendar.ics"
+		server := &http.Server{
+			Handler:           feed.handler(path),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "localhost"
+			fmt.Fprintln(os.Stderr, "Listening on all interfaces; anyone who can reach this port and knows the URL can read the feed.")
+		}
+		what := "events"
+		if freeBusyOnly {
+			what = "free/busy"
+		}
+		fmt.Fprintf(os.Stderr, "Publishing %s for the next %d days, refreshed every %s (Ctrl+C to stop):\n", what, days, refresh)
+		fmt.Println("http://" + net.JoinHostPort(host, port) + path)
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		go func() {
+			<-ctx.Done()
+			shutdown, done := context.WithTimeout(context.Background(), 5*time.Second)
+			defer done()
+			_ = server.Shutdown(shutdown)
+		}()
+
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+// publishedFeed caches the feed body and rebuilds it once it is older than
+// refresh. A failed rebuild keeps serving the last good copy.
+type publishedFeed struct {
+	build   func() ([]byte, error)
+	refresh time.Duration
+
+	// buildMu is held while rebuilding, so one request rebuilds while the
+	// others are served the previous copy
+	buildMu sync.Mutex
+	mu      sync.Mutex // guards data and built
+	data    []byte
+	built   time.Time
+}
+
+// get returns the feed and when it was built, rebuilding it if it is stale
+func (f *publishedFeed) get() ([]byte, time.Time, error) {
+	data, built := f.current()
+	if data != nil && time.Since(built) < f.refresh {
+		return data, built, nil
+	}
+	if !f.buildMu.TryLock() {
+		if data != nil {
+			return data, built, nil
+		}
+		f.buildMu.Lock()
+	}
+	defer f.buildMu.Unlock()
+
+	// Another request may have rebuilt it while this one waited
+	if data, built = f.current(); data != nil && time.Since(built) < f.refresh {
+		return data, built, nil
+	}
+	fresh, err := f.build()
+	if err != nil {
+		if data != nil {
+			fmt.Fprintf(os.Stderr, "Warning: refreshing the feed failed, serving the previous copy: %v\n", formatError(err))
+			return data, built, nil
+		}
+		return nil, time.Time{}, err
+	}
+	now := time.Now()
+	f.mu.Lock()
+	f.data, f.built = fresh, now
+	f.mu.Unlock()
+	return fresh, now, nil
+}
+
+// current returns the cached feed and when it was built
+func (f *publishedFeed) current() ([]byte, time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data, f.built
+}
+
+// handler serves the feed at path only, comparing it in constant time so
+// the secret can't be guessed from response timings
+func (f *publishedFeed) handler(path string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.URL.Path), []byte(path)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		data, built, err := f.get()
+		if err != nil {
+			debug.Log("Building feed failed: %v", err)
+			http.Error(w, "calendar unavailable", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(f.refresh.Seconds())))
+		http.ServeContent(w, r, "calendar.ics", built, bytes.NewReader(data))
+	})
+}
+
+// freeBusyFeed builds a feed of busy blocks only
+func freeBusyFeed(client *api.Client, calendars string, from, to time.Time) ([]byte, error) {
+	resp, err := client.GetFreeBusy(api.FreeBusyParams{From: from, To: to, Calendars: calendars})
+	if err != nil {
+		return nil, err
+	}
+	var busy []api.BusyPeriod
+	for _, c := range resp.Calendars {
+		busy = append(busy, c.Busy...)
+	}
+	var buf bytes.Buffer
+	if err := bundle.WriteFreeBusyICS(&buf, "Availability", from, to, busy); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// eventsFeed builds a feed of full events, optionally limited to calendars
+// (comma-separated IDs)
+func eventsFeed(client *api.Client, calendars string, from, to time.Time) ([]byte, error) {
+	resp, err := client.GetAllEvents(api.EventParams{From: from, To: to, Limit: 100})
+	if err != nil {
+		return nil, err
+	}
+	events := resp.Events
+	if calendars != "" {
+		keep := map[string]bool{}
+		for _, id := range strings.Split(calendars, ",") {
+			keep[id] = true
+		}
+		events = events[:0]
+		for _, e := range resp.Events {
+			if keep[strconv.FormatInt(e.CalendarID, 10)] {
+				events = append(events, e)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := bundle.WriteICS(&buf, "Calendar", events); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// publishSecret returns the profile's feed secret, creating one on first use
+// or when renew is set
+func publishSecret(profile string, renew bool) (string, error) {
+	name := "publish-" + profile + ".secret"
+	if !renew {
+		data, err := cache.Read(name)
+		if err == nil && len(data) > 0 {
+			return string(data), nil
+		}
+		if err != nil && !errors.Is(err, cache.ErrNotFound) {
+			return "", err
+		}
+	}
+	raw := make([]byte, 18)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(raw)
+	if err := cache.Write(name, []byte(secret)); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func init() {
+	calendarPublishCmd.Flags().String("listen", "127.0.0.1:8089", "Address to serve the feed on (host:port, or :port for all interfaces)")
+	calendarPublishCmd.Flags().Bool("freebusy-only", false, "Publish busy blocks only, without event details")
+	calendarPublishCmd.Flags().Int("days", 30, "How many days ahead the feed covers")
+	calendarPublishCmd.Flags().String("calendars", "", "Comma-separated calendar IDs or names (default: all)")
+	calendarPublishCmd.Flags().Duration("refresh", 5*time.Minute, "Rebuild the feed from the API when it is older than this")
+	calendarPublishCmd.Flags().Bool("new-secret", false, "Replace the secret path, invalidating the old feed URL")
+
+	calendarCmd.AddCommand(calendarPublishCmd)
+}
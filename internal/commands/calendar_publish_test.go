@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPublishedFeedHandler(t *testing.T) {
+	const path = "/s3cret/calendar.ics"
+	body, fail := "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", false
+	feed := &publishedFeed{refresh: time.Minute, build: func() ([]byte, error) {
+		if fail {
+			return nil, errors.New("API unreachable")
+		}
+		return []byte(body), nil
+	}}
+	handler := feed.handler(path)
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	// Nothing built yet and the API fails: no feed to fall back on
+	fail = true
+	if rec := serve("GET", path); rec.Code != http.StatusBadGateway {
+		t.Errorf("GET with no feed = %d, want 502", rec.Code)
+	}
+
+	fail = false
+	rec := serve("GET", path)
+	if rec.Code != http.StatusOK || rec.Body.String() != body {
+		t.Fatalf("GET = %d %q, want 200 with the feed", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if rec.Header().Get("Last-Modified") == "" {
+		t.Error("no Last-Modified header")
+	}
+
+	for _, target := range []string{"/", "/calendar.ics", "/s3cre/calendar.ics", path + "x"} {
+		if rec := serve("GET", target); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", target, rec.Code)
+		}
+	}
+	for _, method := range []string{"POST", "PUT", "DELETE"} {
+		rec := serve(method, path)
+		if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+			t.Errorf("%s = %d, Allow %q; want 405 allowing GET, HEAD", method, rec.Code, rec.Header().Get("Allow"))
+		}
+	}
+
+	// A stale feed whose rebuild fails is still served
+	old := body
+	feed.mu.Lock()
+	feed.built = time.Now().Add(-time.Hour)
+	feed.mu.Unlock()
+	body, fail = "changed", true
+	if rec := serve("GET", path); rec.Code != http.StatusOK || rec.Body.String() != old {
+		t.Errorf("GET after a failed rebuild = %d %q, want 200 with the previous feed", rec.Code, rec.Body.String())
+	}
+
+	// Once the API is back, the next request rebuilds it
+	fail = false
+	if rec := serve("GET", path); rec.Body.String() != "changed" {
+		t.Errorf("GET after recovery = %q, want the rebuilt feed", rec.Body.String())
+	}
+}
+
+func TestPublishedFeedServesWhileRebuilding(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	feed := &publishedFeed{refresh: time.Minute, data: []byte("old"), built: time.Now().Add(-time.Hour), build: func() ([]byte, error) {
+		close(started)
+		<-release
+		return []byte("new"), nil
+	}}
+
+	done := make(chan []byte)
+	go func() {
+		data, _, _ := feed.get()
+		done <- data
+	}()
+	<-started
+
+	// Served the previous copy without waiting on the slow rebuild
+	if data, _, err := feed.get(); err != nil || string(data) != "old" {
+		t.Errorf("get during a rebuild = %q, %v; want the previous copy", data, err)
+	}
+	close(release)
+	if data := <-done; string(data) != "new" {
+		t.Errorf("rebuild returned %q", data)
+	}
+	if data, _, _ := feed.get(); string(data) != "new" {
+		t.Errorf("get after the rebuild = %q, want the new copy", data)
+	}
+}
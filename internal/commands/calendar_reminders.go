@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// reminderUsage describes --reminder on calendar create and update
+const reminderUsage = "Reminders before the start, replacing the calendar's defaults, e.g. 10m,1h or email:1d (popup unless prefixed with email:)"
+
+// maxReminderMinutes is the furthest ahead a reminder may be, four weeks
+const maxReminderMinutes = 4 * 7 * 24 * 60
+
+// eventReminder is one row of 'calendar reminders'
+type eventReminder struct {
+	Method  string `json:"method"`
+	Minutes int    `json:"minutes"`
+	At      string `json:"at"`
+}
+
+// eventRemindersResult is the output of 'calendar reminders'
+type eventRemindersResult struct {
+	EventID    string          `json:"eventId"`
+	Title      string          `json:"title"`
+	UseDefault bool            `json:"useDefault"`
+	Reminders  []eventReminder `json:"reminders"`
+}
+
+var calendarRemindersCmd = &cobra.Command{
+	Use:   "reminders <eventId>",
+	Short: "Show an event's reminders",
+	Long: `Show the reminders set on an event and when each one fires. Events without
+reminders of their own use their calendar's defaults.
+
+Change reminders with 'calendar update --reminder', go back to the calendar's
+defaults with --default-reminders, or remove them all with --no-reminders.
+
+Examples:
+  porteden calendar reminders <eventId>
+  porteden calendar reminders <eventId> -j`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		eventID, err := eventIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		resp, err := client.GetEvent(eventID)
+		if err != nil {
+			return formatError(err)
+		}
+		e := resp.Event
+		result := eventRemindersResult{EventID: e.ID, Title: e.Title, Reminders: []eventReminder{}}
+		if result.Title == "" {
+			result.Title = e.Summary
+		}
+		if e.Reminders == nil || e.Reminders.UseDefault {
+			result.UseDefault = true
+		} else {
+			for _, r := range e.Reminders.Overrides {
+				at := e.StartUtc.Add(-time.Duration(r.Minutes) * time.Minute)
+				result.Reminders = append(result.Reminders, eventReminder{Method: r.Method, Minutes: r.Minutes, At: output.FormatLocalTime(at)})
+			}
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(result, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		switch {
+		case result.UseDefault:
+			fmt.Println("Uses the calendar's default reminders.")
+			return nil
+		case len(result.Reminders) == 0:
+			fmt.Println("No reminders.")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		output.TableHeader(w, "BEFORE", "METHOD", "AT")
+		for _, r := range result.Reminders {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", output.FormatMinutes(r.Minutes), r.Method, r.At)
+		}
+		return w.Flush()
+	},
+}
+
+// addReminderFlags registers the flags read by reminderFlags
+func addReminderFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("reminder", nil, reminderUsage)
+	cmd.Flags().Bool("no-reminders", false, "Don't remind of the event at all")
+}
+
+// reminderFlags returns the reminders chosen with --reminder, --no-reminders
+// or --default-reminders, or nil to leave them as they are
+func reminderFlags(cmd *cobra.Command) (*api.EventReminders, error) {
+	given := 0
+	for _, name := range []string{"reminder", "no-reminders", "default-reminders"} {
+		if cmd.Flags().Changed(name) {
+			given++
+		}
+	}
+	switch {
+	case given > 1:
+		return nil, fmt.Errorf("give only one of --reminder, --no-reminders and --default-reminders")
+	case cmd.Flags().Changed("reminder"):
+		values, _ := cmd.Flags().GetStringSlice("reminder")
+		overrides, err := parseReminders(values)
+		if err != nil {
+			return nil, err
+		}
+		return &api.EventReminders{Overrides: overrides}, nil
+	case cmd.Flags().Changed("no-reminders"):
+		return &api.EventReminders{}, nil
+	case cmd.Flags().Changed("default-reminders"):
+		return &api.EventReminders{UseDefault: true}, nil
+	}
+	return nil, nil
+}
+
+// parseReminders parses --reminder values: a time before the start such as
+// 10m, 1h or 2d, optionally prefixed with the method (popup: or email:)
+func parseReminders(values []string) ([]api.Reminder, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("--reminder needs at least one time, e.g. 10m; use --no-reminders for none")
+	}
+	reminders := make([]api.Reminder, 0, len(values))
+	for _, v := range values {
+		method, before := api.ReminderPopup, strings.TrimSpace(v)
+		if m, rest, ok := strings.Cut(before, ":"); ok {
+			method, before = strings.ToLower(m), rest
+		}
+		if method != api.ReminderPopup && method != api.ReminderEmail {
+			return nil, fmt.Errorf("invalid reminder %q: the method must be popup or email", v)
+		}
+		d, err := parseAge(before)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder %q: %w", v, err)
+		}
+		if d < 0 || d%time.Minute != 0 || d > maxReminderMinutes*time.Minute {
+			return nil, fmt.Errorf("invalid reminder %q: use whole minutes, up to 4 weeks before", v)
+		}
+		reminders = append(reminders, api.Reminder{Method: method, Minutes: int(d / time.Minute)})
+	}
+	return reminders, nil
+}
+
+func init() {
+	calendarCmd.AddCommand(calendarRemindersCmd)
+}
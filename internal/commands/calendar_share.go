@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var calendarShareCmd = &cobra.Command{
+	Use:   "share <calendar>",
+	Short: "Share a calendar with someone",
+	Long: `Give someone read or write access to a calendar, given by ID or name. Sharing
+with someone who already has access changes their role.
+
+Roles:
+  reader  See event details
+  writer  See, create and change events
+
+Examples:
+  porteden calendar share 12345 --with priya@example.com --role reader
+  porteden calendar share 12345 --with assistant@example.com --role writer --no-notify
+  porteden calendar share "Team Events" --with priya@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		with, _ := cmd.Flags().GetString("with")
+		role, _ := cmd.Flags().GetString("role")
+		noNotify, _ := cmd.Flags().GetBool("no-notify")
+		if with == "" {
+			return errors.New("--with is required")
+		}
+		if role != "reader" && role != "writer" {
+			return fmt.Errorf("invalid --role %q: use reader or writer", role)
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		calendarID, err := resolveCalendarID(cmd, client, args[0])
+		if err != nil {
+			return err
+		}
+
+		req := api.ShareCalendarRequest{Email: with, Role: role}
+		if noNotify {
+			f := false
+			req.SendNotification = &f
+		}
+		share, err := client.ShareCalendar(calendarID, req)
+		if err != nil {
+			return formatError(err)
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.Print(share, output.FormatJSON)
+			return nil
+		}
+		output.PrintSuccess(fmt.Sprintf("Shared calendar %d with %s as %s", calendarID, share.Email, share.Role))
+		return nil
+	},
+}
+
+var calendarSharesCmd = &cobra.Command{
+	Use:   "shares <calendar>",
+	Short: "List who a calendar is shared with",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		calendarID, err := resolveCalendarID(cmd, client, args[0])
+		if err != nil {
+			return err
+		}
+
+		shares, err := client.GetCalendarShares(calendarID)
+		if err != nil {
+			return formatError(err)
+		}
+
+		output.PrintWithOptions(shares, getOutputFormat(cmd), output.PrintOptions{
+			Compact: IsCompactMode(),
+		})
+		return nil
+	},
+}
+
+func init() {
+	calendarShareCmd.Flags().String("with", "", "Email address to share with (required)")
+	calendarShareCmd.Flags().String("role", "reader", "Access to grant: reader or writer")
+	calendarShareCmd.Flags().Bool("no-notify", false, "Skip the email telling them about the calendar")
+
+	calendarCmd.AddCommand(calendarShareCmd)
+	calendarCmd.AddCommand(calendarSharesCmd)
+}
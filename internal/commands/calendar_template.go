@@ -0,0 +1,328 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/porteden/cli/internal/config"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// templateFlags maps the fields of an event template to the calendar create
+// flags they fill in
+var templateFlags = []struct{ field, flag string }{
+	{"title", "summary"},
+	{"duration", "duration"},
+	{"calendar", "calendar"},
+	{"description", "description"},
+	{"location", "location"},
+	{"attendees", "attendees"},
+	{"recurrence", "recurrence"},
+}
+
+// eventTemplate is one entry of 'calendar template list'
+type eventTemplate struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title,omitempty"`
+	Duration    string   `json:"duration,omitempty"`
+	Calendar    string   `json:"calendar,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	Attendees   []string `json:"attendees,omitempty"`
+	Recurrence  []string `json:"recurrence,omitempty"`
+}
+
+var calendarTemplateCmd = &cobra.Command{
+	Use:     "template",
+	Aliases: []string{"templates"},
+	Short:   "Save reusable event definitions",
+	Long: `Store the boilerplate of an event you create often (title, length, attendees,
+description, recurrence) under a name, and create events from it with
+'calendar create --template <name>' or 'calendar template apply <name>'.
+
+Templates live in the config file as templates.<name>.<field>, so single
+fields can also be changed with 'porteden config set'.
+
+Examples:
+  porteden calendar template save 1on1 --summary "1:1 with Sam" --duration 30m --attendees sam@example.com
+  porteden calendar template list
+  porteden calendar create --template 1on1 --from 2026-02-10T09:00:00Z
+  porteden calendar template apply 1on1 --from 2026-02-10T09:00:00Z --location "Room 2"`,
+}
+
+var calendarTemplateSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save an event template",
+	Long: `Save an event template from the given fields. Every field is optional, but
+at least one is needed. The calendar is kept as given and resolved when the
+template is used. Saving an existing name replaces it.
+
+Examples:
+  porteden calendar template save 1on1 --summary "1:1 with Sam" --duration 30m --attendees sam@example.com
+  porteden calendar template save standup --summary Standup --duration 15m --calendar Work \
+    --repeat weekdays`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !savedNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid name %q: use letters, digits, - and _", name)
+		}
+		given := cmd.Flags().Changed("repeat")
+		for _, tf := range templateFlags {
+			given = given || cmd.Flags().Changed(tf.flag)
+		}
+		if !given {
+			return fmt.Errorf("give at least one of --summary, --duration, --calendar, --description, --location, --attendees, --recurrence or --repeat")
+		}
+		duration, hasDuration, err := eventDuration(cmd)
+		if err != nil {
+			return err
+		}
+		rule, hasRule, err := repeatRule(cmd, output.GetOutputLocation())
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		prefix := "templates." + name
+		_, exists := cfg.Get(prefix)
+		cfg.Unset(prefix)
+		for _, tf := range templateFlags {
+			key := prefix + "." + tf.field
+			switch {
+			case tf.flag == "recurrence" && hasRule:
+				err = cfg.SetList(key, []string{rule})
+			case !cmd.Flags().Changed(tf.flag):
+				continue
+			case tf.flag == "duration":
+				if hasDuration {
+					err = cfg.Set(key, shortDuration(duration))
+				}
+			case tf.flag == "attendees" || tf.flag == "recurrence":
+				items, _ := cmd.Flags().GetStringSlice(tf.flag)
+				err = cfg.SetList(key, items)
+			default:
+				value, _ := cmd.Flags().GetString(tf.flag)
+				err = cfg.Set(key, value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+
+		verb := "Saved"
+		if exists {
+			verb = "Updated"
+		}
+		output.PrintSuccess(fmt.Sprintf("%s template %q", verb, name))
+		return nil
+	},
+}
+
+var calendarTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List event templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates := loadTemplates()
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(templates, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		if len(templates) == 0 {
+			fmt.Println("No event templates. Save one with: porteden calendar template save <name> --summary ... --duration ...")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		output.TableHeader(w, "NAME", "TITLE", "DURATION", "CALENDAR", "ATTENDEES")
+		for _, t := range templates {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, t.Title, t.Duration, t.Calendar, strings.Join(t.Attendees, ", "))
+		}
+		return w.Flush()
+	},
+}
+
+var calendarTemplateApplyCmd = &cobra.Command{
+	Use:   "apply <name>",
+	Short: "Create an event from a template",
+	Long: `Create an event from a template; the same as 'calendar create --template'.
+Takes every calendar create flag, and flags given override the template's
+fields. --to or --duration replaces the template's length.
+
+Examples:
+  porteden calendar template apply 1on1 --from 2026-02-10T09:00:00Z
+  porteden calendar template apply 1on1 --from 2026-02-10T09:00:00Z --duration 1h --summary "1:1 (long)"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Flags().Set("template", args[0]); err != nil {
+			return err
+		}
+		return applyTemplate(cmd)
+	},
+}
+
+var calendarTemplateRemoveCmd = &cobra.Command{
+	Use:               "remove <name>",
+	Aliases:           []string{"rm"},
+	Short:             "Delete an event template",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		key := "templates." + args[0]
+		if _, ok := cfg.Get(key); !ok {
+			return fmt.Errorf("no event template named %q", args[0])
+		}
+		cfg.Unset(key)
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %q\n", args[0])
+		return nil
+	},
+}
+
+// applyTemplate sets calendar create flags from the templates.<name> entry
+// named by --template. Flags given on the command line take precedence; --to
+// replaces the template's duration and --repeat its recurrence.
+func applyTemplate(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("template")
+	if name == "" {
+		return nil
+	}
+	// Errors from here on are in the config file, not the invocation
+	cmd.SilenceUsage = true
+
+	prefix := "templates." + name
+	if _, ok := userConfig.Get(prefix); !ok {
+		return fmt.Errorf("no event template named %q (see 'porteden calendar template list')", name)
+	}
+	for _, tf := range templateFlags {
+		key := prefix + "." + tf.field
+		v, ok := userConfig.Get(key)
+		f := cmd.Flags().Lookup(tf.flag)
+		if !ok || f.Changed ||
+			(tf.flag == "duration" && cmd.Flags().Changed("to")) ||
+			(tf.flag == "recurrence" && cmd.Flags().Changed("repeat")) {
+			continue
+		}
+		// List items may hold commas (RRULEs do), so they are set whole
+		// rather than parsed from a string
+		var err error
+		if list, isList := f.Value.(interface{ Replace([]string) error }); isList {
+			if err = list.Replace(userConfig.List(key)); err == nil {
+				f.Changed = true
+			}
+		} else {
+			err = cmd.Flags().Set(tf.flag, config.FormatValue(v))
+		}
+		if err != nil {
+			return fmt.Errorf("template %q: %s: %w", name, tf.field, err)
+		}
+	}
+	return nil
+}
+
+// loadTemplates returns the event templates in the config file, by name.
+// It reads the file itself because shell completion runs without the root
+// command's setup.
+func loadTemplates() []eventTemplate {
+	cfg, _ := config.Load()
+	byName := map[string]*eventTemplate{}
+	for _, key := range cfg.Keys() {
+		rest, ok := strings.CutPrefix(key, "templates.")
+		if !ok {
+			continue
+		}
+		name, field, _ := strings.Cut(rest, ".")
+		t := byName[name]
+		if t == nil {
+			t = &eventTemplate{Name: name}
+			byName[name] = t
+		}
+		switch field {
+		case "title":
+			t.Title = cfg.String(key)
+		case "duration":
+			t.Duration = cfg.String(key)
+		case "calendar":
+			t.Calendar = cfg.String(key)
+		case "description":
+			t.Description = cfg.String(key)
+		case "location":
+			t.Location = cfg.String(key)
+		case "attendees":
+			t.Attendees = cfg.List(key)
+		case "recurrence":
+			t.Recurrence = cfg.List(key)
+		}
+	}
+	templates := make([]eventTemplate, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, *t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// completeTemplateNames lists the event templates, for completion
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, t := range loadTemplates() {
+		names = append(names, t.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// shortDuration formats d without trailing zero units ("30m", not "30m0s")
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func init() {
+	calendarTemplateSaveCmd.Flags().String("summary", "", "Event title")
+	calendarTemplateSaveCmd.Flags().String("duration", "", "Length of the event, e.g. 30m, 1h30m or 2d")
+	calendarTemplateSaveCmd.Flags().String("calendar", "", "Calendar ID or name")
+	calendarTemplateSaveCmd.Flags().String("description", "", "Event description")
+	calendarTemplateSaveCmd.Flags().String("location", "", "Event location")
+	calendarTemplateSaveCmd.Flags().StringSlice("attendees", nil, "Attendee emails")
+	_ = calendarTemplateSaveCmd.RegisterFlagCompletionFunc("attendees", completeRecipients)
+	calendarTemplateSaveCmd.Flags().StringSlice("recurrence", nil, "RRULE recurrence patterns")
+	addRepeatFlags(calendarTemplateSaveCmd)
+
+	addCreateFlags(calendarTemplateApplyCmd)
+	calendarTemplateApplyCmd.Flags().String("template", "", "")
+	_ = calendarTemplateApplyCmd.Flags().MarkHidden("template")
+	calendarTemplateApplyCmd.RunE = createCmd.RunE
+
+	calendarTemplateCmd.AddCommand(calendarTemplateSaveCmd)
+	calendarTemplateCmd.AddCommand(calendarTemplateListCmd)
+	calendarTemplateCmd.AddCommand(calendarTemplateApplyCmd)
+	calendarTemplateCmd.AddCommand(calendarTemplateRemoveCmd)
+
+	calendarCmd.AddCommand(calendarTemplateCmd)
+}
@@ -0,0 +1,245 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/config"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// userConfig is the loaded config file; empty if it is missing or invalid
+var userConfig *config.File
+
+// loadUserConfig reads config.json, warning (rather than failing) on errors so
+// a broken file can still be repaired with 'porteden config edit'
+func loadUserConfig() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring config file: %v\n", err)
+	}
+	userConfig = cfg
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI settings",
+	Long: `Get and set persistent settings stored in ~/.config/porteden/config.json.
+
+Precedence: CLI flag > environment variable > config file > default.
+
+Examples:
+  porteden config get
+  porteden config get output.format
+  porteden config set output.format json
+  porteden config set api.max_wait 1m
+  porteden config unset output.format
+  porteden config edit`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Show a setting, or all settings",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			if _, err := config.LookupKey(args[0]); err != nil {
+				return err
+			}
+			v, ok := cfg.Get(args[0])
+			if !ok {
+				return fmt.Errorf("%s is not set", args[0])
+			}
+			if getOutputFormat(cmd) == output.FormatJSON {
+				output.PrintWithOptions(v, output.FormatJSON, output.PrintOptions{})
+			} else {
+				fmt.Println(config.FormatValue(v))
+			}
+			return nil
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			values := map[string]interface{}{}
+			for _, key := range cfg.Keys() {
+				values[key], _ = cfg.Get(key)
+			}
+			output.PrintWithOptions(values, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		output.TableHeader(w, "KEY", "VALUE", "DESCRIPTION")
+		shown := map[string]bool{}
+		for _, k := range config.Schema {
+			if strings.HasSuffix(k.Name, "*") {
+				continue
+			}
+			value := output.ColorGray("(unset)")
+			if v, ok := cfg.Get(k.Name); ok {
+				value = config.FormatValue(v)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", k.Name, value, k.Description)
+			shown[k.Name] = true
+		}
+		// Wildcard keys (e.g. filters.<name>) are listed only when set
+		for _, key := range cfg.Keys() {
+			if !shown[key] {
+				v, _ := cfg.Get(key)
+				k, _ := config.LookupKey(key)
+				description := ""
+				if k != nil {
+					description = k.Description
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", key, config.FormatValue(v), description)
+			}
+		}
+		return w.Flush()
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Set %s\n", args[0])
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.LookupKey(args[0]); err != nil {
+			return err
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		cfg.Unset(args[0])
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Unset %s\n", args[0])
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Long: `Open config.json in $VISUAL or $EDITOR. The edited file is validated
+before it is saved; on errors you can re-open it to fix them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.FilePath()
+		if err != nil {
+			return err
+		}
+		original, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+		if len(bytes.TrimSpace(original)) == 0 {
+			original = []byte("{\n}\n")
+		}
+
+		tmp, err := os.CreateTemp("", "porteden-config-*.json")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		if _, err := tmp.Write(original); err != nil {
+			tmp.Close()
+			return err
+		}
+		tmp.Close()
+
+		for {
+			if err := runEditor(tmp.Name()); err != nil {
+				return err
+			}
+			edited, err := os.ReadFile(tmp.Name())
+			if err != nil {
+				return err
+			}
+
+			err = config.Parse(edited, map[string]interface{}{})
+			if err == nil {
+				if bytes.Equal(edited, original) {
+					fmt.Println("No changes.")
+					return nil
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, edited, 0600); err != nil {
+					return fmt.Errorf("failed to write config: %w", err)
+				}
+				fmt.Printf("Saved %s\n", path)
+				return nil
+			}
+
+			fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+			if !auth.IsInteractiveTerminal() || i18n.No(readLine(i18n.T("Edit again?")+" "+i18n.T("[Y/n]")+": ")) {
+				return fmt.Errorf("config not saved")
+			}
+		}
+	},
+}
+
+// runEditor opens path in the user's editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// EDITOR may include arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// countResult is the JSON output of the count commands
+type countResult struct {
+	Count int `json:"count"`
+}
+
+var calendarCountCmd = &cobra.Command{
+	Use:   "count",
+	Short: "Count calendar events",
+	Long: `Print how many events match, without listing them. Takes the same time
+range and search flags as 'calendar events'.
+
+Examples:
+  porteden calendar count --today
+  porteden calendar count --week -q "interview"
+  porteden calendar count --month --attendees cfo@example.com`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		params, err := buildEventParams(cmd, client)
+		if err != nil {
+			return err
+		}
+		params.Limit = 1
+
+		resp, err := client.GetEvents(params)
+		if err != nil {
+			return formatError(err)
+		}
+		count := len(resp.Events)
+		if resp.Meta != nil {
+			count = max(count, resp.Meta.TotalCount)
+		}
+		if resp.Meta == nil || resp.Meta.HasMore && resp.Meta.TotalCount == 0 {
+			// No total from the API: page through to count
+			if resp, err = client.GetAllEvents(params); err != nil {
+				return formatError(err)
+			}
+			count = len(resp.Events)
+		}
+
+		printCount(cmd, count)
+		return nil
+	},
+}
+
+var emailCountCmd = &cobra.Command{
+	Use:   "count",
+	Short: "Count emails",
+	Long: `Print how many emails match, without listing them. Takes the same search
+and time flags as 'email messages'.
+
+Examples:
+  porteden email count --unread
+  porteden email count --today --from boss@example.com
+  if [ "$(porteden email count --unread --label IMPORTANT)" -gt 0 ]; then echo "Check mail"; fi`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		params, err := buildEmailParams(cmd)
+		if err != nil {
+			return err
+		}
+		params.Limit = 1
+
+		resp, err := client.GetEmails(params)
+		if err != nil {
+			return formatError(err)
+		}
+		count := max(len(resp.Emails), resp.TotalCount)
+		if resp.HasMore && resp.TotalCount == 0 {
+			// No total from the API: page through to count
+			params.Limit = 50
+			if resp, err = client.GetAllEmails(params); err != nil {
+				return formatError(err)
+			}
+			count = len(resp.Emails)
+		}
+
+		printCount(cmd, count)
+		return nil
+	},
+}
+
+// printCount prints just the number, or {"count": n} as JSON
+func printCount(cmd *cobra.Command, count int) {
+	if getOutputFormat(cmd) == output.FormatJSON {
+		output.Print(countResult{Count: count}, output.FormatJSON)
+		return
+	}
+	fmt.Println(count)
+}
+
+func init() {
+	calendarCountCmd.Flags().String("calendar", "", "Filter by calendar (ID or name)")
+	calendarCountCmd.Flags().Bool("include-cancelled", false, "Include cancelled events (default: false)")
+	calendarCountCmd.Flags().StringP("query", "q", "", "Keyword search in title, description, location")
+	calendarCountCmd.Flags().String("attendees", "", "Comma-separated attendee emails to filter by")
+	addEmailFilterFlags(emailCountCmd)
+
+	calendarCmd.AddCommand(calendarCountCmd)
+	emailCmd.AddCommand(emailCountCmd)
+}
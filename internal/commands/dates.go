@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/output"
+)
+
+// Helper function to parse date/datetime strings
+func parseDateTime(s string) (time.Time, error) {
+	// Try RFC3339 first (full datetime)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	// Try date only (YYYY-MM-DD)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	// Try a time with a zone offset or abbreviation, e.g. 2026-03-01 14:00 PST
+	if m := zonedTime.FindStringSubmatch(s); m != nil {
+		return parseZoned(m[1], m[2], m[3])
+	}
+
+	if hint, why := suggestDate(s); hint != "" {
+		return time.Time{}, fmt.Errorf("invalid date format (use YYYY-MM-DD or RFC3339): did you mean %s?%s", hint, why)
+	}
+	return time.Time{}, fmt.Errorf("invalid date format (use YYYY-MM-DD, RFC3339 or a time with a zone such as \"2026-03-01 14:00 PST\")")
+}
+
+// zonedTime matches an optional date, a time, and a zone offset or
+// abbreviation
+var zonedTime = regexp.MustCompile(`^(?:(\d{4}-\d{2}-\d{2})[T ])?(\d{1,2}:\d{2}(?::\d{2})?) ?(Z|[+-]\d{2}:?\d{2}|[A-Za-z]{2,5})$`)
+
+// zoneOffsets are the UTC offsets of common zone abbreviations, used when
+// the abbreviation isn't the output timezone's own
+var zoneOffsets = map[string]int{
+	"UTC": 0, "GMT": 0, "Z": 0,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5,
+	"MST": -7, "MDT": -6, "PST": -8, "PDT": -7,
+	"AKST": -9, "AKDT": -8, "HST": -10,
+	"WET": 0, "WEST": 1, "BST": 1, "CET": 1, "CEST": 2, "EET": 2, "EEST": 3,
+	"JST": 9, "KST": 9, "AEST": 10, "AEDT": 11, "NZST": 12, "NZDT": 13,
+}
+
+// parseZoned parses a time in the given zone, on date or, without one, on
+// today's date in that zone. An abbreviation is first matched against the
+// output timezone, so "CET" and "CEST" follow Europe/Berlin's rules when
+// that is the output timezone.
+func parseZoned(date, clock, zone string) (time.Time, error) {
+	loc, err := zoneLocation(strings.ToUpper(zone), date, clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if date == "" {
+		date = time.Now().In(loc).Format("2006-01-02")
+	}
+	t, err := time.ParseInLocation(clockLayout(clock), date+" "+clock, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use HH:MM", clock)
+	}
+	return t, nil
+}
+
+// clockLayout is the layout of a date followed by clock, which has one or
+// two hour digits and may have seconds
+func clockLayout(clock string) string {
+	if strings.Count(clock, ":") == 2 {
+		return "2006-01-02 15:04:05"
+	}
+	return "2006-01-02 15:04"
+}
+
+// zoneLocation resolves a zone offset such as +02:00 or an abbreviation
+func zoneLocation(zone, date, clock string) (*time.Location, error) {
+	if zone[0] == '+' || zone[0] == '-' {
+		digits := strings.ReplaceAll(zone[1:], ":", "")
+		offset := (atoi(digits[:2])*60 + atoi(digits[2:])) * 60
+		if zone[0] == '-' {
+			offset = -offset
+		}
+		return time.FixedZone(zone, offset), nil
+	}
+
+	out := output.GetOutputLocation()
+	if date != "" {
+		if t, err := time.ParseInLocation(clockLayout(clock), date+" "+clock, out); err == nil {
+			if name, _ := t.Zone(); name == zone {
+				return out, nil
+			}
+		}
+	} else if name, _ := time.Now().In(out).Zone(); name == zone {
+		return out, nil
+	}
+
+	hours, ok := zoneOffsets[zone]
+	if !ok {
+		return nil, fmt.Errorf("unknown time zone %q: use an offset such as +02:00", zone)
+	}
+	return time.FixedZone(zone, hours*3600), nil
+}
+
+var (
+	slashDate = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/(\d{4})$`)   // 03/04/2026
+	dotDate   = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})\.(\d{4})$`) // 04.03.2026
+	isoSlash  = regexp.MustCompile(`^(\d{4})/(\d{1,2})/(\d{1,2})$`)   // 2026/03/04
+	zoneless  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2})?$`)
+)
+
+// suggestDate guesses what a date that failed to parse was meant to be and
+// returns it in a form parseDateTime accepts, plus any explanation worth
+// adding. The guess is "" if there isn't a good one.
+func suggestDate(s string) (string, string) {
+	if m := slashDate.FindStringSubmatch(s); m != nil {
+		a, b := atoi(m[1]), atoi(m[2])
+		if a > 12 {
+			// Can't be a month, so day/month/year
+			return isoDate(m[3], b, a), ""
+		}
+		return isoDate(m[3], a, b), " (read as month/day/year)"
+	}
+	if m := dotDate.FindStringSubmatch(s); m != nil {
+		return isoDate(m[3], atoi(m[2]), atoi(m[1])), ""
+	}
+	if m := isoSlash.FindStringSubmatch(s); m != nil {
+		return isoDate(m[1], atoi(m[2]), atoi(m[3])), ""
+	}
+	if zoneless.MatchString(s) {
+		for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+			if t, err := time.ParseInLocation(layout, s, output.GetOutputLocation()); err == nil {
+				return t.Format(time.RFC3339), " (times need a time zone)"
+			}
+		}
+	}
+	return "", ""
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// isoDate formats a date as YYYY-MM-DD, or returns "" if it doesn't exist
+func isoDate(year string, month, day int) string {
+	t, err := time.Parse("2006-1-2", fmt.Sprintf("%s-%d-%d", year, month, day))
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// checkDateRange makes sure a range given by two flags ends after it starts,
+// suggesting the flags the other way round when they look swapped
+func checkDateRange(fromFlag, toFlag, fromStr, toStr string, from, to time.Time) error {
+	if from.Before(to) {
+		return nil
+	}
+	if from.After(to) {
+		return fmt.Errorf("--%s %s is after --%s %s: did you mean --%s %s --%s %s?",
+			fromFlag, fromStr, toFlag, toStr, fromFlag, toStr, toFlag, fromStr)
+	}
+	return fmt.Errorf("--%s must be later than --%s", toFlag, fromFlag)
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	t.Setenv("PE_TIMEZONE", "Europe/Berlin")
+
+	tests := []struct {
+		in   string
+		want string // RFC3339 in UTC
+	}{
+		{"2026-03-01T14:00:00Z", "2026-03-01T14:00:00Z"},
+		{"2026-03-01T14:00:00+02:00", "2026-03-01T12:00:00Z"},
+		{"2026-03-01", "2026-03-01T00:00:00Z"},
+		{"2026-03-01T14:00Z", "2026-03-01T14:00:00Z"},
+		{"2026-03-01 14:00 Z", "2026-03-01T14:00:00Z"},
+		{"2026-03-01 14:00 +05:30", "2026-03-01T08:30:00Z"},
+		{"2026-03-01T14:00+0530", "2026-03-01T08:30:00Z"},
+		{"2026-03-01 14:00 -03:00", "2026-03-01T17:00:00Z"},
+		{"2026-03-01 14:00 PST", "2026-03-01T22:00:00Z"},
+		{"2026-03-01 9:30 PST", "2026-03-01T17:30:00Z"},
+		{"2026-03-01 09:30 pst", "2026-03-01T17:30:00Z"},
+		{"2026-03-01 14:00:30 PST", "2026-03-01T22:00:30Z"},
+		{"2026-03-01 9:05:07 EST", "2026-03-01T14:05:07Z"},
+		{"2026-07-01 9:30 EDT", "2026-07-01T13:30:00Z"},
+		// Berlin's clocks go forward at 02:00 on 29 March 2026
+		{"2026-03-28 12:00 CET", "2026-03-28T11:00:00Z"},
+		{"2026-03-29 1:30 CET", "2026-03-29T00:30:00Z"},
+		{"2026-03-29 9:30 CEST", "2026-03-29T07:30:00Z"},
+		{"2026-03-29 12:00:15 CEST", "2026-03-29T10:00:15Z"},
+		// Not the output timezone's abbreviation at the time: a fixed offset
+		{"2026-01-15 12:00 CEST", "2026-01-15T10:00:00Z"},
+		{"2026-07-15 12:00 CET", "2026-07-15T11:00:00Z"},
+	}
+	for _, tt := range tests {
+		got, err := parseDateTime(tt.in)
+		if err != nil {
+			t.Errorf("parseDateTime(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if s := got.UTC().Format(time.RFC3339); s != tt.want {
+			t.Errorf("parseDateTime(%q) = %s, want %s", tt.in, s, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"2026-03-01 14:00", "2026-03-01 25:00 PST", "2026-03-01 14:00 XYZ", "03/01/2026", "tomorrow"} {
+		if got, err := parseDateTime(bad); err == nil {
+			t.Errorf("parseDateTime(%q) = %v, want an error", bad, got)
+		}
+	}
+}
+
+func TestParseDateTimeToday(t *testing.T) {
+	t.Setenv("PE_TIMEZONE", "Europe/Berlin")
+
+	// Without a date, the time is on today's date in the zone given
+	for _, in := range []string{"9:30 PST", "09:30 PST", "9:30:00 PST", "9:30 -08:00"} {
+		got, err := parseDateTime(in)
+		if err != nil {
+			t.Errorf("parseDateTime(%q) failed: %v", in, err)
+			continue
+		}
+		_, offset := got.Zone()
+		today := time.Now().In(time.FixedZone("", -8*3600)).Format("2006-01-02")
+		if got.Hour() != 9 || got.Minute() != 30 || offset != -8*3600 || got.Format("2006-01-02") != today {
+			t.Errorf("parseDateTime(%q) = %v, want 09:30 -08:00 today (%s)", in, got, today)
+		}
+	}
+}
+
+func TestZoneLocation(t *testing.T) {
+	t.Setenv("PE_TIMEZONE", "America/New_York")
+
+	tests := []struct {
+		zone, date, clock string
+		wantName          string // location name; "" for a fixed offset
+		wantOffset        int    // seconds east of UTC at date and clock
+	}{
+		{"+05:30", "2026-03-01", "9:00", "", 5*3600 + 1800},
+		{"+0530", "2026-03-01", "9:00", "", 5*3600 + 1800},
+		{"-0800", "", "14:00", "", -8 * 3600},
+		{"EST", "2026-03-07", "9:00", "America/New_York", -5 * 3600},
+		// New York's clocks go forward at 02:00 on 8 March 2026
+		{"EST", "2026-03-08", "1:59", "America/New_York", -5 * 3600},
+		{"EDT", "2026-03-08", "3:00", "America/New_York", -4 * 3600},
+		{"EDT", "2026-03-08", "10:00:30", "America/New_York", -4 * 3600},
+		{"EDT", "2026-01-15", "9:00", "", -4 * 3600},
+		{"PST", "2026-03-08", "9:00", "", -8 * 3600},
+		{"JST", "2026-03-08", "23:00", "", 9 * 3600},
+	}
+	for _, tt := range tests {
+		loc, err := zoneLocation(tt.zone, tt.date, tt.clock)
+		if err != nil {
+			t.Errorf("zoneLocation(%q, %q, %q) failed: %v", tt.zone, tt.date, tt.clock, err)
+			continue
+		}
+		name := ""
+		if loc.String() == "America/New_York" {
+			name = loc.String()
+		}
+		date := tt.date
+		if date == "" {
+			date = "2026-03-01"
+		}
+		at, err := time.ParseInLocation(clockLayout(tt.clock), date+" "+tt.clock, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, offset := at.Zone(); name != tt.wantName || offset != tt.wantOffset {
+			t.Errorf("zoneLocation(%q, %q, %q) = %s with offset %d, want %q with %d", tt.zone, tt.date, tt.clock, loc, offset, tt.wantName, tt.wantOffset)
+		}
+	}
+
+	if _, err := zoneLocation("XYZ", "2026-03-01", "9:00"); err == nil {
+		t.Error("zoneLocation accepted an unknown abbreviation")
+	}
+}
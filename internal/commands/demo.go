@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/fakeserver"
+	"github.com/spf13/cobra"
+)
+
+// demoProfile is the reserved profile name that serves built-in sample data
+const demoProfile = "demo"
+
+var demoMode bool
+
+// isDemo reports whether the command should run against sample data instead
+// of a PortEden account (--demo or --profile demo)
+func isDemo(cmd *cobra.Command) bool {
+	return getProfile(cmd) == demoProfile
+}
+
+// newDemoClient returns a client backed by an in-process fake server. Changes
+// made in demo mode only last for the current command.
+func newDemoClient() *api.Client {
+	return newAPIClient(demoProfile).WithBaseTransport(fakeserver.New())
+}
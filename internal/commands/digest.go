@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Today's agenda plus unread email highlights",
+	Long: `Compose today's agenda and unread email highlights into a single summary.
+
+Designed to run from cron and be piped to mail or chat tools.
+
+Examples:
+  porteden digest
+  porteden digest --tomorrow
+  porteden digest --hide-declined
+  porteden digest --style markdown
+  porteden digest --style html | mail -a "Content-Type: text/html" -s "Digest" me@example.com
+  porteden digest --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style, _ := cmd.Flags().GetString("style")
+		switch output.DigestStyle(style) {
+		case output.DigestText, output.DigestMarkdown, output.DigestHTML:
+		default:
+			return fmt.Errorf("invalid style: %s (must be text, markdown, or html)", style)
+		}
+
+		maxEmails, _ := cmd.Flags().GetInt("max-emails")
+		tomorrow, _ := cmd.Flags().GetBool("tomorrow")
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		day := time.Now()
+		if tomorrow {
+			day = day.AddDate(0, 0, 1)
+		}
+		from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+
+		events, err := client.GetAllEvents(api.EventParams{
+			From:  from,
+			To:    from.AddDate(0, 0, 1),
+			Limit: 50,
+		})
+		if err != nil {
+			return formatError(err)
+		}
+
+		unread := true
+		emails, err := client.GetEmails(api.EmailParams{
+			Unread: &unread,
+			Limit:  maxEmails,
+		})
+		if err != nil {
+			return formatError(err)
+		}
+
+		digest := &output.Digest{
+			Date:        output.DigestDate(from),
+			Events:      filterEvents(cmd, events.Events, events.CurrentUserCalendarEmail),
+			Unread:      emails.Emails,
+			UnreadTotal: emails.TotalCount,
+			MoreUnread:  emails.HasMore && emails.TotalCount <= len(emails.Emails),
+		}
+		if digest.UnreadTotal < len(digest.Unread) {
+			digest.UnreadTotal = len(digest.Unread)
+		}
+
+		sort.SliceStable(digest.Events, func(i, j int) bool {
+			return digest.Events[i].StartUtc.Before(digest.Events[j].StartUtc)
+		})
+		// Surface high-importance mail first, keeping the API's recency order otherwise
+		sort.SliceStable(digest.Unread, func(i, j int) bool {
+			return digest.Unread[i].Importance == "high" && digest.Unread[j].Importance != "high"
+		})
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.Print(digest, output.FormatJSON)
+			return nil
+		}
+
+		output.RenderDigest(os.Stdout, digest, output.DigestStyle(style))
+		return nil
+	},
+}
+
+func init() {
+	digestCmd.Flags().String("style", "text", "Digest markup: text, markdown, html")
+	digestCmd.Flags().Int("max-emails", 10, "Maximum unread emails to highlight")
+	digestCmd.Flags().Bool("tomorrow", false, "Build the digest for tomorrow instead of today")
+	digestCmd.Flags().Bool("hide-declined", false, "Leave out events you declined (default from calendar.hide_declined)")
+}
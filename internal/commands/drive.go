@@ -1,14 +1,13 @@
 package commands
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/porteden/cli/internal/api"
 	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
 	"github.com/porteden/cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -49,6 +48,20 @@ Examples:
 		}
 
 		params := buildDriveListParams(cmd)
+
+		if stream, _ := cmd.Flags().GetBool("stream"); stream {
+			s := newStreamer(cmd, client)
+			defer s.Close()
+			hasMore, err := client.ForEachDriveFilesPage(params, func(resp *api.DriveFilesResponse) error {
+				s.Page(resp)
+				return s.Stop()
+			})
+			if hasMore {
+				fmt.Fprintln(os.Stderr, "Warning: pagination cap reached (50 pages). Results may be incomplete.")
+			}
+			return formatError(pagingError(err))
+		}
+
 		fetchAll, _ := cmd.Flags().GetBool("all")
 
 		var response *api.DriveFilesResponse
@@ -65,7 +78,8 @@ Examples:
 		}
 
 		output.PrintWithOptions(response, getOutputFormat(cmd), output.PrintOptions{
-			Compact: IsCompactMode(),
+			Compact:   IsCompactMode(),
+			Highlight: searchQuery(cmd),
 		})
 		return nil
 	},
@@ -308,11 +322,8 @@ Examples:
 
 func runDeleteFile(client *api.Client, fileID string, yes bool) error {
 	if !yes && auth.IsInteractiveTerminal() {
-		fmt.Printf("Move file '%s' to trash? [y/N]: ", fileID)
-		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
-		choice := strings.TrimSpace(strings.ToLower(line))
-		if choice != "y" && choice != "yes" {
-			fmt.Println("Cancelled.")
+		if !confirm(i18n.T("Move file '%s' to trash?", fileID)) {
+			fmt.Println(i18n.T("Cancelled."))
 			return nil
 		}
 	}
@@ -461,6 +472,7 @@ func init() {
 	driveFilesCmd.Flags().String("modified-before", "", "Files modified before date (ISO 8601)")
 	driveFilesCmd.Flags().Int("limit", 25, "Results per page (1-100)")
 	driveFilesCmd.Flags().Bool("all", false, "Auto-paginate to fetch all results")
+	driveFilesCmd.Flags().Bool("stream", false, "Auto-paginate, printing each page as it arrives (NDJSON with --json)")
 	driveFilesCmd.Flags().String("order-by", "modified_time", "Sort field: name, modified_time, created_time, size")
 
 	// upload flags
@@ -3,11 +3,14 @@ package commands
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/markdown"
 	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/refs"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +31,15 @@ Examples:
   porteden email messages --today
   porteden email messages --from boss@example.com
   porteden email messages -q "project update"
-  porteden email messages --subject invoice --after 2026-02-01`,
+  porteden email messages --subject invoice --after 2026-02-01
+  porteden email messages --week --group-threads
+  porteden email messages --today --order asc
+  porteden email messages --unread --since-token=          # Full listing plus a sync token
+  porteden email messages --unread --since-token <token>   # Only what changed since
+
+With --since-token, only emails that arrived or changed since the token was
+issued are listed, and deleted email IDs are reported. Repeat the same
+filters each time; the next token is printed on stderr (in the JSON with -j).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getClient(cmd)
 		if err != nil {
@@ -40,20 +51,90 @@ Examples:
 			return err
 		}
 
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		groupThreads, _ := cmd.Flags().GetBool("group-threads")
+		order, err := listOrder(cmd)
+		if err != nil {
+			return err
+		}
+		token, syncing, err := sinceToken(cmd)
+		if err != nil {
+			return err
+		}
+		params.SyncToken = token
+
+		if stream, _ := cmd.Flags().GetBool("stream"); stream {
+			if groupThreads {
+				return fmt.Errorf("--group-threads cannot be combined with --stream")
+			}
+			includeBody := params.IncludeBody
+			params.IncludeBody = false
+			s := newStreamer(cmd, client)
+			defer s.Close()
+			var seen []api.Email
+			defer func() {
+				rememberEmails(cmd, seen)
+				rememberListing(cmd, refs.KindEmail, emailIDs(seen))
+			}()
+			_, err := client.ForEachEmailsPage(params, func(resp *api.EmailsResponse) error {
+				if includeBody {
+					if err := client.FetchEmailBodies(resp.Emails, concurrency); err != nil {
+						return err
+					}
+				}
+				s.Page(resp)
+				seen = append(seen, resp.Emails...)
+				return s.Stop()
+			})
+			return formatError(pagingError(err))
+		}
+
 		fetchAll, _ := cmd.Flags().GetBool("all")
+		fetchAll = fetchAll || syncing
 		var response *api.EmailsResponse
 
-		if fetchAll {
+		if fetchAll && params.IncludeBody {
+			// Page through lightweight listings, then fetch bodies concurrently
+			params.IncludeBody = false
+			response, err = client.GetAllEmails(params)
+			if err == nil {
+				err = client.FetchEmailBodies(response.Emails, concurrency)
+			}
+		} else if fetchAll {
 			response, err = client.GetAllEmails(params)
 		} else {
 			response, err = client.GetEmails(params)
 		}
 		if err != nil {
-			return formatError(err)
+			return syncTokenError(err)
+		}
+		sortEmails(response.Emails, order)
+		rememberEmails(cmd, response.Emails)
+		if syncing {
+			defer printSyncState(cmd, "emails", response.DeletedIDs, response.NextSyncToken)
 		}
 
+		if groupThreads {
+			// Row references pick the latest listed message of each thread
+			groups := output.GroupByThread(response.Emails)
+			if order == orderAsc {
+				slices.Reverse(groups)
+			}
+			latest := make([]string, len(groups))
+			for i, g := range groups {
+				latest[i] = g.LatestID
+			}
+			rememberListing(cmd, refs.KindEmail, latest)
+			output.PrintWithOptions(groups, getOutputFormat(cmd), output.PrintOptions{
+				Highlight: searchQuery(cmd),
+			})
+			return nil
+		}
+		rememberListing(cmd, refs.KindEmail, emailIDs(response.Emails))
+
 		output.PrintWithOptions(response, getOutputFormat(cmd), output.PrintOptions{
-			Compact: IsCompactMode(),
+			Compact:   IsCompactMode(),
+			Highlight: searchQuery(cmd),
 		})
 		return nil
 	},
@@ -62,16 +143,35 @@ Examples:
 var messageCmd = &cobra.Command{
 	Use:   "message <emailId>",
 	Short: "Get a single email",
-	Args:  cobra.ExactArgs(1),
+	Long: `Get a single email.
+
+--headers prints every header of the original message instead, in the order
+they appear: the Received chain, SPF, DKIM and DMARC results, Message-ID and
+so on. Useful for working out why mail was delayed or marked as spam.
+
+Examples:
+  porteden email message <emailId>
+  porteden email message <emailId> --headers
+  porteden email message <emailId> --headers -j`,
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		emailID := args[0]
 		includeBody, _ := cmd.Flags().GetBool("include-body")
+		showHeaders, _ := cmd.Flags().GetBool("headers")
 
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
 
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		if showHeaders {
+			return printMessageHeaders(cmd, client, emailID)
+		}
+
 		email, err := client.GetEmail(emailID, includeBody)
 		if err != nil {
 			return formatError(err)
@@ -84,22 +184,60 @@ var messageCmd = &cobra.Command{
 	},
 }
 
+// printMessageHeaders prints all headers of an email's original message
+func printMessageHeaders(cmd *cobra.Command, client *api.Client, emailID string) error {
+	raw, err := client.GetEmailRaw(emailID)
+	if err != nil {
+		return formatError(err)
+	}
+	headers, err := api.ParseHeaders(raw)
+	if err != nil {
+		return err
+	}
+
+	if getOutputFormat(cmd) == output.FormatJSON {
+		output.PrintWithOptions(headers, output.FormatJSON, output.PrintOptions{})
+		return nil
+	}
+	for _, h := range headers {
+		fmt.Printf("%s %s\n", output.ColorBold(h.Name+":"), h.Value)
+	}
+	return nil
+}
+
 var threadCmd = &cobra.Command{
 	Use:   "thread <threadId>",
 	Short: "Get an email thread",
-	Args:  cobra.ExactArgs(1),
+	Long: `Get all messages of an email thread.
+
+Full message bodies are included by default. For a quick look at a long
+thread, --headers-only skips them: senders, recipients, dates, subjects and
+short previews are still shown.
+
+Examples:
+  porteden email thread <threadId>
+  porteden email thread <threadId> --headers-only`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		threadID := args[0]
+		includeBodies, _ := cmd.Flags().GetBool("include-bodies")
+		if headersOnly, _ := cmd.Flags().GetBool("headers-only"); headersOnly {
+			if includeBodies && cmd.Flags().Changed("include-bodies") {
+				return fmt.Errorf("--headers-only and --include-bodies contradict each other")
+			}
+			includeBodies = false
+		}
 
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
 
-		thread, err := client.GetThread(threadID)
+		thread, err := client.GetThread(threadID, includeBodies)
 		if err != nil {
 			return formatError(err)
 		}
+		rememberEmails(cmd, thread.Messages)
 
 		output.PrintWithOptions(thread, getOutputFormat(cmd), output.PrintOptions{
 			Compact: IsCompactMode(),
@@ -115,7 +253,9 @@ var sendEmailCmd = &cobra.Command{
 
 Examples:
   porteden email send --to user@example.com --subject "Hello" --body "Hi there"
-  porteden email send --to user@example.com --cc team@example.com --subject "Update" --body-file message.txt`,
+  porteden email send --to user@example.com --cc team@example.com --subject "Update" --body-file message.txt
+  porteden email send --to user@example.com --subject "Notes" --body-markdown "**Done:** shipped the *beta*"
+  porteden email send --to user@example.com --subject "Notes" --body-file notes.md --body-type markdown`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getClient(cmd)
 		if err != nil {
@@ -126,13 +266,15 @@ Examples:
 		if err != nil {
 			return err
 		}
+		checkRecipientTypos(cmd, participantEmails(req.To, req.CC, req.BCC))
 
 		resp, err := client.SendEmail(req)
 		if err != nil {
-			return formatError(err)
+			return queueFailedEmail(cmd, outboxSend, "", outboxSummary(outboxSend, "", req.Subject, req.To), req, err)
 		}
 
 		if resp.Success {
+			rememberRecipients(cmd, participantAddresses(req.To, req.CC, req.BCC))
 			fmt.Printf("Email sent successfully")
 			if resp.EmailID != "" {
 				fmt.Printf(" (ID: %s)", resp.EmailID)
@@ -153,11 +295,15 @@ var replyEmailCmd = &cobra.Command{
 
 Examples:
   porteden email reply <emailId> --body "Thanks for the update"
-  porteden email reply <emailId> --body-file reply.txt --reply-all`,
-	Args: cobra.ExactArgs(1),
+  porteden email reply <emailId> --body-file reply.txt --reply-all
+  porteden email reply <emailId> --edit
+
+With --edit the reply opens in $VISUAL or $EDITOR with the original quoted
+below it ("> " prefixed, under an "On <date>, <sender> wrote:" line) and the
+email.signature config value above the quote. Any --body text starts the
+reply. It is sent as plain text, or converted with --body-type markdown.`,
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		emailID := args[0]
-
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
@@ -168,9 +314,22 @@ Examples:
 			return err
 		}
 
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+		if edit, _ := cmd.Flags().GetBool("edit"); edit {
+			if err := editReply(cmd, client, emailID, &req); err != nil {
+				return err
+			}
+		}
+		if err := addReplyAutoCopies(cmd, client, emailID, &req); err != nil {
+			return err
+		}
+
 		resp, err := client.ReplyToEmail(emailID, req)
 		if err != nil {
-			return formatError(err)
+			return queueFailedEmail(cmd, outboxReply, emailID, outboxSummary(outboxReply, emailID, "", nil), req, err)
 		}
 
 		if resp.Success {
@@ -195,10 +354,8 @@ var forwardEmailCmd = &cobra.Command{
 Examples:
   porteden email forward <emailId> --to colleague@example.com
   porteden email forward <emailId> --to user@example.com --body "FYI"`,
-	Args: cobra.ExactArgs(1),
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		emailID := args[0]
-
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
@@ -208,13 +365,20 @@ Examples:
 		if err != nil {
 			return err
 		}
+		checkRecipientTypos(cmd, participantEmails(req.To, req.CC, req.BCC))
+
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
 
 		resp, err := client.ForwardEmail(emailID, req)
 		if err != nil {
-			return formatError(err)
+			return queueFailedEmail(cmd, outboxForward, emailID, outboxSummary(outboxForward, emailID, "", req.To), req, err)
 		}
 
 		if resp.Success {
+			rememberRecipients(cmd, participantAddresses(req.To, req.CC, req.BCC))
 			fmt.Printf("Email forwarded successfully")
 			if resp.EmailID != "" {
 				fmt.Printf(" (ID: %s)", resp.EmailID)
@@ -231,15 +395,29 @@ Examples:
 var deleteEmailCmd = &cobra.Command{
 	Use:   "delete <emailId>",
 	Short: "Delete (trash) an email",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		emailID := args[0]
+	Long: `Move an email to the trash.
 
+Without an email ID on a terminal, pick any number of recent emails to delete at once.`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
 		}
 
+		if len(args) == 0 {
+			chosen, err := pickEmails(cmd, client, "Delete emails>")
+			if err != nil {
+				return err
+			}
+			return runBulk("Delete", "email", chosen, client.DeleteEmail)
+		}
+
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
 		if err := client.DeleteEmail(emailID); err != nil {
 			return formatError(err)
 		}
@@ -249,6 +427,52 @@ var deleteEmailCmd = &cobra.Command{
 	},
 }
 
+var archiveEmailCmd = &cobra.Command{
+	Use:   "archive <emailId>",
+	Short: "Archive an email",
+	Long: `Archive an email: remove it from the inbox and mark it read.
+
+Without an email ID on a terminal, pick any number of recent emails to archive at once.
+
+Examples:
+  porteden email archive <emailId>
+  porteden email archive                        # Choose emails interactively`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		archive := func(id string) error { return archiveEmail(client, id) }
+		if len(args) == 0 {
+			chosen, err := pickEmails(cmd, client, "Archive emails>")
+			if err != nil {
+				return err
+			}
+			return runBulk("Archive", "email", chosen, archive)
+		}
+
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		if err := archive(emailID); err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Email archived: %s\n", emailID)
+		return nil
+	},
+}
+
+// archiveEmail removes an email from the inbox and marks it read
+func archiveEmail(client *api.Client, emailID string) error {
+	read := true
+	return client.ModifyEmail(emailID, api.ModifyEmailRequest{MarkAsRead: &read, RemoveLabels: []string{"INBOX"}})
+}
+
 var modifyEmailCmd = &cobra.Command{
 	Use:   "modify <emailId>",
 	Short: "Modify email properties",
@@ -259,10 +483,8 @@ Examples:
   porteden email modify <emailId> --mark-unread
   porteden email modify <emailId> --add-labels IMPORTANT,STARRED
   porteden email modify <emailId> --remove-labels INBOX`,
-	Args: cobra.ExactArgs(1),
+	Args: pickableArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		emailID := args[0]
-
 		client, err := getClient(cmd)
 		if err != nil {
 			return err
@@ -273,6 +495,11 @@ Examples:
 			return err
 		}
 
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
 		if err := client.ModifyEmail(emailID, req); err != nil {
 			return formatError(err)
 		}
@@ -284,27 +511,21 @@ Examples:
 
 func init() {
 	// Messages command flags (search/filter)
-	messagesCmd.Flags().StringP("query", "q", "", "Free-text search query")
-	messagesCmd.Flags().String("from", "", "Filter by sender email")
-	messagesCmd.Flags().String("to", "", "Filter by recipient email")
-	messagesCmd.Flags().String("subject", "", "Filter by subject (partial match)")
-	messagesCmd.Flags().String("label", "", "Filter by label/category")
-	messagesCmd.Flags().Bool("unread", false, "Show only unread emails")
-	messagesCmd.Flags().Bool("has-attachment", false, "Show only emails with attachments")
+	addEmailFilterFlags(messagesCmd)
 	messagesCmd.Flags().Int("limit", 20, "Maximum emails to return (1-50)")
 	messagesCmd.Flags().Bool("include-body", false, "Include full email body in results")
 	messagesCmd.Flags().Bool("all", false, "Fetch all pages")
-
-	// Time filters for messages
-	messagesCmd.Flags().Bool("today", false, "Show today's emails")
-	messagesCmd.Flags().Bool("yesterday", false, "Show yesterday's emails")
-	messagesCmd.Flags().Bool("week", false, "Show this week's emails")
-	messagesCmd.Flags().Int("days", 0, "Show emails from the last N days")
-	messagesCmd.Flags().String("after", "", "Emails after this date (YYYY-MM-DD or RFC3339)")
-	messagesCmd.Flags().String("before", "", "Emails before this date (YYYY-MM-DD or RFC3339)")
+	messagesCmd.Flags().String("since-token", "", sinceTokenUsage)
+	messagesCmd.Flags().Bool("stream", false, "Fetch all pages, printing each page as it arrives (NDJSON with --json)")
+	messagesCmd.Flags().Int("concurrency", 8, "Parallel body requests with --include-body --all")
+	messagesCmd.Flags().Bool("group-threads", false, "Show one row per thread with message counts and last activity")
+	messagesCmd.Flags().String("order", orderDesc, "Sort by received time: desc (most recent first) or asc")
 
 	// Message command flags
 	messageCmd.Flags().Bool("include-body", true, "Include full email body")
+	messageCmd.Flags().Bool("headers", false, "Print all headers of the original message (Received, SPF/DKIM results, Message-ID)")
+	threadCmd.Flags().Bool("include-bodies", true, "Include the full body of every message")
+	threadCmd.Flags().Bool("headers-only", false, "Skip message bodies (same as --include-bodies=false)")
 
 	// Send command flags
 	sendEmailCmd.Flags().StringSlice("to", nil, "To recipients (email or Name <email> format)")
@@ -313,24 +534,41 @@ func init() {
 	sendEmailCmd.Flags().String("subject", "", "Email subject")
 	sendEmailCmd.Flags().String("body", "", "Email body content")
 	sendEmailCmd.Flags().String("body-file", "", "Read body from file")
-	sendEmailCmd.Flags().String("body-type", "html", "Body type: html or text")
+	sendEmailCmd.Flags().String("body-markdown", "", "Email body in Markdown, sent as HTML with a plain text alternative")
+	sendEmailCmd.Flags().String("body-type", "html", bodyTypeUsage)
 	sendEmailCmd.Flags().String("importance", "normal", "Importance: low, normal, high")
 	sendEmailCmd.Flags().Int64("connection-id", 0, "Specific connection to send from")
+	sendEmailCmd.Flags().Bool("request-read-receipt", false, "Ask recipients' mail clients to confirm when the email is read")
+	sendEmailCmd.Flags().Bool("request-delivery-receipt", false, "Ask the recipients' mail servers to confirm delivery")
+	sendEmailCmd.Flags().Bool("no-auto-cc", false, noAutoCCUsage)
+	sendEmailCmd.Flags().String("mailbox", "", mailboxSendUsage)
+	for _, flag := range []string{"to", "cc", "bcc"} {
+		_ = sendEmailCmd.RegisterFlagCompletionFunc(flag, completeRecipients)
+	}
 	_ = sendEmailCmd.MarkFlagRequired("to")
 	_ = sendEmailCmd.MarkFlagRequired("subject")
 
 	// Reply command flags
 	replyEmailCmd.Flags().String("body", "", "Reply body content")
 	replyEmailCmd.Flags().String("body-file", "", "Read body from file")
-	replyEmailCmd.Flags().String("body-type", "html", "Body type: html or text")
+	replyEmailCmd.Flags().String("body-markdown", "", "Reply body in Markdown, sent as HTML with a plain text alternative")
+	replyEmailCmd.Flags().String("body-type", "html", bodyTypeUsage)
 	replyEmailCmd.Flags().Bool("reply-all", false, "Reply to all recipients")
+	replyEmailCmd.Flags().Bool("edit", false, "Write the reply in $EDITOR, quoting the original message")
+	replyEmailCmd.Flags().Bool("no-auto-cc", false, noAutoCCUsage)
+	replyEmailCmd.Flags().String("mailbox", "", mailboxSendUsage)
 
 	// Forward command flags
 	forwardEmailCmd.Flags().StringSlice("to", nil, "Forward recipients")
 	forwardEmailCmd.Flags().StringSlice("cc", nil, "CC recipients")
 	forwardEmailCmd.Flags().String("body", "", "Optional message to prepend")
 	forwardEmailCmd.Flags().String("body-file", "", "Read body from file")
-	forwardEmailCmd.Flags().String("body-type", "html", "Body type: html or text")
+	forwardEmailCmd.Flags().String("body-markdown", "", "Optional message in Markdown, sent as HTML with a plain text alternative")
+	forwardEmailCmd.Flags().String("body-type", "html", bodyTypeUsage)
+	forwardEmailCmd.Flags().Bool("no-auto-cc", false, noAutoCCUsage)
+	forwardEmailCmd.Flags().String("mailbox", "", mailboxSendUsage)
+	_ = forwardEmailCmd.RegisterFlagCompletionFunc("to", completeRecipients)
+	_ = forwardEmailCmd.RegisterFlagCompletionFunc("cc", completeRecipients)
 	_ = forwardEmailCmd.MarkFlagRequired("to")
 
 	// Modify command flags
@@ -347,9 +585,31 @@ func init() {
 	emailCmd.AddCommand(replyEmailCmd)
 	emailCmd.AddCommand(forwardEmailCmd)
 	emailCmd.AddCommand(deleteEmailCmd)
+	emailCmd.AddCommand(archiveEmailCmd)
 	emailCmd.AddCommand(modifyEmailCmd)
 }
 
+// addEmailFilterFlags registers the search and time filters read by
+// buildEmailParams
+func addEmailFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("query", "q", "", "Free-text search query")
+	cmd.Flags().String("from", "", "Filter by sender email")
+	cmd.Flags().String("to", "", "Filter by recipient email")
+	cmd.Flags().String("subject", "", "Filter by subject (partial match)")
+	cmd.Flags().String("label", "", "Filter by label/category")
+	cmd.Flags().Bool("unread", false, "Show only unread emails")
+	cmd.Flags().Bool("has-attachment", false, "Show only emails with attachments")
+	cmd.Flags().String("mailbox", "", "Read a shared mailbox connected to the account (its email address)")
+
+	// Time filters
+	cmd.Flags().Bool("today", false, "Show today's emails")
+	cmd.Flags().Bool("yesterday", false, "Show yesterday's emails")
+	cmd.Flags().Bool("week", false, "Show this week's emails")
+	cmd.Flags().Int("days", 0, "Show emails from the last N days")
+	cmd.Flags().String("after", "", "Emails after this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().String("before", "", "Emails before this date (YYYY-MM-DD or RFC3339)")
+}
+
 // buildEmailParams builds email search parameters from command flags
 func buildEmailParams(cmd *cobra.Command) (api.EmailParams, error) {
 	params := api.EmailParams{
@@ -371,6 +631,11 @@ func buildEmailParams(cmd *cobra.Command) (api.EmailParams, error) {
 	if label, _ := cmd.Flags().GetString("label"); label != "" {
 		params.Label = label
 	}
+	mailbox, err := getMailbox(cmd)
+	if err != nil {
+		return params, err
+	}
+	params.Mailbox = mailbox
 
 	if cmd.Flags().Changed("unread") {
 		unread, _ := cmd.Flags().GetBool("unread")
@@ -429,6 +694,11 @@ func buildEmailParams(cmd *cobra.Command) (api.EmailParams, error) {
 			}
 			params.Before = t
 		}
+		if afterStr != "" && beforeStr != "" {
+			if err := checkDateRange("after", "before", afterStr, beforeStr, params.After, params.Before); err != nil {
+				return params, err
+			}
+		}
 	}
 
 	return params, nil
@@ -458,16 +728,14 @@ func buildSendEmailRequest(cmd *cobra.Command) (api.SendEmailRequest, error) {
 
 	req.Subject, _ = cmd.Flags().GetString("subject")
 
-	body, err := getBodyContent(cmd)
+	body, err := getBody(cmd)
 	if err != nil {
 		return req, err
 	}
-	if body == "" {
-		return req, fmt.Errorf("either --body or --body-file is required")
+	if body.content == "" {
+		return req, fmt.Errorf("either --body, --body-markdown or --body-file is required")
 	}
-	req.Body = body
-
-	req.BodyType, _ = cmd.Flags().GetString("body-type")
+	req.Body, req.BodyType, req.TextBody = body.content, body.bodyType, body.text
 
 	importance, _ := cmd.Flags().GetString("importance")
 	if importance != "" && importance != "normal" {
@@ -479,6 +747,14 @@ func buildSendEmailRequest(cmd *cobra.Command) (api.SendEmailRequest, error) {
 		req.ConnectionID = &connID
 	}
 
+	req.RequestReadReceipt, _ = cmd.Flags().GetBool("request-read-receipt")
+	req.RequestDeliveryReceipt, _ = cmd.Flags().GetBool("request-delivery-receipt")
+
+	if req.Mailbox, err = getMailbox(cmd); err != nil {
+		return req, err
+	}
+
+	req.CC, req.BCC = addAutoCopies(cmd, req.To, req.CC, req.BCC)
 	return req, nil
 }
 
@@ -486,16 +762,18 @@ func buildSendEmailRequest(cmd *cobra.Command) (api.SendEmailRequest, error) {
 func buildReplyRequest(cmd *cobra.Command) (api.ReplyEmailRequest, error) {
 	req := api.ReplyEmailRequest{}
 
-	body, err := getBodyContent(cmd)
+	body, err := getBody(cmd)
 	if err != nil {
 		return req, err
 	}
-	if body == "" {
-		return req, fmt.Errorf("either --body or --body-file is required")
+	if edit, _ := cmd.Flags().GetBool("edit"); body.content == "" && !edit {
+		return req, fmt.Errorf("either --body, --body-markdown, --body-file or --edit is required")
 	}
-	req.Body = body
-	req.BodyType, _ = cmd.Flags().GetString("body-type")
+	req.Body, req.BodyType, req.TextBody = body.content, body.bodyType, body.text
 	req.ReplyAll, _ = cmd.Flags().GetBool("reply-all")
+	if req.Mailbox, err = getMailbox(cmd); err != nil {
+		return req, err
+	}
 
 	return req, nil
 }
@@ -516,16 +794,66 @@ func buildForwardRequest(cmd *cobra.Command) (api.ForwardEmailRequest, error) {
 		req.CC = append(req.CC, p)
 	}
 
-	body, err := getBodyContent(cmd)
+	body, err := getBody(cmd)
 	if err != nil {
 		return req, err
 	}
-	req.Body = body
-	req.BodyType, _ = cmd.Flags().GetString("body-type")
+	req.Body, req.BodyType, req.TextBody = body.content, body.bodyType, body.text
+	if req.Mailbox, err = getMailbox(cmd); err != nil {
+		return req, err
+	}
+	req.CC, req.BCC = addAutoCopies(cmd, req.To, req.CC, nil)
 
 	return req, nil
 }
 
+// mailboxSendUsage describes --mailbox on send, reply and forward
+const mailboxSendUsage = "Send from a shared mailbox connected to the account (its email address)"
+
+// sharedMailbox is the mailbox named by --mailbox, for error messages
+var sharedMailbox string
+
+// getMailbox reads --mailbox, checking that it is an email address
+func getMailbox(cmd *cobra.Command) (string, error) {
+	mailbox, _ := cmd.Flags().GetString("mailbox")
+	if mailbox != "" && !strings.Contains(mailbox, "@") {
+		return "", fmt.Errorf("invalid --mailbox %q: use the email address of the shared mailbox", mailbox)
+	}
+	sharedMailbox = mailbox
+	return mailbox, nil
+}
+
+// noAutoCCUsage describes --no-auto-cc on send, reply and forward
+const noAutoCCUsage = "Don't add the email.always_cc and email.always_bcc addresses"
+
+// addAutoCopies appends the email.always_cc and email.always_bcc addresses,
+// skipping any already among the recipients, unless --no-auto-cc is set
+func addAutoCopies(cmd *cobra.Command, to, cc, bcc []api.Participant) ([]api.Participant, []api.Participant) {
+	if skip, _ := cmd.Flags().GetBool("no-auto-cc"); skip {
+		return cc, bcc
+	}
+
+	seen := make(map[string]bool)
+	for _, list := range [][]api.Participant{to, cc, bcc} {
+		for _, p := range list {
+			seen[strings.ToLower(p.Email)] = true
+		}
+	}
+	add := func(list []api.Participant, key string) []api.Participant {
+		for _, addr := range userConfig.List(key) {
+			p := parseParticipant(addr)
+			if k := strings.ToLower(p.Email); !seen[k] {
+				seen[k] = true
+				list = append(list, p)
+			}
+		}
+		return list
+	}
+	cc = add(cc, "email.always_cc")
+	bcc = add(bcc, "email.always_bcc")
+	return cc, bcc
+}
+
 // buildModifyRequest builds a modify request from command flags
 func buildModifyRequest(cmd *cobra.Command) (api.ModifyEmailRequest, error) {
 	req := api.ModifyEmailRequest{}
@@ -560,14 +888,56 @@ func buildModifyRequest(cmd *cobra.Command) (api.ModifyEmailRequest, error) {
 	return req, nil
 }
 
+// bodyTypeUsage describes --body-type on send, reply and forward
+const bodyTypeUsage = "Body type: html, text or markdown (converted to HTML with a plain text alternative)"
+
+// emailBody is the body of an outgoing email as read from the flags
+type emailBody struct {
+	content  string
+	bodyType string
+	text     string // Plain text alternative, set for Markdown bodies
+}
+
+// getBody reads the body and its type. Markdown, from --body-markdown or
+// --body-type markdown, is converted to HTML with a plain text alternative.
+func getBody(cmd *cobra.Command) (emailBody, error) {
+	content, err := getBodyContent(cmd)
+	if err != nil {
+		return emailBody{}, err
+	}
+	bodyType, _ := cmd.Flags().GetString("body-type")
+	if md, _ := cmd.Flags().GetString("body-markdown"); md != "" {
+		if cmd.Flags().Changed("body-type") && bodyType != "markdown" {
+			return emailBody{}, fmt.Errorf("--body-markdown cannot be used with --body-type %s", bodyType)
+		}
+		content, bodyType = md, "markdown"
+	}
+
+	switch bodyType {
+	case "html", "text":
+		return emailBody{content: content, bodyType: bodyType}, nil
+	case "markdown":
+		if content == "" {
+			return emailBody{}, nil
+		}
+		return emailBody{content: markdown.ToHTML(content), bodyType: "html", text: markdown.ToText(content)}, nil
+	default:
+		return emailBody{}, fmt.Errorf("invalid --body-type %q (use html, text or markdown)", bodyType)
+	}
+}
+
 // getBodyContent reads body content from --body flag or --body-file flag
 func getBodyContent(cmd *cobra.Command) (string, error) {
 	bodyStr, _ := cmd.Flags().GetString("body")
 	bodyFile, _ := cmd.Flags().GetString("body-file")
+	bodyMarkdown, _ := cmd.Flags().GetString("body-markdown")
 
 	if bodyStr != "" && bodyFile != "" {
 		return "", fmt.Errorf("cannot use both --body and --body-file")
 	}
+	if bodyMarkdown != "" && (bodyStr != "" || bodyFile != "") {
+		return "", fmt.Errorf("cannot use --body-markdown with --body or --body-file")
+	}
 
 	if bodyFile != "" {
 		content, err := os.ReadFile(bodyFile)
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var emailToEventCmd = &cobra.Command{
+	Use:   "to-event <emailId>",
+	Short: "Create a calendar event from an email",
+	Long: `Create a calendar event pre-filled from an email message.
+
+The subject becomes the event title, the sender and recipients become attendees,
+and the description links back to the originating thread.
+
+Examples:
+  porteden email to-event <emailId> --from 2026-02-10T15:00:00Z
+  porteden email to-event <emailId> --from 2026-02-10T15:00:00Z --duration 1h --calendar 12345
+  porteden email to-event <emailId> --from 2026-02-10T15:00:00Z --no-attendees --summary "Follow-up"`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromStr, _ := cmd.Flags().GetString("from")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		calendarRef, _ := cmd.Flags().GetString("calendar")
+		summary, _ := cmd.Flags().GetString("summary")
+		noAttendees, _ := cmd.Flags().GetBool("no-attendees")
+
+		start, err := parseDateTime(fromStr)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %w", err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("--duration must be positive")
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.GetEmail(emailID, true)
+		if err != nil {
+			return formatError(err)
+		}
+		email := resp.Email
+
+		calendarID, err := resolveCalendarID(cmd, client, calendarRef)
+		if err != nil {
+			return err
+		}
+		if calendarID == 0 {
+			calendarID, err = primaryCalendarID(client)
+			if err != nil {
+				return err
+			}
+		}
+
+		if summary == "" {
+			summary = strings.TrimSpace(email.Subject)
+		}
+		if summary == "" {
+			summary = "Follow-up"
+		}
+
+		req := api.CreateEventRequest{
+			CalendarID:  calendarID,
+			Summary:     summary,
+			Description: emailEventDescription(email),
+			From:        start,
+			To:          start.Add(duration),
+		}
+		if !noAttendees {
+			req.Attendees = emailAttendees(email)
+		}
+
+		event, err := client.CreateEvent(req)
+		if err != nil {
+			return formatError(err)
+		}
+
+		fmt.Printf("Event created successfully (ID: %s)\n", event.ID)
+		output.PrintWithOptions(event, getOutputFormat(cmd), output.PrintOptions{
+			Compact: IsCompactMode(),
+		})
+		return nil
+	},
+}
+
+// primaryCalendarID returns the ID of the user's primary calendar
+func primaryCalendarID(client *api.Client) (int64, error) {
+	calendars, err := client.GetCalendars()
+	if err != nil {
+		return 0, formatError(err)
+	}
+	for _, c := range calendars.Data {
+		if c.IsPrimary {
+			return c.ID, nil
+		}
+	}
+	if len(calendars.Data) == 1 {
+		return calendars.Data[0].ID, nil
+	}
+	return 0, fmt.Errorf("no primary calendar found; pass --calendar (see 'porteden calendar calendars')")
+}
+
+// emailAttendees collects the unique sender and recipient addresses of an email
+func emailAttendees(email api.Email) []string {
+	seen := make(map[string]bool)
+	var attendees []string
+
+	add := func(p api.Participant) {
+		addr := strings.ToLower(strings.TrimSpace(p.Email))
+		if addr == "" || seen[addr] {
+			return
+		}
+		seen[addr] = true
+		attendees = append(attendees, p.Email)
+	}
+
+	if email.From != nil {
+		add(*email.From)
+	}
+	for _, p := range email.To {
+		add(p)
+	}
+	for _, p := range email.CC {
+		add(p)
+	}
+	return attendees
+}
+
+// emailEventDescription builds an event description that links back to the source email
+func emailEventDescription(email api.Email) string {
+	var b strings.Builder
+
+	preview := email.BodyPreview
+	if preview == "" && email.BodyType != "html" {
+		preview = email.Body
+	}
+	if preview = strings.TrimSpace(preview); preview != "" {
+		b.WriteString(truncateRunes(preview, 500))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("Created from email")
+	if email.From != nil {
+		fmt.Fprintf(&b, " from %s", email.From.Email)
+	}
+	if !email.ReceivedAt.IsZero() {
+		fmt.Fprintf(&b, " received %s", email.ReceivedAt.Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Message: %s\n", email.ID)
+	if email.ThreadID != "" {
+		fmt.Fprintf(&b, "Thread: %s (porteden email thread %s)\n", email.ThreadID, email.ThreadID)
+	}
+	return b.String()
+}
+
+func init() {
+	emailToEventCmd.Flags().String("from", "", "Event start time (required)")
+	emailToEventCmd.Flags().Duration("duration", 30*time.Minute, "Event length (e.g., 30m, 1h)")
+	emailToEventCmd.Flags().String("calendar", "", "Calendar ID or name (default: primary calendar)")
+	emailToEventCmd.Flags().String("summary", "", "Event title (default: email subject)")
+	emailToEventCmd.Flags().Bool("no-attendees", false, "Don't invite the email's participants")
+	_ = emailToEventCmd.MarkFlagRequired("from")
+
+	emailCmd.AddCommand(emailToEventCmd)
+}
@@ -0,0 +1,499 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// mergeMessage is one rendered email, ready to send
+type mergeMessage struct {
+	Row     int                  `json:"row"`
+	Request api.SendEmailRequest `json:"request"`
+}
+
+// mergeProgress is one line of the progress file, written after each send
+type mergeProgress struct {
+	Row     int       `json:"row"`
+	To      string    `json:"to"`
+	EmailID string    `json:"emailId,omitempty"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+// mergeResult is the outcome for one recipient
+type mergeResult struct {
+	Row     int    `json:"row"`
+	To      string `json:"to"`
+	Status  string `json:"status"` // sent, skipped or failed
+	EmailID string `json:"emailId,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+var emailMergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Send personalized email to each row of a CSV file",
+	Long: `Render a template for each row of a CSV file and send each recipient their
+own email.
+
+The template starts with header lines, then a blank line, then the body:
+
+  Subject: Welcome aboard, {{.first_name}}!
+  Cc: {{.manager}}
+
+  Hi {{.first_name}},
+
+  Your account for {{.company}} is ready.
+
+Subject is required; Cc and Bcc are optional. Fields are the CSV column names.
+A column named "email" (see --to-column) holds the recipient, and a "name"
+column, if present, is used as the display name. Templates use Go template
+syntax; a field missing from the CSV is an error. Templates ending in .html
+are sent as HTML with field values escaped.
+
+Every row is rendered and checked before anything is sent. Each successful
+send is appended to a progress file (default: <data>.progress.jsonl), so an
+interrupted run can be repeated and resumes where it stopped. Use --restart to
+ignore earlier progress.
+
+Examples:
+  porteden email merge --template welcome.tmpl --data recipients.csv --dry-run
+  porteden email merge --template welcome.tmpl --data recipients.csv
+  porteden email merge --template welcome.html --data recipients.csv --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templatePath, _ := cmd.Flags().GetString("template")
+		dataPath, _ := cmd.Flags().GetString("data")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		restart, _ := cmd.Flags().GetBool("restart")
+		toColumn, _ := cmd.Flags().GetString("to-column")
+		progressPath, _ := cmd.Flags().GetString("progress")
+		if progressPath == "" {
+			progressPath = dataPath + ".progress.jsonl"
+		}
+		jsonOutput := getOutputFormat(cmd) == output.FormatJSON
+
+		// From here on, errors are about the files' contents, not the invocation
+		cmd.SilenceUsage = true
+
+		tmpl, err := loadMergeTemplate(templatePath)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("body-type") {
+			tmpl.bodyType, _ = cmd.Flags().GetString("body-type")
+		}
+
+		f, err := os.Open(dataPath)
+		if err != nil {
+			return fmt.Errorf("failed to open data file: %w", err)
+		}
+		defer f.Close()
+
+		messages, err := renderMerge(tmpl, f, toColumn)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("connection-id") {
+			connID, _ := cmd.Flags().GetInt64("connection-id")
+			for i := range messages {
+				messages[i].Request.ConnectionID = &connID
+			}
+		}
+
+		sent := map[string]bool{}
+		if !restart {
+			if sent, err = loadMergeProgress(progressPath); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			if jsonOutput {
+				output.PrintWithOptions(messages, output.FormatJSON, output.PrintOptions{})
+				return nil
+			}
+			printMergePreview(messages, sent)
+			fmt.Printf("Dry run: %d email(s) rendered, nothing sent.\n", len(messages))
+			return nil
+		}
+
+		pending := 0
+		for _, m := range messages {
+			if !sent[mergeKey(m)] {
+				pending++
+			}
+		}
+		if pending == 0 {
+			fmt.Fprintf(os.Stderr, "All %d recipients were already sent to (see %s). Use --restart to send again.\n", len(messages), progressPath)
+			return nil
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		if err := applyRateFlag(cmd, client); err != nil {
+			return err
+		}
+
+		if !yes && auth.IsInteractiveTerminal() {
+			prompt := i18n.T("Send %d email(s)?", pending)
+			if skipped := len(messages) - pending; skipped > 0 {
+				prompt = i18n.T("Send %d email(s), skipping %d already sent?", pending, skipped)
+			}
+			if !confirm(prompt) {
+				fmt.Println(i18n.T("Cancelled. Nothing sent."))
+				return nil
+			}
+		}
+
+		if restart {
+			if err := os.Remove(progressPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to reset progress file: %w", err)
+			}
+		}
+
+		results, err := sendMerge(client, messages, sent, progressPath, !jsonOutput)
+		if jsonOutput {
+			output.PrintWithOptions(results, output.FormatJSON, output.PrintOptions{})
+		}
+		if err != nil {
+			return err
+		}
+
+		counts := map[string]int{}
+		for _, r := range results {
+			counts[r.Status]++
+		}
+		if !jsonOutput {
+			fmt.Printf("\nSent %d, skipped %d already sent, failed %d\n", counts["sent"], counts["skipped"], counts["failed"])
+		}
+		if counts["failed"] > 0 {
+			return fmt.Errorf("%d of %d emails failed; run the same command again to retry them", counts["failed"], len(results))
+		}
+		return nil
+	},
+}
+
+// mergeTemplate is a parsed template file
+type mergeTemplate struct {
+	subject  *template.Template
+	cc, bcc  *template.Template
+	body     interface{ Execute(io.Writer, any) error }
+	bodyType string
+}
+
+// loadMergeTemplate parses the header lines and body of a template file
+func loadMergeTemplate(path string) (*mergeTemplate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	text := strings.ReplaceAll(string(raw), "\r\n", "\n")
+
+	headerText, bodyText, ok := strings.Cut(text, "\n\n")
+	if !ok {
+		return nil, errors.New("template needs header lines (Subject: ...), a blank line, then the body")
+	}
+
+	t := &mergeTemplate{bodyType: "text"}
+	parse := func(name, src string) (*template.Template, error) {
+		tt, err := template.New(name).Option("missingkey=error").Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", name, err)
+		}
+		return tt, nil
+	}
+	for _, line := range strings.Split(headerText, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid template header %q (expected Subject:, Cc: or Bcc:)", line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "subject":
+			t.subject, err = parse("subject", value)
+		case "cc":
+			t.cc, err = parse("cc", value)
+		case "bcc":
+			t.bcc, err = parse("bcc", value)
+		default:
+			return nil, fmt.Errorf("unknown template header %q (expected Subject:, Cc: or Bcc:)", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.subject == nil {
+		return nil, errors.New("template has no Subject: header")
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".html" || ext == ".htm" {
+		t.bodyType = "html"
+		t.body, err = htmltemplate.New("body").Option("missingkey=error").Parse(bodyText)
+	} else {
+		t.body, err = template.New("body").Option("missingkey=error").Parse(bodyText)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid template body: %w", err)
+	}
+	return t, nil
+}
+
+// renderMerge renders the template for every CSV row, collecting all problems
+// so nothing is sent from a partly broken file
+func renderMerge(t *mergeTemplate, in io.Reader, toColumn string) ([]mergeMessage, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("data file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data file: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	if !slices.Contains(header, toColumn) {
+		return nil, fmt.Errorf("data file has no %q column for recipients (see --to-column)", toColumn)
+	}
+
+	var messages []mergeMessage
+	var problems []string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data file: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, h := range header {
+			if i < len(record) {
+				fields[h] = strings.TrimSpace(record[i])
+			} else {
+				fields[h] = ""
+			}
+		}
+
+		req, err := renderMergeRow(t, fields, toColumn)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("  row %d: %v", line, err))
+			continue
+		}
+		messages = append(messages, mergeMessage{Row: line, Request: req})
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d invalid row(s), nothing sent:\n%s", len(problems), strings.Join(problems, "\n"))
+	}
+	if len(messages) == 0 {
+		return nil, errors.New("data file has no recipient rows")
+	}
+	return messages, nil
+}
+
+// renderMergeRow renders one recipient's email
+func renderMergeRow(t *mergeTemplate, fields map[string]string, toColumn string) (api.SendEmailRequest, error) {
+	req := api.SendEmailRequest{BodyType: t.bodyType}
+
+	to := fields[toColumn]
+	if !strings.Contains(to, "@") {
+		return req, fmt.Errorf("%q is not an email address", to)
+	}
+	req.To = []api.Participant{{Email: to, Name: fields["name"]}}
+
+	render := func(tt interface{ Execute(io.Writer, any) error }) (string, error) {
+		var b bytes.Buffer
+		if err := tt.Execute(&b, fields); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	var err error
+	if req.Subject, err = render(t.subject); err != nil {
+		return req, err
+	}
+	if strings.TrimSpace(req.Subject) == "" {
+		return req, errors.New("subject renders empty")
+	}
+	if req.Body, err = render(t.body); err != nil {
+		return req, err
+	}
+	for _, list := range []struct {
+		tmpl *template.Template
+		dst  *[]api.Participant
+	}{{t.cc, &req.CC}, {t.bcc, &req.BCC}} {
+		if list.tmpl == nil {
+			continue
+		}
+		value, err := render(list.tmpl)
+		if err != nil {
+			return req, err
+		}
+		for _, addr := range strings.Split(value, ",") {
+			if strings.TrimSpace(addr) != "" {
+				*list.dst = append(*list.dst, parseParticipant(addr))
+			}
+		}
+	}
+	return req, nil
+}
+
+// mergeKey identifies a recipient in the progress file
+func mergeKey(m mergeMessage) string {
+	return strings.ToLower(m.Request.To[0].Email)
+}
+
+// loadMergeProgress reads the recipients already sent to
+func loadMergeProgress(path string) (map[string]bool, error) {
+	sent := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read progress file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p mergeProgress
+		// A line cut short by a crash is ignored; that recipient is retried
+		if json.Unmarshal(scanner.Bytes(), &p) == nil && p.To != "" {
+			sent[strings.ToLower(p.To)] = true
+		}
+	}
+	return sent, scanner.Err()
+}
+
+// sendMerge sends each pending message, recording every success in the
+// progress file before moving on
+func sendMerge(client *api.Client, messages []mergeMessage, sent map[string]bool, progressPath string, report bool) ([]mergeResult, error) {
+	progress, err := os.OpenFile(progressPath, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open progress file: %w", err)
+	}
+	defer progress.Close()
+	// End a line cut short by a crash, so the next one isn't joined to it
+	if info, err := progress.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := progress.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := progress.Write([]byte{'\n'}); err != nil {
+				return nil, fmt.Errorf("failed to write progress file: %w", err)
+			}
+		}
+	}
+
+	results := make([]mergeResult, 0, len(messages))
+	for _, m := range messages {
+		to := m.Request.To[0].Email
+		res := mergeResult{Row: m.Row, To: to}
+		if sent[mergeKey(m)] {
+			res.Status = "skipped"
+			results = append(results, res)
+			continue
+		}
+
+		resp, err := client.SendEmail(m.Request)
+		if err == nil && !resp.Success {
+			err = errors.New(resp.ErrorMessage)
+		}
+		if err != nil {
+			res.Status, res.Error = "failed", formatError(err).Error()
+			if report {
+				fmt.Fprintf(os.Stderr, "  %s row %d %s: %s\n", output.ColorRed(output.Symbols("✗")), m.Row, to, res.Error)
+			}
+			results = append(results, res)
+			continue
+		}
+
+		res.Status, res.EmailID = "sent", resp.EmailID
+		line, _ := json.Marshal(mergeProgress{Row: m.Row, To: to, EmailID: resp.EmailID, SentAt: time.Now().UTC()})
+		if _, err := progress.Write(append(line, '\n')); err != nil {
+			return append(results, res), fmt.Errorf("sent to %s but failed to record progress: %w", to, err)
+		}
+		if err := progress.Sync(); err != nil {
+			return append(results, res), fmt.Errorf("sent to %s but failed to record progress: %w", to, err)
+		}
+		sent[mergeKey(m)] = true
+		if report {
+			output.PrintSuccess(fmt.Sprintf("row %d %s", m.Row, to))
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func printMergePreview(messages []mergeMessage, sent map[string]bool) {
+	for _, m := range messages {
+		req := m.Request
+		status := ""
+		if sent[mergeKey(m)] {
+			status = output.ColorGray(" (already sent, will be skipped)")
+		}
+		fmt.Println(output.ColorGray(output.Symbols(fmt.Sprintf("── row %d ──", m.Row))) + status)
+		fmt.Printf("To: %s\n", formatTriageParticipant(req.To[0]))
+		if len(req.CC) > 0 {
+			fmt.Printf("Cc: %s\n", joinParticipants(req.CC))
+		}
+		if len(req.BCC) > 0 {
+			fmt.Printf("Bcc: %s\n", joinParticipants(req.BCC))
+		}
+		fmt.Printf("Subject: %s\n\n%s\n\n", req.Subject, strings.TrimRight(req.Body, "\n"))
+	}
+}
+
+func joinParticipants(ps []api.Participant) string {
+	parts := make([]string, len(ps))
+	for i, p := range ps {
+		parts[i] = formatTriageParticipant(p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	emailMergeCmd.Flags().String("template", "", "Template file: header lines, a blank line, then the body (required)")
+	emailMergeCmd.Flags().String("data", "", "CSV file with one recipient per row (required)")
+	emailMergeCmd.Flags().String("to-column", "email", "CSV column holding the recipient address")
+	emailMergeCmd.Flags().String("body-type", "", "Body type: html or text (default: html for .html templates, else text)")
+	emailMergeCmd.Flags().Int64("connection-id", 0, "Specific connection to send from")
+	emailMergeCmd.Flags().String("progress", "", "Progress file for resuming (default: <data>.progress.jsonl)")
+	emailMergeCmd.Flags().Bool("restart", false, "Ignore earlier progress and send to every row")
+	emailMergeCmd.Flags().Bool("dry-run", false, "Render and preview every email without sending")
+	emailMergeCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	emailMergeCmd.Flags().String("rate", "", rateFlagUsage)
+	_ = emailMergeCmd.MarkFlagRequired("template")
+	_ = emailMergeCmd.MarkFlagRequired("data")
+
+	emailCmd.AddCommand(emailMergeCmd)
+}
@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/fakeserver"
+)
+
+// writeMergeFile writes a template or data file into dir
+func writeMergeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const mergeTestTemplate = "Subject: Welcome, {{.first}}!\nCc: {{.manager}}\n\nHi {{.first}},\nyour team is {{.team}}.\n"
+
+func TestRenderMerge(t *testing.T) {
+	dir := t.TempDir()
+	tmpl, err := loadMergeTemplate(writeMergeFile(t, dir, "welcome.tmpl", mergeTestTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Header names are trimmed, including a byte order mark; columns can be
+	// in any order, blank rows are skipped and short rows padded
+	data := "\ufeffteam, email ,first,name,manager\n" +
+		"Sales,ana@example.com,Ana,Ana Diaz,boss@example.com\n" +
+		"\n" +
+		"Ops, ben@example.com , Ben\n"
+	messages, err := renderMerge(tmpl, strings.NewReader(data), "email")
+	if err != nil {
+		t.Fatalf("renderMerge failed: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(messages))
+	}
+
+	ana := messages[0]
+	if ana.Row != 2 || ana.Request.To[0].Email != "ana@example.com" || ana.Request.To[0].Name != "Ana Diaz" {
+		t.Errorf("row 2 = %d %+v", ana.Row, ana.Request.To)
+	}
+	if ana.Request.Subject != "Welcome, Ana!" || ana.Request.Body != "Hi Ana,\nyour team is Sales.\n" || ana.Request.BodyType != "text" {
+		t.Errorf("row 2 rendered %q / %q (%s)", ana.Request.Subject, ana.Request.Body, ana.Request.BodyType)
+	}
+	if len(ana.Request.CC) != 1 || ana.Request.CC[0].Email != "boss@example.com" {
+		t.Errorf("row 2 Cc = %+v", ana.Request.CC)
+	}
+
+	ben := messages[1]
+	if ben.Row != 4 || ben.Request.To[0].Email != "ben@example.com" || ben.Request.To[0].Name != "" || len(ben.Request.CC) != 0 {
+		t.Errorf("row 4 = %d %+v cc %+v", ben.Row, ben.Request.To, ben.Request.CC)
+	}
+
+	// A different recipient column
+	messages, err = renderMerge(tmpl, strings.NewReader("first,team,manager,work\nCy,IT,,cy@example.com\n"), "work")
+	if err != nil || messages[0].Request.To[0].Email != "cy@example.com" {
+		t.Errorf("--to-column work: %v, %v", messages, err)
+	}
+}
+
+func TestRenderMergeErrors(t *testing.T) {
+	dir := t.TempDir()
+	tmpl, err := loadMergeTemplate(writeMergeFile(t, dir, "welcome.tmpl", mergeTestTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, data, want string
+	}{
+		{"empty", "", "data file is empty"},
+		{"no recipient column", "first,team,manager\nAna,Sales,\n", `no "email" column`},
+		{"no rows", "email,first,team,manager\n", "no recipient rows"},
+		// Every broken row is reported, and none is sent
+		{"bad rows", "email,first,team,manager\nnot-an-address,A,S,\nok@example.com,B,S,\n,C,S,\n", "2 invalid row(s)"},
+		{"missing field", "email,first,manager\nana@example.com,Ana,\n", `row 2: template: body`},
+	}
+	for _, tt := range tests {
+		if _, err := renderMerge(tmpl, strings.NewReader(tt.data), "email"); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLoadMergeTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	tmpl, err := loadMergeTemplate(writeMergeFile(t, dir, "note.html", "subject: For {{.first}}\r\nBcc: audit@example.com, {{.manager}}\r\n\r\n<p>Hi {{.first}}</p>\r\n"))
+	if err != nil {
+		t.Fatalf("loading an HTML template failed: %v", err)
+	}
+	req, err := renderMergeRow(tmpl, map[string]string{"email": "x@example.com", "first": "<Xi>", "manager": "m@example.com"}, "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.BodyType != "html" || req.Body != "<p>Hi &lt;Xi&gt;</p>\n" {
+		t.Errorf("HTML body = %q (%s), want the field escaped", req.Body, req.BodyType)
+	}
+	if req.Subject != "For <Xi>" || len(req.BCC) != 2 || req.BCC[1].Email != "m@example.com" {
+		t.Errorf("subject %q, Bcc %+v", req.Subject, req.BCC)
+	}
+
+	for name, content := range map[string]string{
+		"no-body.tmpl":    "Subject: Hi",
+		"no-subject.tmpl": "Cc: a@example.com\n\nBody",
+		"unknown.tmpl":    "Subject: Hi\nReply-To: a@example.com\n\nBody",
+		"broken.tmpl":     "Subject: Hi {{.first\n\nBody",
+	} {
+		if _, err := loadMergeTemplate(writeMergeFile(t, dir, name, content)); err == nil {
+			t.Errorf("%s: loaded, want an error", name)
+		}
+	}
+}
+
+// sentRecorder counts the emails sent through it to the fake server
+type sentRecorder struct {
+	base http.RoundTripper
+	mu   sync.Mutex
+	to   []string
+}
+
+func (r *sentRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/messages/send") {
+		body, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(strings.NewReader(string(body)))
+		var send api.SendEmailRequest
+		_ = json.Unmarshal(body, &send)
+		r.mu.Lock()
+		r.to = append(r.to, send.To[0].Email)
+		r.mu.Unlock()
+	}
+	return r.base.RoundTrip(req)
+}
+
+func TestSendMergeResumes(t *testing.T) {
+	dir := t.TempDir()
+	progressPath := filepath.Join(dir, "people.csv.progress.jsonl")
+	tmpl, err := loadMergeTemplate(writeMergeFile(t, dir, "welcome.tmpl", mergeTestTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := renderMerge(tmpl, strings.NewReader("email,first,team,manager\n"+
+		"a@example.com,A,S,\nb@example.com,B,S,\nc@example.com,C,S,\n"), "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier run sent to A (with a different case) and crashed while
+	// writing the next line
+	writeMergeFile(t, dir, filepath.Base(progressPath), `{"row":2,"to":"A@Example.com","emailId":"m1"}`+"\n"+`{"row":3,"to":"b@exa`)
+	sent, err := loadMergeProgress(progressPath)
+	if err != nil || len(sent) != 1 || !sent["a@example.com"] {
+		t.Fatalf("loadMergeProgress = %v, %v", sent, err)
+	}
+
+	fake := fakeserver.New()
+	fake.InjectError("/api/access/email/messages/send", http.StatusBadRequest, 1)
+	rec := &sentRecorder{base: fake}
+	client := api.NewClient("").WithBaseURL("http://fake").WithBaseTransport(rec)
+
+	// B fails, C is sent
+	results, err := sendMerge(client, messages, sent, progressPath, false)
+	if err != nil {
+		t.Fatalf("sendMerge failed: %v", err)
+	}
+	statuses := []string{results[0].Status, results[1].Status, results[2].Status}
+	if strings.Join(statuses, ",") != "skipped,failed,sent" || results[2].EmailID == "" {
+		t.Errorf("results = %+v", results)
+	}
+
+	// Running again sends only B
+	sent, err = loadMergeProgress(progressPath)
+	if err != nil || len(sent) != 2 || !sent["c@example.com"] {
+		t.Fatalf("progress after the first run = %v, %v", sent, err)
+	}
+	results, err = sendMerge(client, messages, sent, progressPath, false)
+	if err != nil || results[1].Status != "sent" || results[2].Status != "skipped" {
+		t.Errorf("second run = %+v, %v", results, err)
+	}
+	if got := strings.Join(rec.to, ","); got != "b@example.com,c@example.com,b@example.com" {
+		t.Errorf("sent to %s", got)
+	}
+
+	info, err := os.Stat(progressPath)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("progress file: %v, %v", info, err)
+	}
+}
+
+func TestEmailMergeDryRun(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("PE_FORMAT", "")
+	templatePath := writeMergeFile(t, dir, "welcome.tmpl", mergeTestTemplate)
+	dataPath := writeMergeFile(t, dir, "people.csv", "email,first,team,manager\na@example.com,A,S,\nb@example.com,B,S,\n")
+	writeMergeFile(t, dir, "people.csv.progress.jsonl", `{"row":2,"to":"a@example.com"}`+"\n")
+
+	// No account is configured: a dry run never needs one. Flags keep their
+	// values between runs of rootCmd, so --json is reset explicitly.
+	out := captureStdout(t, func() {
+		rootCmd.SetArgs([]string{"email", "merge", "--template", templatePath, "--data", dataPath, "--dry-run", "--json=false"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("dry run failed: %v", err)
+		}
+	})
+	for _, want := range []string{"row 2", "(already sent, will be skipped)", "To: b@example.com", "Subject: Welcome, B!", "Dry run: 2 email(s) rendered, nothing sent."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry run output has no %q:\n%s", want, out)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		rootCmd.SetArgs([]string{"email", "merge", "--template", templatePath, "--data", dataPath, "--dry-run", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("JSON dry run failed: %v", err)
+		}
+	})
+	var messages []mergeMessage
+	if err := json.Unmarshal([]byte(out), &messages); err != nil || len(messages) != 2 || messages[1].Request.Subject != "Welcome, B!" {
+		t.Errorf("JSON dry run = %s (%v)", out, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "people.csv.progress.jsonl")); err != nil {
+		t.Errorf("dry run touched the progress file: %v", err)
+	}
+}
+
+// captureStdout returns what f writes to stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	f()
+	w.Close()
+	return <-done
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/browser"
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/sanitize"
+	"github.com/spf13/cobra"
+)
+
+var emailPreviewCmd = &cobra.Command{
+	Use:   "preview <emailId>",
+	Short: "Open a sanitized copy of an email in the browser",
+	Long: `Render an email's HTML body to a temporary file and open it in the browser,
+for messages that don't read well as text.
+
+The HTML is sanitized first: scripts, frames, forms, event handlers and
+javascript: links are removed. Remote images are blocked, since loading
+them tells the sender the message was opened; --images loads them.
+Plain text emails are shown as they are.
+
+Examples:
+  porteden email preview <emailId>
+  porteden email preview <emailId> --images
+  porteden email preview <emailId> --print     # Just write the file and print its path`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		images, _ := cmd.Flags().GetBool("images")
+		printOnly, _ := cmd.Flags().GetBool("print")
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		emailID, err := emailIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.GetEmail(emailID, true)
+		if err != nil {
+			return formatError(err)
+		}
+		page, blocked := previewPage(resp.Email, sanitize.Options{RemoteImages: images})
+
+		path := filepath.Join(os.TempDir(), "porteden-preview-"+previewFileID.ReplaceAllString(resp.Email.ID, "_")+".html")
+		if err := os.WriteFile(path, []byte(page), 0600); err != nil {
+			return fmt.Errorf("failed to write preview: %w", err)
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(map[string]interface{}{
+				"emailId":       resp.Email.ID,
+				"file":          path,
+				"blockedImages": blocked,
+			}, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+
+		if printOnly {
+			fmt.Println(path)
+			return nil
+		}
+		fmt.Printf("Opening %s\n", path)
+		if blocked > 0 {
+			fmt.Println(output.ColorGray(fmt.Sprintf("Blocked %d remote image(s); use --images to load them", blocked)))
+		}
+		if err := browser.OpenFile(path); err != nil {
+			return fmt.Errorf("failed to open browser: %w", err)
+		}
+		return nil
+	},
+}
+
+// previewFileID matches characters not safe in the preview file name
+var previewFileID = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// previewPage builds a standalone HTML page for an email, with its headers
+// above the sanitized body. A Content-Security-Policy backs up the
+// sanitizer, so nothing but inline styles and allowed images can load.
+func previewPage(e api.Email, opts sanitize.Options) (string, int) {
+	var body string
+	blocked := 0
+	if e.BodyType == "html" {
+		body, blocked = sanitize.HTML(e.Body, opts)
+	} else {
+		body = `<pre style="white-space: pre-wrap; font: inherit">` + html.EscapeString(e.Body) + "</pre>"
+	}
+
+	imgSrc := "data:"
+	if opts.RemoteImages {
+		imgSrc += " https: http:"
+	}
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<meta http-equiv=\"Content-Security-Policy\" content=\"default-src 'none'; img-src %s; style-src 'unsafe-inline'\">\n", imgSrc)
+	fmt.Fprintf(&b, "<title>%s</title>\n</head>\n<body>\n", html.EscapeString(e.Subject))
+
+	b.WriteString(`<div style="font: 14px sans-serif; border-bottom: 1px solid #ccc; padding-bottom: 8px; margin-bottom: 16px">` + "\n")
+	fmt.Fprintf(&b, "<div style=\"font-size: 18px; font-weight: bold\">%s</div>\n", html.EscapeString(e.Subject))
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "<div><b>%s:</b> %s</div>\n", label, html.EscapeString(value))
+		}
+	}
+	if e.From != nil {
+		row("From", participantList([]api.Participant{*e.From}))
+	}
+	row("To", participantList(e.To))
+	row("Cc", participantList(e.CC))
+	if !e.ReceivedAt.IsZero() {
+		row("Date", e.ReceivedAt.In(output.GetOutputLocation()).Format("Mon, 2 Jan 2006 15:04 MST"))
+	}
+	if blocked > 0 {
+		fmt.Fprintf(&b, "<div style=\"color: #a60\">%d remote image(s) blocked. Run with --images to load them.</div>\n", blocked)
+	}
+	b.WriteString("</div>\n")
+
+	b.WriteString(body)
+	b.WriteString("\n</body>\n</html>\n")
+	return b.String(), blocked
+}
+
+// participantList lists participants as "Name <email>" separated by commas
+func participantList(list []api.Participant) string {
+	parts := make([]string, len(list))
+	for i, p := range list {
+		parts[i] = p.Email
+		if p.Name != "" {
+			parts[i] = p.Name + " <" + p.Email + ">"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	emailPreviewCmd.Flags().Bool("images", false, "Load remote images (lets the sender see the message was opened)")
+	emailPreviewCmd.Flags().Bool("print", false, "Write the preview file and print its path without opening it")
+
+	emailCmd.AddCommand(emailPreviewCmd)
+}
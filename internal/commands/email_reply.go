@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/extract"
+	"github.com/porteden/cli/internal/markdown"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// editReply opens the reply in the user's editor, pre-populated with any
+// --body text, the signature and the quoted original, and replaces the
+// request body with the result. The edited text is sent as plain text, or
+// converted from Markdown with --body-type markdown.
+func editReply(cmd *cobra.Command, client *api.Client, emailID string, req *api.ReplyEmailRequest) error {
+	resp, err := client.GetEmail(emailID, true)
+	if err != nil {
+		return err
+	}
+	initial, err := getBodyContent(cmd)
+	if err != nil {
+		return err
+	}
+	if md, _ := cmd.Flags().GetString("body-markdown"); md != "" {
+		initial = md
+	}
+	draft := replyDraft(initial, userConfig.String("email.signature"), resp.Email)
+
+	tmp, err := os.CreateTemp("", "porteden-reply-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(draft); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	if err := runEditor(tmp.Name()); err != nil {
+		return err
+	}
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+	text := strings.ReplaceAll(string(edited), "\r\n", "\n")
+	if text == draft || strings.TrimSpace(text) == "" {
+		return fmt.Errorf("reply not sent: the message was not edited")
+	}
+
+	req.Body, req.BodyType, req.TextBody = text, "text", ""
+	if bodyType, _ := cmd.Flags().GetString("body-type"); bodyType == "markdown" || cmd.Flags().Changed("body-markdown") {
+		req.Body, req.BodyType, req.TextBody = markdown.ToHTML(text), "html", markdown.ToText(text)
+	}
+	return nil
+}
+
+// addReplyAutoCopies adds the email.always_cc and email.always_bcc addresses
+// to a reply, skipping any the reply already goes to: the original's sender,
+// and with --reply-all its other recipients
+func addReplyAutoCopies(cmd *cobra.Command, client *api.Client, emailID string, req *api.ReplyEmailRequest) error {
+	if skip, _ := cmd.Flags().GetBool("no-auto-cc"); skip {
+		return nil
+	}
+	if len(userConfig.List("email.always_cc")) == 0 && len(userConfig.List("email.always_bcc")) == 0 {
+		return nil
+	}
+
+	resp, err := client.GetEmail(emailID, false)
+	if err != nil {
+		return err
+	}
+	orig := resp.Email
+	var thread []api.Participant
+	if orig.From != nil {
+		thread = append(thread, *orig.From)
+	}
+	if req.ReplyAll {
+		thread = append(thread, orig.To...)
+		thread = append(thread, orig.CC...)
+	}
+	req.CC, req.BCC = addAutoCopies(cmd, thread, req.CC, req.BCC)
+	return nil
+}
+
+// replyDraft lays out a reply the way mail clients do: the new text, the
+// signature after a "-- " line, then an attribution line and the original
+// with each line prefixed by "> "
+func replyDraft(body, signature string, orig api.Email) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(body, "\n"))
+	b.WriteString("\n\n")
+	if signature = strings.ReplaceAll(signature, `\n`, "\n"); strings.TrimSpace(signature) != "" {
+		b.WriteString("-- \n" + strings.TrimRight(signature, "\n") + "\n\n")
+	}
+
+	b.WriteString(attribution(orig) + "\n")
+	text := orig.Body
+	if orig.BodyType == "html" {
+		text = extract.HTML(text)
+	}
+	if text == "" {
+		text = orig.BodyPreview
+	}
+	for _, line := range strings.Split(strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n") {
+		if strings.HasPrefix(line, ">") {
+			b.WriteString(">" + line + "\n")
+		} else {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// attribution returns the "On <date>, <sender> wrote:" line above a quote
+func attribution(orig api.Email) string {
+	sender := "someone"
+	if orig.From != nil {
+		sender = orig.From.Email
+		if orig.From.Name != "" {
+			sender = orig.From.Name + " <" + orig.From.Email + ">"
+		}
+	}
+	sent := orig.SentAt
+	if sent.IsZero() {
+		sent = orig.ReceivedAt
+	}
+	if sent.IsZero() {
+		return sender + " wrote:"
+	}
+	return fmt.Sprintf("On %s, %s wrote:", sent.In(output.GetOutputLocation()).Format("Mon, 2 Jan 2006 at 15:04"), sender)
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/config"
+	"github.com/porteden/cli/internal/fakeserver"
+	"github.com/spf13/cobra"
+)
+
+func TestAddReplyAutoCopies(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := userConfig
+	t.Cleanup(func() { userConfig = saved })
+	userConfig, _ = config.Load()
+	always := []string{"Priya.Shah@example.com", "sam.okafor@example.com", "boss@example.com"}
+	if err := userConfig.SetList("email.always_cc", always); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := fakeserver.New()
+	fake.APIKey = "test-key"
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	client := api.NewClient("test-key").WithBaseURL(srv.URL)
+
+	// From Priya, to Alex (the user) and Sam
+	emails, err := client.GetEmails(api.EmailParams{Query: "Q3 planning"})
+	if err != nil || len(emails.Emails) == 0 {
+		t.Fatalf("GetEmails = %v, %v", emails, err)
+	}
+	emailID := emails.Emails[len(emails.Emails)-1].ID
+
+	tests := []struct {
+		name     string
+		replyAll bool
+		noAuto   bool
+		want     string
+	}{
+		{"reply", false, false, "sam.okafor@example.com,boss@example.com"},
+		{"reply all", true, false, "boss@example.com"},
+		{"no auto cc", false, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("no-auto-cc", tt.noAuto, "")
+			req := api.ReplyEmailRequest{ReplyAll: tt.replyAll}
+			if err := addReplyAutoCopies(cmd, client, emailID, &req); err != nil {
+				t.Fatal(err)
+			}
+			var cc []string
+			for _, p := range req.CC {
+				cc = append(cc, p.Email)
+			}
+			if got := strings.Join(cc, ","); got != tt.want {
+				t.Errorf("CC = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/porteden/cli/internal/apierr"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes let scripts branch on the failure class. Documented in the root help
+// and README; never renumber existing codes.
+const (
+	exitOK           = 0
+	exitError        = 1 // Generic failure (network, invalid input, unexpected errors)
+	exitUnauthorized = 3 // UNAUTHENTICATED: missing, invalid, or revoked credentials
+	exitAccessDenied = 4 // ACCESS_DENIED
+	exitNotFound     = 5 // NOT_FOUND
+	exitRateLimited  = 6 // RATE_LIMITED
+	exitValidation   = 7 // VALIDATION: the API rejected the request parameters
+	exitServerError  = 8 // SERVER_ERROR: backend failure, safe to retry later
+	exitConflict     = 9 // CONFLICT
+)
+
+// errNotAuthenticated is returned when no credentials are configured, before
+// any request reaches the API
+var errNotAuthenticated = errors.New("not authenticated")
+
+// exitCode maps an error to the process exit code
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, errNotAuthenticated) {
+		return exitUnauthorized
+	}
+	var apiErr *apierr.APIError
+	if !errors.As(err, &apiErr) {
+		return exitError
+	}
+	switch apiErr.ErrorCode() {
+	case "UNAUTHENTICATED", "UNAUTHORIZED", "INVALID_TOKEN":
+		return exitUnauthorized
+	case "ACCESS_DENIED", "FORBIDDEN":
+		return exitAccessDenied
+	case "NOT_FOUND":
+		return exitNotFound
+	case "RATE_LIMITED", "TOO_MANY_REQUESTS":
+		return exitRateLimited
+	case "VALIDATION", "VALIDATION_ERROR", "INVALID_REQUEST", "BAD_REQUEST":
+		return exitValidation
+	case "SERVER_ERROR", "INTERNAL_ERROR", "SERVICE_UNAVAILABLE":
+		return exitServerError
+	case "CONFLICT":
+		return exitConflict
+	}
+
+	// Unrecognized backend code: fall back to the HTTP status class
+	fallback := &apierr.APIError{StatusCode: apiErr.StatusCode}
+	if code := fallback.ErrorCode(); code != "API_ERROR" {
+		return exitCode(fallback)
+	}
+	return exitError
+}
+
+// userError is a user-friendly message that keeps the underlying API error
+// reachable for structured output
+type userError struct {
+	message string
+	apiErr  *apierr.APIError
+}
+
+func (e *userError) Error() string { return e.message }
+func (e *userError) Unwrap() error { return e.apiErr }
+
+// Helper function to format API errors
+func formatError(err error) error {
+	if apiErr, ok := err.(*apierr.APIError); ok {
+		message := apierr.UserFriendlyError(apiErr)
+		if delegateAs != "" && apiErr.StatusCode == 403 {
+			message = fmt.Sprintf("Access denied. You don't have enough delegated access to %s's calendar. Ask them to share it with you as a writer.", delegateAs)
+		}
+		if sharedMailbox != "" && apiErr.StatusCode == 403 {
+			message = fmt.Sprintf("Access denied. %s isn't a shared mailbox you can use. Ask its owner to give you access, then reconnect the account.", sharedMailbox)
+		}
+		return &userError{message: withRequestID(message, apiErr), apiErr: apiErr}
+	}
+	return err
+}
+
+// withRequestID adds the failed request's ID to message, for support tickets
+func withRequestID(message string, apiErr *apierr.APIError) string {
+	if apiErr.RequestID == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (request ID: %s)", message, apiErr.RequestID)
+}
+
+// printCommandError reports a failed command on stderr, as a JSON object when
+// JSON output was requested
+func printCommandError(cmd *cobra.Command, err error) {
+	if cmd == nil || getOutputFormat(cmd) != output.FormatJSON {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	detail := output.ErrorDetail{
+		Code:    "CLI_ERROR",
+		Message: err.Error(),
+	}
+	var apiErr *apierr.APIError
+	if errors.Is(err, errNotAuthenticated) {
+		detail.Code = "UNAUTHENTICATED"
+	} else if errors.As(err, &apiErr) {
+		detail.Code = apiErr.ErrorCode()
+		detail.Status = apiErr.StatusCode
+		detail.RequestID = apiErr.RequestID
+	}
+	output.PrintJSONError(os.Stderr, detail)
+}
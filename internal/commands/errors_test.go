@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/porteden/cli/internal/apierr"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"plain error", errors.New("invalid --min"), exitError},
+		{"no credentials", fmt.Errorf("%w. Run 'porteden auth login' to authenticate", errNotAuthenticated), exitUnauthorized},
+		{"no credentials for profile", fmt.Errorf("%w (profile: work)", errNotAuthenticated), exitUnauthorized},
+		{"rejected key", &apierr.APIError{StatusCode: 401, Code: "UNAUTHENTICATED"}, exitUnauthorized},
+		{"formatted API error", formatError(&apierr.APIError{StatusCode: 404, Code: "NOT_FOUND"}), exitNotFound},
+		{"unknown code", &apierr.APIError{StatusCode: 503, Code: "MAINTENANCE"}, exitServerError},
+		{"unknown status", &apierr.APIError{StatusCode: 418}, exitError},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
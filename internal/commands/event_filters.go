@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// filterEvents applies the client-side event filters selected by flags. me
+// is the user's calendar email.
+func filterEvents(cmd *cobra.Command, events []api.Event, me string) []api.Event {
+	if meetings, _ := cmd.Flags().GetBool("meetings-only"); meetings {
+		events = meetingsOnly(events, me)
+	}
+	if hideDeclined(cmd) {
+		events = withoutDeclined(events, me)
+	}
+	if mine, _ := cmd.Flags().GetBool("organized-by-me"); mine {
+		events = organizedBy(events, me)
+	}
+	if awaiting, _ := cmd.Flags().GetBool("awaiting-my-response"); awaiting {
+		events = awaitingResponse(events, me)
+	}
+	if email, _ := cmd.Flags().GetString("needs-rsvp-from"); email != "" {
+		events = awaitingResponse(events, email)
+	}
+	return events
+}
+
+// hideDeclined reports whether declined events should be left out, from
+// --hide-declined or else the calendar.hide_declined setting
+func hideDeclined(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("hide-declined") {
+		hide, _ := cmd.Flags().GetBool("hide-declined")
+		return hide
+	}
+	return cmd.Flags().Lookup("hide-declined") != nil && userConfig.Bool("calendar.hide_declined")
+}
+
+// meetingsOnly keeps events with at least one attendee besides the user
+func meetingsOnly(events []api.Event, me string) []api.Event {
+	var out []api.Event
+	for _, e := range events {
+		for _, a := range e.Attendees {
+			if !strings.EqualFold(a.Email, me) {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// organizedBy keeps events whose organizer is the given email
+func organizedBy(events []api.Event, email string) []api.Event {
+	var out []api.Event
+	for _, e := range events {
+		if email != "" && strings.EqualFold(e.Organizer, email) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// awaitingResponse keeps events where the given attendee hasn't replied
+func awaitingResponse(events []api.Event, email string) []api.Event {
+	var out []api.Event
+	for _, e := range events {
+		for _, a := range e.Attendees {
+			if strings.EqualFold(a.Email, email) && attendeeResponse(a) == "needsAction" {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// attendeeResponse returns an attendee's reply, treating no reply as
+// needsAction
+func attendeeResponse(a api.Attendee) string {
+	switch {
+	case a.Response != "":
+		return a.Response
+	case a.ResponseStatus != "":
+		return a.ResponseStatus
+	}
+	return "needsAction"
+}
+
+// withoutDeclined drops events the user has declined
+func withoutDeclined(events []api.Event, me string) []api.Event {
+	var out []api.Event
+	for _, e := range events {
+		if !declinedBy(e, me) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/holidays"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// addHolidayBlocks marks the region's public holidays between from and to as
+// all-day busy blocks on every calendar in resp
+func addHolidayBlocks(resp *api.FreeBusyResponse, region string, from, to time.Time) error {
+	loc := output.GetOutputLocation()
+	days, err := holidays.Between(region, from.In(loc), to.Add(-time.Nanosecond).In(loc))
+	if err != nil {
+		return err
+	}
+	for i := range resp.Calendars {
+		cal := &resp.Calendars[i]
+		for _, h := range days {
+			start := time.Date(h.Date.Year(), h.Date.Month(), h.Date.Day(), 0, 0, 0, 0, loc)
+			end := start.AddDate(0, 0, 1)
+			cal.Busy = append(cal.Busy, api.BusyPeriod{
+				StartUtc:        start.UTC(),
+				EndUtc:          end.UTC(),
+				DurationMinutes: int(end.Sub(start).Minutes()),
+				Holiday:         h.Name,
+			})
+		}
+		sort.SliceStable(cal.Busy, func(a, b int) bool {
+			return cal.Busy[a].StartUtc.Before(cal.Busy[b].StartUtc)
+		})
+	}
+	return nil
+}
+
+// holidayRegions completes --skip-holidays
+func holidayRegions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var regions []string
+	for _, r := range holidays.Regions() {
+		if strings.HasPrefix(r, strings.ToUpper(toComplete)) {
+			regions = append(regions, r)
+		}
+	}
+	return regions, cobra.ShellCompDirectiveNoFileComp
+}
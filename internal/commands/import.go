@@ -0,0 +1,352 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/bundle"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// importItem is one exported event and what import does with it
+type importItem struct {
+	SourceID   string    `json:"sourceId"`
+	Summary    string    `json:"summary"`
+	Start      time.Time `json:"start"`
+	CalendarID int64     `json:"calendarId"`
+	// Status is create or skip when planned, then created, skipped or failed
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	EventID string `json:"eventId,omitempty"`
+	Error   string `json:"error,omitempty"`
+
+	req api.CreateEventRequest
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <manifest.json|directory>",
+	Short: "Restore events from an export",
+	Long: `Recreate events from a directory written by 'porteden export'.
+
+Events go back into the calendar they were exported from, matched by ID and
+then by name, or into --calendar. An event is skipped when one with the same
+title, start and end is already in the calendar, so running an import twice
+doesn't duplicate anything. Cancelled events are skipped too.
+
+Attendees are left off unless --invite is given, since adding them sends
+invitations. Recurring events were exported as single occurrences and are
+restored that way. Files are checked against the manifest's checksums before
+anything is created.
+
+Only calendar data can be imported: the API has no way to recreate emails.
+
+Examples:
+  porteden import backup/ --dry-run
+  porteden import backup/manifest.json --what calendar --yes
+  porteden import backup/ --calendar Restored`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		what, _ := cmd.Flags().GetStringSlice("what")
+		calendarRef, _ := cmd.Flags().GetString("calendar")
+		invite, _ := cmd.Flags().GetBool("invite")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		jsonOutput := getOutputFormat(cmd) == output.FormatJSON
+
+		for _, w := range what {
+			switch strings.ToLower(strings.TrimSpace(w)) {
+			case bundle.KindCalendar:
+			case bundle.KindEmail:
+				return fmt.Errorf("email can't be imported: the API has no way to recreate messages or drafts")
+			default:
+				return fmt.Errorf("invalid --what %q: use calendar", w)
+			}
+		}
+
+		m, dir, err := bundle.ReadManifest(args[0])
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		if err := applyRateFlag(cmd, client); err != nil {
+			return err
+		}
+
+		calendarID, err := resolveCalendarID(cmd, client, calendarRef)
+		if err != nil {
+			return err
+		}
+		items, err := planImport(cmd, client, m, dir, calendarID, invite)
+		if err != nil {
+			return err
+		}
+		if err := markCollisions(client, items); err != nil {
+			return err
+		}
+
+		toCreate := 0
+		for _, it := range items {
+			if it.Status == "create" {
+				toCreate++
+			}
+		}
+
+		if dryRun {
+			if jsonOutput {
+				output.PrintWithOptions(items, output.FormatJSON, output.PrintOptions{})
+				return nil
+			}
+			printImportPlan(items)
+			fmt.Printf("\nDry run: %d event(s) to create, %d skipped, nothing created.\n", toCreate, len(items)-toCreate)
+			return nil
+		}
+		if toCreate == 0 {
+			if jsonOutput {
+				output.PrintWithOptions(skipAll(items), output.FormatJSON, output.PrintOptions{})
+				return nil
+			}
+			output.PrintInfo(fmt.Sprintf("Nothing to import: all %d event(s) are already in place or cancelled", len(items)))
+			return nil
+		}
+
+		if !jsonOutput {
+			printImportPlan(items)
+			fmt.Println()
+		}
+		if !yes && auth.IsInteractiveTerminal() {
+			if !confirm(i18n.T("Create %d event(s)?", toCreate)) {
+				fmt.Println(i18n.T("Cancelled. No events created."))
+				return nil
+			}
+		}
+
+		failed := 0
+		for i := range items {
+			it := &items[i]
+			if it.Status != "create" {
+				it.Status = "skipped"
+				continue
+			}
+			event, err := client.CreateEvent(it.req)
+			if err != nil {
+				it.Status, it.Error = "failed", formatError(err).Error()
+				failed++
+				if !jsonOutput {
+					fmt.Fprintf(os.Stderr, "  %s %s: %s\n", output.ColorRed(output.Symbols("✗")), it.Summary, it.Error)
+				}
+				continue
+			}
+			it.Status, it.EventID = "created", event.ID
+			if !jsonOutput {
+				output.PrintSuccess(fmt.Sprintf("%s (ID: %s)", it.Summary, event.ID))
+			}
+		}
+
+		if jsonOutput {
+			output.PrintWithOptions(items, output.FormatJSON, output.PrintOptions{})
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d events failed", failed, toCreate)
+		}
+		if !jsonOutput {
+			fmt.Printf("\nCreated %d event(s), skipped %d\n", toCreate, len(items)-toCreate)
+		}
+		return nil
+	},
+}
+
+// planImport reads the exported events and builds their create requests.
+// Events go into calendarID when set, otherwise into the calendar they came
+// from.
+func planImport(cmd *cobra.Command, client *api.Client, m *bundle.Manifest, dir string, calendarID int64, invite bool) ([]importItem, error) {
+	var calendars []api.Calendar
+	if calendarID == 0 {
+		var err error
+		if calendars, err = fetchCalendarList(cmd, client); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []importItem
+	for _, f := range m.Files {
+		if f.Kind != bundle.KindCalendar || f.Format != bundle.FormatJSON {
+			continue
+		}
+		data, err := bundle.Open(dir, f)
+		if err != nil {
+			return nil, err
+		}
+		var events []api.Event
+		if err := json.Unmarshal(data, &events); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.Path, err)
+		}
+
+		target := calendarID
+		if target == 0 {
+			if target, err = importTarget(calendars, f); err != nil {
+				return nil, err
+			}
+		}
+		for _, e := range events {
+			items = append(items, importEvent(e, target, invite))
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("the export has no events")
+	}
+	return items, nil
+}
+
+// importTarget picks the calendar for an exported calendar file: the same
+// calendar if it still exists, else one with the same name
+func importTarget(calendars []api.Calendar, f bundle.File) (int64, error) {
+	for _, c := range calendars {
+		if c.ID == f.CalendarID {
+			return c.ID, nil
+		}
+	}
+	for _, c := range calendars {
+		if f.CalendarName != "" && strings.EqualFold(c.Name, f.CalendarName) {
+			return c.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("calendar %q (%d) from the export no longer exists; pass --calendar to import into another one", f.CalendarName, f.CalendarID)
+}
+
+// importEvent builds the item for one exported event
+func importEvent(e api.Event, calendarID int64, invite bool) importItem {
+	title := e.Title
+	if title == "" {
+		title = e.Summary
+	}
+	it := importItem{SourceID: e.ID, Summary: title, Start: e.StartUtc, CalendarID: calendarID, Status: "create"}
+	it.req = api.CreateEventRequest{
+		CalendarID:   calendarID,
+		Summary:      title,
+		Description:  e.Description,
+		Location:     e.Location,
+		From:         e.StartUtc,
+		To:           e.EndUtc,
+		IsAllDay:     e.AllDay || e.IsAllDay,
+		EventType:    e.EventType,
+		Transparency: e.Transparency,
+	}
+	if invite {
+		for _, a := range e.Attendees {
+			it.req.Attendees = append(it.req.Attendees, a.Email)
+		}
+	}
+	if strings.EqualFold(e.Status, "cancelled") {
+		it.Status, it.Reason = "skip", "cancelled"
+	}
+	return it
+}
+
+// markCollisions skips events that already exist in their target calendar,
+// or appear twice in the export, matching on title, start and end
+func markCollisions(client *api.Client, items []importItem) error {
+	windows := map[int64][2]time.Time{}
+	for _, it := range items {
+		w, ok := windows[it.CalendarID]
+		if !ok || it.req.From.Before(w[0]) {
+			w[0] = it.req.From
+		}
+		if !ok || it.req.To.After(w[1]) {
+			w[1] = it.req.To
+		}
+		windows[it.CalendarID] = w
+	}
+
+	existing := map[string]string{}
+	for calendarID, w := range windows {
+		params := api.EventParams{From: w[0], To: w[1], CalendarID: calendarID, Limit: 100}
+		err := client.ForEachEventsPage(params, func(resp *api.EventsResponse) error {
+			for _, e := range resp.Events {
+				if strings.EqualFold(e.Status, "cancelled") {
+					continue
+				}
+				title := e.Title
+				if title == "" {
+					title = e.Summary
+				}
+				existing[importKey(calendarID, title, e.StartUtc, e.EndUtc)] = e.ID
+			}
+			return nil
+		})
+		if err != nil {
+			return formatError(err)
+		}
+	}
+
+	planned := map[string]string{}
+	for i := range items {
+		it := &items[i]
+		if it.Status != "create" {
+			continue
+		}
+		key := importKey(it.CalendarID, it.Summary, it.req.From, it.req.To)
+		if id, ok := existing[key]; ok {
+			it.Status, it.Reason = "skip", "already exists as "+id
+		} else if id, ok := planned[key]; ok {
+			it.Status, it.Reason = "skip", "duplicate of "+id
+		} else {
+			planned[key] = it.SourceID
+		}
+	}
+	return nil
+}
+
+func importKey(calendarID int64, title string, start, end time.Time) string {
+	return strconv.FormatInt(calendarID, 10) + "|" + strings.ToLower(strings.TrimSpace(title)) +
+		"|" + start.UTC().Format(time.RFC3339) + "|" + end.UTC().Format(time.RFC3339)
+}
+
+// skipAll marks every planned item skipped, for output when nothing is created
+func skipAll(items []importItem) []importItem {
+	for i := range items {
+		items[i].Status = "skipped"
+	}
+	return items
+}
+
+func printImportPlan(items []importItem) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	output.TableHeader(w, "ACTION", "START", "CALENDAR", "SUMMARY", "REASON")
+	for _, it := range items {
+		layout := "2006-01-02 15:04"
+		if it.req.IsAllDay {
+			layout = "2006-01-02"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			it.Status,
+			it.Start.In(output.GetOutputLocation()).Format(layout),
+			it.CalendarID,
+			truncateRunes(it.Summary, 40),
+			it.Reason,
+		)
+	}
+	w.Flush()
+}
+
+func init() {
+	importCmd.Flags().StringSlice("what", []string{bundle.KindCalendar}, "What to import: calendar")
+	importCmd.Flags().String("calendar", "", "Import every event into this calendar ID or name (default: the calendar it came from)")
+	importCmd.Flags().Bool("invite", false, "Add the original attendees, sending them invitations")
+	importCmd.Flags().Bool("dry-run", false, "Show what would be created and skipped without creating anything")
+	importCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	importCmd.Flags().String("rate", "", rateFlagUsage)
+}
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/index"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// indexSyncOverlap re-fetches a little before the last sync so messages that
+// arrive out of order are not missed
+const indexSyncOverlap = time.Hour
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Local full-text search index",
+	Long: `Maintain a local full-text index of emails and events for instant offline search.
+
+Examples:
+  porteden index build --days 180
+  porteden index update
+  porteden index search "budget review"
+  porteden index search invoice --kind email -j`,
+}
+
+var indexBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build the index from scratch",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return syncIndex(cmd, index.New(), true)
+	},
+}
+
+var indexUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Add new emails and refresh recent events",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ix, err := index.Load(getProfile(cmd))
+		if err != nil {
+			return err
+		}
+		if ix.Len() == 0 {
+			return fmt.Errorf("no index yet. Run 'porteden index build' first")
+		}
+		return syncIndex(cmd, ix, false)
+	},
+}
+
+var indexSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the local index (offline)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, _ := cmd.Flags().GetString("kind")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		if kind != "" && kind != index.KindEmail && kind != index.KindEvent {
+			return fmt.Errorf("invalid --kind %q: use email or event", kind)
+		}
+
+		ix, err := index.Load(getProfile(cmd))
+		if err != nil {
+			return err
+		}
+		if ix.Len() == 0 {
+			return fmt.Errorf("no index yet. Run 'porteden index build' first")
+		}
+
+		query := strings.Join(args, " ")
+		output.PrintWithOptions(ix.Search(query, kind, limit), getOutputFormat(cmd), output.PrintOptions{
+			Highlight: query,
+		})
+		return nil
+	},
+}
+
+// syncIndex fetches emails and events into ix and saves it. A full sync covers
+// the whole --days window; otherwise only emails since the last sync are fetched.
+func syncIndex(cmd *cobra.Command, ix *index.Index, full bool) error {
+	days, _ := cmd.Flags().GetInt("days")
+	bodies, _ := cmd.Flags().GetBool("bodies")
+
+	client, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	windowStart := now.AddDate(0, 0, -days)
+
+	// Emails
+	emailParams := api.EmailParams{Limit: 50, After: windowStart}
+	if last := ix.Synced[index.KindEmail]; !full && !last.IsZero() {
+		emailParams.After = last.Add(-indexSyncOverlap)
+	}
+	emailCount := 0
+	_, err = client.ForEachEmailsPage(emailParams, func(resp *api.EmailsResponse) error {
+		if bodies {
+			if err := client.FetchEmailBodies(resp.Emails, 8); err != nil {
+				return err
+			}
+		}
+		for _, e := range resp.Emails {
+			ix.Add(emailDocument(e))
+		}
+		emailCount += len(resp.Emails)
+		return nil
+	})
+	if err != nil {
+		return formatError(err)
+	}
+	ix.Synced[index.KindEmail] = now
+
+	// Events: past window plus the same span ahead, always refreshed since
+	// upcoming events change
+	eventParams := api.EventParams{Limit: 100, From: windowStart, To: now.AddDate(0, 0, days)}
+	if !full {
+		eventParams.From = now.AddDate(0, 0, -7)
+	}
+	eventCount := 0
+	err = client.ForEachEventsPage(eventParams, func(resp *api.EventsResponse) error {
+		for _, e := range resp.Events {
+			ix.Add(eventDocument(e))
+		}
+		eventCount += len(resp.Events)
+		return nil
+	})
+	if err != nil {
+		return formatError(err)
+	}
+	ix.Synced[index.KindEvent] = now
+
+	if err := ix.Save(getProfile(cmd)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Indexed %d emails and %d events (%d documents total)\n", emailCount, eventCount, ix.Len())
+	return nil
+}
+
+func emailDocument(e api.Email) index.Document {
+	from := ""
+	if e.From != nil {
+		from = e.From.Email
+		if e.From.Name != "" {
+			from = e.From.Name + " <" + e.From.Email + ">"
+		}
+	}
+	text := e.BodyPreview
+	if e.Body != "" {
+		text = e.Body
+	}
+	return index.Document{
+		Kind:    index.KindEmail,
+		ID:      e.ID,
+		Title:   e.Subject,
+		From:    from,
+		Date:    e.ReceivedAt,
+		Snippet: truncateRunes(oneLineText(e.BodyPreview), 160),
+		Text:    text,
+	}
+}
+
+func eventDocument(e api.Event) index.Document {
+	title := e.Title
+	if title == "" {
+		title = e.Summary
+	}
+	var attendees []string
+	for _, a := range e.Attendees {
+		attendees = append(attendees, a.Name, a.Email)
+	}
+	return index.Document{
+		Kind:    index.KindEvent,
+		ID:      e.ID,
+		Title:   title,
+		From:    e.Organizer,
+		Date:    e.StartUtc,
+		Snippet: truncateRunes(oneLineText(e.Location+" "+e.Description), 160),
+		Text:    strings.Join(append([]string{e.Description, e.Location}, attendees...), " "),
+	}
+}
+
+// oneLineText collapses whitespace runs into single spaces
+func oneLineText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateRunes shortens s to at most n runes, adding an ellipsis
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{indexBuildCmd, indexUpdateCmd} {
+		cmd.Flags().Int("days", 90, "Days of history to index (events also N days ahead)")
+		cmd.Flags().Bool("bodies", false, "Index full email bodies (slower, larger index)")
+	}
+	indexSearchCmd.Flags().String("kind", "", "Only search one kind: email or event")
+	indexSearchCmd.Flags().Int("limit", 20, "Maximum results")
+
+	indexCmd.AddCommand(indexBuildCmd)
+	indexCmd.AddCommand(indexUpdateCmd)
+	indexCmd.AddCommand(indexSearchCmd)
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/browser"
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/qr"
+	"github.com/spf13/cobra"
+)
+
+var joinCmd = &cobra.Command{
+	Use:   "join <eventId>",
+	Short: "Open an event's meeting link",
+	Long: `Open the video meeting link of an event in the browser.
+
+The link comes from the event's join URL, or failing that, the first
+Meet/Zoom/Teams/Webex URL in its location or description.
+
+Examples:
+  porteden calendar join <eventId>
+  porteden calendar join next                 # Also: current, last
+  porteden calendar join <eventId> --qr        # Scan with your phone
+  porteden calendar join <eventId> --print     # Just print the URL`,
+	Args: pickableArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		showQR, _ := cmd.Flags().GetBool("qr")
+		invert, _ := cmd.Flags().GetBool("invert")
+		printOnly, _ := cmd.Flags().GetBool("print")
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		eventID, err := eventIDArg(cmd, client, args)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.GetEvent(eventID)
+		if err != nil {
+			return formatError(err)
+		}
+		event := resp.Event
+
+		link := meetingURL(event)
+		if link == "" {
+			return fmt.Errorf("event %s has no meeting link", event.ID)
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(map[string]string{
+				"eventId": event.ID,
+				"title":   event.Title,
+				"joinUrl": link,
+			}, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+
+		switch {
+		case showQR:
+			code, err := qr.Encode(link)
+			if err != nil {
+				return fmt.Errorf("failed to render QR code: %w", err)
+			}
+			fmt.Print(code.Render(invert))
+			fmt.Println(link)
+		case printOnly:
+			fmt.Println(link)
+		default:
+			fmt.Printf("Joining %s\n%s\n", event.Title, link)
+			if err := browser.OpenURL(link); err != nil {
+				return fmt.Errorf("failed to open browser: %w", err)
+			}
+		}
+		return nil
+	},
+}
+
+// meetingLinkPattern matches links to the common video meeting providers
+var meetingLinkPattern = regexp.MustCompile(`https://(meet\.google\.com|[\w.-]*zoom\.us|teams\.microsoft\.com|teams\.live\.com|[\w.-]*webex\.com)/[^\s<>"')]+`)
+
+// meetingURL returns the event's join link, falling back to a meeting URL
+// mentioned in its location or description
+func meetingURL(e api.Event) string {
+	if e.JoinUrl != "" {
+		return e.JoinUrl
+	}
+	for _, text := range []string{e.Location, e.Description} {
+		if link := meetingLinkPattern.FindString(text); link != "" {
+			return link
+		}
+	}
+	return ""
+}
+
+func init() {
+	joinCmd.Flags().Bool("qr", false, "Show the link as a QR code instead of opening it")
+	joinCmd.Flags().Bool("invert", false, "Invert QR code colors (for light terminal backgrounds)")
+	joinCmd.Flags().Bool("print", false, "Print the link without opening it")
+
+	calendarCmd.AddCommand(joinCmd)
+}
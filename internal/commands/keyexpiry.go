@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/output"
+)
+
+const (
+	// keyExpiryWarning is how long before expiry warnings start
+	keyExpiryWarning = 14 * 24 * time.Hour
+	// keyInfoMaxAge is how long stored key details are trusted before they
+	// are fetched again
+	keyInfoMaxAge = 7 * 24 * time.Hour
+)
+
+// rememberKeyInfo stores what the API reported about a profile's key
+func rememberKeyInfo(profileName string, status *api.AuthStatusResponse) {
+	info, _ := auth.GetKeyInfo(profileName)
+	info.KeyID = status.KeyID
+	info.CreatedAt = status.CreatedAt
+	info.ExpiresAt = status.ExpiresAt
+	info.CheckedAt = time.Now()
+	_ = auth.SetKeyInfo(profileName, info)
+}
+
+// keyExpiryNotice describes how soon a key expires, or "" when it isn't
+// close to expiring
+func keyExpiryNotice(profileName string, expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return ""
+	}
+	left := time.Until(*expiresAt)
+	date := expiresAt.In(output.GetOutputLocation()).Format("2006-01-02")
+	switch {
+	case left <= 0:
+		return fmt.Sprintf("The API key for profile '%s' expired on %s. Run 'porteden auth login' to create a new one.", profileName, date)
+	case left < keyExpiryWarning:
+		return fmt.Sprintf("The API key for profile '%s' expires in %s (%s). Run 'porteden auth login' to create a new one.", profileName, daysLeft(left), date)
+	}
+	return ""
+}
+
+func daysLeft(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch days {
+	case 0:
+		return "less than a day"
+	case 1:
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// warnKeyExpiry warns once a day when the profile's stored key is about to
+// expire. Key details are refreshed from the API when they are missing or
+// stale; failures are ignored and retried the next day.
+func warnKeyExpiry(profileName string, client *api.Client) {
+	if quietMode {
+		return
+	}
+	info, ok := auth.GetKeyInfo(profileName)
+	if !ok || time.Since(info.CheckedAt) > keyInfoMaxAge {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if r := client.Ping(ctx); r.Auth != nil {
+			rememberKeyInfo(profileName, r.Auth)
+			info, _ = auth.GetKeyInfo(profileName)
+		} else {
+			info.CheckedAt = time.Now().Add(24*time.Hour - keyInfoMaxAge)
+			_ = auth.SetKeyInfo(profileName, info)
+		}
+	}
+
+	notice := keyExpiryNotice(profileName, info.ExpiresAt)
+	now := time.Now()
+	if notice == "" || info.WarnedAt != nil && sameDay(*info.WarnedAt, now) {
+		return
+	}
+	fmt.Fprintln(os.Stderr, output.ColorYellow(notice))
+	info.WarnedAt = &now
+	_ = auth.SetKeyInfo(profileName, info)
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
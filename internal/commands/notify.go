@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/cache"
+	"github.com/porteden/cli/internal/debug"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/system"
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Desktop notifications for upcoming events",
+	Long: `Poll upcoming events and show a native desktop notification shortly before each one starts.
+
+Runs until interrupted. Start it in the background from your shell or login items,
+or use --once from cron.
+
+Examples:
+  porteden notify --before 5m
+  porteden notify --before 10m --interval 2m &
+  porteden notify --once --before 15m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		before, _ := cmd.Flags().GetDuration("before")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		once, _ := cmd.Flags().GetBool("once")
+
+		if before <= 0 {
+			return fmt.Errorf("--before must be positive")
+		}
+		if interval < 30*time.Second {
+			return fmt.Errorf("--interval must be at least 30s")
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		// Kept in the cache, so runs of --once from cron don't notify twice
+		notified := loadNotified(cmd)
+
+		for {
+			sent, err := notifyUpcoming(client, before, notified)
+			if sent > 0 {
+				saveNotified(cmd, notified)
+			}
+			if err != nil {
+				if once {
+					return formatError(err)
+				}
+				// Keep the daemon alive through transient failures
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", formatError(err))
+			}
+			if once {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+// notifiedCacheFile names the cache entry of a profile's notified events
+func notifiedCacheFile(profile string) string {
+	return "notified-" + profile + ".json"
+}
+
+// loadNotified returns the events already notified of, keyed by event ID +
+// start so a rescheduled event notifies again, with their start times
+func loadNotified(cmd *cobra.Command) map[string]time.Time {
+	notified := make(map[string]time.Time)
+	data, err := cache.Read(notifiedCacheFile(getProfile(cmd)))
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			debug.Log("Failed to read notified events: %v", err)
+		}
+		return notified
+	}
+	if err := json.Unmarshal(data, &notified); err != nil {
+		debug.Log("Ignoring invalid notified events: %v", err)
+		return make(map[string]time.Time)
+	}
+	return notified
+}
+
+// saveNotified caches the notified events, dropping those that started over
+// an hour ago: they're past the polling window and can't notify again
+func saveNotified(cmd *cobra.Command, notified map[string]time.Time) {
+	cutoff := time.Now().Add(-time.Hour)
+	for key, start := range notified {
+		if start.Before(cutoff) {
+			delete(notified, key)
+		}
+	}
+	data, err := json.Marshal(notified)
+	if err == nil {
+		err = cache.Write(notifiedCacheFile(getProfile(cmd)), data)
+	}
+	if err != nil {
+		debug.Log("Failed to save notified events: %v", err)
+	}
+}
+
+// notifyUpcoming fires notifications for events starting within the lead
+// time that aren't in notified, adds them, and returns how many it added
+func notifyUpcoming(client *api.Client, before time.Duration, notified map[string]time.Time) (int, error) {
+	now := time.Now()
+	resp, err := client.GetEvents(api.EventParams{
+		From:  now.Add(-time.Minute),
+		To:    now.Add(before),
+		Limit: 50,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, e := range resp.Events {
+		if e.AllDay || e.IsAllDay || e.Status == "cancelled" {
+			continue
+		}
+		if e.StartUtc.Before(now.Add(-time.Minute)) || e.StartUtc.After(now.Add(before)) {
+			continue
+		}
+
+		key := e.ID + "@" + e.StartUtc.Format(time.RFC3339)
+		if _, ok := notified[key]; ok {
+			continue
+		}
+		notified[key] = e.StartUtc
+		sent++
+
+		title := e.Title
+		if title == "" {
+			title = e.Summary
+		}
+
+		mins := int(time.Until(e.StartUtc).Round(time.Minute).Minutes())
+		message := "Starting now"
+		if mins > 0 {
+			message = fmt.Sprintf("Starts in %d min (%s)", mins, safeClock(e))
+		}
+		if e.Location != "" {
+			message += " · " + e.Location
+		}
+		if e.JoinUrl != "" {
+			message += "\n" + e.JoinUrl
+		}
+
+		debug.Log("Notifying for event %s: %s", e.ID, title)
+		if err := system.Notify(title, message); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	return sent, nil
+}
+
+// safeClock returns the local HH:MM start time of an event
+func safeClock(e api.Event) string {
+	start := output.GetLocalStart(e.StartLocal, e.StartUtc)
+	if len(start) < 16 {
+		return start
+	}
+	return start[11:16]
+}
+
+func init() {
+	notifyCmd.Flags().Duration("before", 5*time.Minute, "Notify this long before an event starts")
+	notifyCmd.Flags().Duration("interval", time.Minute, "How often to poll for upcoming events")
+	notifyCmd.Flags().Bool("once", false, "Check once and exit (for cron)")
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// oooResult is the JSON output of 'ooo'
+type oooResult struct {
+	Event     *api.Event             `json:"event"`
+	AutoReply *api.AutoReplySettings `json:"autoReply,omitempty"`
+}
+
+var oooCmd = &cobra.Command{
+	Use:   "ooo",
+	Short: "Block out time away and turn on the auto-reply",
+	Long: `Mark yourself out of office for a range of days.
+
+Creates an all-day out-of-office event repeating daily from --from to --to
+(both inclusive) that shows you as busy. With --decline-new, invitations
+overlapping it are declined automatically. With --auto-reply, the email
+auto-reply is switched on for the same days.
+
+Examples:
+  porteden ooo --from 2026-04-01 --to 2026-04-05
+  porteden ooo --from 2026-04-01 --to 2026-04-05 --decline-new \
+    --auto-reply "I'm away until April 6 with limited access to email."`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromStr, _ := cmd.Flags().GetString("from")
+		toStr, _ := cmd.Flags().GetString("to")
+		title, _ := cmd.Flags().GetString("title")
+		calendarRef, _ := cmd.Flags().GetString("calendar")
+		declineNew, _ := cmd.Flags().GetBool("decline-new")
+		message, _ := cmd.Flags().GetString("auto-reply")
+		subject, _ := cmd.Flags().GetString("auto-reply-subject")
+
+		loc := output.GetOutputLocation()
+		first, err := time.ParseInLocation("2006-01-02", fromStr, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --from %q: use YYYY-MM-DD", fromStr)
+		}
+		last, err := time.ParseInLocation("2006-01-02", toStr, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --to %q: use YYYY-MM-DD", toStr)
+		}
+		if last.Before(first) {
+			return errors.New("--to must not be before --from")
+		}
+		if cmd.Flags().Changed("auto-reply") && message == "" {
+			return errors.New("--auto-reply needs a message")
+		}
+		days := int(last.Sub(first).Hours()/24+0.5) + 1
+		end := last.AddDate(0, 0, 1)
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		calendarID, err := resolveCalendarID(cmd, client, calendarRef)
+		if err != nil {
+			return err
+		}
+		if calendarID == 0 {
+			if calendarID, err = primaryCalendarID(client); err != nil {
+				return err
+			}
+		}
+
+		req := api.CreateEventRequest{
+			CalendarID:            calendarID,
+			Summary:               title,
+			From:                  first,
+			To:                    first.AddDate(0, 0, 1),
+			IsAllDay:              true,
+			EventType:             "outOfOffice",
+			Transparency:          "opaque",
+			DeclineNewInvitations: declineNew,
+		}
+		if days > 1 {
+			req.Recurrence = []string{fmt.Sprintf("RRULE:FREQ=DAILY;COUNT=%d", days)}
+		}
+		event, err := client.CreateEvent(req)
+		if err != nil {
+			return formatError(err)
+		}
+		result := oooResult{Event: event}
+
+		if message != "" {
+			result.AutoReply, err = client.SetAutoReply(api.AutoReplySettings{
+				Enabled: true,
+				Subject: subject,
+				Message: message,
+				Start:   &first,
+				End:     &end,
+			})
+			if err != nil {
+				return fmt.Errorf("created out-of-office event %s, but the auto-reply could not be turned on: %w", event.ID, formatError(err))
+			}
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.Print(result, output.FormatJSON)
+			return nil
+		}
+		span := first.Format("Jan 2")
+		if days > 1 {
+			span += " to " + last.Format("Jan 2")
+		}
+		output.PrintSuccess(fmt.Sprintf("Out of office %s: %s", span, event.ID))
+		if declineNew {
+			output.PrintInfo("New invitations for these days will be declined")
+		}
+		if result.AutoReply != nil {
+			output.PrintSuccess("Auto-reply on through " + last.Format("Jan 2"))
+		}
+		return nil
+	},
+}
+
+func init() {
+	oooCmd.Flags().String("from", "", "First day away (YYYY-MM-DD, required)")
+	oooCmd.Flags().String("to", "", "Last day away (YYYY-MM-DD, required)")
+	oooCmd.Flags().String("title", "Out of office", "Event title")
+	oooCmd.Flags().String("calendar", "", "Calendar ID or name (default: primary calendar)")
+	oooCmd.Flags().Bool("decline-new", false, "Decline new invitations during the time away")
+	oooCmd.Flags().String("auto-reply", "", "Turn on the email auto-reply with this message")
+	oooCmd.Flags().String("auto-reply-subject", "Out of office", "Subject of the auto-reply")
+	_ = oooCmd.MarkFlagRequired("from")
+	_ = oooCmd.MarkFlagRequired("to")
+}
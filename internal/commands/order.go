@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// Values of --order
+const (
+	orderAsc  = "asc"
+	orderDesc = "desc"
+)
+
+// listOrder returns the --order value, or "" to keep the API's order when
+// the flag wasn't given
+func listOrder(cmd *cobra.Command) (string, error) {
+	if !cmd.Flags().Changed("order") {
+		return "", nil
+	}
+	order, _ := cmd.Flags().GetString("order")
+	if order != orderAsc && order != orderDesc {
+		return "", fmt.Errorf("invalid --order %q: use asc or desc", order)
+	}
+	if stream, _ := cmd.Flags().GetBool("stream"); stream {
+		return "", fmt.Errorf("--order cannot be combined with --stream")
+	}
+	return order, nil
+}
+
+// sortEvents orders events by start time
+func sortEvents(events []api.Event, order string) {
+	if order == "" {
+		return
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if order == orderDesc {
+			return events[i].StartUtc.After(events[j].StartUtc)
+		}
+		return events[i].StartUtc.Before(events[j].StartUtc)
+	})
+}
+
+// sortEmails orders emails by the time they were received
+func sortEmails(emails []api.Email, order string) {
+	if order == "" {
+		return
+	}
+	sort.SliceStable(emails, func(i, j int) bool {
+		if order == orderDesc {
+			return emails[i].ReceivedAt.After(emails[j].ReceivedAt)
+		}
+		return emails[i].ReceivedAt.Before(emails[j].ReceivedAt)
+	})
+}
@@ -0,0 +1,289 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/queue"
+	"github.com/spf13/cobra"
+)
+
+// outboxQueue names the queue of emails that failed to send
+const outboxQueue = "outbox"
+
+// Operations of queued emails
+const (
+	outboxSend    = "send"
+	outboxReply   = "reply"
+	outboxForward = "forward"
+)
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Emails saved after a failed send",
+	Long: `When sending, replying or forwarding fails because of a network problem or
+a server error, the email is saved to a local outbox instead of being lost.
+Send it later with 'porteden email outbox flush'.
+
+A server error can occur after an email went out, so check your Sent folder
+before flushing if the failure was a server error rather than a lost connection.
+
+Examples:
+  porteden email outbox list
+  porteden email outbox flush
+  porteden email outbox flush 3
+  porteden email outbox discard 3 --yes`,
+}
+
+var outboxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List emails waiting to be sent",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := queue.Load(outboxQueue, getProfile(cmd))
+		if err != nil {
+			return err
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(q.Entries, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		if len(q.Entries) == 0 {
+			fmt.Println("Outbox is empty.")
+			return nil
+		}
+		printQueue(q.Entries)
+		return nil
+	},
+}
+
+var outboxFlushCmd = &cobra.Command{
+	Use:   "flush [id...]",
+	Short: "Send queued emails",
+	Long: `Send queued emails, oldest first, or only the given ones.
+
+Sent emails are removed from the outbox. Flushing stops at the first network or
+server error, since the rest would fail the same way. An email the API rejects
+stays in the outbox with the reason, so it can be discarded.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := queue.Load(outboxQueue, getProfile(cmd))
+		if err != nil {
+			return err
+		}
+		entries, err := selectQueued(q, args, "porteden email outbox list")
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("Outbox is empty.")
+			return nil
+		}
+		cmd.SilenceUsage = true
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		sent, failed := 0, 0
+		for _, e := range entries {
+			emailID, err := sendQueued(cmd, client, e)
+			if err == nil {
+				q.Remove(e.ID)
+				sent++
+				output.PrintSuccess(fmt.Sprintf("Sent %s: %s (ID: %s)", e.ID, e.Summary, emailID))
+			} else {
+				failed++
+				entry, _ := q.Find(e.ID)
+				entry.Attempts++
+				entry.LastError = err.Error()
+				fmt.Fprintf(os.Stderr, "  %s %s: %s: %v\n", output.ColorRed(output.Symbols("✗")), e.ID, e.Summary, err)
+			}
+			// Saved after every email so an interrupted flush never sends one twice
+			if saveErr := q.Save(); saveErr != nil {
+				return fmt.Errorf("failed to update the outbox: %w", saveErr)
+			}
+			if err != nil && api.IsTransient(err) {
+				break
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("sent %d email(s); %d could not be sent and remain in the outbox", sent, len(entries)-sent)
+		}
+		fmt.Printf("Sent %d email(s)\n", sent)
+		return nil
+	},
+}
+
+var outboxDiscardCmd = &cobra.Command{
+	Use:   "discard [id...]",
+	Short: "Delete queued emails without sending them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if len(args) == 0 && !all {
+			return fmt.Errorf("give the IDs of the emails to discard, or --all")
+		}
+
+		q, err := queue.Load(outboxQueue, getProfile(cmd))
+		if err != nil {
+			return err
+		}
+		entries, err := selectQueued(q, args, "porteden email outbox list")
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("Outbox is empty.")
+			return nil
+		}
+
+		if !yes && auth.IsInteractiveTerminal() {
+			printQueue(entries)
+			fmt.Println()
+			if !confirm(i18n.T("Discard %d queued email(s)?", len(entries))) {
+				fmt.Println(i18n.T("Cancelled. No changes made."))
+				return nil
+			}
+		}
+		for _, e := range entries {
+			q.Remove(e.ID)
+		}
+		if err := q.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Discarded %d email(s)\n", len(entries))
+		return nil
+	},
+}
+
+// queueFailedEmail saves an email to the outbox when err is a network or
+// server error, and otherwise just formats err
+func queueFailedEmail(cmd *cobra.Command, op, target, summary string, req interface{}, err error) error {
+	if !api.IsTransient(err) {
+		return formatError(err)
+	}
+	entry, qerr := enqueue(cmd, outboxQueue, op, target, summary, req)
+	if qerr != nil {
+		return fmt.Errorf("%w (and it could not be saved to the outbox: %v)", formatError(err), qerr)
+	}
+	cmd.SilenceUsage = true
+	return fmt.Errorf("%w\nThe email was saved to the outbox as %s. Send it later with 'porteden email outbox flush'", formatError(err), entry.ID)
+}
+
+// enqueue adds a request to the named queue of the current profile
+func enqueue(cmd *cobra.Command, name, op, target, summary string, req interface{}) (*queue.Entry, error) {
+	q, err := queue.Load(name, getProfile(cmd))
+	if err != nil {
+		return nil, err
+	}
+	entry, err := q.Add(op, target, summary, req)
+	if err != nil {
+		return nil, err
+	}
+	return entry, q.Save()
+}
+
+// sendQueued sends a queued email and returns the sent email's ID
+func sendQueued(cmd *cobra.Command, client *api.Client, e queue.Entry) (string, error) {
+	var resp *api.EmailActionResponse
+	var err error
+	var used []api.Participant
+	switch e.Op {
+	case outboxSend:
+		var req api.SendEmailRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return "", fmt.Errorf("unreadable queued email: %w", err)
+		}
+		resp, err = client.SendEmail(req)
+		used = append(append(append(used, req.To...), req.CC...), req.BCC...)
+	case outboxReply:
+		var req api.ReplyEmailRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return "", fmt.Errorf("unreadable queued email: %w", err)
+		}
+		resp, err = client.ReplyToEmail(e.Target, req)
+	case outboxForward:
+		var req api.ForwardEmailRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return "", fmt.Errorf("unreadable queued email: %w", err)
+		}
+		resp, err = client.ForwardEmail(e.Target, req)
+		used = append(append(append(used, req.To...), req.CC...), req.BCC...)
+	default:
+		return "", fmt.Errorf("unknown queued operation %q", e.Op)
+	}
+	if err != nil {
+		return "", formatError(err)
+	}
+	if !resp.Success {
+		return "", fmt.Errorf("failed to send: %s", resp.ErrorMessage)
+	}
+	rememberRecipients(cmd, participantAddresses(used))
+	return resp.EmailID, nil
+}
+
+// outboxSummary describes a queued email for listings
+func outboxSummary(op, target, subject string, to []api.Participant) string {
+	var emails []string
+	for _, p := range to {
+		emails = append(emails, p.Email)
+	}
+	switch op {
+	case outboxReply:
+		return "Reply to " + target
+	case outboxForward:
+		return "Forward " + target + " to " + strings.Join(emails, ", ")
+	}
+	return fmt.Sprintf("%q to %s", subject, strings.Join(emails, ", "))
+}
+
+// selectQueued returns the entries with the given IDs, or all of them.
+// listCmd is the command that lists the queue, for the not found error.
+func selectQueued(q *queue.Queue, ids []string, listCmd string) ([]queue.Entry, error) {
+	if len(ids) == 0 {
+		return append([]queue.Entry(nil), q.Entries...), nil
+	}
+	var out []queue.Entry
+	for _, id := range ids {
+		e, ok := q.Find(id)
+		if !ok {
+			return nil, fmt.Errorf("nothing queued with ID %s (see '%s')", id, listCmd)
+		}
+		out = append(out, *e)
+	}
+	return out, nil
+}
+
+func printQueue(entries []queue.Entry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	output.TableHeader(w, "ID", "QUEUED", "SUMMARY", "ATTEMPTS", "LAST ERROR")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
+			e.ID,
+			output.FormatLocalTime(e.QueuedAt),
+			truncateRunes(e.Summary, 50),
+			e.Attempts,
+			truncateRunes(oneLineText(e.LastError), 60),
+		)
+	}
+	w.Flush()
+}
+
+func init() {
+	outboxDiscardCmd.Flags().Bool("all", false, "Discard every queued email")
+	outboxDiscardCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+
+	outboxCmd.AddCommand(outboxListCmd)
+	outboxCmd.AddCommand(outboxFlushCmd)
+	outboxCmd.AddCommand(outboxDiscardCmd)
+	emailCmd.AddCommand(outboxCmd)
+}
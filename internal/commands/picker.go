@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/picker"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// canPick reports whether a missing ID can be chosen interactively
+func canPick() bool {
+	return auth.IsInteractiveTerminal() && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// pickableArgs expects n arguments, the first being an ID, but allows the ID
+// to be left out on a terminal so it can be picked instead
+func pickableArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == n-1 && canPick() {
+			return nil
+		}
+		return cobra.ExactArgs(n)(cmd, args)
+	}
+}
+
+// eventIDArg resolves the event ID argument, or offers a picker when it was
+// left out
+func eventIDArg(cmd *cobra.Command, client *api.Client, args []string) (string, error) {
+	if len(args) > 0 {
+		return resolveEventID(cmd, client, args[0])
+	}
+	items, err := eventChoices(client)
+	if err != nil {
+		return "", err
+	}
+	return pick(cmd, "Event>", items)
+}
+
+// emailIDArg resolves the email ID argument, or offers a picker of recent
+// email when it was left out
+func emailIDArg(cmd *cobra.Command, client *api.Client, args []string) (string, error) {
+	if len(args) > 0 {
+		return resolveEmailID(cmd, args[0])
+	}
+	items, err := emailChoices(client)
+	if err != nil {
+		return "", err
+	}
+	return pick(cmd, "Email>", items)
+}
+
+// eventChoices lists events from yesterday through the next two weeks
+func eventChoices(client *api.Client) ([]picker.Item, error) {
+	now := time.Now()
+	resp, err := client.GetEvents(api.EventParams{
+		From:  now.Add(-24 * time.Hour),
+		To:    now.Add(14 * 24 * time.Hour),
+		Limit: 100,
+	})
+	if err != nil {
+		return nil, formatError(err)
+	}
+	if len(resp.Events) == 0 {
+		return nil, fmt.Errorf("no events between yesterday and the next two weeks to choose from")
+	}
+	return eventPickerItems(resp.Events), nil
+}
+
+// emailChoices lists the most recent email
+func emailChoices(client *api.Client) ([]picker.Item, error) {
+	resp, err := client.GetEmails(api.EmailParams{Limit: 50})
+	if err != nil {
+		return nil, formatError(err)
+	}
+	if len(resp.Emails) == 0 {
+		return nil, fmt.Errorf("no recent email to choose from")
+	}
+	return emailPickerItems(resp.Emails), nil
+}
+
+func pick(cmd *cobra.Command, prompt string, items []picker.Item) (string, error) {
+	// Usage text is no help once the user has seen the choices
+	cmd.SilenceUsage = true
+	choice, err := picker.Pick(prompt, items)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(os.Stderr, output.ColorGray(choice.Label))
+	return choice.ID, nil
+}
+
+// pickMany offers a multi-select picker and returns the chosen items
+func pickMany(cmd *cobra.Command, prompt string, items []picker.Item) ([]picker.Item, error) {
+	cmd.SilenceUsage = true
+	return picker.PickMany(prompt, items)
+}
+
+// pickEmails offers a multi-select picker of recent email
+func pickEmails(cmd *cobra.Command, client *api.Client, prompt string) ([]picker.Item, error) {
+	items, err := emailChoices(client)
+	if err != nil {
+		return nil, err
+	}
+	return pickMany(cmd, prompt, items)
+}
+
+func eventPickerItems(events []api.Event) []picker.Item {
+	items := make([]picker.Item, len(events))
+	for i, e := range events {
+		start := e.StartUtc.In(output.GetOutputLocation())
+		when := start.Format("Mon Jan 2 15:04")
+		if e.AllDay || e.IsAllDay {
+			when = start.Format("Mon Jan 2") + " all day"
+		}
+		title := e.Title
+		if title == "" {
+			title = e.Summary
+		}
+		items[i] = picker.Item{ID: e.ID, Label: fmt.Sprintf("%-18s %s", when, title)}
+	}
+	return items
+}
+
+func emailPickerItems(emails []api.Email) []picker.Item {
+	items := make([]picker.Item, len(emails))
+	for i, e := range emails {
+		from := ""
+		if e.From != nil {
+			from = e.From.Name
+			if from == "" {
+				from = e.From.Email
+			}
+		}
+		when := e.ReceivedAt.In(output.GetOutputLocation()).Format("Jan 2")
+		items[i] = picker.Item{ID: e.ID, Label: fmt.Sprintf("%-6s %-20.20s %s", when, from, e.Subject)}
+	}
+	return items
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// pingJSON is the JSON output of 'ping'
+type pingJSON struct {
+	BaseURL      string                  `json:"baseUrl"`
+	RemoteAddr   string                  `json:"remoteAddr,omitempty"`
+	Status       int                     `json:"status,omitempty"`
+	LatencyMs    int64                   `json:"latencyMs"`
+	DNSMs        int64                   `json:"dnsMs"`
+	ConnectMs    int64                   `json:"connectMs"`
+	TLSMs        int64                   `json:"tlsMs"`
+	FirstByteMs  int64                   `json:"firstByteMs"`
+	TLS          *api.TLSInfo            `json:"tls,omitempty"`
+	APIVersion   *api.VersionInfo        `json:"apiVersion,omitempty"`
+	Auth         *api.AuthStatusResponse `json:"auth,omitempty"`
+	Result       string                  `json:"result"` // ok, network_error, unauthenticated, api_error
+	NetworkError string                  `json:"networkError,omitempty"`
+}
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check the connection to the PortEden API",
+	Long: `Make one request to the PortEden API and report where it went, how long
+each step took, the TLS connection, and whether the API key was accepted.
+
+A network problem (DNS, firewall, proxy, offline) fails before the API
+answers; an authentication problem gets an answer that rejects the key.
+Works without being signed in.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var client *api.Client
+		if isDemo(cmd) {
+			client = newDemoClient()
+		} else if oauthClient, ok := newOAuthClient(getProfile(cmd)); ok {
+			client = oauthClient
+		} else {
+			// A missing key still shows whether the API can be reached
+			apiKey, _ := auth.GetAPIKey(getProfile(cmd))
+			client = newAPIClient(apiKey)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		r := client.Ping(ctx)
+
+		out := pingJSON{
+			BaseURL:      r.BaseURL,
+			RemoteAddr:   r.RemoteAddr,
+			Status:       r.Status,
+			LatencyMs:    r.Latency.Milliseconds(),
+			DNSMs:        r.DNS.Milliseconds(),
+			ConnectMs:    r.Connect.Milliseconds(),
+			TLSMs:        r.TLS.Milliseconds(),
+			FirstByteMs:  r.FirstByte.Milliseconds(),
+			TLS:          r.TLSInfo,
+			APIVersion:   r.Version,
+			Auth:         r.Auth,
+			Result:       "ok",
+			NetworkError: r.NetworkError,
+		}
+		var err error
+		switch {
+		case r.NetworkError != "":
+			out.Result = "network_error"
+			err = fmt.Errorf("network problem: could not reach %s: %s", r.BaseURL, r.NetworkError)
+		case r.APIError != nil && r.Status == 401:
+			out.Result = "unauthenticated"
+			err = &userError{
+				message: withRequestID("the API is reachable, but the API key was rejected. Run 'porteden auth login' to authenticate", r.APIError),
+				apiErr:  r.APIError,
+			}
+		case r.APIError != nil:
+			out.Result = "api_error"
+			err = &userError{
+				message: fmt.Sprintf("the API is reachable, but answered HTTP %d: %s", r.Status, formatError(r.APIError)),
+				apiErr:  r.APIError,
+			}
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.Print(out, output.FormatJSON)
+		} else {
+			printPing(r)
+		}
+		if err != nil {
+			cmd.SilenceUsage = true
+		}
+		return err
+	},
+}
+
+// printPing prints the steps of a ping, one per line
+func printPing(r *api.PingResult) {
+	fmt.Printf("URL:      %s\n", r.BaseURL)
+	if r.RemoteAddr != "" {
+		fmt.Printf("Address:  %s\n", r.RemoteAddr)
+	}
+	if r.NetworkError != "" {
+		fmt.Printf("Network:  %s\n", output.ColorRed("failed after "+roundMs(r.Latency)))
+		return
+	}
+
+	var steps []string
+	for _, s := range []struct {
+		name string
+		d    time.Duration
+	}{{"DNS", r.DNS}, {"connect", r.Connect}, {"TLS", r.TLS}, {"first byte", r.FirstByte}} {
+		if s.d > 0 {
+			steps = append(steps, s.name+" "+roundMs(s.d))
+		}
+	}
+	latency := roundMs(r.Latency)
+	if len(steps) > 0 {
+		latency += " (" + strings.Join(steps, ", ") + ")"
+	}
+	fmt.Printf("Latency:  %s\n", latency)
+
+	if t := r.TLSInfo; t != nil {
+		info := t.Version + ", " + t.CipherSuite
+		if t.Issuer != "" {
+			info += fmt.Sprintf(", certificate for %s from %s, valid until %s", t.ServerName, t.Issuer, t.NotAfter.Format("2006-01-02"))
+		}
+		fmt.Printf("TLS:      %s\n", info)
+	}
+
+	if v := r.Version; v != nil {
+		negotiated := v.Negotiated
+		if negotiated == "" {
+			negotiated = v.Requested
+		}
+		info := negotiated
+		if negotiated != v.Requested {
+			info += " (asked for " + v.Requested + ")"
+		}
+		if v.Deprecated {
+			deprecated := "deprecated"
+			if v.Sunset != nil {
+				deprecated += ", stops working " + v.Sunset.Format("2006-01-02")
+			}
+			info += ", " + output.ColorYellow(deprecated)
+		}
+		if len(v.Supported) > 0 {
+			info += "; supported: " + strings.Join(v.Supported, ", ")
+		}
+		fmt.Printf("API:      version %s\n", info)
+	}
+
+	switch {
+	case r.Auth != nil:
+		fmt.Printf("Auth:     %s\n", output.ColorGreen("OK, "+r.Auth.Email))
+	case r.Status == 401:
+		fmt.Printf("Auth:     %s\n", output.ColorRed("API key rejected (HTTP 401)"))
+	default:
+		fmt.Printf("Status:   %s\n", output.ColorRed(fmt.Sprintf("HTTP %d", r.Status)))
+	}
+}
+
+// roundMs formats a duration in whole milliseconds
+func roundMs(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/porteden/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// applyPreset sets flags from the filters.<name> preset named by --preset.
+// Flags given on the command line take precedence over the preset.
+func applyPreset(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("preset")
+	if name == "" {
+		return nil
+	}
+	// Errors from here on are in the config file, not the invocation
+	cmd.SilenceUsage = true
+
+	v, _ := userConfig.Get("filters." + name)
+	preset, ok := v.(map[string]interface{})
+	if !ok || len(preset) == 0 {
+		return fmt.Errorf("no filter preset named %q (define one with: porteden config set filters.%s.<flag> <value>)", name, name)
+	}
+
+	flags := make([]string, 0, len(preset))
+	for flag := range preset {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	for _, flag := range flags {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || flag == "preset" {
+			return fmt.Errorf("preset %q: %s has no --%s flag", name, cmd.CommandPath(), flag)
+		}
+		if f.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, config.FormatValue(preset[flag])); err != nil {
+			return fmt.Errorf("preset %q: --%s: %w", name, flag, err)
+		}
+	}
+	return nil
+}
+
+// presetNames lists the configured filter presets, for completion
+func presetNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _ := config.Load()
+	seen := make(map[string]bool)
+	var names []string
+	for _, key := range cfg.Keys() {
+		rest, ok := strings.CutPrefix(key, "filters.")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(rest, ".")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
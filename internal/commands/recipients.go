@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/debug"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/recipients"
+	"github.com/spf13/cobra"
+)
+
+// rememberRecipients records addresses the user sent to or invited, for
+// completion and typo checks. Failures only cost the convenience, so they're
+// logged rather than returned.
+func rememberRecipients(cmd *cobra.Command, used []recipients.Address) {
+	if len(used) == 0 {
+		return
+	}
+	profileName := getProfile(cmd)
+	history, err := recipients.Load(profileName)
+	if err != nil {
+		debug.Log("Failed to load recipient history: %v", err)
+	}
+	history.Record(used)
+	if err := history.Save(profileName); err != nil {
+		debug.Log("Failed to save recipient history: %v", err)
+	}
+}
+
+// participantAddresses converts email participants for rememberRecipients
+func participantAddresses(lists ...[]api.Participant) []recipients.Address {
+	var out []recipients.Address
+	for _, list := range lists {
+		for _, p := range list {
+			out = append(out, recipients.Address{Email: p.Email, Name: p.Name})
+		}
+	}
+	return out
+}
+
+// plainAddresses converts attendee emails for rememberRecipients
+func plainAddresses(emails []string) []recipients.Address {
+	out := make([]recipients.Address, len(emails))
+	for i, e := range emails {
+		out[i] = recipients.Address{Email: e}
+	}
+	return out
+}
+
+// participantEmails points at the addresses of email participants, for
+// checkRecipientTypos
+func participantEmails(lists ...[]api.Participant) []*string {
+	var out []*string
+	for _, list := range lists {
+		for i := range list {
+			out = append(out, &list[i].Email)
+		}
+	}
+	return out
+}
+
+// plainEmails points at attendee emails, for checkRecipientTypos
+func plainEmails(emails []string) []*string {
+	out := make([]*string, len(emails))
+	for i := range emails {
+		out[i] = &emails[i]
+	}
+	return out
+}
+
+// checkRecipientTypos looks for addresses never used before that are a likely
+// typo of one that was. In a terminal it offers to use the remembered address
+// instead; otherwise it only warns.
+func checkRecipientTypos(cmd *cobra.Command, emails []*string) {
+	history, err := recipients.Load(getProfile(cmd))
+	if err != nil {
+		debug.Log("Failed to load recipient history: %v", err)
+		return
+	}
+	for _, email := range emails {
+		suggestion, ok := history.Suggest(*email)
+		if !ok {
+			continue
+		}
+		if !auth.IsInteractiveTerminal() {
+			fmt.Fprintf(os.Stderr, "Warning: %s hasn't been used before; did you mean %s?\n", *email, suggestion)
+			continue
+		}
+		if confirm(i18n.T("Did you mean %s instead of %s?", suggestion, *email)) {
+			*email = suggestion
+		}
+	}
+}
+
+// completeRecipients completes remembered addresses for --to, --cc, --bcc and
+// --attendees, after any comma-separated addresses already typed
+func completeRecipients(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	typed, prefix := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		typed, prefix = toComplete[:i+1], toComplete[i+1:]
+	}
+	history, _ := recipients.Load(getProfile(cmd))
+	var out []string
+	for _, a := range history.Complete(prefix) {
+		if a.Name != "" {
+			out = append(out, typed+a.Email+"\t"+a.Name)
+		} else {
+			out = append(out, typed+a.Email)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
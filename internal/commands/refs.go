@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/debug"
+	"github.com/porteden/cli/internal/refs"
+	"github.com/spf13/cobra"
+)
+
+// rememberEvents records listed events so later commands accept short IDs.
+// Failures only cost the convenience, so they're logged rather than returned.
+func rememberEvents(cmd *cobra.Command, events []api.Event) {
+	items := make([]refs.Item, len(events))
+	for i, e := range events {
+		items[i] = refs.Item{ID: e.ID, Title: e.Title}
+	}
+	remember(cmd, refs.KindEvent, items)
+}
+
+// rememberEmails records listed emails so later commands accept short IDs
+func rememberEmails(cmd *cobra.Command, emails []api.Email) {
+	items := make([]refs.Item, len(emails))
+	for i, e := range emails {
+		items[i] = refs.Item{ID: e.ID, Title: e.Subject}
+	}
+	remember(cmd, refs.KindEmail, items)
+}
+
+// rememberListing records the IDs of the rows just listed, in display order,
+// so the next commands in this terminal session can refer to them as %1, %2...
+func rememberListing(cmd *cobra.Command, kind refs.Kind, ids []string) {
+	profileName := getProfile(cmd)
+	listing, err := refs.LoadListing(profileName)
+	if err != nil {
+		debug.Log("Failed to load last listing: %v", err)
+	}
+	listing.SetRows(kind, ids)
+	if err := listing.Save(profileName); err != nil {
+		debug.Log("Failed to save last listing: %v", err)
+	}
+}
+
+func eventIDs(events []api.Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func emailIDs(emails []api.Email) []string {
+	ids := make([]string, len(emails))
+	for i, e := range emails {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func remember(cmd *cobra.Command, kind refs.Kind, items []refs.Item) {
+	if len(items) == 0 {
+		return
+	}
+	profileName := getProfile(cmd)
+	store, err := refs.Load(profileName)
+	if err != nil {
+		debug.Log("Failed to load recent IDs: %v", err)
+	}
+	store.Remember(kind, items)
+	if err := store.Save(profileName); err != nil {
+		debug.Log("Failed to save recent IDs: %v", err)
+	}
+}
+
+// eventRefWindow bounds how far from now next and last look for events
+const eventRefWindow = 7 * 24 * time.Hour
+
+// resolveEventID turns next, current (or now) and last into the matching
+// event around the present time, and otherwise resolves a %N row of the last
+// listing or a unique prefix of a recently listed event ID
+func resolveEventID(cmd *cobra.Command, client *api.Client, ref string) (string, error) {
+	switch strings.ToLower(ref) {
+	case "next", "current", "now", "last":
+		return resolveRelativeEvent(client, strings.ToLower(ref), time.Now())
+	}
+	return resolveRef(cmd, refs.KindEvent, ref)
+}
+
+// resolveRelativeEvent finds the event that starts next, is in progress, or
+// ended most recently. All-day, cancelled and declined events are skipped
+// since they are rarely what "my next meeting" means.
+func resolveRelativeEvent(client *api.Client, which string, now time.Time) (string, error) {
+	params := api.EventParams{From: now.Add(-24 * time.Hour), To: now, Limit: 250}
+	switch which {
+	case "next":
+		params = api.EventParams{From: now, To: now.Add(eventRefWindow), Limit: 100}
+	case "last":
+		params.From = now.Add(-eventRefWindow)
+	}
+
+	resp, err := client.GetEvents(params)
+	if err != nil {
+		return "", formatError(err)
+	}
+
+	var events []api.Event
+	for _, e := range resp.Events {
+		if e.AllDay || e.IsAllDay || e.Status == "cancelled" || declinedBy(e, resp.CurrentUserCalendarEmail) {
+			continue
+		}
+		events = append(events, e)
+	}
+
+	var match *api.Event
+	switch which {
+	case "next":
+		sort.SliceStable(events, func(i, j int) bool { return events[i].StartUtc.Before(events[j].StartUtc) })
+		for i := range events {
+			if events[i].StartUtc.After(now) {
+				match = &events[i]
+				break
+			}
+		}
+	case "last":
+		sort.SliceStable(events, func(i, j int) bool { return events[i].EndUtc.After(events[j].EndUtc) })
+		for i := range events {
+			if !events[i].EndUtc.After(now) {
+				match = &events[i]
+				break
+			}
+		}
+	default:
+		// With overlapping meetings, the one that started most recently wins
+		sort.SliceStable(events, func(i, j int) bool { return events[i].StartUtc.After(events[j].StartUtc) })
+		for i := range events {
+			if !events[i].StartUtc.After(now) && events[i].EndUtc.After(now) {
+				match = &events[i]
+				break
+			}
+		}
+	}
+
+	if match == nil {
+		switch which {
+		case "next":
+			return "", fmt.Errorf("no upcoming event in the next %d days", int(eventRefWindow.Hours()/24))
+		case "last":
+			return "", fmt.Errorf("no event ended in the past %d days", int(eventRefWindow.Hours()/24))
+		default:
+			return "", fmt.Errorf("no event in progress")
+		}
+	}
+	debug.Log("Resolved %q to event %s (%s)", which, match.ID, match.Title)
+	return match.ID, nil
+}
+
+// declinedBy reports whether the given attendee declined the event
+func declinedBy(e api.Event, email string) bool {
+	if email == "" {
+		return false
+	}
+	for _, a := range e.Attendees {
+		if strings.EqualFold(a.Email, email) {
+			return a.Response == "declined" || a.ResponseStatus == "declined"
+		}
+	}
+	return false
+}
+
+// resolveEmailID resolves a %N row of the last listing or expands a unique
+// prefix of a recently listed email ID
+func resolveEmailID(cmd *cobra.Command, ref string) (string, error) {
+	return resolveRef(cmd, refs.KindEmail, ref)
+}
+
+func resolveRef(cmd *cobra.Command, kind refs.Kind, ref string) (string, error) {
+	if refs.IsRowRef(ref) {
+		listing, err := refs.LoadListing(getProfile(cmd))
+		if err != nil {
+			debug.Log("Failed to load last listing: %v", err)
+		}
+		id, err := listing.Row(kind, ref)
+		if err != nil {
+			return "", err
+		}
+		debug.Log("Resolved %s %s to %s", kind, ref, id)
+		return id, nil
+	}
+
+	store, err := refs.Load(getProfile(cmd))
+	if err != nil {
+		debug.Log("Failed to load recent IDs: %v", err)
+	}
+	id, err := store.Resolve(kind, ref)
+	if err != nil {
+		return "", err
+	}
+	if id != ref {
+		debug.Log("Resolved %s %q to %s", kind, ref, id)
+	}
+	return id, nil
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// repeatFrequencies maps --repeat values to RRULE frequencies. "weekdays" is
+// a weekly rule on Monday to Friday.
+var repeatFrequencies = map[string]string{
+	"daily":    "DAILY",
+	"weekdays": "WEEKLY",
+	"weekly":   "WEEKLY",
+	"monthly":  "MONTHLY",
+	"yearly":   "YEARLY",
+}
+
+// weekdayCodes are the RRULE BYDAY codes, in week order
+var weekdayCodes = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// addRepeatFlags registers the flags compiled into an RRULE by repeatRule
+func addRepeatFlags(cmd *cobra.Command) {
+	cmd.Flags().String("repeat", "", "Repeat the event: daily, weekdays, weekly, monthly or yearly")
+	cmd.Flags().Int("every", 1, "With --repeat, the interval, e.g. --repeat weekly --every 2 for every other week")
+	cmd.Flags().StringSlice("on", nil, "With --repeat weekly, the days to repeat on, e.g. mon,wed")
+	cmd.Flags().String("until", "", "With --repeat, the last date to repeat on (inclusive)")
+	cmd.Flags().Int("count", 0, "With --repeat, the number of occurrences")
+	_ = cmd.RegisterFlagCompletionFunc("repeat", cobra.FixedCompletions([]string{"daily", "weekdays", "weekly", "monthly", "yearly"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// repeatRule compiles --repeat, --every, --on, --until and --count into an
+// RRULE line, and reports whether --repeat was given. A date alone in
+// --until means the end of that day in loc, the event's timezone.
+func repeatRule(cmd *cobra.Command, loc *time.Location) (string, bool, error) {
+	flags := cmd.Flags()
+	if !flags.Changed("repeat") {
+		for _, name := range []string{"every", "on", "until", "count"} {
+			if flags.Changed(name) {
+				return "", false, fmt.Errorf("--%s needs --repeat", name)
+			}
+		}
+		return "", false, nil
+	}
+	if flags.Changed("recurrence") {
+		return "", false, fmt.Errorf("--repeat cannot be combined with --recurrence")
+	}
+
+	repeat, _ := flags.GetString("repeat")
+	repeat = strings.ToLower(repeat)
+	freq, ok := repeatFrequencies[repeat]
+	if !ok {
+		return "", false, fmt.Errorf("invalid --repeat %q: use daily, weekdays, weekly, monthly or yearly", repeat)
+	}
+	rule := []string{"FREQ=" + freq}
+
+	every, _ := flags.GetInt("every")
+	if every < 1 {
+		return "", false, fmt.Errorf("--every must be at least 1")
+	}
+	if every > 1 {
+		rule = append(rule, "INTERVAL="+strconv.Itoa(every))
+	}
+
+	on, _ := flags.GetStringSlice("on")
+	switch {
+	case repeat == "weekdays" && len(on) > 0:
+		return "", false, fmt.Errorf("--on cannot be combined with --repeat weekdays")
+	case repeat == "weekdays":
+		rule = append(rule, "BYDAY="+strings.Join(weekdayCodes[:5], ","))
+	case len(on) > 0 && repeat != "weekly":
+		return "", false, fmt.Errorf("--on needs --repeat weekly")
+	case len(on) > 0:
+		days, err := parseWeekdays(on)
+		if err != nil {
+			return "", false, err
+		}
+		rule = append(rule, "BYDAY="+strings.Join(days, ","))
+	}
+
+	count, _ := flags.GetInt("count")
+	if flags.Changed("count") && count < 1 {
+		return "", false, fmt.Errorf("--count must be at least 1")
+	}
+	if flags.Changed("until") {
+		if count > 0 {
+			return "", false, fmt.Errorf("--until cannot be combined with --count")
+		}
+		value, _ := flags.GetString("until")
+		until, err := repeatUntil(value, allDayFlag(cmd), loc)
+		if err != nil {
+			return "", false, err
+		}
+		rule = append(rule, "UNTIL="+until)
+	} else if count > 0 {
+		rule = append(rule, "COUNT="+strconv.Itoa(count))
+	}
+
+	return "RRULE:" + strings.Join(rule, ";"), true, nil
+}
+
+// parseWeekdays turns day names (mon, Monday, mo) into BYDAY codes in week
+// order, without duplicates
+func parseWeekdays(names []string) ([]string, error) {
+	chosen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		code := ""
+		if len(name) >= 2 {
+			for i, c := range weekdayCodes {
+				full := strings.ToLower(time.Weekday((i + 1) % 7).String())
+				if strings.HasPrefix(full, name) {
+					code = c
+					break
+				}
+			}
+		}
+		if code == "" {
+			return nil, fmt.Errorf("invalid day %q in --on: use mon, tue, wed, thu, fri, sat or sun", name)
+		}
+		chosen[code] = true
+	}
+	var days []string
+	for _, c := range weekdayCodes {
+		if chosen[c] {
+			days = append(days, c)
+		}
+	}
+	return days, nil
+}
+
+// repeatUntil formats the --until limit for an RRULE. A date alone covers
+// the whole of that day in loc. All-day events take a date; others a UTC time.
+func repeatUntil(value string, allDay bool, loc *time.Location) (string, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		if allDay {
+			return date.Format("20060102"), nil
+		}
+		end := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, loc)
+		return end.UTC().Format("20060102T150405Z"), nil
+	}
+	t, err := parseDateTime(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid --until: %w", err)
+	}
+	if allDay {
+		return t.In(loc).Format("20060102"), nil
+	}
+	return t.UTC().Format("20060102T150405Z"), nil
+}
+
+// eventLocation returns the timezone of an event starting at start: the
+// output timezone when start has its offset, so its daylight saving rules
+// apply, or else start's own fixed offset
+func eventLocation(start time.Time) *time.Location {
+	loc := output.GetOutputLocation()
+	_, offset := start.Zone()
+	if _, local := start.In(loc).Zone(); local == offset {
+		return loc
+	}
+	return time.FixedZone("", offset)
+}
+
+// allDayFlag reports whether --all-day is set, on commands that have it
+func allDayFlag(cmd *cobra.Command) bool {
+	allDay, _ := cmd.Flags().GetBool("all-day")
+	return allDay
+}
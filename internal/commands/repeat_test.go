@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRepeatRule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no timezone data")
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--repeat", "daily"}, "RRULE:FREQ=DAILY"},
+		{[]string{"--repeat", "Weekly", "--every", "2"}, "RRULE:FREQ=WEEKLY;INTERVAL=2"},
+		{[]string{"--repeat", "weekdays"}, "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{[]string{"--repeat", "weekly", "--on", "fri,Monday,we,mon"}, "RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR"},
+		{[]string{"--repeat", "monthly", "--count", "6"}, "RRULE:FREQ=MONTHLY;COUNT=6"},
+		{[]string{"--repeat", "yearly", "--every", "1"}, "RRULE:FREQ=YEARLY"},
+		// The end of the day in Berlin, in winter and in summer time
+		{[]string{"--repeat", "daily", "--until", "2026-03-20"}, "RRULE:FREQ=DAILY;UNTIL=20260320T225959Z"},
+		{[]string{"--repeat", "daily", "--until", "2026-06-30"}, "RRULE:FREQ=DAILY;UNTIL=20260630T215959Z"},
+		{[]string{"--repeat", "weekly", "--every", "3", "--until", "2026-06-30T08:00:00-04:00"}, "RRULE:FREQ=WEEKLY;INTERVAL=3;UNTIL=20260630T120000Z"},
+		{[]string{"--repeat", "daily", "--all-day", "--until", "2026-03-20"}, "RRULE:FREQ=DAILY;UNTIL=20260320"},
+		{[]string{"--repeat", "weekly", "--all-day", "--until", "2026-03-20T23:30:00Z"}, "RRULE:FREQ=WEEKLY;UNTIL=20260321"},
+		{[]string{"--repeat", "weekdays", "--all-day", "--count", "10"}, "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;COUNT=10"},
+	}
+	for _, tt := range tests {
+		rule, ok, err := repeatRule(repeatTestCommand(t, tt.args), berlin)
+		if err != nil || !ok || rule != tt.want {
+			t.Errorf("%v: got %q, %v, %v; want %q", tt.args, rule, ok, err, tt.want)
+		}
+	}
+
+	if rule, ok, err := repeatRule(repeatTestCommand(t, nil), berlin); rule != "" || ok || err != nil {
+		t.Errorf("no flags: got %q, %v, %v", rule, ok, err)
+	}
+
+	for _, bad := range [][]string{
+		{"--every", "2"},
+		{"--count", "3"},
+		{"--repeat", "hourly"},
+		{"--repeat", "daily", "--every", "0"},
+		{"--repeat", "daily", "--on", "mon"},
+		{"--repeat", "weekdays", "--on", "mon"},
+		{"--repeat", "weekly", "--on", "m"},
+		{"--repeat", "daily", "--count", "0"},
+		{"--repeat", "daily", "--count", "3", "--until", "2026-03-20"},
+		{"--repeat", "daily", "--until", "next week"},
+		{"--repeat", "daily", "--recurrence", "RRULE:FREQ=DAILY"},
+	} {
+		if rule, _, err := repeatRule(repeatTestCommand(t, bad), berlin); err == nil {
+			t.Errorf("%v: got %q, want an error", bad, rule)
+		}
+	}
+}
+
+func TestEventLocation(t *testing.T) {
+	t.Setenv("PE_TIMEZONE", "America/New_York")
+
+	// The output timezone's offset: its rules apply, so --until after the
+	// clocks change ends the day at local midnight
+	start, _ := time.Parse(time.RFC3339, "2026-03-02T09:00:00-05:00")
+	until, err := repeatUntil("2026-03-20", false, eventLocation(start))
+	if err != nil || until != "20260321T035959Z" {
+		t.Errorf("until in New York = %q, %v; want 20260321T035959Z", until, err)
+	}
+
+	// Any other offset is kept as it is
+	start, _ = time.Parse(time.RFC3339, "2026-03-02T09:00:00+05:30")
+	if _, offset := time.Now().In(eventLocation(start)).Zone(); offset != 5*3600+1800 {
+		t.Errorf("eventLocation(+05:30) has offset %d", offset)
+	}
+}
+
+// repeatTestCommand returns a command with the flags repeatRule reads, parsed
+// from args
+func repeatTestCommand(t *testing.T, args []string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "create"}
+	addRepeatFlags(cmd)
+	cmd.Flags().StringSlice("recurrence", nil, "")
+	cmd.Flags().Bool("all-day", false, "")
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("parsing %v: %v", args, err)
+	}
+	return cmd
+}
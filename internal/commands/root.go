@@ -3,25 +3,42 @@ package commands
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/porteden/cli/internal/api"
 	"github.com/porteden/cli/internal/auth"
 	"github.com/porteden/cli/internal/config"
 	"github.com/porteden/cli/internal/debug"
 	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/picker"
+	"github.com/porteden/cli/internal/progress"
+	"github.com/porteden/cli/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputFormat  string
-	profile       string
-	colorMode     string
-	compactOutput bool
+	outputFormat   string
+	profile        string
+	colorMode      string
+	asciiMode      bool
+	truncateMode   string
+	noTruncate     bool
+	maxWidth       int
+	compactOutput  bool
+	fields         []string
+	maxItems       int
+	maxOutputBytes int
+	maxWait        time.Duration
+	uaSuffix       string
+	quietMode      bool
 )
 
 var rootCmd = &cobra.Command{
 	Use:     "porteden",
 	Short:   "PortEden CLI - Calendar, email, and Google Drive from your terminal",
 	Version: config.Version,
+	// Errors are printed by Execute so they can be rendered as JSON when requested
+	SilenceErrors: true,
 	Long: `PortEden CLI provides command-line access to calendars, email, and Google Drive.
 
 Authentication:
@@ -29,21 +46,40 @@ Authentication:
   porteden auth login --token <key>      Authenticate with API key (non-interactive)
   porteden auth use <profile>            Switch active profile
   porteden auth status                   Check authentication status
+  porteden --demo <command>              Try any command with sample data, no account needed
 
 Calendar:
   porteden calendar events       List/search events
+  porteden calendar count        Count matching events
   porteden calendar create       Create an event
+  porteden calendar create-batch Create events from a CSV file
   porteden calendar update       Update an event
   porteden calendar delete       Delete an event
   porteden calendar respond      Respond to invitation
+  porteden calendar join         Open meeting link (--qr for a QR code)
   porteden calendar freebusy     Check free/busy times
+  porteden calendar share        Share a calendar (--with, --role)
+  porteden calendar shares       List who a calendar is shared with
+  porteden sync flush            Apply calendar changes queued with --queue
 
 Email:
   porteden email messages        List/search emails
+  porteden email count           Count matching emails
   porteden email send            Send a new email
+  porteden email merge           Send personalized email from a CSV file
   porteden email reply           Reply to an email
   porteden email forward         Forward an email
+  porteden email outbox          Send or discard emails saved after a failed send
   porteden email delete          Delete an email
+  porteden email archive         Archive an email
+  porteden email attachments     Download attachments
+  porteden email to-event        Create an event from an email
+  porteden email triage          Interactive inbox triage
+
+Saved searches:
+  porteden saved add             Save an event or email query by name
+  porteden saved list            List saved searches
+  porteden saved run             Run a saved search
 
 Drive:
   porteden drive files           List/search files
@@ -63,10 +99,49 @@ Sheets (Google Sheets):
   porteden sheets append         Append rows
   porteden sheets create         Create a new Google Sheet
 
+Digest:
+  porteden digest                Today's agenda plus unread email highlights
+  porteden notify                Desktop notifications for upcoming events
+  porteden ooo                   Block out time away and turn on the auto-reply
+
+Search:
+  porteden search                Search events and email together
+  porteden show <id>             Show an event, email, thread or calendar by ID
+
+Offline search:
+  porteden index build           Index recent emails and events locally
+  porteden index update          Add new items to the index
+  porteden index search          Search the index without calling the API
+  porteden cache status          Show local cache disk usage
+  porteden cache purge           Delete cached data
+  porteden cache encrypt         Encrypt local caches at rest
+
+Backup:
+  porteden export                Export calendar and email data to a directory
+  porteden import                Restore events from an export
+
 System:
+  porteden config                Get/set persistent settings
+  porteden ping                  Check the connection, TLS and API key
+  porteden status                Show service status and ongoing incidents
   porteden update                Update to the latest version
-  porteden uninstall             Uninstall the CLI`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+  porteden uninstall             Uninstall the CLI
+
+Exit codes:
+  0  success                     5  not found
+  1  general error               6  rate limited
+  3  not authenticated           7  validation error
+  4  access denied               8  server error
+                                 9  conflict`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		loadUserConfig()
+		applyUserConfig(cmd)
+
+		// Usage text would corrupt machine-readable error output
+		if getOutputFormat(cmd) == output.FormatJSON {
+			cmd.SilenceUsage = true
+		}
+
 		// Apply color settings
 		switch colorMode {
 		case "never":
@@ -75,10 +150,36 @@ System:
 			output.SetColorEnabled(true)
 			// "auto" uses the detection from init()
 		}
+		output.SetASCII(asciiMode)
+		picker.ASCII = asciiMode
+
+		if noTruncate {
+			truncateMode = string(output.TruncateNone)
+		}
+		if err := output.SetTruncation(output.TruncateMode(truncateMode), maxWidth); err != nil {
+			return err
+		}
+		output.SetFields(fields)
+		if !cmd.Flags().Changed("ua-suffix") {
+			uaSuffix = os.Getenv("PE_UA_SUFFIX")
+		}
+		if !api.ValidUserAgentSuffix(uaSuffix) {
+			return fmt.Errorf("invalid --ua-suffix %q: use at most 128 printable ASCII characters", uaSuffix)
+		}
+		if err := output.SetOutputLimits(maxItems, maxOutputBytes); err != nil {
+			return err
+		}
+
+		if isDemo(cmd) {
+			if progress.Enabled() {
+				fmt.Fprintln(os.Stderr, output.ColorGray("Demo mode: showing sample data, nothing is sent or saved"))
+			}
+			return nil
+		}
 
 		// Skip credential store initialization if PE_API_KEY is set (it takes precedence)
 		if os.Getenv("PE_API_KEY") != "" {
-			return
+			return nil
 		}
 
 		// Initialize credential store
@@ -89,21 +190,33 @@ System:
 				os.Exit(1)
 			}
 		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.SetVersionTemplate("porteden " + config.FullVersion() + "\n")
 
-	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "", "Output format: json, table, plain")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "", "Output format: json, table, plain, csv")
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Profile name (default: 'default')")
 	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Color mode: auto, always, never")
+	rootCmd.PersistentFlags().BoolVar(&asciiMode, "ascii", false, "Use plain ASCII instead of box drawing characters and symbols")
+	rootCmd.PersistentFlags().IntVar(&maxWidth, "max-width", 0, "Maximum width of shortened table columns (default: per column)")
+	rootCmd.PersistentFlags().StringVar(&truncateMode, "truncate", "end", "How to shorten long table values: end, middle, none")
+	rootCmd.PersistentFlags().BoolVar(&noTruncate, "no-truncate", false, "Show table values in full (same as --truncate none)")
 	// Bind verbose flag directly to debug.Verbose - single source of truth
 	rootCmd.PersistentFlags().BoolVarP(&debug.Verbose, "verbose", "v", false, "Verbose output for debugging")
 
 	rootCmd.PersistentFlags().BoolP("json", "j", false, "Output as JSON")
 	rootCmd.PersistentFlags().BoolP("plain", "p", false, "Output as plain text (TSV)")
 	rootCmd.PersistentFlags().BoolVarP(&compactOutput, "compact", "c", false, "Compact output for AI agents (filters noise, truncates fields)")
+	rootCmd.PersistentFlags().StringSliceVar(&fields, "fields", nil, "Only output these JSON fields, e.g. id,title,attendees.email (implies --json)")
+	rootCmd.PersistentFlags().IntVar(&maxItems, "max-items", 0, "Cut JSON output to at most N results, adding a truncation notice")
+	rootCmd.PersistentFlags().IntVar(&maxOutputBytes, "max-output-bytes", 0, "Cut JSON output to at most N bytes, adding a truncation notice")
+	rootCmd.PersistentFlags().BoolVar(&quietMode, "quiet", false, "Don't print warnings about expiring keys or deprecated API versions")
+	rootCmd.PersistentFlags().BoolVar(&demoMode, "demo", false, "Use built-in sample data instead of a PortEden account")
+	rootCmd.PersistentFlags().StringVar(&uaSuffix, "ua-suffix", "", "Text appended to the User-Agent, e.g. the name of an automation (or PE_UA_SUFFIX)")
+	rootCmd.PersistentFlags().DurationVar(&maxWait, "max-wait", 0, "Maximum total time to wait on rate limits before failing (e.g. 30s, 2m)")
 
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(calendarCmd)
@@ -111,19 +224,40 @@ func init() {
 	rootCmd.AddCommand(driveCmd)
 	rootCmd.AddCommand(docsCmd)
 	rootCmd.AddCommand(sheetsCmd)
+	rootCmd.AddCommand(digestCmd)
+	rootCmd.AddCommand(notifyCmd)
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(savedCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(oooCmd)
+	rootCmd.AddCommand(pingCmd)
+	rootCmd.AddCommand(serviceStatusCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(selfUpdateCmd)
 	rootCmd.AddCommand(uninstallCmd)
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	cmd, err := rootCmd.ExecuteC()
+	if !quietMode {
+		version.WarnDeprecatedAPI()
+	}
+	if err != nil {
+		printCommandError(cmd, err)
+		os.Exit(exitCode(err))
 	}
 }
 
 // Helper function to get the active profile
 func getProfile(cmd *cobra.Command) string {
+	if demoMode {
+		return demoProfile
+	}
 	if profile != "" {
 		return profile
 	}
@@ -133,6 +267,28 @@ func getProfile(cmd *cobra.Command) string {
 	return "default"
 }
 
+// applyUserConfig fills in settings from the config file where no flag (or
+// environment variable) set them
+func applyUserConfig(cmd *cobra.Command) {
+	if !cmd.Flags().Changed("color") {
+		if v := userConfig.String("output.color"); v != "" {
+			colorMode = v
+		}
+	}
+	if !cmd.Flags().Changed("ascii") {
+		asciiMode = userConfig.Bool("output.ascii")
+	}
+	if !cmd.Flags().Changed("max-wait") {
+		if d := userConfig.Duration("api.max_wait"); d > 0 {
+			maxWait = d
+		}
+	}
+	if v, ok := userConfig.Get("output.more"); ok && v == false {
+		output.SetMore(false)
+	}
+	output.SetDefaultTimezone(userConfig.String("output.timezone"))
+}
+
 // Helper function to get output format
 func getOutputFormat(cmd *cobra.Command) output.Format {
 	// Check flags first
@@ -145,12 +301,20 @@ func getOutputFormat(cmd *cobra.Command) output.Format {
 	if outputFormat != "" {
 		return output.Format(outputFormat)
 	}
+	if len(fields) > 0 {
+		return output.FormatJSON
+	}
 
 	// Check environment variable
 	if envFormat := os.Getenv("PE_FORMAT"); envFormat != "" {
 		return output.Format(envFormat)
 	}
 
+	// Then the config file
+	if cfgFormat := userConfig.String("output.format"); cfgFormat != "" {
+		return output.Format(cfgFormat)
+	}
+
 	// Default to table
 	return output.FormatTable
 }
@@ -159,3 +323,19 @@ func getOutputFormat(cmd *cobra.Command) output.Format {
 func IsCompactMode() bool {
 	return compactOutput
 }
+
+// newStreamer creates a page-by-page printer for --stream. Page progress is
+// turned off on the client since the rows themselves show progress.
+func newStreamer(cmd *cobra.Command, client *api.Client) *output.Streamer {
+	client.WithPageProgress(nil)
+	return output.NewStreamer(getOutputFormat(cmd), output.PrintOptions{
+		Compact:   IsCompactMode(),
+		Highlight: searchQuery(cmd),
+	})
+}
+
+// searchQuery returns the -q search terms, used to highlight matches in tables
+func searchQuery(cmd *cobra.Command) string {
+	query, _ := cmd.Flags().GetString("query")
+	return query
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func TestFieldsImplyJSON(t *testing.T) {
+	t.Setenv("PE_FORMAT", "table")
+	defer func(f []string, format string) { fields, outputFormat = f, format }(fields, outputFormat)
+
+	tests := []struct {
+		fields []string
+		format string
+		args   []string
+		want   output.Format
+	}{
+		{nil, "", nil, output.FormatTable},
+		{[]string{"id"}, "", nil, output.FormatJSON},
+		// An explicit format still wins
+		{[]string{"id"}, "", []string{"--plain"}, output.FormatPlain},
+		{[]string{"id"}, "csv", nil, output.FormatCSV},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "events"}
+		cmd.Flags().BoolP("json", "j", false, "")
+		cmd.Flags().BoolP("plain", "p", false, "")
+		if err := cmd.Flags().Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		fields, outputFormat = tt.fields, tt.format
+		if got := getOutputFormat(cmd); got != tt.want {
+			t.Errorf("--fields %v, --format %q, %v: format %q, want %q", tt.fields, tt.format, tt.args, got, tt.want)
+		}
+	}
+}
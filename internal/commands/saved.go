@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/porteden/cli/internal/config"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// savedQueryCommands are the listing commands a saved search may run
+var savedQueryCommands = []string{"calendar events", "calendar by-contact", "email messages"}
+
+// savedNamePattern keeps names usable as a single config key segment
+var savedNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// savedSearch is one entry of 'saved list'
+type savedSearch struct {
+	Name    string   `json:"name"`
+	Args    []string `json:"args"`
+	Command string   `json:"command"`
+}
+
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Save and re-run event and email searches",
+	Long: `Store a calendar or email query under a name and run it again later.
+
+Saved searches live in the config file as saved.<name>, holding the command
+and its flags exactly as given.
+
+Examples:
+  porteden saved add standups -- calendar events --week -q standup
+  porteden saved add vip -- email messages --from boss@example.com --unread
+  porteden saved list
+  porteden saved run vip
+  porteden saved run vip -j -- --limit 5`,
+}
+
+var savedAddCmd = &cobra.Command{
+	Use:   "add <name> -- <command> [flags]",
+	Short: "Save a search",
+	Long: `Save a calendar events, calendar by-contact or email messages query.
+
+Put "--" after the name so the query's flags are stored rather than parsed.
+Adding an existing name replaces it.
+
+Examples:
+  porteden saved add vip -- email messages --from boss@example.com --unread
+  porteden saved add offsite -- calendar events --days 30 -q offsite`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, query := args[0], args[1:]
+		cmd.SilenceUsage = true
+		if !savedNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid name %q: use letters, digits, - and _", name)
+		}
+		if err := checkSavedQuery(query); err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		_, exists := cfg.Get("saved." + name)
+		if err := cfg.SetList("saved."+name, query); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+
+		verb := "Saved"
+		if exists {
+			verb = "Updated"
+		}
+		output.PrintSuccess(fmt.Sprintf("%s %q: porteden %s", verb, name, joinArgs(query)))
+		return nil
+	},
+}
+
+var savedListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		searches := loadSavedSearches()
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(searches, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		if len(searches) == 0 {
+			fmt.Println("No saved searches. Add one with: porteden saved add <name> -- <command> [flags]")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		output.TableHeader(w, "NAME", "COMMAND")
+		for _, s := range searches {
+			fmt.Fprintf(w, "%s\t%s\n", s.Name, s.Command)
+		}
+		return w.Flush()
+	},
+}
+
+var savedRunCmd = &cobra.Command{
+	Use:   "run <name> [-- extra flags]",
+	Short: "Run a saved search",
+	Long: `Run a saved search. Global flags such as --json or --profile apply to
+the search, and flags after "--" are appended to the saved ones.
+
+Examples:
+  porteden saved run vip
+  porteden saved run vip -j -- --limit 5`,
+	Args: cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for _, s := range loadSavedSearches() {
+			names = append(names, s.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		cmd.SilenceUsage = true
+		query := userConfig.List("saved." + name)
+		if len(query) == 0 {
+			return fmt.Errorf("no saved search named %q (see 'porteden saved list')", name)
+		}
+		if err := checkSavedQuery(query); err != nil {
+			return fmt.Errorf("saved search %q: %w", name, err)
+		}
+
+		// Global flags given to 'saved run' are already set on the shared
+		// persistent flags, so only the query itself is passed on
+		rootCmd.SetArgs(append(slices.Clone(query), args[1:]...))
+		_, err := rootCmd.ExecuteC()
+		return err
+	},
+}
+
+var savedRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a saved search",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		key := "saved." + args[0]
+		if _, ok := cfg.Get(key); !ok {
+			return fmt.Errorf("no saved search named %q", args[0])
+		}
+		cfg.Unset(key)
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %q\n", args[0])
+		return nil
+	},
+}
+
+// checkSavedQuery verifies a query names a listing command and that its
+// flags parse
+func checkSavedQuery(query []string) error {
+	target, rest, err := rootCmd.Find(query)
+	if err != nil {
+		return err
+	}
+	path := strings.TrimPrefix(target.CommandPath(), rootCmd.Name()+" ")
+	if !slices.Contains(savedQueryCommands, path) {
+		return fmt.Errorf("%q can't be saved; use one of: %s", strings.Join(query, " "), strings.Join(savedQueryCommands, ", "))
+	}
+	if err := target.ParseFlags(rest); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if path != "calendar by-contact" && len(target.Flags().Args()) > 0 {
+		return errors.New(path + " takes no arguments besides flags")
+	}
+	return nil
+}
+
+// loadSavedSearches returns the saved searches in the config file, by name.
+// It reads the file itself because shell completion runs without the
+// root command's setup.
+func loadSavedSearches() []savedSearch {
+	cfg, _ := config.Load()
+	var searches []savedSearch
+	for _, key := range cfg.Keys() {
+		name, ok := strings.CutPrefix(key, "saved.")
+		if !ok {
+			continue
+		}
+		query := cfg.List(key)
+		searches = append(searches, savedSearch{Name: name, Args: query, Command: "porteden " + joinArgs(query)})
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].Name < searches[j].Name })
+	return searches
+}
+
+// joinArgs renders arguments as a shell command line, quoting where needed
+func joinArgs(args []string) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"\\$`*?&|;<>()#~") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		parts[i] = a
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	savedCmd.AddCommand(savedAddCmd)
+	savedCmd.AddCommand(savedListCmd)
+	savedCmd.AddCommand(savedRunCmd)
+	savedCmd.AddCommand(savedRemoveCmd)
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/index"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search events and email together",
+	Long: `Search calendar events and email at once and list the matches together,
+newest first.
+
+Events are searched from --days back to --days ahead, emails from --days back.
+If one kind can't be searched, the other's results are still shown.
+
+Examples:
+  porteden search "budget review"
+  porteden search invoice --kind email --days 365
+  porteden search offsite -j`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, _ := cmd.Flags().GetString("kind")
+		limit, _ := cmd.Flags().GetInt("limit")
+		days, _ := cmd.Flags().GetInt("days")
+		if kind != "" && kind != index.KindEmail && kind != index.KindEvent {
+			return fmt.Errorf("invalid --kind %q: use email or event", kind)
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		query := strings.Join(args, " ")
+		now := time.Now()
+		results := &output.SearchResults{Query: query, Results: []index.Result{}}
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			failures []error
+			searched int
+			events   []api.Event
+			emails   []api.Email
+		)
+		add := func(what string, docs []index.Document, total int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, err)
+				results.Warnings = append(results.Warnings, fmt.Sprintf("%s search failed: %v", what, formatError(err)))
+				return
+			}
+			for _, d := range docs {
+				results.Results = append(results.Results, index.Result{
+					Kind: d.Kind, ID: d.ID, Title: d.Title, From: d.From, Date: d.Date, Snippet: d.Snippet,
+				})
+			}
+			results.Total += total
+		}
+
+		if kind != index.KindEmail {
+			searched++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.GetEvents(api.EventParams{
+					Query: query,
+					From:  now.AddDate(0, 0, -days),
+					To:    now.AddDate(0, 0, days),
+					Limit: limit,
+				})
+				var docs []index.Document
+				total := 0
+				if err == nil {
+					events = resp.Events
+					for _, e := range resp.Events {
+						docs = append(docs, eventDocument(e))
+					}
+					total = len(resp.Events)
+					if resp.Meta != nil && resp.Meta.TotalCount > total {
+						total = resp.Meta.TotalCount
+					}
+				}
+				add("event", docs, total, err)
+			}()
+		}
+		if kind != index.KindEvent {
+			searched++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.GetEmails(api.EmailParams{
+					Query: query,
+					After: now.AddDate(0, 0, -days),
+					Limit: limit,
+				})
+				var docs []index.Document
+				total := 0
+				if err == nil {
+					emails = resp.Emails
+					for _, e := range resp.Emails {
+						docs = append(docs, emailDocument(e))
+					}
+					total = max(resp.TotalCount, len(resp.Emails))
+				}
+				add("email", docs, total, err)
+			}()
+		}
+		wg.Wait()
+
+		// Nothing to show if every search failed
+		if len(failures) == searched {
+			return formatError(failures[0])
+		}
+		rememberEvents(cmd, events)
+		rememberEmails(cmd, emails)
+
+		sort.SliceStable(results.Results, func(i, j int) bool {
+			return results.Results[i].Date.After(results.Results[j].Date)
+		})
+
+		format := getOutputFormat(cmd)
+		if format != output.FormatJSON {
+			for _, w := range results.Warnings {
+				fmt.Fprintln(os.Stderr, output.ColorYellow("Warning: "+w))
+			}
+		}
+		output.PrintWithOptions(results, format, output.PrintOptions{Highlight: query})
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().String("kind", "", "Only search one kind: email or event")
+	searchCmd.Flags().Int("limit", 20, "Maximum results of each kind")
+	searchCmd.Flags().Int("days", 90, "Search events N days back and ahead, and email N days back")
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/apierr"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/refs"
+	"github.com/spf13/cobra"
+)
+
+// Kinds of item 'show' can find
+const (
+	showEvent    = "event"
+	showEmail    = "email"
+	showThread   = "thread"
+	showCalendar = "calendar"
+)
+
+// showResult is the JSON output of 'show', naming the kind that was found
+type showResult struct {
+	Kind string      `json:"kind"`
+	ID   string      `json:"id"`
+	Item interface{} `json:"item"`
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show an event, email, thread or calendar by ID",
+	Long: `Show the details of any ID without knowing what it refers to.
+
+Numeric IDs are looked up among your calendars. Other IDs are tried as an
+event, an email and a thread, starting with the kind suggested by recently
+listed items or the ID's shape. Short ID prefixes of listed items work too.
+
+Examples:
+  porteden show evt_1001
+  porteden show msg_10
+  porteden show 12345 -j`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+
+		id := args[0]
+		rowRef := refs.IsRowRef(id)
+		var lastErr *apierr.APIError
+		var rowErr error
+		for _, kind := range showProbeOrder(cmd, id) {
+			probeID := id
+			if kind == showEvent || kind == showEmail {
+				refKind := refs.KindEvent
+				if kind == showEmail {
+					refKind = refs.KindEmail
+				}
+				resolved, err := resolveRef(cmd, refKind, id)
+				if err != nil && rowRef {
+					// The last listing may have been of the other kind
+					rowErr = err
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				probeID = resolved
+			} else if rowRef {
+				continue
+			}
+
+			item, err := fetchShowItem(client, kind, probeID)
+			if err == nil && item != nil {
+				return printShowResult(cmd, kind, probeID, item)
+			}
+			var apiErr *apierr.APIError
+			if err != nil && !(errors.As(err, &apiErr) && showMissing(apiErr)) {
+				return formatError(err)
+			}
+			if apiErr != nil {
+				lastErr = apiErr
+			}
+		}
+		if rowErr != nil && lastErr == nil {
+			return rowErr
+		}
+		return &userError{message: fmt.Sprintf("no event, email, thread or calendar found with ID %q", id), apiErr: lastErr}
+	},
+}
+
+// showProbeOrder lists the kinds to try for id, most likely first
+func showProbeOrder(cmd *cobra.Command, id string) []string {
+	if _, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return []string{showCalendar, showEvent, showEmail, showThread}
+	}
+
+	switch {
+	case strings.HasPrefix(id, "evt_"):
+		return []string{showEvent, showEmail, showThread}
+	case strings.HasPrefix(id, "msg_"):
+		return []string{showEmail, showThread, showEvent}
+	case strings.HasPrefix(id, "thr_"):
+		return []string{showThread, showEmail, showEvent}
+	}
+
+	// An ID (or prefix) remembered from an email listing is most likely an email
+	store, _ := refs.Load(getProfile(cmd))
+	if resolved, err := store.Resolve(refs.KindEmail, id); err == nil && store.Known(refs.KindEmail, resolved) {
+		return []string{showEmail, showThread, showEvent}
+	}
+	return []string{showEvent, showEmail, showThread}
+}
+
+// showMissing reports whether a probe failed only because the ID isn't of
+// that kind
+func showMissing(err *apierr.APIError) bool {
+	switch err.ErrorCode() {
+	case "NOT_FOUND", "VALIDATION", "VALIDATION_ERROR", "INVALID_REQUEST", "BAD_REQUEST":
+		return true
+	}
+	return false
+}
+
+// fetchShowItem looks up id as one kind. A nil item with a nil error means
+// no calendar has that ID.
+func fetchShowItem(client *api.Client, kind, id string) (interface{}, error) {
+	switch kind {
+	case showEvent:
+		return client.GetEvent(id)
+	case showEmail:
+		return client.GetEmail(id, true)
+	case showThread:
+		return client.GetThread(id, true)
+	case showCalendar:
+		resp, err := client.GetCalendars()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range resp.Data {
+			if strconv.FormatInt(c.ID, 10) == id {
+				return []api.Calendar{c}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func printShowResult(cmd *cobra.Command, kind, id string, item interface{}) error {
+	format := getOutputFormat(cmd)
+	if format == output.FormatJSON {
+		output.PrintWithOptions(showResult{Kind: kind, ID: id, Item: item}, format, output.PrintOptions{
+			Compact: IsCompactMode(),
+		})
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, output.ColorGray(fmt.Sprintf("Found %s %s", kind, id)))
+	output.PrintWithOptions(item, format, output.PrintOptions{Compact: IsCompactMode()})
+	return nil
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show PortEden service status and ongoing incidents",
+	Long: `Check the PortEden status page for ongoing incidents and scheduled
+maintenance. Useful when commands fail with server errors.
+
+The status page is public, so this works without being signed in.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newAPIClient("")
+		if isDemo(cmd) {
+			client = newDemoClient()
+		}
+		cmd.SilenceUsage = true
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		status, err := client.GetServiceStatus(ctx)
+		if err != nil {
+			return fmt.Errorf("%w (see %s)", err, api.StatusURL())
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.Print(status, output.FormatJSON)
+			return nil
+		}
+		printServiceStatus(status)
+		return nil
+	},
+}
+
+// printServiceStatus prints the overall state, components that aren't
+// operational, and each unresolved incident with its latest update
+func printServiceStatus(s *api.ServiceStatus) {
+	description := s.Status.Description
+	if description == "" {
+		description = "Unknown"
+	}
+	switch s.Status.Indicator {
+	case "none", "":
+		description = output.ColorGreen(description)
+	case "minor", "maintenance":
+		description = output.ColorYellow(description)
+	default:
+		description = output.ColorRed(description)
+	}
+	fmt.Printf("PortEden status: %s\n", description)
+
+	for _, c := range s.Components {
+		if c.Status != "operational" {
+			fmt.Printf("  %s: %s\n", c.Name, strings.ReplaceAll(c.Status, "_", " "))
+		}
+	}
+
+	if len(s.Incidents) > 0 {
+		fmt.Println("\nIncidents:")
+		for _, inc := range s.Incidents {
+			printIncident(inc)
+		}
+	}
+	if len(s.ScheduledMaintenances) > 0 {
+		fmt.Println("\nScheduled maintenance:")
+		for _, inc := range s.ScheduledMaintenances {
+			printIncident(inc)
+		}
+	}
+
+	url := s.Page.URL
+	if url == "" {
+		url = api.StatusURL()
+	}
+	fmt.Printf("\nDetails: %s\n", url)
+}
+
+func printIncident(inc api.Incident) {
+	impact := ""
+	if inc.Impact != "" && inc.Impact != "none" {
+		impact = " [" + inc.Impact + "]"
+	}
+	fmt.Printf("  %s%s\n", output.ColorBold(inc.Name), impact)
+	fmt.Printf("    %s, updated %s\n", strings.ReplaceAll(inc.Status, "_", " "), output.FormatRelativeTime(inc.UpdatedAt))
+	if len(inc.Updates) > 0 {
+		fmt.Printf("    %s\n", strings.TrimSpace(inc.Updates[0].Body))
+	}
+	if inc.ShortLink != "" {
+		fmt.Printf("    %s\n", inc.ShortLink)
+	}
+}
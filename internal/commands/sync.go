@@ -0,0 +1,338 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/apierr"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/porteden/cli/internal/queue"
+	"github.com/spf13/cobra"
+)
+
+// syncQueue names the queue of calendar changes made with --queue
+const syncQueue = "sync"
+
+// Operations of queued calendar changes
+const (
+	syncCreate = "create"
+	syncUpdate = "update"
+	syncDelete = "delete"
+)
+
+// queueFlagUsage describes --queue on calendar changes
+const queueFlagUsage = "If the API can't be reached, save the change for 'porteden sync flush'"
+
+// queuedCreate is a queued event creation. Calendar holds a calendar name
+// that couldn't be resolved offline.
+type queuedCreate struct {
+	Calendar string                 `json:"calendar,omitempty"`
+	Event    api.CreateEventRequest `json:"event"`
+}
+
+// queuedDelete is a queued event deletion
+type queuedDelete struct {
+	NotifyAttendees bool   `json:"notifyAttendees"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+// errSyncConflict marks a queued change that no longer fits the calendar
+var errSyncConflict = errors.New("conflict")
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Replay calendar changes queued while offline",
+	Long: `Calendar changes made with --queue are saved locally when the API can't be
+reached, and replayed here once you're back online.
+
+Before replaying, each change is checked against the calendar. A change is
+held back as a conflict when the event was changed or deleted by someone else
+after it was queued, or when an identical event was already created. Review
+conflicts with 'porteden sync list', then apply them with --force or discard
+them.
+
+Examples:
+  porteden calendar create --summary "Standup" --from ... --to ... --queue
+  porteden sync list
+  porteden sync flush
+  porteden sync flush 2 --force
+  porteden sync discard 2`,
+}
+
+var syncListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued calendar changes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := queue.Load(syncQueue, getProfile(cmd))
+		if err != nil {
+			return err
+		}
+
+		if getOutputFormat(cmd) == output.FormatJSON {
+			output.PrintWithOptions(q.Entries, output.FormatJSON, output.PrintOptions{})
+			return nil
+		}
+		if len(q.Entries) == 0 {
+			fmt.Println("No queued changes.")
+			return nil
+		}
+		printQueue(q.Entries)
+		return nil
+	},
+}
+
+var syncFlushCmd = &cobra.Command{
+	Use:   "flush [id...]",
+	Short: "Apply queued calendar changes",
+	Long: `Apply queued calendar changes in the order they were made, or only the
+given ones.
+
+Applied changes are removed from the queue. Flushing stops at the first network
+or server error. Conflicts and changes the API rejects stay queued with the
+reason. Use --force to apply conflicting changes anyway.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+
+		q, err := queue.Load(syncQueue, getProfile(cmd))
+		if err != nil {
+			return err
+		}
+		entries, err := selectQueued(q, args, "porteden sync list")
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("No queued changes.")
+			return nil
+		}
+		cmd.SilenceUsage = true
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Events changed earlier in this flush aren't conflicts for later changes
+		touched := map[string]time.Time{}
+		applied, conflicts := 0, 0
+		for _, e := range entries {
+			result, err := replayChange(cmd, client, e, force, touched)
+			if err == nil {
+				q.Remove(e.ID)
+				applied++
+				output.PrintSuccess(fmt.Sprintf("%s %s: %s", e.ID, e.Summary, result))
+			} else {
+				if errors.Is(err, errSyncConflict) {
+					conflicts++
+				}
+				entry, _ := q.Find(e.ID)
+				entry.Attempts++
+				entry.LastError = err.Error()
+				fmt.Fprintf(os.Stderr, "  %s %s %s: %v\n", output.ColorRed(output.Symbols("✗")), e.ID, e.Summary, err)
+			}
+			// Saved after every change so an interrupted flush never applies one twice
+			if saveErr := q.Save(); saveErr != nil {
+				return fmt.Errorf("failed to update the queue: %w", saveErr)
+			}
+			if err != nil && api.IsTransient(err) {
+				break
+			}
+		}
+
+		if remaining := len(entries) - applied; remaining > 0 {
+			msg := fmt.Sprintf("applied %d change(s); %d remain queued", applied, remaining)
+			if conflicts > 0 {
+				msg += fmt.Sprintf(", %d of them conflicts (apply with --force or discard them)", conflicts)
+			}
+			return errors.New(msg)
+		}
+		fmt.Printf("Applied %d change(s)\n", applied)
+		return nil
+	},
+}
+
+var syncDiscardCmd = &cobra.Command{
+	Use:   "discard [id...]",
+	Short: "Delete queued calendar changes without applying them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if len(args) == 0 && !all {
+			return fmt.Errorf("give the IDs of the changes to discard, or --all")
+		}
+
+		q, err := queue.Load(syncQueue, getProfile(cmd))
+		if err != nil {
+			return err
+		}
+		entries, err := selectQueued(q, args, "porteden sync list")
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("No queued changes.")
+			return nil
+		}
+
+		if !yes && auth.IsInteractiveTerminal() {
+			printQueue(entries)
+			fmt.Println()
+			if !confirm(i18n.T("Discard %d queued change(s)?", len(entries))) {
+				fmt.Println(i18n.T("Cancelled. No changes made."))
+				return nil
+			}
+		}
+		for _, e := range entries {
+			q.Remove(e.ID)
+		}
+		if err := q.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Discarded %d change(s)\n", len(entries))
+		return nil
+	},
+}
+
+// queueFailedChange saves a calendar change for 'sync flush' when --queue is
+// set and err is a network or server error, and otherwise just formats err
+func queueFailedChange(cmd *cobra.Command, op, target, summary string, req interface{}, err error) error {
+	if useQueue, _ := cmd.Flags().GetBool("queue"); !useQueue || !api.IsTransient(err) {
+		return formatError(err)
+	}
+	if delegateAs != "" {
+		return fmt.Errorf("%w (changes made with --as can't be queued)", formatError(err))
+	}
+	entry, qerr := enqueue(cmd, syncQueue, op, target, summary, req)
+	if qerr != nil {
+		return fmt.Errorf("%w (and it could not be queued: %v)", formatError(err), qerr)
+	}
+	cmd.SilenceUsage = true
+	fmt.Fprintln(os.Stderr, formatError(err))
+	if getOutputFormat(cmd) == output.FormatJSON {
+		output.PrintWithOptions(entry, output.FormatJSON, output.PrintOptions{})
+		return nil
+	}
+	fmt.Printf("Change queued as %s. Apply it later with 'porteden sync flush'\n", entry.ID)
+	return nil
+}
+
+// replayChange applies one queued change after checking it for conflicts,
+// and describes the outcome
+func replayChange(cmd *cobra.Command, client *api.Client, e queue.Entry, force bool, touched map[string]time.Time) (string, error) {
+	switch e.Op {
+	case syncCreate:
+		var c queuedCreate
+		if err := json.Unmarshal(e.Request, &c); err != nil {
+			return "", fmt.Errorf("unreadable queued change: %w", err)
+		}
+		if c.Calendar != "" {
+			id, err := resolveCalendarID(cmd, client, c.Calendar)
+			if err != nil {
+				return "", err
+			}
+			c.Event.CalendarID = id
+		}
+		if !force {
+			if id, err := findDuplicateEvent(client, c.Event); err != nil {
+				return "", err
+			} else if id != "" {
+				return "", fmt.Errorf("%w: an identical event already exists (%s), perhaps created before the connection dropped", errSyncConflict, id)
+			}
+		}
+		event, err := client.CreateEvent(c.Event)
+		if err != nil {
+			return "", formatError(err)
+		}
+		rememberRecipients(cmd, plainAddresses(c.Event.Attendees))
+		touched[event.ID] = event.UpdatedUtc
+		return "created " + event.ID, nil
+
+	case syncUpdate, syncDelete:
+		current, err := client.GetEvent(e.Target)
+		if err != nil {
+			var apiErr *apierr.APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+				if e.Op == syncDelete {
+					return "already deleted", nil
+				}
+				return "", fmt.Errorf("%w: the event was deleted after the change was queued", errSyncConflict)
+			}
+			return "", formatError(err)
+		}
+		updated := current.Event.UpdatedUtc
+		if !force && updated.After(e.QueuedAt) && !updated.Equal(touched[e.Target]) {
+			return "", fmt.Errorf("%w: the event was changed at %s, after the change was queued", errSyncConflict, output.FormatLocalTime(updated))
+		}
+
+		if e.Op == syncDelete {
+			var d queuedDelete
+			if err := json.Unmarshal(e.Request, &d); err != nil {
+				return "", fmt.Errorf("unreadable queued change: %w", err)
+			}
+			if _, err := client.DeleteEvent(e.Target, d.NotifyAttendees, d.Scope); err != nil {
+				return "", formatError(err)
+			}
+			return "deleted", nil
+		}
+
+		var req api.UpdateEventRequest
+		if err := json.Unmarshal(e.Request, &req); err != nil {
+			return "", fmt.Errorf("unreadable queued change: %w", err)
+		}
+		event, err := client.UpdateEvent(e.Target, req)
+		if err != nil {
+			return "", formatError(err)
+		}
+		rememberRecipients(cmd, plainAddresses(req.AddAttendees))
+		touched[e.Target] = event.UpdatedUtc
+		return "updated", nil
+	}
+	return "", fmt.Errorf("unknown queued operation %q", e.Op)
+}
+
+// findDuplicateEvent returns the ID of an event in the request's calendar
+// with the same title, start and end, if there is one
+func findDuplicateEvent(client *api.Client, req api.CreateEventRequest) (string, error) {
+	resp, err := client.GetEvents(api.EventParams{From: req.From, To: req.To, CalendarID: req.CalendarID, Limit: 100})
+	if err != nil {
+		return "", formatError(err)
+	}
+	for _, e := range resp.Events {
+		title := e.Title
+		if title == "" {
+			title = e.Summary
+		}
+		if strings.EqualFold(title, req.Summary) && e.StartUtc.Equal(req.From) && e.EndUtc.Equal(req.To) && e.Status != "cancelled" {
+			return e.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// syncSummary describes a queued calendar change for listings
+func syncSummary(op, target, title string) string {
+	switch op {
+	case syncCreate:
+		return fmt.Sprintf("Create %q", title)
+	case syncDelete:
+		return "Delete " + target
+	}
+	return "Update " + target
+}
+
+func init() {
+	syncFlushCmd.Flags().Bool("force", false, "Apply changes even when they conflict")
+	syncDiscardCmd.Flags().Bool("all", false, "Discard every queued change")
+	syncDiscardCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+
+	syncCmd.AddCommand(syncListCmd)
+	syncCmd.AddCommand(syncFlushCmd)
+	syncCmd.AddCommand(syncDiscardCmd)
+}
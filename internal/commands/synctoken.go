@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// sinceTokenUsage describes --since-token on calendar events and email messages
+const sinceTokenUsage = "Only list changes since this sync token from an earlier run; pass --since-token= for a first full listing that prints one"
+
+// sinceToken returns the --since-token value and whether it was given. A
+// sync token covers the whole listing, so every page is fetched.
+func sinceToken(cmd *cobra.Command) (string, bool, error) {
+	if !cmd.Flags().Changed("since-token") {
+		return "", false, nil
+	}
+	if stream, _ := cmd.Flags().GetBool("stream"); stream {
+		return "", false, fmt.Errorf("--since-token cannot be combined with --stream")
+	}
+	token, _ := cmd.Flags().GetString("since-token")
+	return token, true, nil
+}
+
+// syncTokenError explains an expired sync token, which can't be resumed
+func syncTokenError(err error) error {
+	if api.IsSyncTokenExpired(err) {
+		return fmt.Errorf("the sync token has expired: run again with --since-token= for a full listing and a new token")
+	}
+	return formatError(err)
+}
+
+// printSyncState writes the deleted IDs and the next sync token to stderr,
+// where they don't mix with the listing. JSON output carries them already.
+func printSyncState(cmd *cobra.Command, kind string, deleted []string, next string) {
+	if getOutputFormat(cmd) == output.FormatJSON {
+		return
+	}
+	if len(deleted) > 0 {
+		fmt.Fprintf(os.Stderr, "Deleted %s: %s\n", kind, strings.Join(deleted, ", "))
+	}
+	if next != "" {
+		fmt.Fprintf(os.Stderr, "Next sync token: %s\n", next)
+	}
+}
@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/auth"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// triageAction is a pending modification recorded during triage and applied at the end
+type triageAction struct {
+	email api.Email
+	kind  string // archive, delete, reply, label, read
+	label string
+	body  string
+}
+
+var emailTriageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Step through unread email with single-key actions",
+	Long: `Interactively triage unread email one message at a time.
+
+Keys:
+  a  archive (remove from inbox, mark read)
+  d  delete (move to trash)
+  r  reply (prompts for a one-line reply)
+  l  add a label
+  m  mark as read
+  s  skip (also Space or Enter)
+  q  stop and review pending actions
+
+Nothing is changed until you confirm the batch at the end.
+
+Examples:
+  porteden email triage
+  porteden email triage --limit 100 --from boss@example.com`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !auth.IsInteractiveTerminal() {
+			return errors.New("triage requires an interactive terminal")
+		}
+
+		client, err := getClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		from, _ := cmd.Flags().GetString("from")
+		unread := true
+
+		resp, err := client.GetEmails(api.EmailParams{
+			Unread: &unread,
+			From:   from,
+			Limit:  limit,
+		})
+		if err != nil {
+			return formatError(err)
+		}
+
+		if len(resp.Emails) == 0 {
+			output.PrintSuccess(output.Symbols("Inbox zero — no unread email."))
+			return nil
+		}
+
+		actions, err := runTriage(resp.Emails)
+		if err != nil {
+			return err
+		}
+		if len(actions) == 0 {
+			fmt.Println("No actions recorded.")
+			return nil
+		}
+
+		printTriageSummary(actions)
+		if !confirm(i18n.T("Apply these actions?")) {
+			fmt.Println(i18n.T("Cancelled. No changes made."))
+			return nil
+		}
+
+		return applyTriage(client, actions)
+	},
+}
+
+// runTriage shows each email and records the chosen action
+func runTriage(emails []api.Email) ([]triageAction, error) {
+	var actions []triageAction
+
+	for i, e := range emails {
+		printTriageCard(i+1, len(emails), e)
+
+		action, quit, err := promptTriageAction(e)
+		if err != nil {
+			return nil, err
+		}
+		if action != nil {
+			actions = append(actions, *action)
+		}
+		if quit {
+			return actions, nil
+		}
+	}
+
+	return actions, nil
+}
+
+// promptTriageAction reads keys until a valid action is chosen.
+// Returns a nil action for skip, and quit=true when the user stops triage.
+func promptTriageAction(e api.Email) (*triageAction, bool, error) {
+	for {
+		fmt.Print(output.ColorGray("[a]rchive [d]elete [r]eply [l]abel [m]ark read [s]kip [q]uit: "))
+		key, err := readKey()
+		if err != nil {
+			return nil, false, err
+		}
+		fmt.Println()
+
+		switch key {
+		case 'a':
+			return &triageAction{email: e, kind: "archive"}, false, nil
+		case 'd':
+			return &triageAction{email: e, kind: "delete"}, false, nil
+		case 'm':
+			return &triageAction{email: e, kind: "read"}, false, nil
+		case 'r':
+			if body := readLine(i18n.T("Reply: ")); body != "" {
+				return &triageAction{email: e, kind: "reply", body: body}, false, nil
+			}
+			fmt.Println(output.ColorGray("Empty reply, skipped."))
+		case 'l':
+			if label := readLine(i18n.T("Label: ")); label != "" {
+				return &triageAction{email: e, kind: "label", label: label}, false, nil
+			}
+		case 's', ' ', '\r', '\n':
+			return nil, false, nil
+		case 'q', 3: // q or Ctrl+C
+			return nil, true, nil
+		}
+	}
+}
+
+func printTriageCard(n, total int, e api.Email) {
+	fmt.Println()
+	fmt.Printf("%s %s\n", output.ColorCyan(fmt.Sprintf("[%d/%d]", n, total)), output.ColorBold(e.Subject))
+	if e.From != nil {
+		fmt.Printf("  From: %s\n", formatTriageParticipant(*e.From))
+	}
+	if !e.ReceivedAt.IsZero() {
+		fmt.Printf("  Date: %s\n", output.FormatLocalTime(e.ReceivedAt))
+	}
+	if e.HasAttachments {
+		fmt.Println("  " + output.ColorYellow("Has attachments"))
+	}
+	if preview := strings.Join(strings.Fields(e.BodyPreview), " "); preview != "" {
+		fmt.Printf("\n  %s\n\n", truncateRunes(preview, 240))
+	}
+}
+
+func formatTriageParticipant(p api.Participant) string {
+	if p.Name != "" {
+		return fmt.Sprintf("%s <%s>", p.Name, p.Email)
+	}
+	return p.Email
+}
+
+func printTriageSummary(actions []triageAction) {
+	counts := make(map[string]int)
+	for _, a := range actions {
+		counts[a.kind]++
+	}
+	fmt.Println()
+	fmt.Println(output.ColorBold("Pending actions:"))
+	for _, kind := range []string{"archive", "delete", "reply", "label", "read"} {
+		if counts[kind] > 0 {
+			fmt.Printf("  %-8s %d\n", kind, counts[kind])
+		}
+	}
+	fmt.Println()
+}
+
+// applyTriage executes recorded actions, continuing past individual failures
+func applyTriage(client *api.Client, actions []triageAction) error {
+	failed := 0
+	for _, a := range actions {
+		var err error
+		switch a.kind {
+		case "archive":
+			err = archiveEmail(client, a.email.ID)
+		case "delete":
+			err = client.DeleteEmail(a.email.ID)
+		case "read":
+			read := true
+			err = client.ModifyEmail(a.email.ID, api.ModifyEmailRequest{MarkAsRead: &read})
+		case "label":
+			err = client.ModifyEmail(a.email.ID, api.ModifyEmailRequest{AddLabels: []string{a.label}})
+		case "reply":
+			var resp *api.EmailActionResponse
+			resp, err = client.ReplyToEmail(a.email.ID, api.ReplyEmailRequest{Body: a.body, BodyType: "text"})
+			if err == nil && !resp.Success {
+				err = errors.New(resp.ErrorMessage)
+			}
+		}
+
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "  %s %s %q: %v\n", output.ColorRed(output.Symbols("✗")), a.kind, a.email.Subject, formatError(err))
+			continue
+		}
+		output.PrintSuccess(fmt.Sprintf("%s %q", a.kind, a.email.Subject))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d actions failed", failed, len(actions))
+	}
+	return nil
+}
+
+// readKey reads a single keypress from stdin in raw mode
+func readKey() (byte, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read key: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	return stdinReader.ReadByte()
+}
+
+// stdinReader buffers standard input for readKey and readLine. There is one
+// for the whole session: a reader per prompt would drop what it had buffered
+// past the line it returned, such as the answers piped in after it.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readLine prompts for a line of input in cooked mode
+func readLine(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := stdinReader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// confirm asks a yes/no question that defaults to no
+func confirm(question string) bool {
+	return i18n.Yes(readLine(question + " " + i18n.T("[y/N]") + ": "))
+}
+
+func init() {
+	emailTriageCmd.Flags().Int("limit", 50, "Maximum unread emails to triage")
+	emailTriageCmd.Flags().String("from", "", "Only triage email from this sender")
+
+	emailCmd.AddCommand(emailTriageCmd)
+}
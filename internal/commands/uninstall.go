@@ -1,13 +1,12 @@
 package commands
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 
+	"github.com/porteden/cli/internal/i18n"
 	"github.com/porteden/cli/internal/output"
 	"github.com/porteden/cli/internal/system"
 	"github.com/spf13/cobra"
@@ -61,12 +60,8 @@ func runUninstall(purge, yes bool) error {
 
 	// Confirm
 	if !yes {
-		fmt.Print("Continue? [y/N] ")
-		reader := bufio.NewReader(os.Stdin)
-		answer, _ := reader.ReadString('\n')
-		answer = strings.TrimSpace(strings.ToLower(answer))
-		if answer != "y" && answer != "yes" {
-			fmt.Println("Aborted.")
+		if !confirm(i18n.T("Continue?")) {
+			fmt.Println(i18n.T("Aborted."))
 			return nil
 		}
 	}
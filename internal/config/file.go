@@ -0,0 +1,425 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyType is the value type of a config key
+type KeyType string
+
+const (
+	TypeString   KeyType = "string"
+	TypeBool     KeyType = "bool"
+	TypeInt      KeyType = "int"
+	TypeDuration KeyType = "duration"
+	TypeList     KeyType = "list"
+	TypeFlag     KeyType = "flag" // A command-line flag value: string, bool, number or list
+)
+
+// Key describes a supported config key. A "*" segment matches any single
+// segment, e.g. "filters.*.*" accepts "filters.vip.attendees".
+type Key struct {
+	Name        string
+	Type        KeyType
+	Description string
+	Allowed     []string // Permitted values for string keys, if restricted
+}
+
+// Schema lists every key accepted in config.json
+var Schema = []Key{
+	{Name: "output.format", Type: TypeString, Description: "Default output format", Allowed: []string{"table", "json", "plain", "csv"}},
+	{Name: "output.color", Type: TypeString, Description: "Color mode", Allowed: []string{"auto", "always", "never"}},
+	{Name: "output.more", Type: TypeBool, Description: "Prompt with -- more -- when a table is longer than the terminal (default true)"},
+	{Name: "output.ascii", Type: TypeBool, Description: "Use plain ASCII instead of box drawing characters and symbols"},
+	{Name: "output.timezone", Type: TypeString, Description: "IANA timezone for displayed times (e.g. Europe/Berlin)"},
+	{Name: "api.max_wait", Type: TypeDuration, Description: "Default --max-wait for rate limits (e.g. 30s)"},
+	{Name: "downloads.dir", Type: TypeString, Description: "Default directory for downloaded attachments (~ is expanded)"},
+	{Name: "downloads.flat", Type: TypeBool, Description: "Save downloads directly in the directory instead of per-thread subfolders"},
+	{Name: "calendar.week_starts", Type: TypeString, Description: "First day of the week for --week, --last-week and --week-of (default monday)", Allowed: []string{"monday", "sunday"}},
+	{Name: "calendar.hide_declined", Type: TypeBool, Description: "Leave declined events out of calendar events and digest"},
+	{Name: "calendar.work_hours", Type: TypeString, Description: "Working hours searched by calendar free (default 09:00-17:00)"},
+	{Name: "calendar.work_days", Type: TypeList, Description: "Working days searched by calendar free (default mon,tue,wed,thu,fri)"},
+	{Name: "email.always_cc", Type: TypeList, Description: "Addresses added as CC to every sent, reply and forwarded email"},
+	{Name: "email.always_bcc", Type: TypeList, Description: "Addresses added as BCC to every sent, reply and forwarded email"},
+	{Name: "email.signature", Type: TypeString, Description: "Signature added to replies written with --edit (\\n for line breaks)"},
+	{Name: "saved.*", Type: TypeList, Description: "Saved search: the command and flags run by 'porteden saved run'"},
+	{Name: "filters.*.*", Type: TypeFlag, Description: "Filter preset: a calendar events flag applied by --preset"},
+	{Name: "templates.*.title", Type: TypeString, Description: "Event template: title of events created with --template"},
+	{Name: "templates.*.duration", Type: TypeDuration, Description: "Event template: length of the event (e.g. 30m)"},
+	{Name: "templates.*.calendar", Type: TypeString, Description: "Event template: calendar ID or name"},
+	{Name: "templates.*.description", Type: TypeString, Description: "Event template: description"},
+	{Name: "templates.*.location", Type: TypeString, Description: "Event template: location"},
+	{Name: "templates.*.attendees", Type: TypeList, Description: "Event template: attendee emails"},
+	{Name: "templates.*.recurrence", Type: TypeList, Description: "Event template: RRULE recurrence patterns"},
+}
+
+// LookupKey returns the schema entry for a key
+func LookupKey(name string) (*Key, error) {
+	for i, k := range Schema {
+		if matchKey(k.Name, name) {
+			return &Schema[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown config key %q (run 'porteden config get' to list keys)", name)
+}
+
+// matchKey reports whether name matches pattern segment by segment
+func matchKey(pattern, name string) bool {
+	want, got := strings.Split(pattern, "."), strings.Split(name, ".")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if got[i] == "" || (want[i] != "*" && want[i] != got[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// File is the user config file, ~/.config/porteden/config.json. Values are
+// stored as nested JSON objects keyed by the dotted key path.
+type File struct {
+	path string
+	data map[string]interface{}
+}
+
+// FilePath returns the location of config.json
+func FilePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load reads the config file. A missing file yields an empty config. On error
+// an empty config is still returned, so callers may carry on with defaults.
+func Load() (*File, error) {
+	path, err := FilePath()
+	f := &File{path: path, data: map[string]interface{}{}}
+	if err != nil {
+		return f, err
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	data := map[string]interface{}{}
+	if err := Parse(raw, data); err != nil {
+		return f, fmt.Errorf("%s: %w", path, err)
+	}
+	f.data = data
+	return f, nil
+}
+
+// Parse decodes and validates config JSON into data
+func Parse(raw []byte, data map[string]interface{}) error {
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return validate(data)
+}
+
+// Path returns the file location
+func (f *File) Path() string {
+	return f.path
+}
+
+// Save writes the config file
+func (f *File) Save() error {
+	raw, err := json.MarshalIndent(f.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(f.path, append(raw, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// Get returns the raw value of a key
+func (f *File) Get(key string) (interface{}, bool) {
+	if f == nil {
+		return nil, false
+	}
+	node := f.data
+	parts := strings.Split(key, ".")
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := node[p].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		node = child
+	}
+	v, ok := node[parts[len(parts)-1]]
+	return v, ok
+}
+
+// Set validates and stores a value given as a string, converting it to the
+// key's type. List values are comma-separated.
+func (f *File) Set(key, value string) error {
+	k, err := LookupKey(key)
+	if err != nil {
+		return err
+	}
+	v, err := k.parse(value)
+	if err != nil {
+		return err
+	}
+	f.put(key, v)
+	return nil
+}
+
+// SetList stores a list value item by item, so items may contain commas
+func (f *File) SetList(key string, items []string) error {
+	k, err := LookupKey(key)
+	if err != nil {
+		return err
+	}
+	if k.Type != TypeList {
+		return fmt.Errorf("%s is not a list", k.Name)
+	}
+	v := make([]interface{}, len(items))
+	for i, item := range items {
+		v[i] = item
+	}
+	f.put(key, v)
+	return nil
+}
+
+// put stores an already validated value, creating parent objects as needed
+func (f *File) put(key string, v interface{}) {
+	node := f.data
+	parts := strings.Split(key, ".")
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := node[p].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			node[p] = child
+		}
+		node = child
+	}
+	node[parts[len(parts)-1]] = v
+}
+
+// Unset removes a key, pruning parents left empty
+func (f *File) Unset(key string) {
+	var remove func(node map[string]interface{}, parts []string)
+	remove = func(node map[string]interface{}, parts []string) {
+		if len(parts) == 1 {
+			delete(node, parts[0])
+			return
+		}
+		child, ok := node[parts[0]].(map[string]interface{})
+		if !ok {
+			return
+		}
+		remove(child, parts[1:])
+		if len(child) == 0 {
+			delete(node, parts[0])
+		}
+	}
+	remove(f.data, strings.Split(key, "."))
+}
+
+// Keys returns every key set in the file, sorted
+func (f *File) Keys() []string {
+	var keys []string
+	walk(f.data, "", func(key string, _ interface{}) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// String returns a string value, or "" if unset
+func (f *File) String(key string) string {
+	v, _ := f.Get(key)
+	s, _ := v.(string)
+	return s
+}
+
+// Bool returns a bool value, or false if unset
+func (f *File) Bool(key string) bool {
+	v, _ := f.Get(key)
+	b, _ := v.(bool)
+	return b
+}
+
+// Int returns an int value, or 0 if unset
+func (f *File) Int(key string) int {
+	v, _ := f.Get(key)
+	n, _ := v.(float64) // JSON numbers decode as float64
+	return int(n)
+}
+
+// Duration returns a duration value, or 0 if unset
+func (f *File) Duration(key string) time.Duration {
+	d, _ := time.ParseDuration(f.String(key))
+	return d
+}
+
+// List returns a list value, or nil if unset
+func (f *File) List(key string) []string {
+	v, _ := f.Get(key)
+	items, _ := v.([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// FormatValue renders a raw value for display
+func FormatValue(v interface{}) string {
+	switch t := v.(type) {
+	case []interface{}:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// parse converts a string value to the key's type
+func (k *Key) parse(value string) (interface{}, error) {
+	switch k.Type {
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects true or false", k.Name)
+		}
+		return b, nil
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects a whole number", k.Name)
+		}
+		return n, nil
+	case TypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("%s expects a duration such as 30s or 5m", k.Name)
+		}
+		return value, nil
+	case TypeList:
+		var items []interface{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		if len(k.Allowed) > 0 && !slices.Contains(k.Allowed, value) {
+			return nil, fmt.Errorf("%s must be one of: %s", k.Name, strings.Join(k.Allowed, ", "))
+		}
+		return value, nil
+	}
+}
+
+// check verifies a decoded JSON value matches the key's type
+func (k *Key) check(v interface{}) error {
+	switch k.Type {
+	case TypeBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s expects true or false", k.Name)
+		}
+	case TypeInt:
+		if n, ok := v.(float64); !ok || n != float64(int(n)) {
+			return fmt.Errorf("%s expects a whole number", k.Name)
+		}
+	case TypeFlag:
+		switch t := v.(type) {
+		case string, bool, float64:
+		case []interface{}:
+			for _, item := range t {
+				if _, ok := item.(string); !ok {
+					return fmt.Errorf("%s expects a string, boolean, number or list of strings", k.Name)
+				}
+			}
+		default:
+			return fmt.Errorf("%s expects a string, boolean, number or list of strings", k.Name)
+		}
+	case TypeList:
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s expects a list of strings", k.Name)
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("%s expects a list of strings", k.Name)
+			}
+		}
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s expects a string", k.Name)
+		}
+		_, err := k.parse(s)
+		return err
+	}
+	return nil
+}
+
+// validate checks every leaf against the schema
+func validate(data map[string]interface{}) error {
+	var errs []string
+	walk(data, "", func(key string, v interface{}) {
+		k, err := LookupKey(key)
+		if err != nil {
+			errs = append(errs, err.Error())
+			return
+		}
+		if err := k.check(v); err != nil {
+			errs = append(errs, err.Error())
+		}
+	})
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// walk visits every leaf value with its dotted key
+func walk(node map[string]interface{}, prefix string, fn func(key string, v interface{})) {
+	for name, v := range node {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if child, ok := v.(map[string]interface{}); ok {
+			walk(child, key, fn)
+			continue
+		}
+		fn(key, v)
+	}
+}
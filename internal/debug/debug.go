@@ -42,7 +42,7 @@ func LogResponse(resp *http.Response, requestID string, duration time.Duration)
 		return
 	}
 
-	Log("[%s] Response: %s (took %v)", requestID, resp.Status, duration)
+	Log("[%s] Response: %s %s (took %v)", requestID, resp.Proto, resp.Status, duration)
 
 	// Log rate limit headers if present
 	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
@@ -0,0 +1,49 @@
+// Package extract pulls readable text out of common attachment formats:
+// plain text and CSV, HTML, Word (.docx) documents and PDFs.
+//
+// PDF support is best effort. Text drawn with simple fonts comes out in
+// reading order; text in embedded or CID-keyed fonts, and scanned pages,
+// don't.
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupported is returned for formats text can't be extracted from
+var ErrUnsupported = errors.New("no text preview for this file type")
+
+// Text returns the text of a file, picking the format from its content
+// type, its name and its first bytes
+func Text(name, contentType string, data []byte) (string, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	contentType = strings.ToLower(contentType)
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return PDF(data)
+	case ext == ".docx" || strings.Contains(contentType, "wordprocessingml"):
+		return DOCX(data)
+	case ext == ".html" || ext == ".htm" || strings.HasPrefix(contentType, "text/html"):
+		return HTML(string(data)), nil
+	case isText(data):
+		return strings.ReplaceAll(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\r\n", "\n"), nil
+	}
+	return "", ErrUnsupported
+}
+
+// isText reports whether data looks like UTF-8 text rather than binary
+func isText(data []byte) bool {
+	sample := data
+	if len(sample) > 8192 {
+		sample = sample[:8192]
+		// Don't fail on a multi-byte character cut at the end of the sample
+		for i := 0; i < utf8.UTFMax && !utf8.Valid(sample); i++ {
+			sample = sample[:len(sample)-1]
+		}
+	}
+	return utf8.Valid(sample) && !bytes.ContainsRune(sample, 0)
+}
@@ -0,0 +1,74 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name, contentType string
+		data              string
+		want              string
+	}{
+		{name: "notes.txt", data: "\xef\xbb\xbfline 1\r\nline 2\n", want: "line 1\nline 2\n"},
+		{name: "data.csv", contentType: "text/csv", data: "a,b\n1,2\n", want: "a,b\n1,2\n"},
+		{name: "page.html", data: "<html><head><title>T</title></head><body><p>One&amp;two</p><ul><li>a</li><li>b</li></ul></body></html>", want: "One&two\n\n- a\n\n- b"},
+	}
+	for _, tt := range tests {
+		got, err := Text(tt.name, tt.contentType, []byte(tt.data))
+		if err != nil || got != tt.want {
+			t.Errorf("Text(%s) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := Text("logo.png", "image/png", []byte("\x89PNG\r\n\x1a\n\x00\x00")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("binary files should be unsupported, got %v", err)
+	}
+}
+
+func TestDOCX(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	fmt.Fprint(w, `<?xml version="1.0"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Master </w:t></w:r><w:r><w:t>Services</w:t></w:r></w:p>
+<w:p><w:r><w:t>Term:</w:t><w:tab/><w:t>12 months</w:t></w:r></w:p>
+</w:body></w:document>`)
+	zw.Close()
+
+	got, err := Text("contract.docx", "", buf.Bytes())
+	if want := "Master Services\nTerm:\t12 months\n"; err != nil || got != want {
+		t.Errorf("DOCX = %q, %v, want %q", got, err, want)
+	}
+	if _, err := DOCX([]byte("PK\x03\x04 not really")); err == nil {
+		t.Error("expected an error for a broken .docx")
+	}
+}
+
+func TestPDF(t *testing.T) {
+	page1 := "BT /F1 12 Tf 72 720 Td (Invoice \\(draft\\)) Tj 0 -14 Td [(Total:) -300 (42)] TJ ET"
+	var flate bytes.Buffer
+	zw := zlib.NewWriter(&flate)
+	zw.Write([]byte("BT 72 720 Td <5061676520322e> Tj ET"))
+	zw.Close()
+
+	pdf := "%PDF-1.4\n" +
+		fmt.Sprintf("4 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(page1), page1) +
+		fmt.Sprintf("5 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n", flate.Len(), flate.String()) +
+		"6 0 obj\n<< /Length 4 /Filter /DCTDecode >>\nstream\n\xff\xd8BT\nendstream\nendobj\n%%EOF\n"
+
+	got, err := Text("invoice.pdf", "application/pdf", []byte(pdf))
+	if want := "Invoice (draft)\nTotal: 42\n\nPage 2.\n"; err != nil || got != want {
+		t.Errorf("PDF = %q, %v, want %q", got, err, want)
+	}
+
+	if _, err := PDF([]byte("%PDF-1.4\n% no text\n")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("a PDF without text should be unsupported, got %v", err)
+	}
+}
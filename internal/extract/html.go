@@ -0,0 +1,35 @@
+package extract
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlHidden   = regexp.MustCompile(`(?is)<(script|style|head|title)\b.*?</(script|style|head|title)\s*>`)
+	htmlComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBreak    = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlBlockEnd = regexp.MustCompile(`(?i)</(p|div|li|tr|h[1-6]|blockquote|table)\s*>`)
+	htmlListItem = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlTag      = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRun     = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTML reduces an HTML document to readable text
+func HTML(s string) string {
+	s = htmlComment.ReplaceAllString(s, "")
+	s = htmlHidden.ReplaceAllString(s, "")
+	// Source line breaks aren't significant, but non-breaking spaces are
+	s = strings.Join(strings.Fields(strings.ReplaceAll(s, "\u00a0", "&nbsp;")), " ")
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlListItem.ReplaceAllString(s, "- ")
+	s = htmlBlockEnd.ReplaceAllString(s, "\n\n")
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.TrimSpace(blankRun.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,212 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	pdfStream = regexp.MustCompile(`stream\r?\n`)
+	pdfBlank  = regexp.MustCompile(`\n{3,}`)
+)
+
+// PDF returns the text shown by a PDF's page content streams
+func PDF(data []byte) (string, error) {
+	var b strings.Builder
+	for _, loc := range pdfStream.FindAllIndex(data, -1) {
+		if loc[0] > 0 && bytes.HasSuffix(data[:loc[0]], []byte("end")) {
+			continue // "endstream"
+		}
+		end := bytes.Index(data[loc[1]:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		content := data[loc[1] : loc[1]+end]
+
+		// The stream's dictionary is between the object header and "stream"
+		dict := data[:loc[0]]
+		if i := bytes.LastIndex(dict, []byte(" obj")); i >= 0 {
+			dict = dict[i:]
+		}
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			r, err := zlib.NewReader(bytes.NewReader(content))
+			if err != nil {
+				continue
+			}
+			content, err = io.ReadAll(r)
+			if err != nil && len(content) == 0 {
+				continue
+			}
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			continue // Images and other encodings carry no text
+		}
+		if bytes.Contains(content, []byte("BT")) {
+			pdfContentText(&b, content)
+		}
+	}
+
+	text := strings.TrimSpace(pdfBlank.ReplaceAllString(b.String(), "\n\n"))
+	if text == "" {
+		return "", ErrUnsupported
+	}
+	return text + "\n", nil
+}
+
+// pdfContentText writes the strings shown by text operators in a content
+// stream, starting a new line when the text position moves down the page
+func pdfContentText(b *strings.Builder, s []byte) {
+	var shown []string // String operands waiting for their operator
+	var nums []float64 // Numeric operands since the last operator
+	inArray := false
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '(':
+			str, n := pdfLiteral(s[i:])
+			shown = append(shown, str)
+			i += n
+			continue
+		case c == '<' && i+1 < len(s) && s[i+1] != '<':
+			end := bytes.IndexByte(s[i:], '>')
+			if end < 0 {
+				return
+			}
+			if str, ok := pdfHex(s[i+1 : i+end]); ok {
+				shown = append(shown, str)
+			}
+			i += end + 1
+			continue
+		case c == '[':
+			inArray = true
+		case c == ']':
+			inArray = false
+		case c == '%':
+			for i < len(s) && s[i] != '\n' && s[i] != '\r' {
+				i++
+			}
+			continue
+		case c == '-' || c == '.' || c == '+' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(s) && (s[j] == '.' || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			n, _ := strconv.ParseFloat(string(s[i:j]), 64)
+			if inArray && n < -200 {
+				shown = append(shown, " ") // A wide kern in a TJ array separates words
+			} else if !inArray {
+				nums = append(nums, n)
+			}
+			i = j
+			continue
+		case c == '\'' || c == '"' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || c == '*':
+			j := i + 1
+			for j < len(s) && ((s[j] >= 'A' && s[j] <= 'Z') || (s[j] >= 'a' && s[j] <= 'z') || s[j] == '*') {
+				j++
+			}
+			switch op := string(s[i:j]); op {
+			case "Tj", "TJ":
+				b.WriteString(strings.Join(shown, ""))
+			case "'", "\"":
+				b.WriteString("\n" + strings.Join(shown, ""))
+			case "T*", "ET":
+				b.WriteString("\n")
+			case "Td", "TD":
+				if len(nums) >= 2 && nums[len(nums)-1] != 0 {
+					b.WriteString("\n")
+				} else if len(nums) >= 2 && nums[len(nums)-2] > 0 {
+					b.WriteString(" ")
+				}
+			case "Tm":
+				b.WriteString("\n")
+			}
+			shown, nums = nil, nil
+			i = j
+			continue
+		}
+		i++
+	}
+}
+
+// pdfLiteral decodes a (string) with its escapes and nested parentheses,
+// returning the text and the number of bytes consumed
+func pdfLiteral(s []byte) (string, int) {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '(':
+			if depth > 0 {
+				b.WriteByte(c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return b.String(), i + 1
+			}
+			b.WriteByte(c)
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch e := s[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r', 'b', 'f':
+			case 't':
+				b.WriteByte('\t')
+			case '\r', '\n': // Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					j := i
+					for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+						j++
+					}
+					n, _ := strconv.ParseUint(string(s[i:j]), 8, 8)
+					b.WriteRune(rune(n)) // Latin-1 for simple fonts
+					i = j - 1
+				} else {
+					b.WriteByte(e)
+				}
+			}
+		default:
+			if c < 0x80 {
+				b.WriteByte(c)
+			} else {
+				b.WriteRune(rune(c))
+			}
+		}
+	}
+	return b.String(), len(s)
+}
+
+// pdfHex decodes a <hex> string when it holds single-byte text. Strings in
+// two-byte font encodings can't be mapped without the font and are skipped.
+func pdfHex(s []byte) (string, bool) {
+	h := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, string(s))
+	if len(h)%2 == 1 {
+		h += "0"
+	}
+	raw, err := hex.DecodeString(h)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	for _, c := range raw {
+		if c < 0x20 && c != '\t' && c != '\n' {
+			return "", false
+		}
+		b.WriteRune(rune(c))
+	}
+	return b.String(), true
+}
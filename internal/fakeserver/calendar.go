@@ -0,0 +1,588 @@
+package fakeserver
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// delegators have given the signed-in user delegated access to their
+// calendars; the fake server shows them the same sample data
+var delegators = []string{"morgan@example.com"}
+
+func (s *Server) serveCalendar(w http.ResponseWriter, r *http.Request, path string) {
+	if as := r.URL.Query().Get("onBehalfOf"); as != "" && !slices.Contains(delegators, strings.ToLower(as)) {
+		writeError(w, http.StatusForbidden, "ACCESS_DENIED", "No delegated access to the calendars of "+as)
+		return
+	}
+	switch {
+	case path == "calendars":
+		writeJSON(w, http.StatusOK, api.CalendarsResponse{Data: s.calendars})
+	case strings.HasPrefix(path, "calendars/") && strings.HasSuffix(path, "/shares"):
+		id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(path, "calendars/"), "/shares"), 10, 64)
+		if _, ok := s.shares[id]; err != nil || !ok {
+			notFound(w)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, api.CalendarSharesResponse{CalendarID: id, Shares: s.shares[id]})
+		case http.MethodPost:
+			s.shareCalendar(w, r, id)
+		default:
+			methodNotAllowed(w)
+		}
+	case path == "freebusy":
+		s.freeBusy(w, r)
+	case path == "events/by-contact":
+		s.eventsByContact(w, r)
+	case path == "events":
+		switch r.Method {
+		case http.MethodGet:
+			s.listEvents(w, r)
+		case http.MethodPost:
+			s.createEvent(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+	case strings.HasPrefix(path, "events/"):
+		id, action, _ := strings.Cut(strings.TrimPrefix(path, "events/"), "/")
+		i := s.findEvent(id)
+		if i < 0 {
+			notFound(w)
+			return
+		}
+		switch {
+		case action == "respond" && r.Method == http.MethodPost:
+			s.respondToEvent(w, r, i)
+		case action != "":
+			notFound(w)
+		case r.Method == http.MethodGet:
+			e := withoutExtended(s.events[i], strings.Split(r.URL.Query().Get("expand"), ","))
+			writeJSON(w, http.StatusOK, api.SingleEventResponse{Event: e, CurrentUserCalendarEmail: UserEmail})
+		case r.Method == http.MethodPatch:
+			s.updateEvent(w, r, i)
+		case r.Method == http.MethodDelete:
+			s.deleteEvent(w, r, i)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) shareCalendar(w http.ResponseWriter, r *http.Request, calendarID int64) {
+	var req api.ShareCalendarRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !strings.Contains(req.Email, "@") {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "A valid email is required")
+		return
+	}
+	if req.Role != "reader" && req.Role != "writer" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "Role must be reader or writer")
+		return
+	}
+
+	share := api.CalendarShare{Email: req.Email, Role: req.Role, Type: "user"}
+	shares := s.shares[calendarID]
+	for i, existing := range shares {
+		if strings.EqualFold(existing.Email, req.Email) {
+			if existing.Role == "owner" {
+				writeError(w, http.StatusConflict, "CONFLICT", "The calendar owner's access can't be changed")
+				return
+			}
+			shares[i] = share
+			writeJSON(w, http.StatusOK, share)
+			return
+		}
+	}
+	s.shares[calendarID] = append(shares, share)
+	writeJSON(w, http.StatusOK, share)
+}
+
+func (s *Server) findEvent(id string) int {
+	for i, e := range s.events {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, to := queryTime(r, "from"), queryTime(r, "to")
+	calendarID, _ := strconv.ParseInt(q.Get("calendarId"), 10, 64)
+	connectionID, _ := strconv.ParseInt(q.Get("connectionId"), 10, 64)
+	includeCancelled := q.Get("includeCancelled") == "true"
+	if connectionID > 0 && !s.hasConnection(connectionID) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Connection not found")
+		return
+	}
+
+	since, ok := s.readSyncToken(w, r, syncEvents)
+	if !ok {
+		return
+	}
+
+	var attendees []string
+	if a := q.Get("attendees"); a != "" {
+		attendees = strings.Split(a, ",")
+	}
+
+	var matched []api.Event
+	for _, e := range s.events {
+		if !s.changedSince(syncEvents, e.ID, since) {
+			continue
+		}
+		if !from.IsZero() && !e.EndUtc.After(from) || !to.IsZero() && !e.StartUtc.Before(to) {
+			continue
+		}
+		if calendarID > 0 && e.CalendarID != calendarID {
+			continue
+		}
+		if cal := s.findCalendar(e.CalendarID); connectionID > 0 && (cal == nil || cal.ConnectionID != connectionID) {
+			continue
+		}
+		if e.Status == "cancelled" && !includeCancelled {
+			continue
+		}
+		if text := q.Get("q"); text != "" && !containsFold(e.Title+" "+e.Description+" "+e.Location, text) {
+			continue
+		}
+		if len(attendees) > 0 && !hasAnyAttendee(e, attendees) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+
+	s.writeEvents(w, r, matched, from, to, true, s.deletedSince(syncEvents, since))
+}
+
+func (s *Server) eventsByContact(w http.ResponseWriter, r *http.Request) {
+	email, name := r.URL.Query().Get("email"), r.URL.Query().Get("name")
+	if email == "" && name == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "At least one of email or name is required")
+		return
+	}
+
+	var matched []api.Event
+	for _, e := range s.events {
+		for _, a := range e.Attendees {
+			if (email == "" || containsFold(a.Email, email)) && (name == "" || containsFold(a.Name, name)) {
+				matched = append(matched, e)
+				break
+			}
+		}
+	}
+
+	s.writeEvents(w, r, matched, time.Time{}, time.Time{}, false, nil)
+}
+
+// writeEvents sorts events by start and writes one page of them, starting
+// after the pageToken's event or at offset. When syncable, the last page
+// carries a sync token and the IDs of deleted events.
+func (s *Server) writeEvents(w http.ResponseWriter, r *http.Request, events []api.Event, from, to time.Time, syncable bool, deleted []string) {
+	sort.SliceStable(events, func(i, j int) bool { return eventBefore(events[i], events[j]) })
+
+	offset := queryInt(r, "offset", 0)
+	if token := r.URL.Query().Get("pageToken"); token != "" {
+		last, ok := decodeEventCursor(token)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "VALIDATION", "Invalid pageToken")
+			return
+		}
+		offset = sort.Search(len(events), func(i int) bool { return eventBefore(last, events[i]) })
+	}
+	start, end, hasMore := page(len(events), offset, queryInt(r, "limit", 50))
+	pageEvents := append([]api.Event{}, events[start:end]...)
+	for i := range pageEvents {
+		pageEvents[i] = withoutExtended(pageEvents[i], nil)
+	}
+
+	meta := &api.Meta{
+		Count:      len(pageEvents),
+		Offset:     offset,
+		HasMore:    hasMore,
+		TotalCount: len(events),
+		From:       from,
+		To:         to,
+		Timestamp:  s.now,
+	}
+	resp := api.EventsResponse{
+		RequestID:                s.newID("req_"),
+		Events:                   pageEvents,
+		Meta:                     meta,
+		CurrentUserCalendarEmail: UserEmail,
+	}
+	if hasMore {
+		meta.NextPageToken = encodeEventCursor(events[end-1])
+	} else if syncable {
+		meta.NextSyncToken = s.syncToken(syncEvents)
+		resp.DeletedIDs = deleted
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// eventBefore orders events by start, then ID, so a cursor names a unique
+// position
+func eventBefore(a, b api.Event) bool {
+	if !a.StartUtc.Equal(b.StartUtc) {
+		return a.StartUtc.Before(b.StartUtc)
+	}
+	return a.ID < b.ID
+}
+
+// encodeEventCursor makes a page token pointing just past e
+func encodeEventCursor(e api.Event) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(e.StartUtc.Format(time.RFC3339Nano) + "|" + e.ID))
+}
+
+// decodeEventCursor reads back the start and ID of the event a page token
+// points past
+func decodeEventCursor(token string) (api.Event, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return api.Event{}, false
+	}
+	start, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return api.Event{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, start)
+	if err != nil {
+		return api.Event{}, false
+	}
+	return api.Event{ID: id, StartUtc: t}, true
+}
+
+func (s *Server) createEvent(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateEventRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Summary) == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "summary is required")
+		return
+	}
+	if !req.To.After(req.From) {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "'to' must be after 'from'")
+		return
+	}
+	if msg := checkReminders(req.Reminders); msg != "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", msg)
+		return
+	}
+	cal := s.findCalendar(req.CalendarID)
+	if cal == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Calendar not found")
+		return
+	}
+	if req.ConnectionID != nil && *req.ConnectionID != cal.ConnectionID {
+		writeError(w, http.StatusBadRequest, "VALIDATION", fmt.Sprintf("Calendar %d doesn't belong to connection %d", cal.ID, *req.ConnectionID))
+		return
+	}
+
+	e := api.Event{
+		ID:           s.newID("evt_"),
+		CalendarID:   cal.ID,
+		Title:        req.Summary,
+		Description:  req.Description,
+		Location:     req.Location,
+		StartUtc:     req.From.UTC(),
+		EndUtc:       req.To.UTC(),
+		Status:       "confirmed",
+		AllDay:       req.IsAllDay,
+		Organizer:    UserEmail,
+		EventType:    req.EventType,
+		Transparency: req.Transparency,
+		UpdatedUtc:   s.now,
+		Reminders:    req.Reminders,
+	}
+	for _, a := range req.Attendees {
+		e.Attendees = append(e.Attendees, api.Attendee{Email: a, Response: "needsAction"})
+	}
+	e.IsRecurringEvent = len(req.Recurrence) > 0
+	e.Recurrence = req.Recurrence
+	if e.IsRecurringEvent {
+		// Instances aren't expanded; the event stands for its whole series
+		e.SeriesID = s.newID("ser_")
+	}
+	finishEvent(&e)
+
+	s.events = append(s.events, e)
+	s.changed(syncEvents, e.ID)
+	writeJSON(w, http.StatusCreated, e)
+}
+
+func (s *Server) updateEvent(w http.ResponseWriter, r *http.Request, i int) {
+	var req api.UpdateEventRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if msg := checkReminders(req.Reminders); msg != "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", msg)
+		return
+	}
+	targets, ok := s.seriesTargets(w, i, req.Scope)
+	if !ok {
+		return
+	}
+
+	// New times move every instance covered by as much as the one addressed
+	var startShift, endShift time.Duration
+	if req.From != nil {
+		startShift = req.From.Sub(s.events[i].StartUtc)
+	}
+	if req.To != nil {
+		endShift = req.To.Sub(s.events[i].EndUtc)
+	}
+	for _, j := range targets {
+		if e := s.events[j]; !e.EndUtc.Add(endShift).After(e.StartUtc.Add(startShift)) {
+			writeError(w, http.StatusBadRequest, "VALIDATION", "'to' must be after 'from'")
+			return
+		}
+	}
+
+	for _, j := range targets {
+		e := &s.events[j]
+		if req.Summary != "" {
+			e.Title = req.Summary
+		}
+		if req.Description != "" {
+			e.Description = req.Description
+		}
+		if req.Location != "" {
+			e.Location = req.Location
+		}
+		e.StartUtc = e.StartUtc.Add(startShift)
+		e.EndUtc = e.EndUtc.Add(endShift)
+		if req.IsAllDay != nil {
+			e.AllDay = *req.IsAllDay
+		}
+		for _, a := range req.AddAttendees {
+			if !hasAnyAttendee(*e, []string{a}) {
+				e.Attendees = append(e.Attendees, api.Attendee{Email: a, Response: "needsAction"})
+			}
+		}
+		for _, a := range req.RemoveAttendees {
+			kept := e.Attendees[:0]
+			for _, att := range e.Attendees {
+				if !strings.EqualFold(att.Email, a) {
+					kept = append(kept, att)
+				}
+			}
+			e.Attendees = kept
+		}
+		if req.Reminders != nil {
+			reminders := *req.Reminders
+			e.Reminders = &reminders
+		}
+		e.UpdatedUtc = s.now
+		finishEvent(e)
+		s.changed(syncEvents, e.ID)
+	}
+
+	writeJSON(w, http.StatusOK, s.events[i])
+}
+
+func (s *Server) deleteEvent(w http.ResponseWriter, r *http.Request, i int) {
+	targets, ok := s.seriesTargets(w, i, r.URL.Query().Get("scope"))
+	if !ok {
+		return
+	}
+	// Targets are in order, so removing from the end keeps the rest valid
+	for k := len(targets) - 1; k >= 0; k-- {
+		j := targets[k]
+		s.deleted(syncEvents, s.events[j].ID)
+		s.events = append(s.events[:j], s.events[j+1:]...)
+	}
+	message := "Event deleted"
+	if len(targets) > 1 {
+		message = fmt.Sprintf("%d events in the series deleted", len(targets))
+	}
+	writeJSON(w, http.StatusOK, api.DeleteEventResponse{Success: true, Message: message})
+}
+
+// seriesTargets returns the indexes of the events that a change to event i
+// covers under scope: just i by default, or instances of its series
+func (s *Server) seriesTargets(w http.ResponseWriter, i int, scope string) ([]int, bool) {
+	switch scope {
+	case "", api.ScopeThisInstance:
+		return []int{i}, true
+	case api.ScopeThisAndFollowing, api.ScopeEntireSeries:
+	default:
+		writeError(w, http.StatusBadRequest, "VALIDATION", "Invalid scope: "+scope)
+		return nil, false
+	}
+	e := s.events[i]
+	if e.SeriesID == "" {
+		writeError(w, http.StatusBadRequest, "NOT_RECURRING", "Event is not part of a recurring series")
+		return nil, false
+	}
+	var targets []int
+	for j, o := range s.events {
+		if o.SeriesID == e.SeriesID && (scope == api.ScopeEntireSeries || !o.StartUtc.Before(e.StartUtc)) {
+			targets = append(targets, j)
+		}
+	}
+	return targets, true
+}
+
+func (s *Server) respondToEvent(w http.ResponseWriter, r *http.Request, i int) {
+	var req api.RespondEventRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	switch req.Status {
+	case "accepted", "declined", "tentative":
+	default:
+		writeError(w, http.StatusBadRequest, "VALIDATION", "status must be accepted, declined or tentative")
+		return
+	}
+
+	e := &s.events[i]
+	found := false
+	for j := range e.Attendees {
+		if strings.EqualFold(e.Attendees[j].Email, UserEmail) {
+			e.Attendees[j].Response = req.Status
+			e.Attendees[j].Comment = req.Comment
+			found = true
+		}
+	}
+	if !found {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "You are not an attendee of this event")
+		return
+	}
+	s.changed(syncEvents, e.ID)
+
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) freeBusy(w http.ResponseWriter, r *http.Request) {
+	from, to := queryTime(r, "from"), queryTime(r, "to")
+	if from.IsZero() || to.IsZero() {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "from and to are required")
+		return
+	}
+
+	wanted := map[int64]bool{}
+	for _, id := range strings.Split(r.URL.Query().Get("calendars"), ",") {
+		if n, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64); err == nil {
+			wanted[n] = true
+		}
+	}
+
+	resp := api.FreeBusyResponse{}
+	for _, cal := range s.calendars {
+		if len(wanted) > 0 && !wanted[cal.ID] {
+			continue
+		}
+		fb := api.FreeBusyCalendar{CalendarID: cal.ID, CalendarName: cal.Name, Busy: []api.BusyPeriod{}}
+		for _, e := range s.events {
+			if e.CalendarID != cal.ID || e.Status == "cancelled" || e.AllDay {
+				continue
+			}
+			if !e.EndUtc.After(from) || !e.StartUtc.Before(to) {
+				continue
+			}
+			fb.Busy = append(fb.Busy, api.BusyPeriod{StartUtc: e.StartUtc, EndUtc: e.EndUtc, DurationMinutes: e.DurationMinutes})
+		}
+		sort.Slice(fb.Busy, func(i, j int) bool { return fb.Busy[i].StartUtc.Before(fb.Busy[j].StartUtc) })
+		resp.Calendars = append(resp.Calendars, fb)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) findCalendar(id int64) *api.Calendar {
+	for i := range s.calendars {
+		if s.calendars[i].ID == id {
+			return &s.calendars[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) hasConnection(id int64) bool {
+	for _, c := range s.calendars {
+		if c.ConnectionID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyAttendee(e api.Event, emails []string) bool {
+	for _, a := range e.Attendees {
+		for _, want := range emails {
+			if strings.EqualFold(a.Email, strings.TrimSpace(want)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// finishEvent fills the derived and alias fields the real API returns
+// withoutExtended drops the extended fields not named in expand, as
+// providers that return them only on request do
+func withoutExtended(e api.Event, expand []string) api.Event {
+	if !slices.Contains(expand, api.ExpandRecurrence) {
+		e.Recurrence = nil
+	}
+	if !slices.Contains(expand, api.ExpandAttachments) {
+		e.Attachments = nil
+	}
+	return e
+}
+
+func finishEvent(e *api.Event) {
+	e.Summary = e.Title
+	e.IsAllDay = e.AllDay
+	e.DurationMinutes = int(e.EndUtc.Sub(e.StartUtc).Minutes())
+	e.StartLocal = e.StartUtc.Format("2006-01-02T15:04:05")
+	e.EndLocal = e.EndUtc.Format("2006-01-02T15:04:05")
+	if e.Reminders == nil {
+		e.Reminders = &api.EventReminders{UseDefault: true}
+	}
+	for i := range e.Attendees {
+		e.Attendees[i].DisplayName = e.Attendees[i].Name
+		e.Attendees[i].ResponseStatus = e.Attendees[i].Response
+	}
+}
+
+// checkReminders validates reminders as Google Calendar does: at most five,
+// each popup or email and no more than four weeks ahead. It returns the
+// problem, or "" if there is none.
+func checkReminders(r *api.EventReminders) string {
+	if r == nil {
+		return ""
+	}
+	if r.UseDefault && len(r.Overrides) > 0 {
+		return "reminder overrides cannot be combined with useDefault"
+	}
+	if len(r.Overrides) > 5 {
+		return "an event can have at most 5 reminders"
+	}
+	for _, o := range r.Overrides {
+		if o.Method != api.ReminderPopup && o.Method != api.ReminderEmail {
+			return fmt.Sprintf("unknown reminder method %q", o.Method)
+		}
+		if o.Minutes < 0 || o.Minutes > 40320 {
+			return "reminders must be between 0 and 40320 minutes before the event"
+		}
+	}
+	return ""
+}
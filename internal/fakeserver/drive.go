@@ -0,0 +1,325 @@
+package fakeserver
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+const (
+	mimeFolder = "application/vnd.google-apps.folder"
+	mimeDoc    = "application/vnd.google-apps.document"
+	mimeSheet  = "application/vnd.google-apps.spreadsheet"
+)
+
+func (s *Server) serveDrive(w http.ResponseWriter, r *http.Request, path string) {
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "files" && r.Method == http.MethodGet:
+		s.listFiles(w, r)
+	case path == "files/upload" && r.Method == http.MethodPost:
+		s.uploadFile(w, r)
+	case path == "folders" && r.Method == http.MethodPost:
+		s.createFolder(w, r)
+	case parts[0] == "files" && len(parts) >= 2:
+		i := s.findFile(parts[1])
+		if i < 0 {
+			notFound(w)
+			return
+		}
+		action := strings.Join(parts[2:], "/")
+		s.serveFile(w, r, i, action)
+	case parts[0] == "docs" && len(parts) == 3:
+		s.serveDoc(w, r, parts[1], parts[2])
+	case parts[0] == "sheets" && len(parts) >= 2:
+		s.serveSheet(w, r, parts[1], strings.Join(parts[2:], "/"))
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) findFile(id string) int {
+	for i, f := range s.files {
+		if f.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) listFiles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("trashedOnly") == "true" {
+		writeJSON(w, http.StatusOK, api.DriveFilesResponse{Files: []api.DriveFile{}})
+		return
+	}
+
+	var matched []api.DriveFile
+	for _, f := range s.files {
+		name := *f.Name
+		if v := q.Get("q"); v != "" && !containsFold(name+" "+derefStr(f.Description), v) {
+			continue
+		}
+		if v := q.Get("name"); v != "" && !containsFold(name, v) {
+			continue
+		}
+		if v := q.Get("folderId"); v != "" && derefStr(f.ParentFolderID) != v {
+			continue
+		}
+		if v := q.Get("mimeType"); v != "" && derefStr(f.MimeType) != v {
+			continue
+		}
+		if q.Get("sharedWithMe") == "true" && len(f.SharedWith) == 0 {
+			continue
+		}
+		if v := q.Get("modifiedAfter"); v != "" && derefStr(f.ModifiedTime) < v {
+			continue
+		}
+		if v := q.Get("modifiedBefore"); v != "" && derefStr(f.ModifiedTime) >= v {
+			continue
+		}
+		matched = append(matched, f)
+	}
+
+	switch q.Get("orderBy") {
+	case "name":
+		sort.SliceStable(matched, func(i, j int) bool { return *matched[i].Name < *matched[j].Name })
+	default:
+		sort.SliceStable(matched, func(i, j int) bool {
+			return derefStr(matched[i].ModifiedTime) > derefStr(matched[j].ModifiedTime)
+		})
+	}
+
+	offset := queryInt(r, "pageToken", 0)
+	start, end, hasMore := page(len(matched), offset, queryInt(r, "limit", 25))
+	resp := api.DriveFilesResponse{Files: append([]api.DriveFile{}, matched[start:end]...), HasMore: hasMore}
+	if hasMore {
+		resp.NextPageToken = strPtr(strconv.Itoa(end))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, i int, action string) {
+	f := &s.files[i]
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, api.SingleDriveFileResponse{File: f})
+	case action == "" && r.Method == http.MethodDelete:
+		s.files = append(s.files[:i], s.files[i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	case action == "download":
+		writeJSON(w, http.StatusOK, api.DriveFileLinkResponse{
+			Success:               true,
+			WebViewLink:           f.WebViewLink,
+			DownloadUrl:           f.DownloadLink,
+			FileName:              f.Name,
+			MimeType:              f.MimeType,
+			Size:                  f.Size,
+			IsGoogleWorkspaceFile: strings.HasPrefix(derefStr(f.MimeType), "application/vnd.google-apps."),
+		})
+	case action == "permissions":
+		resp := api.DrivePermissionsResponse{}
+		for n, u := range append(append([]api.DriveUser{}, f.Owners...), f.SharedWith...) {
+			resp.Permissions = append(resp.Permissions, api.DrivePermission{
+				ID:           "perm_" + strconv.Itoa(n+1),
+				Type:         "user",
+				Role:         derefStr(u.Role),
+				EmailAddress: strPtr(u.Email),
+				DisplayName:  u.DisplayName,
+			})
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case action == "rename":
+		var req api.RenameFileRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if strings.TrimSpace(req.NewName) == "" {
+			writeError(w, http.StatusBadRequest, "VALIDATION", "newName is required")
+			return
+		}
+		f.Name = strPtr(req.NewName)
+		s.touch(f)
+		writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(f.ID)})
+	case action == "move":
+		var req api.MoveFileRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		dest := s.findFile(req.DestinationFolderID)
+		if dest < 0 || !s.files[dest].IsFolder {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Destination folder not found")
+			return
+		}
+		f.ParentFolderID, f.ParentFolderName = strPtr(s.files[dest].ID), s.files[dest].Name
+		s.touch(f)
+		writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(f.ID)})
+	case action == "share":
+		var req api.ShareFileRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if req.EmailAddress != nil {
+			f.SharedWith = append(f.SharedWith, api.DriveUser{Email: *req.EmailAddress, Role: strPtr(req.Role)})
+		}
+		writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(f.ID)})
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) uploadFile(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("fileName") == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "fileName is required")
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+
+	mimeType := q.Get("mimeType")
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	f := s.newFile(q.Get("fileName"), mimeType, int64(len(body)), q.Get("folderId"))
+	if d := q.Get("description"); d != "" {
+		f.Description = strPtr(d)
+	}
+	s.files = append(s.files, f)
+	writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(f.ID)})
+}
+
+func (s *Server) createFolder(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateFolderRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "name is required")
+		return
+	}
+
+	f := s.newFile(req.Name, mimeFolder, 0, derefStr(req.ParentFolderID))
+	f.Description = req.Description
+	s.files = append(s.files, f)
+	writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(f.ID)})
+}
+
+func (s *Server) serveDoc(w http.ResponseWriter, r *http.Request, id, action string) {
+	text, ok := s.docs[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	switch {
+	case action == "content" && r.Method == http.MethodGet:
+		i := s.findFile(id)
+		writeJSON(w, http.StatusOK, api.DocContentResponse{PlainText: strPtr(text), Title: s.files[i].Name})
+	case action == "edit" && r.Method == http.MethodPost:
+		var req api.EditDocRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		for _, op := range req.Operations {
+			switch op.Type {
+			case "append":
+				text += derefStr(op.Text)
+			case "insert":
+				at := 0
+				if op.Index != nil && *op.Index <= len(text) {
+					at = *op.Index
+				}
+				text = text[:at] + derefStr(op.Text) + text[at:]
+			case "replace":
+				text = strings.ReplaceAll(text, derefStr(op.Find), derefStr(op.Replace))
+			default:
+				writeError(w, http.StatusBadRequest, "VALIDATION", "unknown operation type: "+op.Type)
+				return
+			}
+		}
+		s.docs[id] = text
+		writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(id)})
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) serveSheet(w http.ResponseWriter, r *http.Request, id, action string) {
+	values, ok := s.sheets[id]
+	if !ok {
+		notFound(w)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		cols := 0
+		for _, row := range values {
+			cols = max(cols, len(row))
+		}
+		writeJSON(w, http.StatusOK, api.SheetMetadataResponse{
+			SpreadsheetID: id,
+			Title:         s.files[s.findFile(id)].Name,
+			Sheets:        []api.SheetTabInfo{{SheetID: 0, Title: "Sheet1", RowCount: len(values), ColumnCount: cols}},
+		})
+	case action == "values" && r.Method == http.MethodGet:
+		// Ranges aren't interpreted; the whole first sheet is returned
+		writeJSON(w, http.StatusOK, api.SheetValuesResponse{Range: r.URL.Query().Get("range"), Values: values})
+	case action == "values" && r.Method == http.MethodPut:
+		var req api.WriteSheetValuesRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		s.sheets[id] = req.Values
+		writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(id)})
+	case action == "values:append" && r.Method == http.MethodPost:
+		var req api.AppendSheetRowsRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		s.sheets[id] = append(values, req.Values...)
+		writeJSON(w, http.StatusOK, api.DriveOperationResult{Success: true, FileID: strPtr(id)})
+	default:
+		notFound(w)
+	}
+}
+
+func (s *Server) newFile(name, mimeType string, size int64, parentID string) api.DriveFile {
+	id := s.newID("file_")
+	f := api.DriveFile{
+		ID:           id,
+		Name:         strPtr(name),
+		MimeType:     strPtr(mimeType),
+		CreatedTime:  strPtr(s.now.Format("2006-01-02T15:04:05Z")),
+		ModifiedTime: strPtr(s.now.Format("2006-01-02T15:04:05Z")),
+		Owners:       []api.DriveUser{{Email: UserEmail, DisplayName: strPtr("Alex Rivera"), Role: strPtr("owner")}},
+		WebViewLink:  strPtr("https://drive.example.com/file/" + id),
+		IsFolder:     mimeType == mimeFolder,
+		Provider:     "google",
+	}
+	if !f.IsFolder {
+		f.Size = &size
+		f.DownloadLink = strPtr("https://drive.example.com/download/" + id)
+	}
+	if parentID != "" {
+		if p := s.findFile(parentID); p >= 0 {
+			f.ParentFolderID, f.ParentFolderName = strPtr(parentID), s.files[p].Name
+		}
+	}
+	return f
+}
+
+func (s *Server) touch(f *api.DriveFile) {
+	f.ModifiedTime = strPtr(s.now.Format("2006-01-02T15:04:05Z"))
+}
+
+func derefStr(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
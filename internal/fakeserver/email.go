@@ -0,0 +1,432 @@
+package fakeserver
+
+import (
+	"mime"
+	"net/http"
+	"net/mail"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// sharedMailboxes are the team mailboxes connected to the demo account
+var sharedMailboxes = []string{support.Email}
+
+func (s *Server) serveEmail(w http.ResponseWriter, r *http.Request, path string) {
+	switch {
+	case path == "messages":
+		s.listEmails(w, r)
+	case path == "messages/send" && r.Method == http.MethodPost:
+		s.sendEmail(w, r)
+	case path == "auto-reply":
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.autoReply)
+		case http.MethodPut:
+			var req api.AutoReplySettings
+			if !decodeBody(w, r, &req) {
+				return
+			}
+			if req.Enabled && strings.TrimSpace(req.Message) == "" {
+				writeError(w, http.StatusBadRequest, "VALIDATION", "message is required")
+				return
+			}
+			s.autoReply = req
+			writeJSON(w, http.StatusOK, s.autoReply)
+		default:
+			methodNotAllowed(w)
+		}
+	case strings.HasPrefix(path, "threads/"):
+		s.getThread(w, r, strings.TrimPrefix(path, "threads/"))
+	case strings.HasPrefix(path, "messages/"):
+		parts := strings.Split(strings.TrimPrefix(path, "messages/"), "/")
+		i := s.findEmail(parts[0])
+		if i < 0 {
+			notFound(w)
+			return
+		}
+		switch {
+		case len(parts) == 3 && parts[1] == "attachments":
+			s.getAttachment(w, parts[0], parts[2])
+		case len(parts) == 2 && parts[1] == "raw" && r.Method == http.MethodGet:
+			s.rawEmail(w, i)
+		case len(parts) == 2 && parts[1] == "reply" && r.Method == http.MethodPost:
+			s.replyToEmail(w, r, i)
+		case len(parts) == 2 && parts[1] == "forward" && r.Method == http.MethodPost:
+			s.forwardEmail(w, r, i)
+		case len(parts) > 1:
+			notFound(w)
+		case r.Method == http.MethodGet:
+			email := s.emails[i]
+			if r.URL.Query().Get("includeBody") == "false" {
+				email.Body = ""
+			}
+			writeJSON(w, http.StatusOK, api.SingleEmailResponse{Email: email})
+		case r.Method == http.MethodPatch:
+			s.modifyEmail(w, r, i)
+		case r.Method == http.MethodDelete:
+			s.deleted(syncEmails, parts[0])
+			s.emails = append(s.emails[:i], s.emails[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		notFound(w)
+	}
+}
+
+// rawEmail writes an email as an RFC 822 message with the headers a
+// receiving server would add
+func (s *Server) rawEmail(w http.ResponseWriter, i int) {
+	e := s.emails[i]
+	from := UserEmail
+	if e.From != nil {
+		from = e.From.Email
+	}
+	domain := from[strings.LastIndex(from, "@")+1:]
+	date := e.ReceivedAt.UTC()
+	var b strings.Builder
+	header := func(name, value string) {
+		if value != "" {
+			b.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+	header("Received", "by mx.porteden.example with SMTP id "+e.ID+";\r\n        "+date.Format(time.RFC1123Z))
+	header("Received", "from mail."+domain+" (mail."+domain+" [192.0.2.25])\r\n        by mx.porteden.example with ESMTPS; "+date.Add(-2*time.Second).Format(time.RFC1123Z))
+	header("Authentication-Results", "mx.porteden.example;\r\n       spf=pass smtp.mailfrom="+from+";\r\n       dkim=pass header.d="+domain+";\r\n       dmarc=pass header.from="+domain)
+	header("Received-SPF", "pass (mx.porteden.example: domain of "+from+" designates 192.0.2.25 as permitted sender)")
+	header("DKIM-Signature", "v=1; a=rsa-sha256; c=relaxed/relaxed; d="+domain+"; s=mail;\r\n        h=from:to:subject:date:message-id; bh=47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	header("Message-ID", "<"+e.ID+"@"+domain+">")
+	header("Date", date.Format(time.RFC1123Z))
+	if e.From != nil {
+		header("From", (&mail.Address{Name: e.From.Name, Address: e.From.Email}).String())
+	}
+	addresses := func(ps []api.Participant) string {
+		var list []string
+		for _, p := range ps {
+			list = append(list, (&mail.Address{Name: p.Name, Address: p.Email}).String())
+		}
+		return strings.Join(list, ", ")
+	}
+	header("To", addresses(e.To))
+	header("Cc", addresses(e.CC))
+	header("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	b.WriteString("\r\n" + e.Body + "\r\n")
+
+	w.Header().Set("Content-Type", "message/rfc822")
+	w.Write([]byte(b.String()))
+}
+
+func (s *Server) findEmail(id string) int {
+	for i, e := range s.emails {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) listEmails(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	mailbox := strings.ToLower(q.Get("mailbox"))
+	if !checkMailbox(w, mailbox) {
+		return
+	}
+	after, before := queryTime(r, "after"), queryTime(r, "before")
+	since, ok := s.readSyncToken(w, r, syncEmails)
+	if !ok {
+		return
+	}
+
+	var matched []api.Email
+	for _, e := range s.emails {
+		if mailboxOf(e) != mailbox || !s.changedSince(syncEmails, e.ID, since) {
+			continue
+		}
+		from := ""
+		if e.From != nil {
+			from = e.From.Name + " " + e.From.Email
+		}
+		if text := q.Get("q"); text != "" && !containsFold(e.Subject+" "+e.Body+" "+from, text) {
+			continue
+		}
+		if v := q.Get("from"); v != "" && !containsFold(from, v) {
+			continue
+		}
+		if v := q.Get("to"); v != "" && !participantsMatch(e.To, v) {
+			continue
+		}
+		if v := q.Get("subject"); v != "" && !containsFold(e.Subject, v) {
+			continue
+		}
+		if v := q.Get("label"); v != "" && !hasLabel(e.Labels, v) {
+			continue
+		}
+		if v, err := strconv.ParseBool(q.Get("unread")); err == nil && e.IsRead == v {
+			continue
+		}
+		if v, err := strconv.ParseBool(q.Get("hasAttachment")); err == nil && e.HasAttachments != v {
+			continue
+		}
+		if !after.IsZero() && e.ReceivedAt.Before(after) || !before.IsZero() && !e.ReceivedAt.Before(before) {
+			continue
+		}
+		if q.Get("includeBody") != "true" {
+			e.Body = ""
+		}
+		matched = append(matched, e)
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].ReceivedAt.After(matched[j].ReceivedAt) })
+
+	// Page tokens are plain offsets; clients must treat them as opaque
+	offset := queryInt(r, "pageToken", 0)
+	start, end, hasMore := page(len(matched), offset, queryInt(r, "limit", 20))
+	resp := api.EmailsResponse{
+		Emails:     append([]api.Email{}, matched[start:end]...),
+		TotalCount: len(matched),
+		HasMore:    hasMore,
+	}
+	if hasMore {
+		resp.NextPageToken = strconv.Itoa(end)
+	} else {
+		resp.NextSyncToken = s.syncToken(syncEmails)
+		resp.DeletedIDs = s.deletedSince(syncEmails, since)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) getThread(w http.ResponseWriter, r *http.Request, id string) {
+	resp := api.ThreadResponse{ID: id, Provider: "google"}
+	includeBodies := r.URL.Query().Get("includeBody") != "false"
+	seen := map[string]bool{}
+	for _, e := range s.emails {
+		if e.ThreadID != id {
+			continue
+		}
+		if !includeBodies {
+			e.Body = ""
+		}
+		resp.Messages = append(resp.Messages, e)
+		for _, p := range append([]api.Participant{*e.From}, e.To...) {
+			if !seen[p.Email] {
+				seen[p.Email] = true
+				resp.Participants = append(resp.Participants, p)
+			}
+		}
+	}
+	if len(resp.Messages) == 0 {
+		notFound(w)
+		return
+	}
+	sort.SliceStable(resp.Messages, func(i, j int) bool { return resp.Messages[i].ReceivedAt.Before(resp.Messages[j].ReceivedAt) })
+
+	resp.Subject = resp.Messages[0].Subject
+	resp.MessageCount = len(resp.Messages)
+	resp.LastMessageAt = resp.Messages[len(resp.Messages)-1].ReceivedAt
+	writeJSON(w, http.StatusOK, map[string]interface{}{"thread": resp})
+}
+
+func (s *Server) getAttachment(w http.ResponseWriter, emailID, attID string) {
+	data, ok := s.attachments[emailID+"/"+attID]
+	if !ok {
+		notFound(w)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) sendEmail(w http.ResponseWriter, r *http.Request) {
+	var req api.SendEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.To) == 0 {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "At least one recipient is required")
+		return
+	}
+	if !checkMailbox(w, req.Mailbox) {
+		return
+	}
+
+	e := s.outgoing(s.newID("thr_"), req.Subject, req.Body, req.BodyType, req.To)
+	e.CC, e.BCC = req.CC, req.BCC
+	sendFrom(&e, req.Mailbox)
+	s.emails = append(s.emails, e)
+	s.changed(syncEmails, e.ID)
+	writeJSON(w, http.StatusOK, api.EmailActionResponse{Success: true, EmailID: e.ID, ThreadID: e.ThreadID})
+}
+
+func (s *Server) replyToEmail(w http.ResponseWriter, r *http.Request, i int) {
+	var req api.ReplyEmailRequest
+	if !decodeBody(w, r, &req) || !checkMailbox(w, req.Mailbox) {
+		return
+	}
+
+	orig := s.emails[i]
+	to := []api.Participant{*orig.From}
+	if req.ReplyAll {
+		for _, p := range append(orig.To, orig.CC...) {
+			if !strings.EqualFold(p.Email, UserEmail) && !strings.EqualFold(p.Email, req.Mailbox) {
+				to = append(to, p)
+			}
+		}
+	}
+
+	e := s.outgoing(orig.ThreadID, prefixSubject("Re: ", orig.Subject), req.Body, req.BodyType, to)
+	e.CC, e.BCC = req.CC, req.BCC
+	e.InReplyTo = orig.ID
+	sendFrom(&e, req.Mailbox)
+	s.emails = append(s.emails, e)
+	s.changed(syncEmails, e.ID)
+	writeJSON(w, http.StatusOK, api.EmailActionResponse{Success: true, EmailID: e.ID, ThreadID: e.ThreadID})
+}
+
+func (s *Server) forwardEmail(w http.ResponseWriter, r *http.Request, i int) {
+	var req api.ForwardEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.To) == 0 {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "At least one recipient is required")
+		return
+	}
+	if !checkMailbox(w, req.Mailbox) {
+		return
+	}
+
+	orig := s.emails[i]
+	body := req.Body + "\n\n---------- Forwarded message ----------\n" + orig.Body
+	e := s.outgoing(s.newID("thr_"), prefixSubject("Fwd: ", orig.Subject), body, req.BodyType, req.To)
+	e.CC, e.BCC = req.CC, req.BCC
+	e.Attachments, e.HasAttachments = orig.Attachments, orig.HasAttachments
+	sendFrom(&e, req.Mailbox)
+	s.emails = append(s.emails, e)
+	s.changed(syncEmails, e.ID)
+	writeJSON(w, http.StatusOK, api.EmailActionResponse{Success: true, EmailID: e.ID, ThreadID: e.ThreadID})
+}
+
+func (s *Server) modifyEmail(w http.ResponseWriter, r *http.Request, i int) {
+	var req api.ModifyEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	e := &s.emails[i]
+	if req.MarkAsRead != nil {
+		e.IsRead = *req.MarkAsRead
+	}
+	for _, l := range req.AddLabels {
+		if !hasLabel(e.Labels, l) {
+			e.Labels = append(e.Labels, l)
+		}
+	}
+	for _, l := range req.RemoveLabels {
+		kept := e.Labels[:0]
+		for _, existing := range e.Labels {
+			if !strings.EqualFold(existing, l) {
+				kept = append(kept, existing)
+			}
+		}
+		e.Labels = kept
+	}
+	s.changed(syncEmails, e.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// outgoing builds a sent message from the signed-in user
+func (s *Server) outgoing(threadID, subject, body, bodyType string, to []api.Participant) api.Email {
+	if bodyType == "" {
+		bodyType = "text"
+	}
+	return api.Email{
+		ID:          s.newID("msg_"),
+		ThreadID:    threadID,
+		Subject:     subject,
+		From:        &api.Participant{Email: UserEmail, Name: "Alex Rivera"},
+		To:          to,
+		Body:        body,
+		BodyType:    bodyType,
+		BodyPreview: preview(body),
+		SentAt:      s.now,
+		ReceivedAt:  s.now,
+		IsRead:      true,
+		Labels:      []string{"SENT"},
+		Provider:    "google",
+	}
+}
+
+// checkMailbox writes a 403 unless mailbox is empty (the account's own
+// mailbox) or one of the shared mailboxes
+func checkMailbox(w http.ResponseWriter, mailbox string) bool {
+	if mailbox != "" && !slices.Contains(sharedMailboxes, strings.ToLower(mailbox)) {
+		writeError(w, http.StatusForbidden, "ACCESS_DENIED", "No access to the mailbox "+mailbox)
+		return false
+	}
+	return true
+}
+
+// mailboxOf returns the shared mailbox an email was sent from or to, or ""
+// for the account's own mailbox
+func mailboxOf(e api.Email) string {
+	people := append(append([]api.Participant{}, e.To...), e.CC...)
+	if e.From != nil {
+		people = append(people, *e.From)
+	}
+	for _, p := range people {
+		if slices.Contains(sharedMailboxes, strings.ToLower(p.Email)) {
+			return strings.ToLower(p.Email)
+		}
+	}
+	return ""
+}
+
+// sendFrom makes an outgoing email come from a shared mailbox
+func sendFrom(e *api.Email, mailbox string) {
+	if mailbox != "" {
+		e.From = &api.Participant{Email: strings.ToLower(mailbox), Name: support.Name}
+	}
+}
+
+func prefixSubject(prefix, subject string) string {
+	if strings.HasPrefix(strings.ToLower(subject), strings.ToLower(prefix)) {
+		return subject
+	}
+	return prefix + subject
+}
+
+// preview collapses whitespace and cuts body to 120 characters, on a rune
+// boundary so a multi-byte character is never split
+func preview(body string) string {
+	r := []rune(strings.Join(strings.Fields(body), " "))
+	if len(r) > 120 {
+		return string(r[:117]) + "..."
+	}
+	return string(r)
+}
+
+func participantsMatch(ps []api.Participant, substr string) bool {
+	for _, p := range ps {
+		if containsFold(p.Name+" "+p.Email, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+package fakeserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// Sample people used across events, emails and drive files
+var (
+	alex    = api.Participant{Email: UserEmail, Name: "Alex Rivera"}
+	priya   = api.Participant{Email: "priya.shah@example.com", Name: "Priya Shah"}
+	sam     = api.Participant{Email: "sam.okafor@example.com", Name: "Sam Okafor"}
+	jordan  = api.Participant{Email: "jordan.lee@acme.example", Name: "Jordan Lee"}
+	billing = api.Participant{Email: "billing@vendor.example", Name: "Vendor Billing"}
+	digest  = api.Participant{Email: "news@weekly.example", Name: "The Weekly"}
+	support = api.Participant{Email: "support@example.com", Name: "Support"}
+	casey   = api.Participant{Email: "casey@customer.example", Name: "Casey Moreno"}
+)
+
+// Calendar IDs of the seeded calendars
+const (
+	WorkCalendarID     int64 = 1001
+	PersonalCalendarID int64 = 1002
+)
+
+// IDs of the connected accounts the seeded calendars belong to
+const (
+	WorkConnectionID     int64 = 7
+	PersonalConnectionID int64 = 8
+)
+
+func (s *Server) seed() {
+	s.calendars = []api.Calendar{
+		{ID: WorkCalendarID, ExternalID: "alex@example.com", Name: "Work", Provider: "google", Timezone: "UTC", IsPrimary: true, IsOperatorOwner: true, OwnerEmail: UserEmail, ConnectionID: WorkConnectionID, LastSyncedAt: s.now},
+		{ID: PersonalCalendarID, ExternalID: "personal-alex", Name: "Personal", Provider: "google", Timezone: "UTC", OwnerEmail: UserEmail, ConnectionID: PersonalConnectionID, LastSyncedAt: s.now},
+	}
+	s.shares = map[int64][]api.CalendarShare{
+		WorkCalendarID:     {{Email: UserEmail, Role: "owner", Type: "user"}, {Email: priya.Email, Role: "reader", Type: "user"}},
+		PersonalCalendarID: {{Email: UserEmail, Role: "owner", Type: "user"}},
+	}
+	s.seedEvents()
+	s.seedEmails()
+	s.seedDrive()
+}
+
+func attendee(p api.Participant, response string) api.Attendee {
+	return api.Attendee{Email: p.Email, Name: p.Name, Response: response}
+}
+
+func (s *Server) addEvent(e api.Event) {
+	e.ID = s.newID("evt_")
+	if e.Status == "" {
+		e.Status = "confirmed"
+	}
+	if e.Organizer == "" {
+		e.Organizer = UserEmail
+	}
+	if e.UpdatedUtc.IsZero() {
+		e.UpdatedUtc = s.now.AddDate(0, 0, -1)
+	}
+	finishEvent(&e)
+	s.events = append(s.events, e)
+}
+
+func (s *Server) seedEvents() {
+	s.addEvent(api.Event{
+		CalendarID:  WorkCalendarID,
+		Title:       "1:1 Priya / Alex",
+		Description: "Weekly check-in. Agenda in the shared doc.",
+		StartUtc:    s.at(0, 14, 0),
+		EndUtc:      s.at(0, 14, 30),
+		Attendees:   []api.Attendee{attendee(alex, "accepted"), attendee(priya, "accepted")},
+		Organizer:   priya.Email,
+	})
+	s.addEvent(api.Event{
+		CalendarID:  WorkCalendarID,
+		Title:       "Quarterly planning meeting",
+		Description: "Review Q3 goals and agree on the roadmap.",
+		Location:    "Room 4B",
+		StartUtc:    s.at(1, 13, 0),
+		EndUtc:      s.at(1, 15, 0),
+		Attendees:   []api.Attendee{attendee(alex, "accepted"), attendee(priya, "tentative"), attendee(sam, "accepted"), attendee(jordan, "needsAction")},
+		Labels:      []string{"planning"},
+		Attachments: []api.EventAttachment{{Title: "Q3 roadmap", URL: "https://docs.example.com/d/roadmap-q3", MimeType: "application/vnd.google-apps.document"}},
+	})
+	s.addEvent(api.Event{
+		CalendarID: WorkCalendarID,
+		Title:      "Acme contract review",
+		Location:   "Zoom",
+		JoinUrl:    "https://zoom.example.com/j/123456789",
+		StartUtc:   s.at(2, 16, 0),
+		EndUtc:     s.at(2, 17, 0),
+		Attendees:  []api.Attendee{attendee(alex, "needsAction"), attendee(jordan, "accepted")},
+		Organizer:  jordan.Email,
+	})
+	s.addEvent(api.Event{
+		CalendarID: WorkCalendarID,
+		Title:      "Team offsite",
+		StartUtc:   s.day(5),
+		EndUtc:     s.day(6),
+		AllDay:     true,
+		Attendees:  []api.Attendee{attendee(alex, "accepted"), attendee(priya, "accepted"), attendee(sam, "accepted")},
+	})
+	s.addEvent(api.Event{
+		CalendarID: WorkCalendarID,
+		Title:      "Design sync (moved)",
+		Status:     "cancelled",
+		StartUtc:   s.at(1, 11, 0),
+		EndUtc:     s.at(1, 11, 30),
+		Attendees:  []api.Attendee{attendee(alex, "declined"), attendee(sam, "accepted")},
+		Organizer:  sam.Email,
+	})
+	s.addEvent(api.Event{
+		CalendarID: WorkCalendarID,
+		Title:      "Retro meeting",
+		StartUtc:   s.at(-2, 15, 0),
+		EndUtc:     s.at(-2, 16, 0),
+		Attendees:  []api.Attendee{attendee(alex, "accepted"), attendee(priya, "accepted"), attendee(sam, "declined")},
+	})
+	s.addEvent(api.Event{
+		CalendarID: PersonalCalendarID,
+		Title:      "Lunch with Sam",
+		Location:   "Corner Bistro",
+		StartUtc:   s.at(0, 12, 0),
+		EndUtc:     s.at(0, 13, 0),
+		Attendees:  []api.Attendee{attendee(alex, "accepted"), attendee(sam, "accepted")},
+	})
+	s.addEvent(api.Event{
+		CalendarID: PersonalCalendarID,
+		Title:      "Dentist",
+		Location:   "12 Main St",
+		StartUtc:   s.at(3, 8, 0),
+		EndUtc:     s.at(3, 8, 45),
+	})
+
+	// Daily standup on weekdays around the anchor date. Seeded last so the
+	// one-off events above keep the same IDs whatever the day of the week.
+	standup := s.newID("ser_")
+	for d := -7; d <= 14; d++ {
+		if wd := s.day(d).Weekday(); wd == time.Saturday || wd == time.Sunday {
+			continue
+		}
+		s.addEvent(api.Event{
+			CalendarID:       WorkCalendarID,
+			Title:            "Team standup",
+			Location:         "Google Meet",
+			JoinUrl:          "https://meet.example.com/abc-defg-hij",
+			StartUtc:         s.at(d, 9, 30),
+			EndUtc:           s.at(d, 9, 45),
+			Attendees:        []api.Attendee{attendee(alex, "accepted"), attendee(priya, "accepted"), attendee(sam, "accepted")},
+			IsRecurringEvent: true,
+			SeriesID:         standup,
+			Recurrence:       []string{"RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"},
+		})
+	}
+}
+
+func (s *Server) addEmail(e api.Email) api.Email {
+	e.ID = s.newID("msg_")
+	if e.ThreadID == "" {
+		e.ThreadID = s.newID("thr_")
+	}
+	if len(e.To) == 0 {
+		e.To = []api.Participant{alex}
+	}
+	if e.BodyType == "" {
+		e.BodyType = "text"
+	}
+	e.BodyPreview = preview(e.Body)
+	e.SentAt = e.ReceivedAt
+	e.HasAttachments = len(e.Attachments) > 0
+	e.Provider = "google"
+	s.emails = append(s.emails, e)
+	return e
+}
+
+func (s *Server) seedEmails() {
+	first := s.addEmail(api.Email{
+		Subject:    "Q3 planning: draft agenda",
+		From:       &priya,
+		To:         []api.Participant{alex, sam},
+		Body:       "Hi both,\n\nAttached is the draft agenda for Thursday's planning meeting. Please add anything I missed.\n\nThanks,\nPriya",
+		ReceivedAt: s.at(-1, 16, 5),
+		IsRead:     true,
+		Labels:     []string{"INBOX", "IMPORTANT"},
+		Attachments: []api.Attachment{
+			{ID: "att_1", Name: "Q3 agenda.pdf", ContentType: "application/pdf", Size: 18},
+		},
+		Importance: "high",
+	})
+	s.attachments[first.ID+"/att_1"] = []byte("%PDF-1.4\n% demo\n")
+
+	s.addEmail(api.Email{
+		ThreadID:   first.ThreadID,
+		InReplyTo:  first.ID,
+		Subject:    "Re: Q3 planning: draft agenda",
+		From:       &sam,
+		To:         []api.Participant{priya, alex},
+		Body:       "Looks good. Can we add 15 minutes for the hiring plan?\n\nSam",
+		ReceivedAt: s.at(0, 8, 12),
+		Labels:     []string{"INBOX"},
+	})
+
+	contract := s.addEmail(api.Email{
+		Subject:    "Acme contract - redlines",
+		From:       &jordan,
+		Body:       "Alex,\n\nPlease find our redlines attached. Happy to walk through them on Wednesday.\n\nBest,\nJordan Lee\nAcme Corp",
+		ReceivedAt: s.at(0, 10, 41),
+		Labels:     []string{"INBOX", "IMPORTANT"},
+		Attachments: []api.Attachment{
+			{ID: "att_1", Name: "Acme MSA v3 (redlines).docx", ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Size: 24},
+			{ID: "att_2", Name: "logo.png", ContentType: "image/png", Size: 8, IsInline: true},
+		},
+	})
+	s.attachments[contract.ID+"/att_1"] = []byte("PK\x03\x04 demo docx content")
+	s.attachments[contract.ID+"/att_2"] = []byte("\x89PNG\r\n\x1a\n")
+
+	s.addEmail(api.Email{
+		Subject:    "Invoice #2024-0142 is due",
+		From:       &billing,
+		Body:       "Your invoice #2024-0142 for $1,240.00 is due in 7 days. Pay online at https://vendor.example/pay.",
+		ReceivedAt: s.at(-2, 7, 0),
+		Labels:     []string{"INBOX", "CATEGORY_UPDATES"},
+	})
+	s.addEmail(api.Email{
+		Subject:    "Lunch tomorrow?",
+		From:       &sam,
+		Body:       "Corner Bistro at 12? My treat this time.",
+		ReceivedAt: s.at(-1, 18, 30),
+		IsRead:     true,
+		Labels:     []string{"INBOX"},
+	})
+
+	// Shared support mailbox
+	s.addEmail(api.Email{
+		Subject:    "Can't export my report",
+		From:       &casey,
+		To:         []api.Participant{support},
+		Body:       "Hello,\n\nThe export button on the reports page does nothing since yesterday. Could you take a look?\n\nCasey",
+		ReceivedAt: s.at(0, 9, 20),
+		Labels:     []string{"INBOX"},
+	})
+
+	// A run of newsletters so listings span several pages
+	for i := 1; i <= 20; i++ {
+		s.addEmail(api.Email{
+			Subject:    fmt.Sprintf("The Weekly #%d: product news and tips", 100+i),
+			From:       &digest,
+			Body:       fmt.Sprintf("Issue %d. This week's highlights, release notes and a few tips for getting more from your calendar.", 100+i),
+			ReceivedAt: s.at(-7*i, 6, 0),
+			IsRead:     i > 1,
+			Labels:     []string{"CATEGORY_PROMOTIONS"},
+		})
+	}
+}
+
+func (s *Server) seedDrive() {
+	projects := s.newFile("Projects", mimeFolder, 0, "")
+	projects.ModifiedTime = strPtr(s.at(-10, 9, 0).Format("2006-01-02T15:04:05Z"))
+	s.files = append(s.files, projects)
+
+	plan := s.newFile("Q3 Plan", mimeDoc, 0, projects.ID)
+	plan.Size = nil
+	plan.SharedWith = []api.DriveUser{{Email: priya.Email, DisplayName: strPtr(priya.Name), Role: strPtr("writer")}}
+	plan.ModifiedTime = strPtr(s.at(-1, 17, 20).Format("2006-01-02T15:04:05Z"))
+	s.files = append(s.files, plan)
+	s.docs[plan.ID] = "Q3 Plan\n\nGoals\n- Ship the new onboarding flow\n- Reduce p95 latency by 20%\n\nOwners: Alex, Priya\n"
+
+	budget := s.newFile("Budget 2026", mimeSheet, 0, projects.ID)
+	budget.Size = nil
+	budget.ModifiedTime = strPtr(s.at(-3, 11, 0).Format("2006-01-02T15:04:05Z"))
+	s.files = append(s.files, budget)
+	s.sheets[budget.ID] = [][]interface{}{
+		{"Item", "Q1", "Q2", "Q3"},
+		{"Hosting", 1200, 1250, 1300},
+		{"Tools", 400, 400, 450},
+		{"Travel", 800, 300, 950},
+	}
+
+	msa := s.newFile("Acme MSA v2.pdf", "application/pdf", 482133, projects.ID)
+	msa.ModifiedTime = strPtr(s.at(-12, 14, 3).Format("2006-01-02T15:04:05Z"))
+	s.files = append(s.files, msa)
+
+	photo := s.newFile("offsite-venue.jpg", "image/jpeg", 2311840, "")
+	photo.ModifiedTime = strPtr(s.at(-20, 8, 45).Format("2006-01-02T15:04:05Z"))
+	s.files = append(s.files, photo)
+}
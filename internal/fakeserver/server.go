@@ -0,0 +1,374 @@
+// Package fakeserver is an in-memory PortEden API for tests and demos. It
+// serves realistic calendars, events, emails, threads and drive files, supports
+// the same pagination and error shapes as the real API, and keeps mutations in
+// memory so create/update/delete flows can be exercised end to end.
+package fakeserver
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// UserEmail is the address of the account the fake server is signed in as
+const UserEmail = "alex@example.com"
+
+// Server is an http.Handler implementing the PortEden API
+type Server struct {
+	// APIKey, when set, is required as a Bearer token on every request
+	APIKey string
+	// RefreshToken, when set, can be exchanged at auth/token/refresh for
+	// access tokens accepted in place of APIKey. It rotates on every use.
+	RefreshToken string
+	// RejectCompressedRequests answers gzip-encoded request bodies with 415,
+	// like servers that only compress responses
+	RejectCompressedRequests bool
+
+	mu          sync.Mutex
+	now         time.Time
+	ids         map[string]int // last ID issued per prefix
+	calendars   []api.Calendar
+	shares      map[int64][]api.CalendarShare
+	autoReply   api.AutoReplySettings
+	events      []api.Event
+	emails      []api.Email
+	attachments map[string][]byte // "emailID/attachmentID" -> content
+	files       []api.DriveFile
+	docs        map[string]string
+	sheets      map[string][][]interface{}
+	faults      []fault
+
+	// Change history for sync tokens: the change number of each item's last
+	// change or deletion, keyed "kind/id". Seeded items have none.
+	seq       int
+	syncFloor int
+	changes   map[string]int
+	deletions map[string]int
+
+	accessTokens map[string]bool // issued for RefreshToken
+}
+
+type fault struct {
+	path   string
+	status int
+	count  int
+}
+
+// New returns a server seeded with sample data anchored to the current day
+func New() *Server {
+	return NewAt(time.Now())
+}
+
+// NewAt returns a server seeded with sample data anchored to the day of now,
+// for tests that need stable timestamps
+func NewAt(now time.Time) *Server {
+	s := &Server{
+		now:         now.UTC(),
+		ids:         make(map[string]int),
+		attachments: make(map[string][]byte),
+		docs:        make(map[string]string),
+		sheets:      make(map[string][][]interface{}),
+		changes:     make(map[string]int),
+		deletions:   make(map[string]int),
+	}
+	s.seed()
+	return s
+}
+
+// InjectError makes the next count requests whose path starts with path fail
+// with status. 429 responses carry "Retry-After: 1".
+func (s *Server) InjectError(path string, status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, fault{path: path, status: status, count: count})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The status page is public and lives outside the API
+	if r.URL.Path == statusSummaryPath {
+		serviceStatus(w)
+		return
+	}
+	setVersionHeaders(w, r)
+
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		gw := &gzipWriter{ResponseWriter: w}
+		defer gw.Close()
+		w = gw
+	}
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		if s.RejectCompressedRequests {
+			writeError(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_ENCODING", "Compressed request bodies are not supported")
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid gzip body")
+			return
+		}
+		r.Body = zr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Refresh tokens are sent in the body, not as a Bearer token
+	if r.URL.Path == "/api/auth/token/refresh" {
+		s.refreshToken(w, r)
+		return
+	}
+	if s.APIKey != "" && !s.authorized(r.Header.Get("Authorization")) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHENTICATED", "Invalid or expired API key")
+		return
+	}
+
+	if s.takeFault(w, r.URL.Path) {
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/")
+	switch {
+	case path == "auth/token/status":
+		s.authStatus(w, r)
+	case path == "auth/token/logout":
+		writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+	case strings.HasPrefix(path, "access/calendar/"):
+		s.serveCalendar(w, r, strings.TrimPrefix(path, "access/calendar/"))
+	case strings.HasPrefix(path, "access/email/"):
+		s.serveEmail(w, r, strings.TrimPrefix(path, "access/email/"))
+	case strings.HasPrefix(path, "access/drive/"):
+		s.serveDrive(w, r, strings.TrimPrefix(path, "access/drive/"))
+	default:
+		notFound(w)
+	}
+}
+
+// RoundTrip serves req in-process, letting the server stand in for the
+// network transport of an API client
+func (s *Server) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+func (s *Server) takeFault(w http.ResponseWriter, path string) bool {
+	for i := range s.faults {
+		f := &s.faults[i]
+		if f.count <= 0 || !strings.HasPrefix(path, f.path) {
+			continue
+		}
+		f.count--
+		if f.status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeError(w, f.status, "", http.StatusText(f.status))
+		return true
+	}
+	return false
+}
+
+func (s *Server) authorized(header string) bool {
+	token := strings.TrimPrefix(header, "Bearer ")
+	return token == s.APIKey || s.accessTokens[token]
+}
+
+// refreshToken exchanges the refresh token for a 15-minute access token and
+// a new refresh token
+func (s *Server) refreshToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if s.RefreshToken == "" || req.RefreshToken != s.RefreshToken {
+		writeError(w, http.StatusUnauthorized, "UNAUTHENTICATED", "Invalid or revoked refresh token")
+		return
+	}
+	if s.accessTokens == nil {
+		s.accessTokens = make(map[string]bool)
+	}
+	access := s.newID("at_")
+	s.accessTokens[access] = true
+	s.RefreshToken = s.newID("rt_")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"accessToken":  access,
+		"expiresIn":    900,
+		"refreshToken": s.RefreshToken,
+	})
+}
+
+func (s *Server) authStatus(w http.ResponseWriter, r *http.Request) {
+	expires := s.day(335)
+	writeJSON(w, http.StatusOK, api.AuthStatusResponse{
+		Email:        UserEmail,
+		OperatorName: "Alex Rivera",
+		KeyID:        42,
+		KeyTitle:     "Demo key",
+		CreatedAt:    s.day(-30),
+		ExpiresAt:    &expires,
+	})
+}
+
+// apiVersions are the versions the fake API accepts, oldest first. All but the
+// newest are deprecated.
+var apiVersions = []string{"2025-06", api.APIVersion}
+
+// setVersionHeaders serves the requested API version when it is supported and
+// the newest otherwise, flagging deprecated ones
+func setVersionHeaders(w http.ResponseWriter, r *http.Request) {
+	latest := apiVersions[len(apiVersions)-1]
+	served := r.Header.Get("X-API-Version")
+	if !slices.Contains(apiVersions, served) {
+		served = latest
+	}
+	w.Header().Set("X-API-Version", served)
+	w.Header().Set("X-API-Supported-Versions", strings.Join(apiVersions, ", "))
+	if served != latest {
+		w.Header().Set("Deprecation", "true")
+	}
+}
+
+// statusSummaryPath is where the status page serves its summary
+const statusSummaryPath = "/api/v2/summary.json"
+
+// serviceStatus reports every component as operational
+func serviceStatus(w http.ResponseWriter) {
+	status := api.ServiceStatus{
+		Components: []api.ServiceComponent{
+			{Name: "API", Status: "operational"},
+			{Name: "Calendar", Status: "operational"},
+			{Name: "Email", Status: "operational"},
+			{Name: "Drive", Status: "operational"},
+		},
+		Incidents:             []api.Incident{},
+		ScheduledMaintenances: []api.Incident{},
+	}
+	status.Page.Name = "PortEden"
+	status.Page.URL = "https://status.porteden.com"
+	status.Status.Indicator = "none"
+	status.Status.Description = "All Systems Operational"
+	writeJSON(w, http.StatusOK, status)
+}
+
+// newID issues sequential IDs per prefix, so seeded IDs stay stable
+// regardless of how many items of other kinds exist
+func (s *Server) newID(prefix string) string {
+	s.ids[prefix]++
+	return prefix + strconv.Itoa(1000+s.ids[prefix])
+}
+
+// day returns midnight UTC offset by n days from the anchor date
+func (s *Server) day(n int) time.Time {
+	y, m, d := s.now.Date()
+	return time.Date(y, m, d+n, 0, 0, 0, 0, time.UTC)
+}
+
+// at returns the anchor date offset by n days at hh:mm UTC
+func (s *Server) at(n, hh, mm int) time.Time {
+	return s.day(n).Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute)
+}
+
+// gzipWriter compresses JSON responses for clients that accept gzip. Other
+// content, such as attachment downloads, is passed through as is.
+type gzipWriter struct {
+	http.ResponseWriter
+	zw          *gzip.Writer
+	wroteHeader bool
+}
+
+func (g *gzipWriter) WriteHeader(status int) {
+	g.wroteHeader = true
+	if strings.HasPrefix(g.Header().Get("Content-Type"), "application/json") {
+		g.Header().Set("Content-Encoding", "gzip")
+		g.Header().Del("Content-Length")
+		g.zw = gzip.NewWriter(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gzipWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.zw != nil {
+		return g.zw.Write(p)
+	}
+	return g.ResponseWriter.Write(p)
+}
+
+func (g *gzipWriter) Close() {
+	if g.zw != nil {
+		g.zw.Close()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	body := map[string]string{"error": message, "message": message}
+	if code != "" {
+		body["code"] = code
+	}
+	writeJSON(w, status, body)
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "NOT_FOUND", "Resource not found")
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "", "Method not allowed")
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "VALIDATION", "Invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// page slices n items at offset/limit, returning the bounds and whether more remain
+func page(n, offset, limit int) (start, end int, hasMore bool) {
+	if offset > n {
+		offset = n
+	}
+	end = n
+	if limit > 0 && offset+limit < n {
+		end = offset + limit
+	}
+	return offset, end, end < n
+}
+
+func queryInt(r *http.Request, key string, def int) int {
+	if n, err := strconv.Atoi(r.URL.Query().Get(key)); err == nil {
+		return n
+	}
+	return def
+}
+
+func queryTime(r *http.Request, key string) time.Time {
+	t, _ := time.Parse(time.RFC3339, r.URL.Query().Get(key))
+	return t
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,82 @@
+package fakeserver
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of items tracked for sync tokens
+const (
+	syncEvents = "events"
+	syncEmails = "emails"
+)
+
+// changed records that an event or email was created or modified, so
+// listings with an older sync token include it
+func (s *Server) changed(kind, id string) {
+	s.seq++
+	s.changes[kind+"/"+id] = s.seq
+}
+
+// deleted records that an event or email was removed, so listings with an
+// older sync token report its ID
+func (s *Server) deleted(kind, id string) {
+	s.seq++
+	delete(s.changes, kind+"/"+id)
+	s.deletions[kind+"/"+id] = s.seq
+}
+
+// ExpireSyncTokens makes every sync token issued so far invalid, as happens
+// when the real API prunes its change history
+func (s *Server) ExpireSyncTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	s.syncFloor = s.seq
+}
+
+// syncToken returns a token for the current point in the change history
+func (s *Server) syncToken(kind string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(kind + "|" + strconv.Itoa(s.seq)))
+}
+
+// readSyncToken returns the change number named by the request's syncToken,
+// or -1 without one. It writes a 410 for tokens that are invalid, belong to
+// another kind or have expired.
+func (s *Server) readSyncToken(w http.ResponseWriter, r *http.Request, kind string) (int, bool) {
+	token := r.URL.Query().Get("syncToken")
+	if token == "" {
+		return -1, true
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	tokenKind, n, _ := strings.Cut(string(raw), "|")
+	since, convErr := strconv.Atoi(n)
+	if err != nil || convErr != nil || tokenKind != kind || since < s.syncFloor {
+		writeError(w, http.StatusGone, "SYNC_TOKEN_EXPIRED", "Sync token is invalid or expired; fetch again without it")
+		return 0, false
+	}
+	return since, true
+}
+
+// changedSince reports whether an item changed after the change number
+func (s *Server) changedSince(kind, id string, since int) bool {
+	return since < 0 || s.changes[kind+"/"+id] > since
+}
+
+// deletedSince lists the IDs of items of kind deleted after the change number
+func (s *Server) deletedSince(kind string, since int) []string {
+	if since < 0 {
+		return nil
+	}
+	var ids []string
+	for key, n := range s.deletions {
+		if k, id, _ := strings.Cut(key, "/"); k == kind && n > since {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
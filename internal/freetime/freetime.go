@@ -0,0 +1,167 @@
+// Package freetime turns busy periods into the free time between them,
+// optionally kept to working hours.
+package freetime
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// Slot is a stretch of free time
+type Slot struct {
+	StartUtc        time.Time `json:"startUtc"`
+	EndUtc          time.Time `json:"endUtc"`
+	DurationMinutes int       `json:"durationMinutes"`
+}
+
+// WorkingHours limit free time to part of each working day. Start and End
+// are minutes after midnight.
+type WorkingHours struct {
+	Start, End int
+	Days       []time.Weekday
+}
+
+// DefaultWorkingHours are 9:00 to 17:00, Monday to Friday
+var DefaultWorkingHours = WorkingHours{
+	Start: 9 * 60,
+	End:   17 * 60,
+	Days:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+}
+
+// ParseHours parses a daily range such as "09:00-17:30" into w's Start and
+// End
+func (w *WorkingHours) ParseHours(s string) error {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return fmt.Errorf("invalid working hours %q: use HH:MM-HH:MM, e.g. 09:00-17:00", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return err
+	}
+	if end <= start {
+		return fmt.Errorf("invalid working hours %q: the end must be after the start", s)
+	}
+	w.Start, w.End = start, end
+	return nil
+}
+
+// ParseDays parses day names (mon, Tuesday, we) into w's Days
+func (w *WorkingHours) ParseDays(names []string) error {
+	var days []time.Weekday
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if len(name) >= 2 && strings.HasPrefix(strings.ToLower(d.String()), name) {
+				days, found = append(days, d), true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("invalid working day %q: use mon, tue, wed, thu, fri, sat or sun", name)
+		}
+	}
+	if len(days) == 0 {
+		return fmt.Errorf("no working days given")
+	}
+	w.Days = days
+	return nil
+}
+
+// parseClock parses "9:00" or "17:30" into minutes after midnight; "24:00"
+// is the end of the day
+func parseClock(s string) (int, error) {
+	var h, m int
+	if n, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &h, &m); err != nil || n != 2 ||
+		h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time of day %q: use HH:MM", s)
+	}
+	return h*60 + m, nil
+}
+
+// Merge sorts busy periods and joins the ones that overlap or touch, so
+// periods from several calendars become one timeline
+func Merge(busy []api.BusyPeriod) []api.BusyPeriod {
+	sorted := append([]api.BusyPeriod(nil), busy...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartUtc.Before(sorted[j].StartUtc) })
+
+	var merged []api.BusyPeriod
+	for _, p := range sorted {
+		if !p.EndUtc.After(p.StartUtc) {
+			continue
+		}
+		if n := len(merged); n > 0 && !p.StartUtc.After(merged[n-1].EndUtc) {
+			if p.EndUtc.After(merged[n-1].EndUtc) {
+				merged[n-1].EndUtc = p.EndUtc
+			}
+			continue
+		}
+		merged = append(merged, api.BusyPeriod{StartUtc: p.StartUtc, EndUtc: p.EndUtc})
+	}
+	return merged
+}
+
+// Free returns the gaps of at least min between busy periods from from to
+// to. With hours, only the working hours of working days count, as seen in
+// loc; a gap never spans two days then. Without, the whole range does.
+func Free(busy []api.BusyPeriod, from, to time.Time, hours *WorkingHours, loc *time.Location, min time.Duration) []Slot {
+	var windows [][2]time.Time
+	if hours == nil {
+		windows = append(windows, [2]time.Time{from, to})
+	} else {
+		from, to := from.In(loc), to.In(loc)
+		for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+			if !slices.Contains(hours.Days, day.Weekday()) {
+				continue
+			}
+			// time.Date rather than Add, so the hours stay put on days
+			// when clocks change
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, hours.Start, 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, hours.End, 0, 0, loc)
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				windows = append(windows, [2]time.Time{start, end})
+			}
+		}
+	}
+
+	merged := Merge(busy)
+	var slots []Slot
+	add := func(start, end time.Time) {
+		if d := end.Sub(start); d > 0 && d >= min {
+			slots = append(slots, Slot{StartUtc: start.UTC(), EndUtc: end.UTC(), DurationMinutes: int(d.Minutes())})
+		}
+	}
+	for _, w := range windows {
+		cursor := w[0]
+		for _, b := range merged {
+			if !b.EndUtc.After(cursor) {
+				continue
+			}
+			if !b.StartUtc.Before(w[1]) {
+				break
+			}
+			add(cursor, b.StartUtc)
+			cursor = b.EndUtc
+		}
+		if cursor.Before(w[1]) {
+			add(cursor, w[1])
+		}
+	}
+	return slots
+}
@@ -0,0 +1,110 @@
+package freetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+func TestMerge(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	merged := Merge([]api.BusyPeriod{
+		{StartUtc: at(14, 0), EndUtc: at(15, 0)},
+		{StartUtc: at(9, 0), EndUtc: at(10, 0)},
+		{StartUtc: at(9, 30), EndUtc: at(11, 0)},
+		{StartUtc: at(11, 0), EndUtc: at(11, 30)}, // touches the one before
+		{StartUtc: at(16, 0), EndUtc: at(16, 0)},  // empty
+	})
+	want := []api.BusyPeriod{
+		{StartUtc: at(9, 0), EndUtc: at(11, 30)},
+		{StartUtc: at(14, 0), EndUtc: at(15, 0)},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("Merge = %+v, want %+v", merged, want)
+	}
+	for i := range want {
+		if !merged[i].StartUtc.Equal(want[i].StartUtc) || !merged[i].EndUtc.Equal(want[i].EndUtc) {
+			t.Errorf("period %d = %v-%v, want %v-%v", i, merged[i].StartUtc, merged[i].EndUtc, want[i].StartUtc, want[i].EndUtc)
+		}
+	}
+}
+
+func TestFree(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no timezone data")
+	}
+	// Friday 27 March 2026 to Monday 30 March; clocks go forward on the 29th
+	at := func(d, h, m int) time.Time { return time.Date(2026, 3, d, h, m, 0, 0, berlin) }
+	busy := []api.BusyPeriod{
+		// From two calendars, overlapping
+		{StartUtc: at(27, 8, 0).UTC(), EndUtc: at(27, 10, 0).UTC()},
+		{StartUtc: at(27, 9, 30).UTC(), EndUtc: at(27, 11, 0).UTC()},
+		{StartUtc: at(27, 12, 0).UTC(), EndUtc: at(27, 12, 20).UTC()},
+		{StartUtc: at(27, 12, 40).UTC(), EndUtc: at(27, 16, 30).UTC()},
+		{StartUtc: at(30, 13, 0).UTC(), EndUtc: at(30, 18, 0).UTC()},
+	}
+	from, to := at(27, 0, 0), at(31, 0, 0)
+
+	tests := []struct {
+		name  string
+		hours *WorkingHours
+		min   time.Duration
+		want  [][2]time.Time
+	}{
+		{"working hours", &DefaultWorkingHours, 30 * time.Minute, [][2]time.Time{
+			{at(27, 11, 0), at(27, 12, 0)},
+			{at(27, 16, 30), at(27, 17, 0)},
+			{at(30, 9, 0), at(30, 13, 0)}, // after the clock change, still 9:00 local
+		}},
+		{"short gaps", &DefaultWorkingHours, 15 * time.Minute, [][2]time.Time{
+			{at(27, 11, 0), at(27, 12, 0)},
+			{at(27, 12, 20), at(27, 12, 40)},
+			{at(27, 16, 30), at(27, 17, 0)},
+			{at(30, 9, 0), at(30, 13, 0)},
+		}},
+		{"any time", nil, time.Hour, [][2]time.Time{
+			{at(27, 0, 0), at(27, 8, 0)},
+			{at(27, 11, 0), at(27, 12, 0)},
+			{at(27, 16, 30), at(30, 13, 0)},
+			{at(30, 18, 0), at(31, 0, 0)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := Free(busy, from, to, tt.hours, berlin, tt.min)
+			if len(slots) != len(tt.want) {
+				t.Fatalf("got %d slots %+v, want %d", len(slots), slots, len(tt.want))
+			}
+			for i, w := range tt.want {
+				s := slots[i]
+				if !s.StartUtc.Equal(w[0]) || !s.EndUtc.Equal(w[1]) || s.DurationMinutes != int(w[1].Sub(w[0]).Minutes()) {
+					t.Errorf("slot %d = %v-%v (%dm), want %v-%v", i, s.StartUtc.In(berlin), s.EndUtc.In(berlin), s.DurationMinutes, w[0], w[1])
+				}
+			}
+		})
+	}
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	var w WorkingHours
+	if err := w.ParseHours("08:30-17:45"); err != nil || w.Start != 8*60+30 || w.End != 17*60+45 {
+		t.Errorf("ParseHours = %+v, %v", w, err)
+	}
+	for _, bad := range []string{"9-17", "17:00-09:00", "09:00-25:00", "09:60-10:00"} {
+		if err := w.ParseHours(bad); err == nil {
+			t.Errorf("ParseHours(%q) accepted", bad)
+		}
+	}
+
+	if err := w.ParseDays([]string{"Sun", "monday", "tu"}); err != nil || len(w.Days) != 3 || w.Days[0] != time.Sunday || w.Days[2] != time.Tuesday {
+		t.Errorf("ParseDays = %v, %v", w.Days, err)
+	}
+	for _, bad := range [][]string{{"t"}, {"funday"}, {}} {
+		if err := w.ParseDays(bad); err == nil {
+			t.Errorf("ParseDays(%q) accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Package holidays computes public holidays for a few regions from built-in
+// rules, so no dataset has to be downloaded or kept up to date.
+package holidays
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Holiday is a public holiday. Date is the civil date at midnight UTC.
+type Holiday struct {
+	Date time.Time `json:"date"`
+	Name string    `json:"name"`
+}
+
+// rules returns a region's holidays in one year
+var rules = map[string]func(year int) []Holiday{
+	"US": us,
+	"GB": gb,
+	"CA": ca,
+	"DE": de,
+	"FR": fr,
+}
+
+// aliases map other common codes to a supported region
+var aliases = map[string]string{
+	"UK": "GB",
+}
+
+// Regions lists the supported region codes
+func Regions() []string {
+	codes := make([]string, 0, len(rules))
+	for code := range rules {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Between returns the region's holidays falling on the days from first to
+// last, inclusive. Only the year, month and day of first and last are used.
+func Between(region string, first, last time.Time) ([]Holiday, error) {
+	code := strings.ToUpper(region)
+	if alias, ok := aliases[code]; ok {
+		code = alias
+	}
+	rule, ok := rules[code]
+	if !ok {
+		return nil, fmt.Errorf("no holidays known for region %q (supported: %s)", region, strings.Join(Regions(), ", "))
+	}
+
+	from := day(first.Year(), first.Month(), first.Day())
+	to := day(last.Year(), last.Month(), last.Day())
+	var out []Holiday
+	for year := from.Year(); year <= to.Year(); year++ {
+		for _, h := range rule(year) {
+			if !h.Date.Before(from) && !h.Date.After(to) {
+				out = append(out, h)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out, nil
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth weekday of a month, counting from the end when
+// n is negative
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n > 0 {
+		first := day(year, month, 1)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+7*(n-1))
+	}
+	last := day(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset+7*(n+1))
+}
+
+func mondayOnOrBefore(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+// easter returns Easter Sunday (Gregorian, anonymous algorithm)
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	return day(year, time.Month(month), (h+l-7*m+114)%31+1)
+}
+
+// observedUS moves a weekend holiday to the nearest weekday
+func observedUS(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// substitute moves a weekend holiday to the next weekday not in taken
+func substitute(t time.Time, taken ...time.Time) time.Time {
+	for t.Weekday() == time.Saturday || t.Weekday() == time.Sunday || contains(taken, t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func contains(days []time.Time, t time.Time) bool {
+	for _, d := range days {
+		if d.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func us(y int) []Holiday {
+	return []Holiday{
+		{observedUS(day(y, time.January, 1)), "New Year's Day"},
+		{nthWeekday(y, time.January, time.Monday, 3), "Martin Luther King Jr. Day"},
+		{nthWeekday(y, time.February, time.Monday, 3), "Washington's Birthday"},
+		{nthWeekday(y, time.May, time.Monday, -1), "Memorial Day"},
+		{observedUS(day(y, time.June, 19)), "Juneteenth"},
+		{observedUS(day(y, time.July, 4)), "Independence Day"},
+		{nthWeekday(y, time.September, time.Monday, 1), "Labor Day"},
+		{nthWeekday(y, time.October, time.Monday, 2), "Columbus Day"},
+		{observedUS(day(y, time.November, 11)), "Veterans Day"},
+		{nthWeekday(y, time.November, time.Thursday, 4), "Thanksgiving Day"},
+		{observedUS(day(y, time.December, 25)), "Christmas Day"},
+	}
+}
+
+// gb covers the bank holidays of England and Wales
+func gb(y int) []Holiday {
+	e := easter(y)
+	christmas := substitute(day(y, time.December, 25))
+	return []Holiday{
+		{substitute(day(y, time.January, 1)), "New Year's Day"},
+		{e.AddDate(0, 0, -2), "Good Friday"},
+		{e.AddDate(0, 0, 1), "Easter Monday"},
+		{nthWeekday(y, time.May, time.Monday, 1), "Early May bank holiday"},
+		{nthWeekday(y, time.May, time.Monday, -1), "Spring bank holiday"},
+		{nthWeekday(y, time.August, time.Monday, -1), "Summer bank holiday"},
+		{christmas, "Christmas Day"},
+		{substitute(day(y, time.December, 26), christmas), "Boxing Day"},
+	}
+}
+
+func ca(y int) []Holiday {
+	christmas := substitute(day(y, time.December, 25))
+	return []Holiday{
+		{substitute(day(y, time.January, 1)), "New Year's Day"},
+		{easter(y).AddDate(0, 0, -2), "Good Friday"},
+		{mondayOnOrBefore(day(y, time.May, 24)), "Victoria Day"},
+		{substitute(day(y, time.July, 1)), "Canada Day"},
+		{nthWeekday(y, time.September, time.Monday, 1), "Labour Day"},
+		{nthWeekday(y, time.October, time.Monday, 2), "Thanksgiving"},
+		{christmas, "Christmas Day"},
+		{substitute(day(y, time.December, 26), christmas), "Boxing Day"},
+	}
+}
+
+// de covers the nationwide holidays of Germany
+func de(y int) []Holiday {
+	e := easter(y)
+	return []Holiday{
+		{day(y, time.January, 1), "Neujahr"},
+		{e.AddDate(0, 0, -2), "Karfreitag"},
+		{e.AddDate(0, 0, 1), "Ostermontag"},
+		{day(y, time.May, 1), "Tag der Arbeit"},
+		{e.AddDate(0, 0, 39), "Christi Himmelfahrt"},
+		{e.AddDate(0, 0, 50), "Pfingstmontag"},
+		{day(y, time.October, 3), "Tag der Deutschen Einheit"},
+		{day(y, time.December, 25), "1. Weihnachtstag"},
+		{day(y, time.December, 26), "2. Weihnachtstag"},
+	}
+}
+
+func fr(y int) []Holiday {
+	e := easter(y)
+	return []Holiday{
+		{day(y, time.January, 1), "Jour de l'an"},
+		{e.AddDate(0, 0, 1), "Lundi de Pâques"},
+		{day(y, time.May, 1), "Fête du Travail"},
+		{day(y, time.May, 8), "Victoire 1945"},
+		{e.AddDate(0, 0, 39), "Ascension"},
+		{e.AddDate(0, 0, 50), "Lundi de Pentecôte"},
+		{day(y, time.July, 14), "Fête nationale"},
+		{day(y, time.August, 15), "Assomption"},
+		{day(y, time.November, 1), "Toussaint"},
+		{day(y, time.November, 11), "Armistice 1918"},
+		{day(y, time.December, 25), "Noël"},
+	}
+}
@@ -0,0 +1,59 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		region string
+		date   string
+		name   string
+	}{
+		{"US", "2026-11-26", "Thanksgiving Day"},
+		{"US", "2026-07-03", "Independence Day"}, // July 4 is a Saturday
+		{"US", "2026-05-25", "Memorial Day"},
+		{"GB", "2026-04-03", "Good Friday"},
+		{"uk", "2026-12-28", "Boxing Day"}, // Dec 26 is a Saturday
+		{"CA", "2026-05-18", "Victoria Day"},
+		{"DE", "2026-05-14", "Christi Himmelfahrt"},
+		{"FR", "2026-05-25", "Lundi de Pentecôte"},
+	}
+	for _, tt := range tests {
+		d, _ := time.Parse("2006-01-02", tt.date)
+		got, err := Between(tt.region, d, d)
+		if err != nil {
+			t.Fatalf("Between(%s, %s): %v", tt.region, tt.date, err)
+		}
+		if len(got) != 1 || got[0].Name != tt.name {
+			t.Errorf("Between(%s, %s) = %v, want %s", tt.region, tt.date, got, tt.name)
+		}
+	}
+}
+
+func TestBetweenRange(t *testing.T) {
+	from, _ := time.Parse("2006-01-02", "2026-12-20")
+	to, _ := time.Parse("2006-01-02", "2027-01-05")
+	got, err := Between("US", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "Christmas Day" || got[1].Name != "New Year's Day" {
+		t.Errorf("got %v, want Christmas Day and New Year's Day", got)
+	}
+}
+
+func TestBetweenUnknownRegion(t *testing.T) {
+	if _, err := Between("XX", time.Now(), time.Now()); err == nil {
+		t.Error("expected an error for an unknown region")
+	}
+}
+
+func TestEaster(t *testing.T) {
+	for year, want := range map[int]string{2024: "2024-03-31", 2025: "2025-04-20", 2026: "2026-04-05"} {
+		if got := easter(year).Format("2006-01-02"); got != want {
+			t.Errorf("easter(%d) = %s, want %s", year, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package i18n
+
+// es is the Spanish catalog
+var es = map[string]string{
+	// Yes/no answers and prompt suffixes
+	"y":     "s",
+	"yes":   "sí",
+	"n":     "n",
+	"no":    "no",
+	"[y/N]": "[s/N]",
+	"[Y/n]": "[S/n]",
+
+	// Prompts
+	"Apply these actions?":     "¿Aplicar estas acciones?",
+	"Continue?":                "¿Continuar?",
+	"Create %d event(s)?":      "¿Crear %d evento(s)?",
+	"Delete %d event(s)?":      "¿Eliminar %d evento(s)?",
+	"Edit again?":              "¿Editar de nuevo?",
+	"Move file '%s' to trash?": "¿Mover el archivo '%s' a la papelera?",
+	"Send %d email(s)?":        "¿Enviar %d correo(s)?",
+	"Send %d email(s), skipping %d already sent?": "¿Enviar %d correo(s), omitiendo %d ya enviados?",
+	"Did you mean %s instead of %s?":              "¿Querías decir %s en lugar de %s?",
+	"Discard %d queued email(s)?":                 "¿Descartar %d correo(s) en cola?",
+	"Discard %d queued change(s)?":                "¿Descartar %d cambio(s) en cola?",
+	"Sign in again and retry?":                    "¿Iniciar sesión de nuevo y reintentar?",
+	"The API key for profile '%s' was rejected.":  "La clave de API del perfil '%s' fue rechazada.",
+	"Would you like to set up now?":               "¿Quieres configurarlo ahora?",
+	"Reply: ":                                     "Respuesta: ",
+	"Label: ":                                     "Etiqueta: ",
+	"Aborted.":                                    "Cancelado.",
+	"Cancelled.":                                  "Cancelado.",
+	"Cancelled. No changes made.":                 "Cancelado. No se hicieron cambios.",
+	"Cancelled. No events created.":               "Cancelado. No se crearon eventos.",
+	"Cancelled. Nothing sent.":                    "Cancelado. No se envió nada.",
+	"-- more -- (Space: next page · Enter: next line · q: quit · j: JSON)": "-- más -- (Espacio: página siguiente · Intro: línea siguiente · q: salir · j: JSON)",
+
+	// Setup wizard
+	"CLI Setup":                                    "Configuración de la CLI",
+	"Your data. Your rules.":                       "Tus datos. Tus reglas.",
+	"Let's connect your PortEden account.":         "Conectemos tu cuenta de PortEden.",
+	"We'll open your browser to sign in securely.": "Abriremos tu navegador para iniciar sesión de forma segura.",
+	"You'll open a sign-in link in any browser.":   "Abrirás un enlace de inicio de sesión en cualquier navegador.",
+	"Signing in with the single sign-on of %s.":    "Iniciando sesión con el inicio de sesión único de %s.",
+	"Press Enter to continue...":                   "Pulsa Intro para continuar...",
+	"Opening browser...":                           "Abriendo el navegador...",
+	"Starting sign-in...":                          "Iniciando el inicio de sesión...",
+	"If it doesn't open, visit: ":                  "Si no se abre, visita: ",
+	"Open this link in any browser:":               "Abre este enlace en cualquier navegador:",
+	"Check that the page shows this code:":         "Comprueba que la página muestra este código:",
+	"Waiting for browser authentication... ":       "Esperando la autenticación en el navegador... ",
+	"Please complete sign-in in your browser.":     "Completa el inicio de sesión en tu navegador.",
+	"Authenticated successfully!":                  "¡Autenticación completada!",
+	"Your API key:":                                "Tu clave de API:",
+	"Additional setup":                             "Configuración adicional",
+	"No account configured yet.":                   "Aún no hay ninguna cuenta configurada.",
+	"You're all set!":                              "¡Todo listo!",
+	"Profile: %s":                                  "Perfil: %s",
+	"Get started:":                                 "Para empezar:",
+	"List your calendars":                          "Lista tus calendarios",
+	"Today's events":                               "Eventos de hoy",
+	"Check connection":                             "Comprueba la conexión",
+	"Need help? Check out the docs at %s":          "¿Necesitas ayuda? Consulta la documentación en %s",
+
+	// Table headers
+	"ATTACH":         "ADJUNTO",
+	"ATTENDEES":      "ASISTENTES",
+	"COLS":           "COLUMNAS",
+	"COMMAND":        "COMANDO",
+	"DATE":           "FECHA",
+	"DESCRIPTION":    "DESCRIPCIÓN",
+	"DURATION":       "DURACIÓN",
+	"HOLIDAY":        "FESTIVO",
+	"EMAIL / DOMAIN": "CORREO / DOMINIO",
+	"EMAIL":          "CORREO",
+	"ENCRYPTED":      "CIFRADO",
+	"END":            "FIN",
+	"EXPORT FORMAT":  "FORMATO DE EXPORTACIÓN",
+	"FROM":           "DE",
+	"KEY":            "CLAVE",
+	"KIND":           "TIPO",
+	"LAST ACTIVITY":  "ÚLTIMA ACTIVIDAD",
+	"MODIFIED":       "MODIFICADO",
+	"MSGS":           "MENSAJES",
+	"NAME":           "NOMBRE",
+	"OWNER":          "PROPIETARIO",
+	"PRIMARY":        "PRINCIPAL",
+	"PROVIDER":       "PROVEEDOR",
+	"READ":           "LEÍDO",
+	"REPEATS":        "REPETICIÓN",
+	"ROLE":           "ROL",
+	"ROW":            "FILA",
+	"ROWS":           "FILAS",
+	"SHEET":          "HOJA",
+	"SIZE":           "TAMAÑO",
+	"START":          "INICIO",
+	"STATUS":         "ESTADO",
+	"SUBJECT":        "ASUNTO",
+	"SUMMARY":        "RESUMEN",
+	"THREAD":         "HILO",
+	"TIME":           "HORA",
+	"TIMEZONE":       "ZONA HORARIA",
+	"TITLE":          "TÍTULO",
+	"TYPE":           "TIPO",
+	"UNREAD":         "NO LEÍDOS",
+	"VALUE":          "VALOR",
+
+	// Table footers
+	"%d threads":          "%d hilos",
+	"No matches":          "Sin resultados",
+	"Showing %d-%d of %d": "Mostrando %d-%d de %d",
+	"Showing %d-%d of %d (use --offset %d for more)":             "Mostrando %d-%d de %d (usa --offset %d para ver más)",
+	"Showing %d of %d matches (use --limit for more)":            "Mostrando %d de %d resultados (usa --limit para ver más)",
+	"Showing %d of %d emails":                                    "Mostrando %d de %d correos",
+	"Showing %d emails (more available, use --all to fetch all)": "Mostrando %d correos (hay más, usa --all para obtenerlos todos)",
+	"Showing %d files (more available, use --all to fetch all)":  "Mostrando %d archivos (hay más, usa --all para obtenerlos todos)",
+}
@@ -0,0 +1,92 @@
+// Package i18n localizes user-facing text. Messages are looked up by their
+// English text, so a string missing from a catalog is shown in English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// catalogs maps a language code to its translations, keyed by English text
+var catalogs = map[string]map[string]string{
+	"es": es,
+}
+
+var (
+	mu   sync.RWMutex
+	lang = normalize(os.Getenv("PE_LANG"))
+)
+
+// normalize reduces a locale such as "es_ES.UTF-8" or "es-MX" to a supported
+// language code, or "en"
+func normalize(locale string) string {
+	code := strings.ToLower(locale)
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := catalogs[code]; ok {
+		return code
+	}
+	return "en"
+}
+
+// SetLanguage selects the language for subsequent messages. Unsupported
+// locales select English.
+func SetLanguage(locale string) {
+	mu.Lock()
+	defer mu.Unlock()
+	lang = normalize(locale)
+}
+
+// Language returns the active language code
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lang
+}
+
+// Languages lists the supported language codes
+func Languages() []string {
+	codes := []string{"en"}
+	for code := range catalogs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes[1:])
+	return codes
+}
+
+// T returns msg in the active language. With args, msg is a format string
+// and the translation must use the same verbs.
+func T(msg string, args ...interface{}) string {
+	mu.RLock()
+	if translated, ok := catalogs[lang][msg]; ok {
+		msg = translated
+	}
+	mu.RUnlock()
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Yes reports whether an answer to a yes/no prompt means yes, accepting
+// English answers as well as the active language's
+func Yes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "" {
+		return false
+	}
+	return answer == "y" || answer == "yes" || answer == strings.ToLower(T("y")) || answer == strings.ToLower(T("yes"))
+}
+
+// No reports whether an answer to a yes/no prompt means no
+func No(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "" {
+		return false
+	}
+	return answer == "n" || answer == "no" || answer == strings.ToLower(T("n")) || answer == strings.ToLower(T("no"))
+}
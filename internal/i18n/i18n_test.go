@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"":            "en",
+		"es":          "es",
+		"ES":          "es",
+		"es_ES.UTF-8": "es",
+		"es-MX":       "es",
+		"fr_FR":       "en",
+		"C":           "en",
+	}
+	for in, want := range tests {
+		if got := normalize(in); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(Language())
+
+	SetLanguage("en")
+	if got := T("Showing %d of %d emails", 5, 10); got != "Showing 5 of 10 emails" {
+		t.Errorf("en: got %q", got)
+	}
+
+	SetLanguage("es_ES.UTF-8")
+	if got := T("Showing %d of %d emails", 5, 10); got != "Mostrando 5 de 10 correos" {
+		t.Errorf("es: got %q", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("untranslated: got %q", got)
+	}
+	if got := T("100%"); got != "100%" {
+		t.Errorf("no args: got %q", got)
+	}
+}
+
+func TestYesNo(t *testing.T) {
+	defer SetLanguage(Language())
+	SetLanguage("es")
+
+	for _, a := range []string{"y", "YES", "s", " Sí "} {
+		if !Yes(a) {
+			t.Errorf("Yes(%q) = false", a)
+		}
+	}
+	for _, a := range []string{"", "n", "no", "nope"} {
+		if Yes(a) {
+			t.Errorf("Yes(%q) = true", a)
+		}
+	}
+	if !No("N") || No("") || No("s") {
+		t.Error("No() mismatch")
+	}
+}
+
+// Translations must keep the English text's format verbs, in order
+func TestCatalogVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for code, catalog := range catalogs {
+		for en, tr := range catalog {
+			if !slices.Equal(verbs.FindAllString(en, -1), verbs.FindAllString(tr, -1)) {
+				t.Errorf("%s: %q translates %q with different verbs", code, en, tr)
+			}
+		}
+	}
+}
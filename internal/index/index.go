@@ -0,0 +1,258 @@
+// Package index is a local full-text index of emails and events, stored in
+// the offline cache so searches run without calling the API.
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/porteden/cli/internal/cache"
+)
+
+// formatVersion is bumped whenever the on-disk layout changes; older files
+// are discarded and must be rebuilt
+const formatVersion = 1
+
+// Kinds of indexed documents
+const (
+	KindEmail = "email"
+	KindEvent = "event"
+)
+
+// Document is one indexed email or event
+type Document struct {
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	From    string    `json:"from,omitempty"`
+	Date    time.Time `json:"date"`
+	Snippet string    `json:"snippet,omitempty"`
+	// Text is tokenized for search but not stored
+	Text string `json:"-"`
+	// Terms holds the document's tokens so postings can be rebuilt on load
+	Terms []string `json:"terms"`
+}
+
+// Result is a search hit
+type Result struct {
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	From    string    `json:"from,omitempty"`
+	Date    time.Time `json:"date"`
+	Snippet string    `json:"snippet,omitempty"`
+	Score   int       `json:"score"`
+}
+
+// SearchResponse is the result of Index.Search
+type SearchResponse struct {
+	Query     string    `json:"query"`
+	Results   []Result  `json:"results"`
+	Total     int       `json:"total"`
+	IndexedAt time.Time `json:"indexedAt"`
+}
+
+// Index is an inverted index over documents
+type Index struct {
+	Version   int                  `json:"version"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+	Synced    map[string]time.Time `json:"synced"` // Last sync time per kind
+	Docs      []Document           `json:"docs"`
+
+	byKey    map[string]int
+	postings map[string][]int
+}
+
+// New returns an empty index
+func New() *Index {
+	ix := &Index{Version: formatVersion, Synced: map[string]time.Time{}}
+	ix.reindex()
+	return ix
+}
+
+// FileName is the cache entry holding the index for a profile
+func FileName(profile string) string {
+	return "index-" + profile + ".json"
+}
+
+// Load reads the index for a profile, returning an empty index if none exists
+func Load(profile string) (*Index, error) {
+	data, err := cache.Read(FileName(profile))
+	if errors.Is(err, cache.ErrNotFound) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ix Index
+	if err := json.Unmarshal(data, &ix); err != nil {
+		return nil, fmt.Errorf("index is corrupt, run 'porteden index build': %w", err)
+	}
+	if ix.Version != formatVersion {
+		return New(), nil
+	}
+	if ix.Synced == nil {
+		ix.Synced = map[string]time.Time{}
+	}
+	ix.reindex()
+	return &ix, nil
+}
+
+// Save writes the index for a profile
+func (ix *Index) Save(profile string) error {
+	ix.UpdatedAt = time.Now()
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	return cache.Write(FileName(profile), data)
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	return len(ix.Docs)
+}
+
+// Count returns the number of indexed documents of a kind
+func (ix *Index) Count(kind string) int {
+	n := 0
+	for _, d := range ix.Docs {
+		if d.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Add inserts documents, replacing any existing document with the same kind and ID
+func (ix *Index) Add(docs ...Document) {
+	for _, d := range docs {
+		d.Terms = Tokenize(d.Title + " " + d.From + " " + d.Text)
+		d.Text = ""
+		if i, ok := ix.byKey[key(d.Kind, d.ID)]; ok {
+			ix.Docs[i] = d
+			continue
+		}
+		ix.Docs = append(ix.Docs, d)
+		ix.byKey[key(d.Kind, d.ID)] = len(ix.Docs) - 1
+	}
+	ix.reindex()
+}
+
+// Search returns documents containing every query term, best matches first.
+// The last term also matches as a prefix, so partial words find results.
+func (ix *Index) Search(query, kind string, limit int) *SearchResponse {
+	terms := Tokenize(query)
+	resp := &SearchResponse{Query: query, Results: []Result{}, IndexedAt: ix.UpdatedAt}
+	if len(terms) == 0 {
+		return resp
+	}
+
+	var matches map[int]int // doc index -> score
+	for i, term := range terms {
+		hits := map[int]int{}
+		for _, doc := range ix.postings[term] {
+			hits[doc] += 2 // Exact term match
+		}
+		if i == len(terms)-1 {
+			for t, docs := range ix.postings {
+				if t != term && strings.HasPrefix(t, term) {
+					for _, doc := range docs {
+						hits[doc]++
+					}
+				}
+			}
+		}
+
+		if matches == nil {
+			matches = hits
+			continue
+		}
+		for doc, score := range matches {
+			if s, ok := hits[doc]; ok {
+				matches[doc] = score + s
+			} else {
+				delete(matches, doc)
+			}
+		}
+	}
+
+	for i, score := range matches {
+		d := ix.Docs[i]
+		if kind != "" && d.Kind != kind {
+			continue
+		}
+		// Title matches rank above body matches
+		title := strings.ToLower(d.Title)
+		for _, term := range terms {
+			if strings.Contains(title, term) {
+				score += 3
+			}
+		}
+		resp.Results = append(resp.Results, Result{
+			Kind:    d.Kind,
+			ID:      d.ID,
+			Title:   d.Title,
+			From:    d.From,
+			Date:    d.Date,
+			Snippet: d.Snippet,
+			Score:   score,
+		})
+	}
+
+	sort.Slice(resp.Results, func(i, j int) bool {
+		a, b := resp.Results[i], resp.Results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		return a.ID < b.ID
+	})
+
+	resp.Total = len(resp.Results)
+	if limit > 0 && len(resp.Results) > limit {
+		resp.Results = resp.Results[:limit]
+	}
+	return resp
+}
+
+// Tokenize splits text into unique lowercase terms. Single letters are
+// dropped as noise; single digits are kept.
+func Tokenize(s string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, f := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if (len(f) < 2 && !unicode.IsDigit(rune(f[0]))) || seen[f] {
+			continue
+		}
+		seen[f] = true
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// reindex rebuilds the lookup tables from Docs
+func (ix *Index) reindex() {
+	ix.byKey = make(map[string]int, len(ix.Docs))
+	ix.postings = map[string][]int{}
+	for i, d := range ix.Docs {
+		ix.byKey[key(d.Kind, d.ID)] = i
+		for _, t := range d.Terms {
+			ix.postings[t] = append(ix.postings[t], i)
+		}
+	}
+}
+
+func key(kind, id string) string {
+	return kind + ":" + id
+}
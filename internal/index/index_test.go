@@ -0,0 +1,46 @@
+package index
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	ix := New()
+	ix.Add(
+		Document{Kind: KindEmail, ID: "1", Title: "Quarterly budget review", Text: "numbers attached", Date: time.Unix(100, 0)},
+		Document{Kind: KindEmail, ID: "2", Title: "Lunch", Text: "the budget for lunch is fine", Date: time.Unix(200, 0)},
+		Document{Kind: KindEvent, ID: "3", Title: "Standup", Date: time.Unix(300, 0)},
+	)
+
+	resp := ix.Search("budget", "", 10)
+	if resp.Total != 2 {
+		t.Fatalf("expected 2 results, got %d", resp.Total)
+	}
+	if resp.Results[0].ID != "1" {
+		t.Errorf("expected title match first, got %s", resp.Results[0].ID)
+	}
+
+	if got := ix.Search("budg", "", 10).Total; got != 2 {
+		t.Errorf("expected prefix match on last term, got %d results", got)
+	}
+	if got := ix.Search("budget lunch", "", 10).Total; got != 1 {
+		t.Errorf("expected all terms to be required, got %d results", got)
+	}
+	if got := ix.Search("standup", KindEmail, 10).Total; got != 0 {
+		t.Errorf("expected kind filter to exclude events, got %d results", got)
+	}
+}
+
+func TestAddReplaces(t *testing.T) {
+	ix := New()
+	ix.Add(Document{Kind: KindEmail, ID: "1", Title: "old subject"})
+	ix.Add(Document{Kind: KindEmail, ID: "1", Title: "new subject"})
+
+	if ix.Len() != 1 {
+		t.Fatalf("expected 1 document, got %d", ix.Len())
+	}
+	if ix.Search("old", "", 10).Total != 0 {
+		t.Error("expected replaced terms to be dropped")
+	}
+}
@@ -0,0 +1,268 @@
+// Package markdown converts the common subset of Markdown used in emails to
+// HTML, and to a plain text alternative for clients that don't show HTML.
+//
+// Supported: headings, paragraphs, hard line breaks, bold, italics, inline
+// code, fenced code blocks, links, bullet and numbered lists, block quotes
+// and horizontal rules. Raw HTML in the source is escaped, not passed through.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	heading    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	rule       = regexp.MustCompile(`^ {0,3}(?:(?:- *){3,}|(?:\* *){3,}|(?:_ *){3,})$`)
+	bullet     = regexp.MustCompile(`^ {0,3}[-*+]\s+(.*)$`)
+	numbered   = regexp.MustCompile(`^ {0,3}(\d{1,9})[.)]\s+(.*)$`)
+	quote      = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	fence      = regexp.MustCompile("^ {0,3}(```|~~~)")
+	link       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	autolink   = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	strong     = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	emStar     = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+	emLine     = regexp.MustCompile(`(^|\W)_(\S(?:[^_]*?\S)?)_(\W|$)`)
+	codeSpan   = regexp.MustCompile("`([^`]+)`")
+	linkMarker = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// block is a run of source lines forming one HTML block
+type block struct {
+	kind  string // p, h1-h6, hr, pre, ul, ol, blockquote
+	lines []string
+	start string // first number of an ol
+}
+
+// ToHTML converts Markdown to an HTML fragment
+func ToHTML(src string) string {
+	var b strings.Builder
+	for _, blk := range parse(src) {
+		switch blk.kind {
+		case "hr":
+			b.WriteString("<hr>\n")
+		case "pre":
+			b.WriteString("<pre><code>" + html.EscapeString(strings.Join(blk.lines, "\n")) + "</code></pre>\n")
+		case "blockquote":
+			b.WriteString("<blockquote>\n" + ToHTML(strings.Join(blk.lines, "\n")) + "</blockquote>\n")
+		case "ul", "ol":
+			open := "<" + blk.kind + ">"
+			if blk.kind == "ol" && blk.start != "" && blk.start != "1" {
+				open = `<ol start="` + blk.start + `">`
+			}
+			b.WriteString(open + "\n")
+			for _, item := range blk.lines {
+				b.WriteString("<li>" + inlineHTML(item) + "</li>\n")
+			}
+			b.WriteString("</" + blk.kind + ">\n")
+		case "p":
+			b.WriteString("<p>" + paragraphHTML(blk.lines) + "</p>\n")
+		default: // headings
+			b.WriteString("<" + blk.kind + ">" + inlineHTML(blk.lines[0]) + "</" + blk.kind + ">\n")
+		}
+	}
+	return b.String()
+}
+
+// ToText converts Markdown to readable plain text: markup is dropped and
+// links are written out as "text (url)"
+func ToText(src string) string {
+	var parts []string
+	for _, blk := range parse(src) {
+		switch blk.kind {
+		case "hr":
+			parts = append(parts, "----------")
+		case "pre":
+			parts = append(parts, "    "+strings.Join(blk.lines, "\n    "))
+		case "blockquote":
+			text := strings.TrimRight(ToText(strings.Join(blk.lines, "\n")), "\n")
+			parts = append(parts, "> "+strings.ReplaceAll(text, "\n", "\n> "))
+		case "ul", "ol":
+			var items []string
+			n := 1
+			if blk.start != "" {
+				fmt.Sscan(blk.start, &n)
+			}
+			for i, item := range blk.lines {
+				marker := "- "
+				if blk.kind == "ol" {
+					marker = fmt.Sprintf("%d. ", n+i)
+				}
+				items = append(items, marker+inlineText(item))
+			}
+			parts = append(parts, strings.Join(items, "\n"))
+		case "p":
+			lines := make([]string, len(blk.lines))
+			for i, l := range blk.lines {
+				lines[i] = inlineText(strings.TrimRight(strings.TrimSuffix(strings.TrimRight(l, " "), "\\"), " "))
+			}
+			parts = append(parts, strings.Join(lines, "\n"))
+		default:
+			parts = append(parts, inlineText(blk.lines[0]))
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// parse splits the source into blocks
+func parse(src string) []block {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var blocks []block
+	var cur *block
+	flush := func() {
+		if cur != nil {
+			blocks = append(blocks, *cur)
+			cur = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if m := fence.FindStringSubmatch(line); m != nil {
+			flush()
+			code := block{kind: "pre"}
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimLeft(lines[i], " "), m[1]); i++ {
+				code.lines = append(code.lines, lines[i])
+			}
+			blocks = append(blocks, code)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if m := quote.FindStringSubmatch(line); m != nil {
+			if cur == nil || cur.kind != "blockquote" {
+				flush()
+				cur = &block{kind: "blockquote"}
+			}
+			cur.lines = append(cur.lines, m[1])
+			continue
+		}
+		if m := heading.FindStringSubmatch(line); m != nil {
+			flush()
+			blocks = append(blocks, block{kind: fmt.Sprintf("h%d", len(m[1])), lines: []string{m[2]}})
+			continue
+		}
+		if rule.MatchString(line) {
+			flush()
+			blocks = append(blocks, block{kind: "hr"})
+			continue
+		}
+		if m := bullet.FindStringSubmatch(line); m != nil {
+			if cur == nil || cur.kind != "ul" {
+				flush()
+				cur = &block{kind: "ul"}
+			}
+			cur.lines = append(cur.lines, m[1])
+			continue
+		}
+		if m := numbered.FindStringSubmatch(line); m != nil {
+			if cur == nil || cur.kind != "ol" {
+				flush()
+				cur = &block{kind: "ol", start: strings.TrimLeft(m[1], "0")}
+			}
+			cur.lines = append(cur.lines, m[2])
+			continue
+		}
+		// Indented lines continue a list item; anything else a paragraph
+		if cur != nil && (cur.kind == "ul" || cur.kind == "ol") && strings.HasPrefix(line, "  ") {
+			cur.lines[len(cur.lines)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		if cur == nil || cur.kind != "p" {
+			flush()
+			cur = &block{kind: "p"}
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	flush()
+	return blocks
+}
+
+// paragraphHTML joins a paragraph's lines, turning lines ending in two
+// spaces or a backslash into hard breaks
+func paragraphHTML(lines []string) string {
+	var b strings.Builder
+	for i, l := range lines {
+		hard := strings.HasSuffix(l, "  ") || strings.HasSuffix(l, "\\")
+		l = strings.TrimSuffix(strings.TrimRight(l, " "), "\\")
+		b.WriteString(inlineHTML(strings.TrimSpace(l)))
+		if i < len(lines)-1 {
+			if hard {
+				b.WriteString("<br>")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// inlineHTML converts inline markup. Code spans and link targets are set
+// aside first so emphasis markers inside them are left alone.
+func inlineHTML(s string) string {
+	var saved []string
+	keep := func(h string) string {
+		saved = append(saved, h)
+		return fmt.Sprintf("\x00%d\x00", len(saved)-1)
+	}
+	s = codeSpan.ReplaceAllStringFunc(s, func(m string) string {
+		return keep("<code>" + html.EscapeString(codeSpan.FindStringSubmatch(m)[1]) + "</code>")
+	})
+	s = autolink.ReplaceAllStringFunc(s, func(m string) string {
+		u := autolink.FindStringSubmatch(m)[1]
+		return keep(`<a href="` + html.EscapeString(u) + `">` + html.EscapeString(u) + "</a>")
+	})
+	s = link.ReplaceAllStringFunc(s, func(m string) string {
+		sub := link.FindStringSubmatch(m)
+		return keep(`<a href="` + html.EscapeString(sub[2]) + `">` + emphasize(html.EscapeString(sub[1])) + "</a>")
+	})
+	s = emphasize(html.EscapeString(s))
+	return restore(s, saved)
+}
+
+// inlineText drops inline markup, writing links out in full
+func inlineText(s string) string {
+	var saved []string
+	keep := func(t string) string {
+		saved = append(saved, t)
+		return fmt.Sprintf("\x00%d\x00", len(saved)-1)
+	}
+	s = codeSpan.ReplaceAllStringFunc(s, func(m string) string {
+		return keep(codeSpan.FindStringSubmatch(m)[1])
+	})
+	s = autolink.ReplaceAllStringFunc(s, func(m string) string {
+		return keep(autolink.FindStringSubmatch(m)[1])
+	})
+	s = link.ReplaceAllStringFunc(s, func(m string) string {
+		sub := link.FindStringSubmatch(m)
+		text := unemphasize(sub[1])
+		if text == sub[2] {
+			return keep(text)
+		}
+		return keep(text + " (" + sub[2] + ")")
+	})
+	return restore(unemphasize(s), saved)
+}
+
+func emphasize(s string) string {
+	s = strong.ReplaceAllString(s, "<strong>$1$2</strong>")
+	s = emStar.ReplaceAllString(s, "<em>$1</em>")
+	return emLine.ReplaceAllString(s, "$1<em>$2</em>$3")
+}
+
+func unemphasize(s string) string {
+	s = strong.ReplaceAllString(s, "$1$2")
+	s = emStar.ReplaceAllString(s, "$1")
+	return emLine.ReplaceAllString(s, "$1$2$3")
+}
+
+func restore(s string, saved []string) string {
+	return linkMarker.ReplaceAllStringFunc(s, func(m string) string {
+		var i int
+		fmt.Sscan(strings.Trim(m, "\x00"), &i)
+		return saved[i]
+	})
+}
@@ -0,0 +1,37 @@
+package markdown
+
+import "testing"
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{name: "heading", in: "## Agenda ##", want: "<h2>Agenda</h2>\n"},
+		{name: "paragraph", in: "one\ntwo", want: "<p>one\ntwo</p>\n"},
+		{name: "hard break", in: "one  \ntwo\\\nthree", want: "<p>one<br>\ntwo<br>\nthree</p>\n"},
+		{name: "emphasis", in: "**bold**, *it* and _it_", want: "<p><strong>bold</strong>, <em>it</em> and <em>it</em></p>\n"},
+		{name: "snake case", in: "call do_the_thing", want: "<p>call do_the_thing</p>\n"},
+		{name: "code span", in: "run `a *b* <c>`", want: "<p>run <code>a *b* &lt;c&gt;</code></p>\n"},
+		{name: "link", in: "[the *docs*](https://x.io/a_b_c)", want: `<p><a href="https://x.io/a_b_c">the <em>docs</em></a></p>` + "\n"},
+		{name: "autolink", in: "<https://x.io>", want: `<p><a href="https://x.io">https://x.io</a></p>` + "\n"},
+		{name: "raw html escaped", in: "<script>x</script>", want: "<p>&lt;script&gt;x&lt;/script&gt;</p>\n"},
+		{name: "bullets", in: "- one\n* two\n  more", want: "<ul>\n<li>one</li>\n<li>two more</li>\n</ul>\n"},
+		{name: "numbered", in: "3. a\n4. b", want: "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{name: "quote", in: "> hi\n> there", want: "<blockquote>\n<p>hi\nthere</p>\n</blockquote>\n"},
+		{name: "fence", in: "```go\nx := <-ch\n```", want: "<pre><code>x := &lt;-ch</code></pre>\n"},
+		{name: "rule", in: "a\n\n* * *\n\nb", want: "<p>a</p>\n<hr>\n<p>b</p>\n"},
+	}
+	for _, tt := range tests {
+		if got := ToHTML(tt.in); got != tt.want {
+			t.Errorf("%s: ToHTML(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToText(t *testing.T) {
+	in := "# Update\n\nShipped **v2** — see [notes](https://x.io/notes) or <https://x.io>.\n\n1. first\n2. second\n\n> quoted\n\n```\ncode\n```"
+	want := "Update\n\nShipped v2 — see notes (https://x.io/notes) or https://x.io.\n\n1. first\n2. second\n\n> quoted\n\n    code\n"
+	if got := ToText(in); got != want {
+		t.Errorf("ToText = %q, want %q", got, want)
+	}
+}
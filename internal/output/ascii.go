@@ -0,0 +1,43 @@
+package output
+
+import "strings"
+
+var asciiEnabled = false
+
+// asciiReplacer maps the box drawing characters and symbols used in terminal
+// output to plain ASCII
+var asciiReplacer = strings.NewReplacer(
+	"─", "-",
+	"│", "|",
+	"┌", "+",
+	"┐", "+",
+	"└", "+",
+	"┘", "+",
+	"├", "+",
+	"—", "-",
+	"–", "-",
+	"·", "-",
+	"…", "...",
+	"✓", "OK",
+	"✗", "X",
+)
+
+// SetASCII switches box drawing characters, dashes and checkmarks to plain
+// ASCII, for screen readers and limited terminals
+func SetASCII(enabled bool) {
+	asciiEnabled = enabled
+}
+
+// ASCIIEnabled reports whether ASCII mode is on
+func ASCIIEnabled() bool {
+	return asciiEnabled
+}
+
+// Symbols returns s unchanged, or with its box drawing characters and symbols
+// replaced by ASCII when ASCII mode is on
+func Symbols(s string) string {
+	if !asciiEnabled {
+		return s
+	}
+	return asciiReplacer.Replace(s)
+}
@@ -0,0 +1,201 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// CSV column names are part of the output contract: dashboards ingest them on a
+// schedule, so columns may be appended but never renamed or reordered.
+var (
+	eventCSVColumns    = []string{"id", "calendar_id", "title", "start_utc", "end_utc", "duration_minutes", "status", "all_day", "location", "organizer", "attendee_count", "is_recurring"}
+	calendarCSVColumns = []string{"id", "name", "provider", "timezone", "is_primary", "owner_email"}
+	shareCSVColumns    = []string{"email", "role", "type"}
+	busyCSVColumns     = []string{"calendar_id", "calendar_name", "start_utc", "end_utc", "duration_minutes"}
+	emailCSVColumns    = []string{"id", "thread_id", "received_utc", "from_email", "from_name", "subject", "is_read", "has_attachments", "labels"}
+	driveCSVColumns    = []string{"id", "name", "type", "mime_type", "size_bytes", "modified_time", "owner", "is_folder"}
+	threadCSVColumns   = []string{"thread_id", "last_activity_utc", "messages", "unread", "senders", "subject", "latest_message_id"}
+)
+
+func printCSV(data interface{}) {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	switch v := data.(type) {
+	case *api.EventsResponse:
+		writeEventsCSV(w, v.Events)
+	case []api.Event:
+		writeEventsCSV(w, v)
+	case *api.SingleEventResponse:
+		writeEventsCSV(w, []api.Event{v.Event})
+	case *api.Event:
+		writeEventsCSV(w, []api.Event{*v})
+	case *api.CalendarsResponse:
+		writeCalendarsCSV(w, v.Data)
+	case []api.Calendar:
+		writeCalendarsCSV(w, v)
+	case *api.CalendarSharesResponse:
+		_ = w.Write(shareCSVColumns)
+		for _, s := range v.Shares {
+			_ = w.Write([]string{s.Email, s.Role, s.Type})
+		}
+	case *api.FreeBusyResponse:
+		_ = w.Write(busyCSVColumns)
+		for _, cal := range v.Calendars {
+			for _, b := range cal.Busy {
+				_ = w.Write([]string{
+					strconv.FormatInt(cal.CalendarID, 10),
+					cal.CalendarName,
+					csvTime(b.StartUtc),
+					csvTime(b.EndUtc),
+					strconv.Itoa(b.DurationMinutes),
+				})
+			}
+		}
+	case *api.EmailsResponse:
+		writeEmailsCSV(w, v.Emails)
+	case *api.SingleEmailResponse:
+		writeEmailsCSV(w, []api.Email{v.Email})
+	case *api.Email:
+		writeEmailsCSV(w, []api.Email{*v})
+	case *api.ThreadResponse:
+		writeEmailsCSV(w, v.Messages)
+	case []ThreadGroup:
+		_ = w.Write(threadCSVColumns)
+		for _, g := range v {
+			_ = w.Write([]string{
+				g.ThreadID,
+				csvTime(g.LastActivity),
+				strconv.Itoa(g.Messages),
+				strconv.Itoa(g.Unread),
+				strings.Join(g.Senders, ";"),
+				g.Subject,
+				g.LatestID,
+			})
+		}
+	case *api.DriveFilesResponse:
+		writeDriveFilesCSV(w, v.Files)
+	case *api.SingleDriveFileResponse:
+		if v.File != nil {
+			writeDriveFilesCSV(w, []api.DriveFile{*v.File})
+		}
+	case *api.SheetValuesResponse:
+		for _, row := range v.Values {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = fmt.Sprintf("%v", cell)
+			}
+			_ = w.Write(cells)
+		}
+	default:
+		// No tabular shape for this type; fall back to plain output
+		w.Flush()
+		printPlain(data)
+	}
+}
+
+func writeEventsCSV(w *csv.Writer, events []api.Event) {
+	_ = w.Write(eventCSVColumns)
+	writeEventsCSVRows(w, events)
+}
+
+func writeEventsCSVRows(w *csv.Writer, events []api.Event) {
+	for _, e := range events {
+		title := e.Title
+		if title == "" {
+			title = e.Summary
+		}
+		_ = w.Write([]string{
+			e.ID,
+			strconv.FormatInt(e.CalendarID, 10),
+			title,
+			csvTime(e.StartUtc),
+			csvTime(e.EndUtc),
+			strconv.Itoa(e.DurationMinutes),
+			e.Status,
+			strconv.FormatBool(e.AllDay || e.IsAllDay),
+			e.Location,
+			e.Organizer,
+			strconv.Itoa(len(e.Attendees)),
+			strconv.FormatBool(e.IsRecurringEvent),
+		})
+	}
+}
+
+func writeCalendarsCSV(w *csv.Writer, calendars []api.Calendar) {
+	_ = w.Write(calendarCSVColumns)
+	for _, c := range calendars {
+		_ = w.Write([]string{
+			strconv.FormatInt(c.ID, 10),
+			c.Name,
+			c.Provider,
+			c.Timezone,
+			strconv.FormatBool(c.IsPrimary),
+			c.OwnerEmail,
+		})
+	}
+}
+
+func writeEmailsCSV(w *csv.Writer, emails []api.Email) {
+	_ = w.Write(emailCSVColumns)
+	writeEmailsCSVRows(w, emails)
+}
+
+func writeEmailsCSVRows(w *csv.Writer, emails []api.Email) {
+	for _, e := range emails {
+		fromEmail, fromName := "", ""
+		if e.From != nil {
+			fromEmail, fromName = e.From.Email, e.From.Name
+		}
+		_ = w.Write([]string{
+			e.ID,
+			e.ThreadID,
+			csvTime(e.ReceivedAt),
+			fromEmail,
+			fromName,
+			e.Subject,
+			strconv.FormatBool(e.IsRead),
+			strconv.FormatBool(e.HasAttachments),
+			strings.Join(e.Labels, ";"),
+		})
+	}
+}
+
+func writeDriveFilesCSV(w *csv.Writer, files []api.DriveFile) {
+	_ = w.Write(driveCSVColumns)
+	writeDriveFilesCSVRows(w, files)
+}
+
+func writeDriveFilesCSVRows(w *csv.Writer, files []api.DriveFile) {
+	for _, f := range files {
+		size := ""
+		if f.Size != nil && !f.IsFolder {
+			size = strconv.FormatInt(*f.Size, 10)
+		}
+		mimeType := derefStr(f.MimeType)
+		_ = w.Write([]string{
+			f.ID,
+			derefStr(f.Name),
+			friendlyMimeType(mimeType, f.IsFolder),
+			mimeType,
+			size,
+			derefStr(f.ModifiedTime),
+			driveFileOwner(f),
+			strconv.FormatBool(f.IsFolder),
+		})
+	}
+}
+
+// csvTime formats timestamps in UTC so rows are stable regardless of PE_TIMEZONE
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
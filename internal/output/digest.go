@@ -0,0 +1,185 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// DigestStyle selects the markup used when rendering a digest
+type DigestStyle string
+
+const (
+	DigestText     DigestStyle = "text"
+	DigestMarkdown DigestStyle = "markdown"
+	DigestHTML     DigestStyle = "html"
+)
+
+// Digest is the combined agenda and unread-email summary for a single day
+type Digest struct {
+	Date        string      `json:"date"`
+	Events      []api.Event `json:"events"`
+	Unread      []api.Email `json:"unread"`
+	UnreadTotal int         `json:"unreadTotal"`
+	MoreUnread  bool        `json:"moreUnread,omitempty"`
+}
+
+// RenderDigest writes the digest in the requested style
+func RenderDigest(w io.Writer, d *Digest, style DigestStyle) {
+	switch style {
+	case DigestMarkdown:
+		renderDigestMarkdown(w, d)
+	case DigestHTML:
+		renderDigestHTML(w, d)
+	default:
+		renderDigestText(w, d)
+	}
+}
+
+func renderDigestText(w io.Writer, d *Digest) {
+	fmt.Fprintf(w, "Daily digest for %s\n\n", d.Date)
+
+	fmt.Fprintf(w, "Agenda (%d)\n", len(d.Events))
+	if len(d.Events) == 0 {
+		fmt.Fprintln(w, "  No events scheduled.")
+	}
+	for _, e := range d.Events {
+		fmt.Fprintf(w, "  %s  %s\n", digestEventTime(e), digestEventTitle(e))
+		if e.Location != "" {
+			fmt.Fprintf(w, "         at %s\n", e.Location)
+		}
+		if e.JoinUrl != "" {
+			fmt.Fprintf(w, "         join %s\n", e.JoinUrl)
+		}
+	}
+
+	fmt.Fprintf(w, "\nUnread email (%s)\n", digestUnreadCount(d))
+	if len(d.Unread) == 0 {
+		fmt.Fprintln(w, "  Inbox zero.")
+	}
+	for _, e := range d.Unread {
+		fmt.Fprintf(w, "  %s  %s\n", truncate(digestSender(e), 24), e.Subject)
+		if e.BodyPreview != "" {
+			fmt.Fprintf(w, "         %s\n", truncate(oneLine(e.BodyPreview), 80))
+		}
+	}
+}
+
+func renderDigestMarkdown(w io.Writer, d *Digest) {
+	fmt.Fprintf(w, "# Daily digest for %s\n\n", d.Date)
+
+	fmt.Fprintf(w, "## Agenda (%d)\n\n", len(d.Events))
+	if len(d.Events) == 0 {
+		fmt.Fprintln(w, "_No events scheduled._")
+	}
+	for _, e := range d.Events {
+		line := fmt.Sprintf("- **%s** %s", digestEventTime(e), digestEventTitle(e))
+		if e.Location != "" {
+			line += Symbols(" — ") + e.Location
+		}
+		if e.JoinUrl != "" {
+			line += fmt.Sprintf(" ([join](%s))", e.JoinUrl)
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintf(w, "\n## Unread email (%s)\n\n", digestUnreadCount(d))
+	if len(d.Unread) == 0 {
+		fmt.Fprintln(w, "_Inbox zero._")
+	}
+	for _, e := range d.Unread {
+		fmt.Fprintf(w, "- **%s**: %s\n", digestSender(e), e.Subject)
+		if e.BodyPreview != "" {
+			fmt.Fprintf(w, "  > %s\n", truncate(oneLine(e.BodyPreview), 120))
+		}
+	}
+}
+
+func renderDigestHTML(w io.Writer, d *Digest) {
+	esc := html.EscapeString
+
+	fmt.Fprintf(w, "<h1>Daily digest for %s</h1>\n", esc(d.Date))
+
+	fmt.Fprintf(w, "<h2>Agenda (%d)</h2>\n", len(d.Events))
+	if len(d.Events) == 0 {
+		fmt.Fprintln(w, "<p><em>No events scheduled.</em></p>")
+	} else {
+		fmt.Fprintln(w, "<ul>")
+		for _, e := range d.Events {
+			fmt.Fprintf(w, "  <li><strong>%s</strong> %s", esc(digestEventTime(e)), esc(digestEventTitle(e)))
+			if e.Location != "" {
+				fmt.Fprintf(w, " &mdash; %s", esc(e.Location))
+			}
+			if e.JoinUrl != "" {
+				fmt.Fprintf(w, ` (<a href="%s">join</a>)`, esc(e.JoinUrl))
+			}
+			fmt.Fprintln(w, "</li>")
+		}
+		fmt.Fprintln(w, "</ul>")
+	}
+
+	fmt.Fprintf(w, "<h2>Unread email (%s)</h2>\n", esc(digestUnreadCount(d)))
+	if len(d.Unread) == 0 {
+		fmt.Fprintln(w, "<p><em>Inbox zero.</em></p>")
+	} else {
+		fmt.Fprintln(w, "<ul>")
+		for _, e := range d.Unread {
+			fmt.Fprintf(w, "  <li><strong>%s</strong>: %s", esc(digestSender(e)), esc(e.Subject))
+			if e.BodyPreview != "" {
+				fmt.Fprintf(w, "<br><small>%s</small>", esc(truncate(oneLine(e.BodyPreview), 160)))
+			}
+			fmt.Fprintln(w, "</li>")
+		}
+		fmt.Fprintln(w, "</ul>")
+	}
+}
+
+func digestEventTime(e api.Event) string {
+	if e.AllDay || e.IsAllDay {
+		return "all day"
+	}
+	start := safeTime(GetLocalStart(e.StartLocal, e.StartUtc))
+	end := safeTime(GetLocalEnd(e.EndLocal, e.EndUtc))
+	if end == "" {
+		return start
+	}
+	return start + "-" + end
+}
+
+func digestEventTitle(e api.Event) string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return e.Summary
+}
+
+func digestSender(e api.Email) string {
+	if e.From == nil {
+		return "(unknown sender)"
+	}
+	if e.From.Name != "" {
+		return e.From.Name
+	}
+	return e.From.Email
+}
+
+func digestUnreadCount(d *Digest) string {
+	if d.MoreUnread {
+		return fmt.Sprintf("%d+", d.UnreadTotal)
+	}
+	return fmt.Sprintf("%d", d.UnreadTotal)
+}
+
+// oneLine collapses whitespace so previews fit on a single line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// DigestDate formats the digest heading date in the output timezone
+func DigestDate(t time.Time) string {
+	return t.In(GetOutputLocation()).Format("Monday, January 2, 2006")
+}
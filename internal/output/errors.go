@@ -0,0 +1,24 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ErrorDetail is the machine-readable shape of a failed command
+type ErrorDetail struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Status    int    `json:"status,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// PrintJSONError writes an error as {"error": {...}} so agents don't need to
+// scrape human-readable messages
+func PrintJSONError(w io.Writer, detail ErrorDetail) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(struct {
+		Error ErrorDetail `json:"error"`
+	}{detail})
+}
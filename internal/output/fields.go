@@ -0,0 +1,200 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// fieldPaths are the dotted JSON paths kept by --fields; nil keeps everything
+var fieldPaths [][]string
+
+// itemKeys name the field holding a response's results, in the order they
+// are looked for
+var itemKeys = []string{"events", "emails", "messages", "files", "results", "data", "shares", "calendars", "event", "email", "file"}
+
+// SetFields limits JSON output to the given dotted paths, such as
+// "attendees.email". Paths are matched against the response first, then
+// against each result it holds, keeping the response's other fields (such
+// as meta) so paging still works.
+func SetFields(fields []string) {
+	fieldPaths = nil
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			fieldPaths = append(fieldPaths, strings.Split(f, "."))
+		}
+	}
+}
+
+// orderedObject is a JSON object that keeps its keys in order
+type orderedObject []objectField
+
+type objectField struct {
+	key   string
+	value interface{}
+}
+
+func (o orderedObject) get(key string) (interface{}, int) {
+	for i, f := range o {
+		if f.key == key {
+			return f.value, i
+		}
+	}
+	return nil, -1
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toJSONValue round-trips data through JSON into orderedObjects, slices and
+// scalars, keeping the field order of the original structs
+func toJSONValue(data interface{}) (interface{}, bool) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	v, err := readJSONValue(dec)
+	return v, err == nil
+}
+
+func readJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := orderedObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, objectField{key.(string), value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := readJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// itemsField returns the index of the field of obj holding its results, or -1
+func itemsField(obj orderedObject) int {
+	for _, key := range itemKeys {
+		if _, i := obj.get(key); i >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectFields prunes a response to the --fields paths
+func selectFields(v interface{}) interface{} {
+	if fieldPaths == nil {
+		return v
+	}
+	obj, ok := v.(orderedObject)
+	if !ok {
+		return project(v, fieldPaths)
+	}
+	for _, p := range fieldPaths {
+		if _, i := obj.get(p[0]); i >= 0 {
+			return project(obj, fieldPaths)
+		}
+	}
+	i := itemsField(obj)
+	if i < 0 {
+		return project(obj, fieldPaths)
+	}
+	out := append(orderedObject{}, obj...)
+	out[i].value = project(obj[i].value, fieldPaths)
+	return out
+}
+
+// selectItemFields prunes a single streamed result to the --fields paths
+func selectItemFields(v interface{}) interface{} {
+	if fieldPaths == nil {
+		return v
+	}
+	return project(v, fieldPaths)
+}
+
+// project keeps the paths of v, applying them to each element of arrays.
+// Fields come out in the order they were asked for.
+func project(v interface{}, paths [][]string) interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, e := range x {
+			out[i] = project(e, paths)
+		}
+		return out
+	case orderedObject:
+		out := orderedObject{}
+		done := make(map[string]bool)
+		for _, p := range paths {
+			key := p[0]
+			if done[key] {
+				continue
+			}
+			done[key] = true
+			value, i := x.get(key)
+			if i < 0 {
+				continue
+			}
+			var sub [][]string
+			whole := false
+			for _, q := range paths {
+				if q[0] != key {
+					continue
+				}
+				if len(q) == 1 {
+					whole = true
+					break
+				}
+				sub = append(sub, q[1:])
+			}
+			if !whole {
+				value = project(value, sub)
+			}
+			out = append(out, objectField{key, value})
+		}
+		return out
+	default:
+		return v
+	}
+}
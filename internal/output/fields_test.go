@@ -0,0 +1,127 @@
+package output
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// capturePrint returns what print writes to stdout
+func capturePrint(t *testing.T, print func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	print()
+	w.Close()
+	return <-done
+}
+
+// compactJSON strips the indentation of printed JSON, for comparison
+func compactJSON(s string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		b.WriteString(strings.Replace(line, `": `, `":`, 1))
+	}
+	return b.String()
+}
+
+// eventsMeta is the meta of testEvents as printed, standing in for META in
+// expected output
+const eventsMeta = `"meta":{"count":2,"hasMore":true,"nextPageToken":"p2","from":"0001-01-01T00:00:00Z","to":"0001-01-01T00:00:00Z","timestamp":"0001-01-01T00:00:00Z"}`
+
+func testEvents() *api.EventsResponse {
+	return &api.EventsResponse{
+		Events: []api.Event{
+			{ID: "e1", Title: "Standup", Location: "Room 1", Attendees: []api.Attendee{
+				{Email: "sam@example.com", Name: "Sam", Response: "accepted"},
+				{Email: "kim@example.com", Response: "declined"},
+			}},
+			{ID: "e2", Title: "Lunch"},
+		},
+		Meta: &api.Meta{Count: 2, HasMore: true, NextPageToken: "p2"},
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		data   interface{}
+		want   string
+	}{
+		{"item fields keep meta", []string{"id", "title"}, testEvents(),
+			`{"events":[{"id":"e1","title":"Standup"},{"id":"e2","title":"Lunch"}],META}`},
+		{"asked-for order", []string{"title", "id"}, testEvents(),
+			`{"events":[{"title":"Standup","id":"e1"},{"title":"Lunch","id":"e2"}],META}`},
+		{"nested path through an array", []string{"id", "attendees.email"}, testEvents(),
+			`{"events":[{"id":"e1","attendees":[{"email":"sam@example.com"},{"email":"kim@example.com"}]},{"id":"e2"}],META}`},
+		{"two nested paths", []string{"attendees.email", "attendees.response"}, testEvents(),
+			`{"events":[{"attendees":[{"email":"sam@example.com","response":"accepted"},{"email":"kim@example.com","response":"declined"}]},{}],META}`},
+		{"whole field wins over a path into it", []string{"meta.count", "meta"}, testEvents(),
+			`{META}`},
+		{"response-level path", []string{"meta.nextPageToken"}, testEvents(),
+			`{"meta":{"nextPageToken":"p2"}}`},
+		{"unknown fields are left out", []string{"id", "nosuch", "attendees.nosuch"}, testEvents(),
+			`{"events":[{"id":"e1","attendees":[{},{}]},{"id":"e2"}],META}`},
+		{"only unknown fields", []string{"nosuch"}, testEvents(),
+			`{"events":[{},{}],META}`},
+		{"top-level array", []string{"email"}, []api.Attendee{{Email: "sam@example.com", Name: "Sam"}},
+			`[{"email":"sam@example.com"}]`},
+		{"single result", []string{"title", "location"}, &api.Event{ID: "e1", Title: "Standup", Location: "Room 1"},
+			`{"title":"Standup","location":"Room 1"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetFields(tt.fields)
+			defer SetFields(nil)
+			got := compactJSON(capturePrint(t, func() { PrintWithOptions(tt.data, FormatJSON, PrintOptions{}) }))
+			if want := strings.Replace(tt.want, "META", eventsMeta, 1); got != want {
+				t.Errorf("--fields %v:\ngot  %s\nwant %s", tt.fields, got, want)
+			}
+		})
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	SetFields([]string{" id ", "", "attendees.email"})
+	defer SetFields(nil)
+	if len(fieldPaths) != 2 || fieldPaths[0][0] != "id" || len(fieldPaths[1]) != 2 || fieldPaths[1][1] != "email" {
+		t.Errorf("fieldPaths = %q", fieldPaths)
+	}
+
+	// Without --fields, output is left as it is
+	SetFields(nil)
+	out := capturePrint(t, func() { PrintWithOptions(&api.Event{ID: "e1", Title: "Standup"}, FormatJSON, PrintOptions{}) })
+	if !strings.Contains(out, `"title": "Standup"`) || !strings.Contains(out, `"id": "e1"`) {
+		t.Errorf("unfiltered output = %s", out)
+	}
+}
+
+func TestStreamedFields(t *testing.T) {
+	SetFields([]string{"id", "attendees.email"})
+	defer SetFields(nil)
+	out := capturePrint(t, func() {
+		s := NewStreamer(FormatJSON, PrintOptions{})
+		s.Page(testEvents())
+		s.Close()
+	})
+	want := `{"id":"e1","attendees":[{"email":"sam@example.com"},{"email":"kim@example.com"}]}` + "\n" + `{"id":"e2"}` + "\n"
+	if out != want {
+		t.Errorf("streamed:\ngot  %s\nwant %s", out, want)
+	}
+}
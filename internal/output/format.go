@@ -3,11 +3,16 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
 
 	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/i18n"
+	"github.com/porteden/cli/internal/index"
 )
 
 type Format string
@@ -16,11 +21,14 @@ const (
 	FormatJSON  Format = "json"
 	FormatTable Format = "table"
 	FormatPlain Format = "plain"
+	FormatCSV   Format = "csv"
 )
 
 // PrintOptions configures output behavior
 type PrintOptions struct {
 	Compact bool
+	// Highlight is a search query whose terms are highlighted in table output
+	Highlight string
 }
 
 func Print(data interface{}, format Format) {
@@ -38,7 +46,11 @@ func PrintWithOptions(data interface{}, format Format, opts PrintOptions) {
 		printJSON(data)
 	case FormatPlain:
 		printPlain(data)
+	case FormatCSV:
+		printCSV(data)
 	default:
+		setHighlight(opts.Highlight)
+		defer setHighlight("")
 		printTable(data)
 	}
 }
@@ -79,6 +91,11 @@ func applyCompact(data interface{}) interface{} {
 }
 
 func printJSON(data interface{}) {
+	if fieldPaths != nil || maxItems > 0 || maxOutputBytes > 0 {
+		if v, ok := toJSONValue(data); ok {
+			data = limitOutput(selectFields(v))
+		}
+	}
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	_ = enc.Encode(data)
@@ -86,10 +103,18 @@ func printJSON(data interface{}) {
 
 func printPlain(data interface{}) {
 	switch v := data.(type) {
+	case *index.SearchResponse:
+		printSearchResultsPlain(v.Results)
+	case *SearchResults:
+		printSearchResultsPlain(v.Results)
 	case *api.EventsResponse:
 		printEventsPlain(v.Events)
 	case *api.CalendarsResponse:
 		printCalendarsPlain(v.Data)
+	case *api.CalendarSharesResponse:
+		for _, s := range v.Shares {
+			fmt.Printf("%s\t%s\t%s\n", s.Email, s.Role, s.Type)
+		}
 	case []api.Event:
 		printEventsPlain(v)
 	case []api.Calendar:
@@ -104,10 +129,14 @@ func printPlain(data interface{}) {
 	case *api.FreeBusyResponse:
 		for _, cal := range v.Calendars {
 			for _, b := range cal.Busy {
-				fmt.Printf("%d\t%s\t%s\t%s\t%dm\n",
+				fmt.Printf("%d\t%s\t%s\t%s\t%dm",
 					cal.CalendarID, cal.CalendarName,
 					FormatLocalTime(b.StartUtc), FormatLocalTime(b.EndUtc),
 					b.DurationMinutes)
+				if b.Holiday != "" {
+					fmt.Printf("\t%s", b.Holiday)
+				}
+				fmt.Println()
 			}
 		}
 	case *api.DeleteEventResponse:
@@ -123,6 +152,8 @@ func printPlain(data interface{}) {
 		printEmailPlain(*v)
 	case *api.ThreadResponse:
 		printThreadPlain(v)
+	case []ThreadGroup:
+		printThreadGroupsPlain(v)
 	// Drive
 	case *api.DriveFilesResponse:
 		printDriveFilesPlain(v.Files)
@@ -184,10 +215,20 @@ func printPlain(data interface{}) {
 }
 
 func printTable(data interface{}) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
+	out, buf := tableOutput()
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer func() {
+		w.Flush()
+		if buf != nil {
+			pageOutput(buf.Bytes(), func() { printJSON(data) })
+		}
+	}()
 
 	switch v := data.(type) {
+	case *index.SearchResponse:
+		printSearchResultsTable(w, v.Results, v.Total)
+	case *SearchResults:
+		printSearchResultsTable(w, v.Results, v.Total)
 	// Handle wrapped API responses
 	case *api.EventsResponse:
 		printEventsTable(w, v.Events, v.Meta)
@@ -199,6 +240,11 @@ func printTable(data interface{}) {
 		if v.AccessInfo != "" {
 			fmt.Fprintf(w, "\nAccess: %s\n", v.AccessInfo)
 		}
+	case *api.CalendarSharesResponse:
+		printCalendarSharesTable(w, v.Shares)
+		if v.AccessInfo != "" {
+			fmt.Fprintf(w, "\nAccess: %s\n", v.AccessInfo)
+		}
 	// Handle unwrapped slices (for backward compatibility)
 	case []api.Event:
 		printEventsTable(w, v, nil)
@@ -229,6 +275,8 @@ func printTable(data interface{}) {
 		printEmailDetail(w, *v)
 	case *api.ThreadResponse:
 		printThreadTable(w, v)
+	case []ThreadGroup:
+		printThreadGroupsTable(w, v)
 	// Drive
 	case *api.DriveFilesResponse:
 		printDriveFilesTable(w, v.Files, v.HasMore)
@@ -265,8 +313,27 @@ func printTable(data interface{}) {
 }
 
 func printEventsTable(w *tabwriter.Writer, events []api.Event, meta *api.Meta) {
-	fmt.Fprintln(w, "ID\tDATE\tTIME\tDURATION\tTITLE\tSTATUS")
-	fmt.Fprintln(w, "──\t────\t────\t────────\t─────\t──────")
+	printEventsTableHeader(w)
+	printEventsTableRows(w, events)
+
+	// Display pagination info if available
+	if meta != nil && meta.TotalCount > 0 {
+		start := meta.Offset + 1
+		end := meta.Offset + meta.Count
+		if meta.HasMore {
+			fmt.Fprintln(w, "\n"+i18n.T("Showing %d-%d of %d (use --offset %d for more)",
+				start, end, meta.TotalCount, end))
+		} else {
+			fmt.Fprintln(w, "\n"+i18n.T("Showing %d-%d of %d", start, end, meta.TotalCount))
+		}
+	}
+}
+
+func printEventsTableHeader(w *tabwriter.Writer) {
+	TableHeader(w, "ID", "DATE", "TIME", "DURATION", "TITLE", "STATUS")
+}
+
+func printEventsTableRows(w *tabwriter.Writer, events []api.Event) {
 	for _, e := range events {
 		localStart := GetLocalStart(e.StartLocal, e.StartUtc)
 		title := e.Title
@@ -278,22 +345,10 @@ func printEventsTable(w *tabwriter.Writer, events []api.Event, meta *api.Meta) {
 			safeDate(localStart),
 			safeTime(localStart),
 			e.DurationMinutes,
-			truncate(title, 30),
+			highlight(cell(title, 30)),
 			ColorStatus(e.Status),
 		)
 	}
-
-	// Display pagination info if available
-	if meta != nil && meta.TotalCount > 0 {
-		start := meta.Offset + 1
-		end := meta.Offset + meta.Count
-		if meta.HasMore {
-			fmt.Fprintf(w, "\nShowing %d-%d of %d (use --offset %d for more)\n",
-				start, end, meta.TotalCount, end)
-		} else {
-			fmt.Fprintf(w, "\nShowing %d-%d of %d\n", start, end, meta.TotalCount)
-		}
-	}
 }
 
 func printEventDetail(w *tabwriter.Writer, e api.Event) {
@@ -339,11 +394,25 @@ func printEventDetail(w *tabwriter.Writer, e api.Event) {
 			fmt.Fprintf(w, "  - %s\t(%s)\n", name, status)
 		}
 	}
+	if e.SeriesID != "" {
+		fmt.Fprintf(w, "Series:\t%s\n", e.SeriesID)
+	}
+	if e.Reminders != nil && !e.Reminders.UseDefault {
+		fmt.Fprintf(w, "Reminders:\t%s\n", FormatReminders(*e.Reminders))
+	}
+	for _, r := range e.Recurrence {
+		fmt.Fprintf(w, "Recurrence:\t%s\n", r)
+	}
+	if len(e.Attachments) > 0 {
+		fmt.Fprintln(w, "Attachments:")
+		for _, a := range e.Attachments {
+			fmt.Fprintf(w, "  - %s\t%s\n", a.Title, a.URL)
+		}
+	}
 }
 
 func printCalendarsTable(w *tabwriter.Writer, calendars []api.Calendar) {
-	fmt.Fprintln(w, "ID\tNAME\tPROVIDER\tTIMEZONE\tPRIMARY\tOWNER")
-	fmt.Fprintln(w, "──\t────\t────────\t────────\t───────\t─────")
+	TableHeader(w, "ID", "NAME", "PROVIDER", "TIMEZONE", "PRIMARY", "OWNER")
 	for _, c := range calendars {
 		primary := ""
 		if c.IsPrimary {
@@ -356,13 +425,18 @@ func printCalendarsTable(w *tabwriter.Writer, calendars []api.Calendar) {
 func printFreeBusyTable(w *tabwriter.Writer, resp *api.FreeBusyResponse) {
 	for _, cal := range resp.Calendars {
 		fmt.Fprintf(w, "Calendar: %s (ID: %d)\n", cal.CalendarName, cal.CalendarID)
-		fmt.Fprintln(w, "  START\tEND\tDURATION")
-		fmt.Fprintln(w, "  ─────\t───\t────────")
+		cols := []string{"START", "END", "DURATION"}
+		if slices.ContainsFunc(cal.Busy, func(b api.BusyPeriod) bool { return b.Holiday != "" }) {
+			cols = append(cols, "HOLIDAY")
+		}
+		names, lines := headerRows(cols...)
+		fmt.Fprintln(w, "  "+names)
+		fmt.Fprintln(w, "  "+lines)
 		for _, b := range cal.Busy {
-			fmt.Fprintf(w, "  %s\t%s\t%dm\n",
+			fmt.Fprintf(w, "  %s\t%s\t%dm\t%s\n",
 				FormatLocalTime(b.StartUtc),
 				FormatLocalTime(b.EndUtc),
-				b.DurationMinutes)
+				b.DurationMinutes, b.Holiday)
 		}
 		fmt.Fprintln(w)
 	}
@@ -405,6 +479,13 @@ func printEventPlain(e api.Event) {
 	}
 }
 
+func printCalendarSharesTable(w *tabwriter.Writer, shares []api.CalendarShare) {
+	TableHeader(w, "EMAIL", "ROLE", "TYPE")
+	for _, s := range shares {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Email, s.Role, s.Type)
+	}
+}
+
 func printCalendarsPlain(calendars []api.Calendar) {
 	for _, c := range calendars {
 		primary := "false"
@@ -415,19 +496,80 @@ func printCalendarsPlain(calendars []api.Calendar) {
 	}
 }
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+// TableHeader writes a localized header row and an underline matching each
+// column name's width
+func TableHeader(w io.Writer, cols ...string) {
+	names, lines := headerRows(cols...)
+	fmt.Fprintln(w, names)
+	fmt.Fprintln(w, lines)
+}
+
+// headerRows returns the tab-separated header and underline rows
+func headerRows(cols ...string) (string, string) {
+	names := make([]string, len(cols))
+	lines := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = i18n.T(c)
+		lines[i] = Symbols(strings.Repeat("─", utf8.RuneCountInString(names[i])))
+	}
+	return strings.Join(names, "\t"), strings.Join(lines, "\t")
+}
+
+// ==================== SEARCH FORMATTERS ====================
+
+// SearchResults is a merged list of event and email matches from the API
+type SearchResults struct {
+	Query    string         `json:"query"`
+	Results  []index.Result `json:"results"`
+	Total    int            `json:"total"`
+	Warnings []string       `json:"warnings,omitempty"` // Kinds that could not be searched
+}
+
+func printSearchResultsTable(w *tabwriter.Writer, results []index.Result, total int) {
+	TableHeader(w, "KIND", "DATE", "TITLE", "FROM", "ID")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			r.Kind,
+			safeDate(FormatLocalTime(r.Date)),
+			highlight(cell(r.Title, 40)),
+			cell(r.From, 24),
+			r.ID,
+		)
+	}
+	if total > len(results) {
+		fmt.Fprintln(w, "\n"+i18n.T("Showing %d of %d matches (use --limit for more)", len(results), total))
+	} else if total == 0 {
+		fmt.Fprintln(w, "\n"+i18n.T("No matches"))
+	}
+}
+
+func printSearchResultsPlain(results []index.Result) {
+	for _, r := range results {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", r.Kind, safeDate(FormatLocalTime(r.Date)), r.Title, r.From, r.ID)
 	}
-	return s[:max-3] + "..."
 }
 
 // ==================== EMAIL FORMATTERS ====================
 
 func printEmailsTable(w *tabwriter.Writer, emails []api.Email, totalCount int, hasMore bool) {
-	fmt.Fprintln(w, "ID\tDATE\tFROM\tSUBJECT\tREAD\tATTACH")
-	fmt.Fprintln(w, "──\t────\t────\t───────\t────\t──────")
+	printEmailsTableHeader(w)
+	printEmailsTableRows(w, emails)
 
+	if totalCount > 0 || len(emails) > 0 {
+		shown := len(emails)
+		if hasMore {
+			fmt.Fprintln(w, "\n"+i18n.T("Showing %d emails (more available, use --all to fetch all)", shown))
+		} else if totalCount > 0 {
+			fmt.Fprintln(w, "\n"+i18n.T("Showing %d of %d emails", shown, totalCount))
+		}
+	}
+}
+
+func printEmailsTableHeader(w *tabwriter.Writer) {
+	TableHeader(w, "ID", "DATE", "FROM", "SUBJECT", "READ", "ATTACH")
+}
+
+func printEmailsTableRows(w *tabwriter.Writer, emails []api.Email) {
 	for _, e := range emails {
 		from := ""
 		if e.From != nil {
@@ -449,23 +591,14 @@ func printEmailsTable(w *tabwriter.Writer, emails []api.Email, totalCount int, h
 		}
 
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			truncate(e.ID, 24),
+			cell(e.ID, 24),
 			safeDate(FormatLocalTime(e.ReceivedAt)),
-			truncate(from, 24),
-			truncate(e.Subject, 40),
+			cell(from, 24),
+			highlight(cell(e.Subject, 40)),
 			readStatus,
 			attach,
 		)
 	}
-
-	if totalCount > 0 || len(emails) > 0 {
-		shown := len(emails)
-		if hasMore {
-			fmt.Fprintf(w, "\nShowing %d emails (more available, use --all to fetch all)\n", shown)
-		} else if totalCount > 0 {
-			fmt.Fprintf(w, "\nShowing %d of %d emails\n", shown, totalCount)
-		}
-	}
 }
 
 func printEmailDetail(w *tabwriter.Writer, e api.Email) {
@@ -509,7 +642,7 @@ func printEmailDetail(w *tabwriter.Writer, e api.Email) {
 	if e.HasAttachments && len(e.Attachments) > 0 {
 		fmt.Fprintln(w, "Attachments:")
 		for _, att := range e.Attachments {
-			sizeStr := formatBytes(att.Size)
+			sizeStr := FormatBytes(att.Size)
 			if att.ContentType != "" {
 				fmt.Fprintf(w, "  - %s\t(%s, %s)\n", att.Name, att.ContentType, sizeStr)
 			} else {
@@ -543,31 +676,7 @@ func printThreadTable(w *tabwriter.Writer, t *api.ThreadResponse) {
 	}
 
 	fmt.Fprintln(w)
-	fmt.Fprintln(w, "ID\tFROM\tSENT\tREAD")
-	fmt.Fprintln(w, "──\t────\t────\t────")
-
-	for _, msg := range t.Messages {
-		from := ""
-		if msg.From != nil {
-			if msg.From.Name != "" {
-				from = msg.From.Name
-			} else {
-				from = msg.From.Email
-			}
-		}
-
-		readStatus := ColorGreen("yes")
-		if !msg.IsRead {
-			readStatus = ColorYellow("no")
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-			truncate(msg.ID, 24),
-			truncate(from, 24),
-			FormatLocalTime(msg.SentAt),
-			readStatus,
-		)
-	}
+	printThreadTree(w, t.Messages)
 }
 
 func printEmailsPlain(emails []api.Email) {
@@ -689,9 +798,9 @@ func driveFileOwner(f api.DriveFile) string {
 
 func driveFileSize(f api.DriveFile) string {
 	if f.Size == nil || f.IsFolder {
-		return "—"
+		return Symbols("—")
 	}
-	return formatBytes(*f.Size)
+	return FormatBytes(*f.Size)
 }
 
 func driveFileModified(f api.DriveFile) string {
@@ -707,22 +816,29 @@ func driveFileModified(f api.DriveFile) string {
 }
 
 func printDriveFilesTable(w *tabwriter.Writer, files []api.DriveFile, hasMore bool) {
-	fmt.Fprintln(w, "ID\tTYPE\tNAME\tSIZE\tMODIFIED\tOWNER")
-	fmt.Fprintln(w, "──\t────\t────\t────\t────────\t─────")
+	printDriveFilesTableHeader(w)
+	printDriveFilesTableRows(w, files)
+	if len(files) > 0 && hasMore {
+		fmt.Fprintln(w, "\n"+i18n.T("Showing %d files (more available, use --all to fetch all)", len(files)))
+	}
+}
+
+func printDriveFilesTableHeader(w *tabwriter.Writer) {
+	TableHeader(w, "ID", "TYPE", "NAME", "SIZE", "MODIFIED", "OWNER")
+}
+
+func printDriveFilesTableRows(w *tabwriter.Writer, files []api.DriveFile) {
 	for _, f := range files {
 		mimeType := derefStr(f.MimeType)
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			truncate(f.ID, 22),
+			cell(f.ID, 22),
 			friendlyMimeType(mimeType, f.IsFolder),
-			truncate(derefStr(f.Name), 35),
+			highlight(cell(derefStr(f.Name), 35)),
 			driveFileSize(f),
 			driveFileModified(f),
-			truncate(driveFileOwner(f), 30),
+			cell(driveFileOwner(f), 30),
 		)
 	}
-	if len(files) > 0 && hasMore {
-		fmt.Fprintf(w, "\nShowing %d files (more available, use --all to fetch all)\n", len(files))
-	}
 }
 
 func printDriveFileDetail(w *tabwriter.Writer, f api.DriveFile) {
@@ -764,8 +880,7 @@ func printDriveFileDetail(w *tabwriter.Writer, f api.DriveFile) {
 }
 
 func printDrivePermissionsTable(w *tabwriter.Writer, perms []api.DrivePermission) {
-	fmt.Fprintln(w, "TYPE\tROLE\tEMAIL / DOMAIN\tNAME")
-	fmt.Fprintln(w, "────\t────\t──────────────\t────")
+	TableHeader(w, "TYPE", "ROLE", "EMAIL / DOMAIN", "NAME")
 	for _, p := range perms {
 		contact := derefStr(p.EmailAddress)
 		if contact == "" {
@@ -796,8 +911,8 @@ func printDriveFileLinksTable(w *tabwriter.Writer, v *api.DriveFileLinkResponse)
 		fmt.Fprintf(w, "Download:\t%s\n", *v.DownloadUrl)
 	}
 	if len(v.ExportLinks) > 0 {
-		fmt.Fprintln(w, "\nEXPORT FORMAT\tURL")
-		fmt.Fprintln(w, "─────────────\t───")
+		fmt.Fprintln(w)
+		TableHeader(w, "EXPORT FORMAT", "URL")
 		for format, link := range v.ExportLinks {
 			fmt.Fprintf(w, "%s\t%s\n", format, link)
 		}
@@ -807,9 +922,9 @@ func printDriveFileLinksTable(w *tabwriter.Writer, v *api.DriveFileLinkResponse)
 func printDriveOperationResult(v *api.DriveOperationResult) {
 	if v.Success {
 		if v.FileID != nil && *v.FileID != "" {
-			fmt.Printf("✓ Done  (id: %s)\n", *v.FileID)
+			fmt.Println(Symbols("✓ Done") + "  (id: " + *v.FileID + ")")
 		} else {
-			fmt.Println("✓ Done")
+			fmt.Println(Symbols("✓ Done"))
 		}
 	} else {
 		msg := derefStr(v.ErrorMessage)
@@ -872,8 +987,7 @@ func printSheetMetadataTable(w *tabwriter.Writer, v *api.SheetMetadataResponse)
 	fmt.Fprintf(w, "Spreadsheet:\t%s\n", title)
 	fmt.Fprintf(w, "ID:\t%s\n", v.SpreadsheetID)
 	fmt.Fprintln(w)
-	fmt.Fprintln(w, "SHEET\tROWS\tCOLS")
-	fmt.Fprintln(w, "─────\t────\t────")
+	TableHeader(w, "SHEET", "ROWS", "COLS")
 	for _, s := range v.Sheets {
 		fmt.Fprintf(w, "%s\t%d\t%d\n", s.Title, s.RowCount, s.ColumnCount)
 	}
@@ -905,7 +1019,7 @@ func printSheetValuesTable(w *tabwriter.Writer, v *api.SheetValuesResponse) {
 		if i > 0 {
 			fmt.Fprint(w, "\t")
 		}
-		fmt.Fprint(w, "────")
+		fmt.Fprint(w, Symbols("────"))
 	}
 	fmt.Fprintln(w)
 
@@ -937,7 +1051,8 @@ func printSheetValuesPlain(v *api.SheetValuesResponse) {
 	}
 }
 
-func formatBytes(b int64) string {
+// FormatBytes renders a byte count as B, KB or MB
+func FormatBytes(b int64) string {
 	switch {
 	case b >= 1024*1024:
 		return fmt.Sprintf("%.1f MB", float64(b)/(1024*1024))
@@ -947,3 +1062,33 @@ func formatBytes(b int64) string {
 		return fmt.Sprintf("%d B", b)
 	}
 }
+
+// FormatReminders renders an event's reminders, e.g. "10m (popup), 1d (email)"
+func FormatReminders(r api.EventReminders) string {
+	if r.UseDefault {
+		return "calendar default"
+	}
+	if len(r.Overrides) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(r.Overrides))
+	for i, o := range r.Overrides {
+		parts[i] = fmt.Sprintf("%s (%s)", FormatMinutes(o.Minutes), o.Method)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatMinutes renders a number of minutes in the largest whole units, as
+// in "45m", "1h30m", "2h" or "1d"
+func FormatMinutes(m int) string {
+	switch {
+	case m > 0 && m%(24*60) == 0:
+		return fmt.Sprintf("%dd", m/(24*60))
+	case m > 60 && m%60 != 0:
+		return fmt.Sprintf("%dh%dm", m/60, m%60)
+	case m >= 60:
+		return fmt.Sprintf("%dh", m/60)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
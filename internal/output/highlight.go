@@ -0,0 +1,50 @@
+package output
+
+import (
+	"strings"
+	"unicode"
+)
+
+// highlightColor marks search matches in table output
+const highlightColor = "\033[1;33m"
+
+// highlightTerms holds the lowercased terms of the active search query
+var highlightTerms []string
+
+// setHighlight sets the search query whose terms are highlighted in table cells
+func setHighlight(query string) {
+	highlightTerms = nil
+	for _, term := range strings.FieldsFunc(query, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '\''
+	}) {
+		// Skip operators such as from:x or OR
+		if strings.Contains(term, ":") || term == "OR" || term == "AND" {
+			continue
+		}
+		highlightTerms = append(highlightTerms, strings.ToLower(term))
+	}
+}
+
+// highlight colors the first match of any search term in s. Every cell in a
+// highlighted column gets exactly one color span (empty when nothing matches)
+// so ANSI escapes add the same width to each row and tabwriter stays aligned.
+func highlight(s string) string {
+	if !colorsEnabled || len(highlightTerms) == 0 {
+		return s
+	}
+
+	lower := strings.ToLower(s)
+	start, end := -1, -1
+	// Byte offsets are only valid if lowercasing kept the length
+	if len(lower) == len(s) {
+		for _, term := range highlightTerms {
+			if i := strings.Index(lower, term); i >= 0 && (start < 0 || i < start) {
+				start, end = i, i+len(term)
+			}
+		}
+	}
+	if start < 0 {
+		return highlightColor + Reset + s
+	}
+	return s[:start] + highlightColor + s[start:end] + Reset + s[end:]
+}
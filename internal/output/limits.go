@@ -0,0 +1,214 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"unicode/utf8"
+)
+
+// Output limits set by --max-items and --max-output-bytes; 0 is no limit
+var (
+	maxItems       int
+	maxOutputBytes int
+)
+
+// minOutputBytes leaves room for the truncation notice itself
+const minOutputBytes = 256
+
+// SetOutputLimits caps how many results and how many bytes JSON output may
+// contain. Output over a limit is cut down to whole results and gets an
+// "outputTruncated" notice, so it is always valid JSON.
+func SetOutputLimits(items, bytes int) error {
+	if items < 0 {
+		return errors.New("--max-items must not be negative")
+	}
+	if bytes < 0 || bytes > 0 && bytes < minOutputBytes {
+		return errors.New("--max-output-bytes must be 0 (no limit) or at least 256")
+	}
+	maxItems, maxOutputBytes = items, bytes
+	return nil
+}
+
+// truncation describes why and how output was cut short
+type truncation struct {
+	Reason             string `json:"reason"`
+	Returned           int    `json:"returned"`
+	Total              *int   `json:"total,omitempty"`
+	MaxItems           int    `json:"maxItems,omitempty"`
+	MaxOutputBytes     int    `json:"maxOutputBytes,omitempty"`
+	StringsShortenedTo *int   `json:"stringsShortenedTo,omitempty"`
+	Hint               string `json:"hint"`
+}
+
+const truncationHint = "Narrow the query, page with --limit, or choose fields with --fields"
+
+func newTruncation(reason string, returned int) truncation {
+	return truncation{
+		Reason:         reason,
+		Returned:       returned,
+		MaxItems:       maxItems,
+		MaxOutputBytes: maxOutputBytes,
+		Hint:           truncationHint,
+	}
+}
+
+// jsonSize is the length of v as printed by printJSON
+func jsonSize(v interface{}) int {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return 0
+	}
+	return len(raw) + 1
+}
+
+// limitOutput cuts a response down to the output limits. Results are
+// dropped from the end first; if a single result is still too large, its
+// long strings are shortened. The kept part is always complete JSON.
+func limitOutput(v interface{}) interface{} {
+	if maxItems == 0 && maxOutputBytes == 0 {
+		return v
+	}
+
+	var items []interface{}
+	hasItems := false
+	obj, isObj := v.(orderedObject)
+	idx := -1
+	switch x := v.(type) {
+	case []interface{}:
+		items, hasItems = x, true
+	case orderedObject:
+		if idx = itemsField(x); idx >= 0 {
+			items, hasItems = x[idx].value.([]interface{})
+		}
+	}
+
+	// build assembles the output with the first n results, strings capped
+	// at limit runes (-1 for no cap), and notice t if it is set
+	build := func(n, limit int, t *truncation) interface{} {
+		var out interface{}
+		switch {
+		case hasItems && !isObj:
+			out = capStrings(items[:n], limit)
+			if t != nil {
+				out = orderedObject{{"items", out}, {"outputTruncated", t}}
+			}
+			return out
+		case hasItems:
+			o := append(orderedObject{}, obj...)
+			o[idx].value = items[:n]
+			out = capStrings(o, limit)
+		default:
+			out = capStrings(v, limit)
+		}
+		if t != nil {
+			if o, ok := out.(orderedObject); ok {
+				out = append(o, objectField{"outputTruncated", t})
+			}
+		}
+		return out
+	}
+
+	total := len(items)
+	n := total
+	var t *truncation
+	if hasItems && maxItems > 0 && n > maxItems {
+		n = maxItems
+		nt := newTruncation("max-items", n)
+		t = &nt
+	}
+	if hasItems {
+		t.setTotal(total)
+	}
+	if maxOutputBytes == 0 || jsonSize(build(n, -1, t)) <= maxOutputBytes {
+		return build(n, -1, t)
+	}
+
+	// Too large: keep as many whole results as fit
+	bt := newTruncation("max-output-bytes", 0)
+	if hasItems {
+		bt.setTotal(total)
+	}
+	fits := func(n, limit int) bool {
+		bt.Returned = n
+		if limit >= 0 {
+			bt.StringsShortenedTo = &limit
+		} else {
+			bt.StringsShortenedTo = nil
+		}
+		return jsonSize(build(n, limit, &bt)) <= maxOutputBytes
+	}
+	if hasItems {
+		keep := sort.Search(n+1, func(k int) bool { return !fits(k, -1) }) - 1
+		if keep >= 1 {
+			fits(keep, -1)
+			return build(keep, -1, &bt)
+		}
+		n = min(n, 1)
+	}
+
+	// Even one result is too large: shorten its strings
+	longest := longestString(build(n, -1, nil))
+	limit := sort.Search(longest+1, func(l int) bool { return !fits(n, l) }) - 1
+	if limit >= 0 {
+		fits(n, limit)
+		return build(n, limit, &bt)
+	}
+	if hasItems && fits(0, -1) {
+		return build(0, -1, &bt)
+	}
+	bt.Returned, bt.StringsShortenedTo = 0, nil
+	return orderedObject{{"outputTruncated", bt}}
+}
+
+func (t *truncation) setTotal(total int) {
+	if t != nil {
+		t.Total = &total
+	}
+}
+
+// capStrings copies v with every string longer than limit runes cut to
+// limit runes and "..."; a negative limit leaves strings alone
+func capStrings(v interface{}, limit int) interface{} {
+	if limit < 0 {
+		return v
+	}
+	switch x := v.(type) {
+	case string:
+		if utf8.RuneCountInString(x) <= limit {
+			return x
+		}
+		return string([]rune(x)[:limit]) + "..."
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, e := range x {
+			out[i] = capStrings(e, limit)
+		}
+		return out
+	case orderedObject:
+		out := make(orderedObject, len(x))
+		for i, f := range x {
+			out[i] = objectField{f.key, capStrings(f.value, limit)}
+		}
+		return out
+	}
+	return v
+}
+
+// longestString returns the rune length of the longest string in v
+func longestString(v interface{}) int {
+	longest := 0
+	switch x := v.(type) {
+	case string:
+		return utf8.RuneCountInString(x)
+	case []interface{}:
+		for _, e := range x {
+			longest = max(longest, longestString(e))
+		}
+	case orderedObject:
+		for _, f := range x {
+			longest = max(longest, longestString(f.value))
+		}
+	}
+	return longest
+}
@@ -0,0 +1,168 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// printLimited prints data as JSON under the given limits and decodes it
+func printLimited(t *testing.T, data interface{}, items, bytes int) (string, map[string]interface{}) {
+	t.Helper()
+	if err := SetOutputLimits(items, bytes); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetOutputLimits(0, 0) }()
+
+	out := capturePrint(t, func() { PrintWithOptions(data, FormatJSON, PrintOptions{}) })
+	if !json.Valid([]byte(out)) {
+		t.Fatalf("output is not valid JSON:\n%s", out)
+	}
+	if !utf8.ValidString(out) {
+		t.Fatalf("output is not valid UTF-8:\n%s", out)
+	}
+	if bytes > 0 && len(out) > bytes {
+		t.Errorf("output is %d bytes, over the limit of %d", len(out), bytes)
+	}
+	var v map[string]interface{}
+	_ = json.Unmarshal([]byte(out), &v)
+	return out, v
+}
+
+func manyEvents(n int) *api.EventsResponse {
+	resp := &api.EventsResponse{Meta: &api.Meta{Count: n, NextPageToken: "p2"}}
+	for i := 0; i < n; i++ {
+		resp.Events = append(resp.Events, api.Event{ID: fmt.Sprintf("e%d", i), Title: fmt.Sprintf("Meeting number %d", i)})
+	}
+	return resp
+}
+
+func TestMaxItems(t *testing.T) {
+	_, v := printLimited(t, manyEvents(5), 2, 0)
+	if events := v["events"].([]interface{}); len(events) != 2 || events[1].(map[string]interface{})["id"] != "e1" {
+		t.Errorf("events = %v, want the first 2", events)
+	}
+	if v["meta"].(map[string]interface{})["nextPageToken"] != "p2" {
+		t.Errorf("meta was not kept: %v", v["meta"])
+	}
+	notice := v["outputTruncated"].(map[string]interface{})
+	if notice["reason"] != "max-items" || notice["returned"] != 2.0 || notice["total"] != 5.0 || notice["maxItems"] != 2.0 || notice["hint"] == "" {
+		t.Errorf("notice = %v", notice)
+	}
+
+	// Under the limit: no notice
+	if _, v := printLimited(t, manyEvents(2), 2, 0); v["outputTruncated"] != nil {
+		t.Errorf("notice without truncation: %v", v["outputTruncated"])
+	}
+}
+
+func TestMaxItemsTopLevelArray(t *testing.T) {
+	calendars := []api.Calendar{{ID: 1, Name: "Work"}, {ID: 2, Name: "Home"}, {ID: 3, Name: "Team"}}
+	_, v := printLimited(t, calendars, 1, 0)
+	if items, _ := v["items"].([]interface{}); len(items) != 1 {
+		t.Errorf("items = %v, want 1 calendar wrapped in an object", v["items"])
+	}
+	if notice, _ := v["outputTruncated"].(map[string]interface{}); notice == nil || notice["total"] != 3.0 {
+		t.Errorf("notice = %v", v["outputTruncated"])
+	}
+}
+
+func TestMaxOutputBytes(t *testing.T) {
+	_, v := printLimited(t, manyEvents(50), 0, 1024)
+	events := v["events"].([]interface{})
+	notice := v["outputTruncated"].(map[string]interface{})
+	if len(events) == 0 || len(events) >= 50 {
+		t.Fatalf("kept %d events, want some but not all", len(events))
+	}
+	if notice["reason"] != "max-output-bytes" || notice["returned"] != float64(len(events)) || notice["total"] != 50.0 || notice["stringsShortenedTo"] != nil {
+		t.Errorf("notice = %v", notice)
+	}
+	// Whole results only, in order
+	for i, e := range events {
+		if title := e.(map[string]interface{})["title"]; title != fmt.Sprintf("Meeting number %d", i) {
+			t.Errorf("event %d title = %v", i, title)
+		}
+	}
+
+	// Both limits: the tighter one wins
+	if _, v := printLimited(t, manyEvents(50), 3, 4096); v["outputTruncated"].(map[string]interface{})["reason"] != "max-items" {
+		t.Errorf("notice = %v, want max-items", v["outputTruncated"])
+	}
+}
+
+func TestMaxOutputBytesLongStrings(t *testing.T) {
+	// A single result too large on its own has its strings shortened, on
+	// rune boundaries
+	event := &api.Event{ID: "e1", Title: "Offsite", Description: strings.Repeat("Überprüfung ✓ ", 200)}
+	_, v := printLimited(t, event, 0, 512)
+	if v["id"] != "e1" {
+		t.Errorf("id = %v", v["id"])
+	}
+	desc, _ := v["description"].(string)
+	if !strings.HasSuffix(desc, "...") || len(desc) >= len(event.Description) {
+		t.Errorf("description was not shortened: %d bytes", len(desc))
+	}
+	notice := v["outputTruncated"].(map[string]interface{})
+	if notice["reason"] != "max-output-bytes" || notice["stringsShortenedTo"] == nil {
+		t.Errorf("notice = %v", notice)
+	}
+
+	// The same for the one result kept from a list
+	resp := &api.EventsResponse{Events: []api.Event{*event, *event}}
+	_, v = printLimited(t, resp, 0, 600)
+	if events := v["events"].([]interface{}); len(events) != 1 {
+		t.Errorf("kept %d events, want 1 with shortened strings", len(events))
+	}
+}
+
+func TestSetOutputLimits(t *testing.T) {
+	defer func() { _ = SetOutputLimits(0, 0) }()
+	for _, bad := range [][2]int{{-1, 0}, {0, -1}, {0, 100}} {
+		if err := SetOutputLimits(bad[0], bad[1]); err == nil {
+			t.Errorf("SetOutputLimits(%d, %d) accepted", bad[0], bad[1])
+		}
+	}
+	if err := SetOutputLimits(10, minOutputBytes); err != nil {
+		t.Errorf("SetOutputLimits(10, %d) failed: %v", minOutputBytes, err)
+	}
+}
+
+func TestStreamedLimits(t *testing.T) {
+	for _, limits := range [][2]int{{3, 0}, {0, 300}} {
+		if err := SetOutputLimits(limits[0], limits[1]); err != nil {
+			t.Fatal(err)
+		}
+		s := NewStreamer(FormatJSON, PrintOptions{})
+		out := capturePrint(t, func() {
+			for page := 0; page < 3 && s.Stop() == nil; page++ {
+				s.Page(manyEvents(4))
+			}
+			s.Close()
+		})
+		_ = SetOutputLimits(0, 0)
+
+		if s.Stop() != ErrOutputLimit {
+			t.Errorf("limits %v: Stop() = %v, want ErrOutputLimit", limits, s.Stop())
+		}
+		if limits[1] > 0 && len(out) > limits[1] {
+			t.Errorf("limits %v: streamed %d bytes", limits, len(out))
+		}
+		lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+		for _, line := range lines {
+			if !json.Valid([]byte(line)) {
+				t.Errorf("limits %v: invalid NDJSON line %q", limits, line)
+			}
+		}
+		last := lines[len(lines)-1]
+		if !strings.HasPrefix(last, `{"outputTruncated":`) {
+			t.Errorf("limits %v: last line %q is not the notice", limits, last)
+		}
+		if limits[0] > 0 && len(lines) != limits[0]+1 {
+			t.Errorf("limits %v: %d lines, want %d results and the notice", limits, len(lines), limits[0])
+		}
+	}
+}
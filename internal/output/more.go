@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/porteden/cli/internal/i18n"
+	"golang.org/x/term"
+)
+
+var moreEnabled = true
+
+// SetMore turns the "-- more --" prompt for long tables on or off
+func SetMore(enabled bool) {
+	moreEnabled = enabled
+}
+
+// tableOutput returns where a table should be written: a buffer to page
+// through when stdout and stdin are terminals, otherwise stdout
+func tableOutput() (io.Writer, *bytes.Buffer) {
+	if !moreEnabled || !term.IsTerminal(int(os.Stdout.Fd())) || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return os.Stdout, nil
+	}
+	buf := &bytes.Buffer{}
+	return buf, buf
+}
+
+// pageOutput prints text one screen at a time, asking before each further
+// screen whether to continue, quit, or print asJSON instead
+func pageOutput(text []byte, asJSON func()) {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || height < 3 || bytes.Count(text, []byte("\n")) < height {
+		_, _ = os.Stdout.Write(text)
+		return
+	}
+
+	lines := bufio.NewScanner(bytes.NewReader(text))
+	lines.Buffer(make([]byte, 0, 64*1024), len(text)+1)
+	remaining := height - 1
+	for lines.Scan() {
+		if remaining == 0 {
+			switch morePrompt() {
+			case moreQuit:
+				return
+			case moreJSON:
+				asJSON()
+				return
+			case moreLine:
+				remaining = 1
+			default:
+				remaining = height - 1
+			}
+		}
+		fmt.Fprintln(os.Stdout, lines.Text())
+		remaining--
+	}
+}
+
+// Answers to the "-- more --" prompt
+const (
+	morePage = iota
+	moreLine
+	moreQuit
+	moreJSON
+)
+
+// morePrompt shows "-- more --" and waits for a key
+func morePrompt() int {
+	fd := int(os.Stdin.Fd())
+	fmt.Fprint(os.Stdout, Colorize(Bold, Symbols(i18n.T("-- more -- (Space: next page · Enter: next line · q: quit · j: JSON)"))))
+	defer fmt.Fprint(os.Stdout, "\r\x1b[K")
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return moreQuit
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	buf := make([]byte, 8)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil || n == 0 {
+			return moreQuit
+		}
+		switch buf[0] {
+		case ' ', 'f':
+			return morePage
+		case '\r', '\n':
+			return moreLine
+		case 'q', 'Q', 3, 4, 27: // Ctrl-C, Ctrl-D, Esc
+			return moreQuit
+		case 'j', 'J':
+			return moreJSON
+		}
+	}
+}
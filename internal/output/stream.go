@@ -0,0 +1,202 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/porteden/cli/internal/api"
+)
+
+// Streamer prints list results page by page as they arrive, so large fetches
+// never have to be held in memory. JSON output becomes NDJSON (one object per
+// line); table, plain and CSV output print their header once and append rows.
+type Streamer struct {
+	format  Format
+	opts    PrintOptions
+	csv     *csv.Writer
+	started bool
+	count   int
+	noun    string
+
+	// JSON items and bytes written, for the output limits
+	items int
+	bytes int
+	full  bool
+}
+
+// ErrOutputLimit is returned by Streamer.Stop once --max-items or
+// --max-output-bytes has been reached
+var ErrOutputLimit = errors.New("output limit reached")
+
+func NewStreamer(format Format, opts PrintOptions) *Streamer {
+	return &Streamer{format: format, opts: opts}
+}
+
+// Page prints one page of *api.EventsResponse, *api.EmailsResponse or
+// *api.DriveFilesResponse
+func (s *Streamer) Page(data interface{}) {
+	if s.opts.Compact {
+		data = applyCompact(data)
+	}
+
+	switch s.format {
+	case FormatJSON:
+		s.pageJSON(data)
+	case FormatPlain:
+		s.pagePlain(data)
+	case FormatCSV:
+		s.pageCSV(data)
+	default:
+		s.pageTable(data)
+	}
+	s.started = true
+}
+
+// Stop returns ErrOutputLimit once the output limits have cut the stream
+// short, so the caller can stop fetching pages
+func (s *Streamer) Stop() error {
+	if s.full {
+		return ErrOutputLimit
+	}
+	return nil
+}
+
+// Close finishes the stream, printing a row count footer for table output
+func (s *Streamer) Close() {
+	if s.csv != nil {
+		s.csv.Flush()
+	}
+	// Only table output counts rows
+	if s.count > 0 {
+		fmt.Printf("\nStreamed %d %s\n", s.count, s.noun)
+	}
+}
+
+func (s *Streamer) pageJSON(data interface{}) {
+	switch v := data.(type) {
+	case *api.EventsResponse:
+		for _, e := range v.Events {
+			s.writeJSON(e)
+		}
+	case *api.EmailsResponse:
+		for _, e := range v.Emails {
+			s.writeJSON(e)
+		}
+	case *api.DriveFilesResponse:
+		for _, f := range v.Files {
+			s.writeJSON(f)
+		}
+	}
+}
+
+// writeJSON prints one NDJSON line, or a truncation notice instead once the
+// output limits are reached
+func (s *Streamer) writeJSON(item interface{}) {
+	if s.full {
+		return
+	}
+	var v interface{} = item
+	if fieldPaths != nil {
+		if jv, ok := toJSONValue(item); ok {
+			v = selectItemFields(jv)
+		}
+	}
+	line, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	reason := ""
+	if maxItems > 0 && s.items >= maxItems {
+		reason = "max-items"
+	} else if maxOutputBytes > 0 && s.bytes+len(line)+len(s.notice("max-output-bytes")) > maxOutputBytes {
+		reason = "max-output-bytes"
+	}
+	if reason != "" {
+		_, _ = os.Stdout.Write(s.notice(reason))
+		s.full = true
+		return
+	}
+	_, _ = os.Stdout.Write(line)
+	s.items++
+	s.bytes += len(line)
+}
+
+// notice is the final NDJSON line of a stream cut short
+func (s *Streamer) notice(reason string) []byte {
+	line, _ := json.Marshal(map[string]truncation{"outputTruncated": newTruncation(reason, s.items)})
+	return append(line, '\n')
+}
+
+func (s *Streamer) pagePlain(data interface{}) {
+	switch v := data.(type) {
+	case *api.EventsResponse:
+		printEventsPlain(v.Events)
+	case *api.EmailsResponse:
+		printEmailsPlain(v.Emails)
+	case *api.DriveFilesResponse:
+		printDriveFilesPlain(v.Files)
+	}
+}
+
+func (s *Streamer) pageCSV(data interface{}) {
+	first := s.csv == nil
+	if first {
+		s.csv = csv.NewWriter(os.Stdout)
+	}
+
+	switch v := data.(type) {
+	case *api.EventsResponse:
+		if first {
+			_ = s.csv.Write(eventCSVColumns)
+		}
+		writeEventsCSVRows(s.csv, v.Events)
+	case *api.EmailsResponse:
+		if first {
+			_ = s.csv.Write(emailCSVColumns)
+		}
+		writeEmailsCSVRows(s.csv, v.Emails)
+	case *api.DriveFilesResponse:
+		if first {
+			_ = s.csv.Write(driveCSVColumns)
+		}
+		writeDriveFilesCSVRows(s.csv, v.Files)
+	}
+	s.csv.Flush()
+}
+
+// pageTable appends rows for one page. Column widths are computed per page, so
+// alignment can shift slightly between pages.
+func (s *Streamer) pageTable(data interface{}) {
+	setHighlight(s.opts.Highlight)
+	defer setHighlight("")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	switch v := data.(type) {
+	case *api.EventsResponse:
+		if !s.started {
+			printEventsTableHeader(w)
+		}
+		printEventsTableRows(w, v.Events)
+		s.count, s.noun = s.count+len(v.Events), "events"
+	case *api.EmailsResponse:
+		if !s.started {
+			printEmailsTableHeader(w)
+		}
+		printEmailsTableRows(w, v.Emails)
+		s.count, s.noun = s.count+len(v.Emails), "emails"
+	case *api.DriveFilesResponse:
+		if !s.started {
+			printDriveFilesTableHeader(w)
+		}
+		printDriveFilesTableRows(w, v.Files)
+		s.count, s.noun = s.count+len(v.Files), "files"
+	}
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/i18n"
+)
+
+// threadNode is a message placed in the reply tree
+type threadNode struct {
+	msg     api.Email
+	prefix  string // Tree lines drawn before the message
+	indent  string // Tree lines continuing below it
+	replies bool   // Whether replies follow in the tree
+}
+
+// threadTree orders messages depth-first along their In-Reply-To chains.
+// Messages whose parent is missing from the thread start a new root, so a
+// thread without reply information reads as a chronological list.
+func threadTree(msgs []api.Email) []threadNode {
+	byID := make(map[string]bool, len(msgs))
+	for _, m := range msgs {
+		byID[m.ID] = true
+	}
+	children := make(map[string][]api.Email)
+	var roots []api.Email
+	for _, m := range msgs {
+		if m.InReplyTo != "" && m.InReplyTo != m.ID && byID[m.InReplyTo] {
+			children[m.InReplyTo] = append(children[m.InReplyTo], m)
+		} else {
+			roots = append(roots, m)
+		}
+	}
+
+	var nodes []threadNode
+	visited := make(map[string]bool, len(msgs))
+	var walk func(list []api.Email, indent string, nested bool)
+	walk = func(list []api.Email, indent string, nested bool) {
+		sortByTime(list)
+		for i, m := range list {
+			if visited[m.ID] {
+				continue
+			}
+			visited[m.ID] = true
+			prefix, next := indent, indent
+			if nested {
+				if i == len(list)-1 {
+					prefix, next = indent+"└─ ", indent+"   "
+				} else {
+					prefix, next = indent+"├─ ", indent+"│  "
+				}
+			}
+			nodes = append(nodes, threadNode{msg: m, prefix: prefix, indent: next, replies: len(children[m.ID]) > 0})
+			walk(children[m.ID], next, true)
+		}
+	}
+	walk(roots, "", false)
+	return nodes
+}
+
+func sortByTime(msgs []api.Email) {
+	sort.SliceStable(msgs, func(i, j int) bool { return messageTime(msgs[i]).Before(messageTime(msgs[j])) })
+}
+
+func messageTime(m api.Email) time.Time {
+	if !m.SentAt.IsZero() {
+		return m.SentAt
+	}
+	return m.ReceivedAt
+}
+
+// printThreadTree draws the thread's messages as a reply tree with a one-line
+// preview under each
+func printThreadTree(w io.Writer, msgs []api.Email) {
+	for _, n := range threadTree(msgs) {
+		from := ""
+		if n.msg.From != nil {
+			from = n.msg.From.Name
+			if from == "" {
+				from = n.msg.From.Email
+			}
+		}
+		line := Symbols(n.prefix) + ColorBold(from) + "  " + ColorGray(FormatRelativeTime(messageTime(n.msg))) + "  " + ColorGray(n.msg.ID)
+		if !n.msg.IsRead {
+			line += "  " + ColorYellow("unread")
+		}
+		fmt.Fprintln(w, line)
+
+		if preview := strings.Join(strings.Fields(n.msg.BodyPreview), " "); preview != "" {
+			bar := "  "
+			if n.replies {
+				bar = "│ "
+			}
+			fmt.Fprintln(w, Symbols(n.indent+bar)+truncate(preview, 72))
+		}
+	}
+}
+
+// ThreadGroup summarizes the listed messages that belong to one thread
+type ThreadGroup struct {
+	ThreadID     string    `json:"threadId"`
+	Subject      string    `json:"subject"`
+	Messages     int       `json:"messages"`
+	Unread       int       `json:"unread"`
+	Senders      []string  `json:"senders"`
+	LatestID     string    `json:"latestMessageId"`
+	LastActivity time.Time `json:"lastActivity"`
+}
+
+// GroupByThread collapses emails into one group per thread, most recently
+// active first. Counts cover only the emails given, not the whole thread.
+func GroupByThread(emails []api.Email) []ThreadGroup {
+	index := make(map[string]int)
+	var groups []ThreadGroup
+	for _, e := range emails {
+		key := e.ThreadID
+		if key == "" {
+			key = e.ID
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ThreadGroup{ThreadID: key})
+		}
+		g := &groups[i]
+		g.Messages++
+		if !e.IsRead {
+			g.Unread++
+		}
+		if e.From != nil {
+			name := e.From.Name
+			if name == "" {
+				name = e.From.Email
+			}
+			if !slices.Contains(g.Senders, name) {
+				g.Senders = append(g.Senders, name)
+			}
+		}
+		if t := messageTime(e); g.LatestID == "" || t.After(g.LastActivity) {
+			g.LatestID, g.LastActivity, g.Subject = e.ID, t, e.Subject
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].LastActivity.After(groups[j].LastActivity) })
+	return groups
+}
+
+func printThreadGroupsTable(w io.Writer, groups []ThreadGroup) {
+	TableHeader(w, "THREAD", "LAST ACTIVITY", "MSGS", "UNREAD", "FROM", "SUBJECT")
+	for _, g := range groups {
+		unread := ""
+		if g.Unread > 0 {
+			unread = ColorYellow(fmt.Sprint(g.Unread))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+			cell(g.ThreadID, 24),
+			FormatRelativeTime(g.LastActivity),
+			g.Messages,
+			unread,
+			cell(strings.Join(g.Senders, ", "), 30),
+			highlight(cell(g.Subject, 40)),
+		)
+	}
+	fmt.Fprintln(w, "\n"+i18n.T("%d threads", len(groups)))
+}
+
+func printThreadGroupsPlain(groups []ThreadGroup) {
+	for _, g := range groups {
+		fmt.Printf("%s\t%s\t%d\t%d\t%s\t%s\n",
+			g.ThreadID, FormatLocalTime(g.LastActivity), g.Messages, g.Unread, strings.Join(g.Senders, ", "), g.Subject)
+	}
+}
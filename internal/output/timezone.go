@@ -1,16 +1,29 @@
 package output
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/porteden/cli/internal/debug"
 )
 
+// defaultTimezone is used when PE_TIMEZONE is unset (from the config file)
+var defaultTimezone string
+
+// SetDefaultTimezone sets the timezone used when PE_TIMEZONE is unset
+func SetDefaultTimezone(name string) {
+	defaultTimezone = name
+}
+
 // GetOutputLocation returns the timezone location for output formatting.
-// It checks PE_TIMEZONE environment variable first, falling back to time.Local.
+// It checks PE_TIMEZONE environment variable first, then the configured
+// default, falling back to time.Local.
 func GetOutputLocation() *time.Location {
 	tzName := os.Getenv("PE_TIMEZONE")
+	if tzName == "" {
+		tzName = defaultTimezone
+	}
 	if tzName == "" {
 		return time.Local
 	}
@@ -18,7 +31,7 @@ func GetOutputLocation() *time.Location {
 	loc, err := time.LoadLocation(tzName)
 	if err != nil {
 		if debug.Verbose {
-			debug.Log("Invalid PE_TIMEZONE %q: %v, using local timezone", tzName, err)
+			debug.Log("Invalid timezone %q: %v, using local timezone", tzName, err)
 		}
 		return time.Local
 	}
@@ -71,3 +84,33 @@ func safeTime(s string) string {
 	}
 	return s[11:16]
 }
+
+// FormatRelativeTime describes a time relative to now, e.g. "5m ago" or
+// "in 2h". Anything more than a week away is shown as a local date.
+func FormatRelativeTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	suffix := " ago"
+	if d < 0 {
+		d, suffix = -d, ""
+	}
+	var s string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		s = fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		s = fmt.Sprintf("%dd", int(d.Hours()/24))
+	default:
+		return t.In(GetOutputLocation()).Format("Jan 2, 2006")
+	}
+	if suffix == "" {
+		return "in " + s
+	}
+	return s + suffix
+}
@@ -0,0 +1,69 @@
+package output
+
+import "fmt"
+
+// TruncateMode is how long values are shortened to fit table columns
+type TruncateMode string
+
+const (
+	TruncateEnd    TruncateMode = "end"
+	TruncateMiddle TruncateMode = "middle"
+	TruncateNone   TruncateMode = "none"
+)
+
+var (
+	truncateMode = TruncateEnd
+	columnWidth  = 0 // 0 keeps each column's default width
+)
+
+// SetTruncation sets how table columns are shortened. A width of 0 keeps
+// each column's default width.
+func SetTruncation(mode TruncateMode, width int) error {
+	switch mode {
+	case TruncateEnd, TruncateMiddle, TruncateNone:
+	default:
+		return fmt.Errorf("invalid truncate mode %q: use end, middle or none", mode)
+	}
+	if width != 0 && width < 4 {
+		return fmt.Errorf("invalid max width %d: must be at least 4", width)
+	}
+	truncateMode = mode
+	columnWidth = width
+	return nil
+}
+
+// cell fits a table value into a column whose default width is width,
+// following the truncation settings
+func cell(s string, width int) string {
+	if columnWidth > 0 {
+		width = columnWidth
+	}
+	switch truncateMode {
+	case TruncateNone:
+		return s
+	case TruncateMiddle:
+		return truncateMiddle(s, width)
+	}
+	return truncate(s, width)
+}
+
+// truncate shortens s to max runes, ending with "..."
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
+
+// truncateMiddle shortens s to max runes by replacing its middle with "...",
+// keeping both ends
+func truncateMiddle(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	keep := max - 3
+	head := (keep + 1) / 2
+	return string(r[:head]) + "..." + string(r[len(r)-(keep-head):])
+}
@@ -1,22 +1,28 @@
 package output
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/porteden/cli/internal/i18n"
+)
 
 const bannerWidth = 41
 
 // PrintBanner displays the branded PortEden CLI header.
 func PrintBanner() {
-	top := "┌" + repeat("─", bannerWidth) + "┐"
-	bot := "└" + repeat("─", bannerWidth) + "┘"
-	blank := "│" + repeat(" ", bannerWidth) + "│"
+	top := Symbols("┌" + repeat("─", bannerWidth) + "┐")
+	bot := Symbols("└" + repeat("─", bannerWidth) + "┘")
+	side := Symbols("│")
+	blank := side + repeat(" ", bannerWidth) + side
 
 	fmt.Println()
 	fmt.Println(ColorCyan(top))
 	fmt.Println(ColorCyan(blank))
-	fmt.Println(ColorCyan("│") + ColorBold(center("P O R T E D E N . C O M", bannerWidth)) + ColorCyan("│"))
-	fmt.Println(ColorCyan("│") + ColorGray(center("CLI Setup", bannerWidth)) + ColorCyan("│"))
+	fmt.Println(ColorCyan(side) + ColorBold(center("P O R T E D E N . C O M", bannerWidth)) + ColorCyan(side))
+	fmt.Println(ColorCyan(side) + ColorGray(center(i18n.T("CLI Setup"), bannerWidth)) + ColorCyan(side))
 	fmt.Println(ColorCyan(blank))
-	fmt.Println(ColorCyan("│") + center("Your data. Your rules.", bannerWidth) + ColorCyan("│"))
+	fmt.Println(ColorCyan(side) + center(i18n.T("Your data. Your rules."), bannerWidth) + ColorCyan(side))
 	fmt.Println(ColorCyan(blank))
 	fmt.Println(ColorCyan(bot))
 	fmt.Println()
@@ -30,7 +36,7 @@ func PrintStep(n, total int, msg string) {
 
 // PrintSuccess prints a green checkmark line.
 func PrintSuccess(msg string) {
-	fmt.Printf("  %s %s\n", ColorGreen("✓"), msg)
+	fmt.Printf("  %s %s\n", ColorGreen(Symbols("✓")), msg)
 }
 
 // PrintInfo prints an indented gray info line.
@@ -41,30 +47,31 @@ func PrintInfo(msg string) {
 // PrintDivider prints a thin separator line.
 func PrintDivider() {
 	fmt.Println()
-	fmt.Println(ColorGray(repeat("─", bannerWidth+2)))
+	fmt.Println(ColorGray(Symbols(repeat("─", bannerWidth+2))))
 	fmt.Println()
 }
 
 // PrintCompletion prints the final success block with quick-start hints.
 func PrintCompletion(profile string) {
 	PrintDivider()
-	PrintSuccess(ColorBold("You're all set!"))
-	fmt.Printf("  Profile: %s\n", ColorCyan(profile))
+	PrintSuccess(ColorBold(i18n.T("You're all set!")))
+	fmt.Println("  " + i18n.T("Profile: %s", ColorCyan(profile)))
 	fmt.Println()
-	fmt.Println(ColorBold("  Get started:"))
-	fmt.Printf("    %s        %s\n", ColorCyan("porteden calendar list"), "List your calendars")
-	fmt.Printf("    %s       %s\n", ColorCyan("porteden events --today"), "Today's events")
-	fmt.Printf("    %s          %s\n", ColorCyan("porteden auth status"), "Check connection")
+	fmt.Println(ColorBold("  " + i18n.T("Get started:")))
+	fmt.Printf("    %s        %s\n", ColorCyan("porteden calendar list"), i18n.T("List your calendars"))
+	fmt.Printf("    %s       %s\n", ColorCyan("porteden events --today"), i18n.T("Today's events"))
+	fmt.Printf("    %s          %s\n", ColorCyan("porteden auth status"), i18n.T("Check connection"))
 	fmt.Println()
-	fmt.Printf("  Need help? Check out the docs at %s\n", ColorCyan("https://docs.porteden.com/cli"))
+	fmt.Println("  " + i18n.T("Need help? Check out the docs at %s", ColorCyan("https://docs.porteden.com/cli")))
 	fmt.Println()
 }
 
 func center(s string, width int) string {
-	if len(s) >= width {
+	n := utf8.RuneCountInString(s)
+	if n >= width {
 		return s
 	}
-	pad := width - len(s)
+	pad := width - n
 	left := pad / 2
 	right := pad - left
 	return repeat(" ", left) + s + repeat(" ", right)
@@ -0,0 +1,285 @@
+// Package picker is a small fuzzy-searchable list selector for the terminal,
+// so commands can offer a choice without depending on fzf.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrCancelled is returned when the user leaves the picker without choosing
+var ErrCancelled = errors.New("selection cancelled")
+
+// maxVisible is how many matches are shown at once
+const maxVisible = 10
+
+// ASCII replaces the arrows and separators in the help line with plain text
+var ASCII = false
+
+// Item is one choice. Label is what's shown and searched.
+type Item struct {
+	ID    string
+	Label string
+}
+
+// Match reports whether all runes of query appear in text in order, ignoring
+// case. Lower scores are better: tight matches near the start rank first.
+func Match(query, text string) (score int, ok bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	first, last, qi := -1, -1, 0
+	for i, r := range []rune(strings.ToLower(text)) {
+		if r != q[qi] {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		if qi++; qi == len(q) {
+			return (last-first+1-len(q))*2 + first, true
+		}
+	}
+	return 0, false
+}
+
+// Filter returns the items matching query, best matches first. Ties keep the
+// original order.
+func Filter(items []Item, query string) []Item {
+	type scored struct {
+		item  Item
+		score int
+	}
+	var matches []scored
+	for _, it := range items {
+		if s, ok := Match(query, it.Label); ok {
+			matches = append(matches, scored{it, s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+
+	out := make([]Item, len(matches))
+	for i, m := range matches {
+		out[i] = m.item
+	}
+	return out
+}
+
+// Pick shows items on stderr and returns the one chosen. Typing narrows the
+// list, arrow keys (or Ctrl+P/Ctrl+N) move, Enter chooses and Esc or Ctrl+C
+// cancels with ErrCancelled.
+func Pick(prompt string, items []Item) (Item, error) {
+	chosen, err := run(&session{prompt: prompt, items: items, matches: items})
+	if err != nil {
+		return Item{}, err
+	}
+	return chosen[0], nil
+}
+
+// PickMany is Pick with multi-select: Space or Tab toggles the highlighted
+// item and Enter returns the toggled items in their original order, or the
+// highlighted one if none were toggled
+func PickMany(prompt string, items []Item) ([]Item, error) {
+	return run(&session{prompt: prompt, items: items, matches: items, multi: true, selected: map[int]bool{}})
+}
+
+func run(p *session) ([]Item, error) {
+	if len(p.items) == 0 {
+		return nil, errors.New("nothing to choose from")
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start picker: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+	defer p.clear()
+
+	buf := make([]byte, 64)
+	for {
+		p.render()
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		key := buf[:n]
+
+		switch {
+		case len(key) >= 3 && key[0] == 27 && key[1] == '[' && key[2] == 'A', key[0] == 16: // Up, Ctrl+P
+			p.move(-1)
+		case len(key) >= 3 && key[0] == 27 && key[1] == '[' && key[2] == 'B', key[0] == 14: // Down, Ctrl+N
+			p.move(1)
+		case key[0] == 27, key[0] == 3: // Esc, Ctrl+C
+			return nil, ErrCancelled
+		case key[0] == '\r', key[0] == '\n':
+			if chosen := p.chosen(); len(chosen) > 0 {
+				return chosen, nil
+			}
+		case p.multi && (key[0] == ' ' || key[0] == '\t'):
+			p.toggle()
+		case key[0] == 127, key[0] == 8: // Backspace
+			if len(p.query) > 0 {
+				p.setQuery(p.query[:len(p.query)-1])
+			}
+		case key[0] == 21: // Ctrl+U
+			p.setQuery(nil)
+		default:
+			query := p.query
+			for len(key) > 0 {
+				r, size := utf8.DecodeRune(key)
+				key = key[size:]
+				if unicode.IsPrint(r) {
+					query = append(query, r)
+				}
+			}
+			p.setQuery(query)
+		}
+	}
+}
+
+// session is the picker's on-screen state
+type session struct {
+	prompt   string
+	items    []Item
+	matches  []Item
+	query    []rune
+	cursor   int // Index into matches
+	top      int // First visible match
+	multi    bool
+	selected map[int]bool // Toggled items, by index into items
+}
+
+// chosen returns what Enter selects: the toggled items, else the highlighted
+// one
+func (p *session) chosen() []Item {
+	var out []Item
+	for i, it := range p.items {
+		if p.selected[i] {
+			out = append(out, it)
+		}
+	}
+	if len(out) == 0 && len(p.matches) > 0 {
+		out = []Item{p.matches[p.cursor]}
+	}
+	return out
+}
+
+// toggle flips the highlighted item and moves to the next one
+func (p *session) toggle() {
+	if len(p.matches) == 0 {
+		return
+	}
+	i := p.indexOf(p.matches[p.cursor])
+	p.selected[i] = !p.selected[i]
+	if p.cursor < len(p.matches)-1 {
+		p.move(1)
+	}
+}
+
+// indexOf finds a match's position in items. Items are compared by ID and
+// label since Filter returns copies.
+func (p *session) indexOf(it Item) int {
+	for i, candidate := range p.items {
+		if candidate == it {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *session) setQuery(q []rune) {
+	p.query = q
+	p.matches = Filter(p.items, string(q))
+	p.cursor, p.top = 0, 0
+}
+
+func (p *session) move(delta int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.cursor = (p.cursor + delta + len(p.matches)) % len(p.matches)
+	if p.cursor < p.top {
+		p.top = p.cursor
+	}
+	if p.cursor >= p.top+maxVisible {
+		p.top = p.cursor - maxVisible + 1
+	}
+}
+
+// render redraws the picker below the cursor, leaving the cursor at the end
+// of the query line. Raw mode needs explicit carriage returns.
+func (p *session) render() {
+	width := 80
+	if w, _, err := term.GetSize(int(os.Stderr.Fd())); err == nil && w > 0 {
+		width = w
+	}
+
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+	end := min(p.top+maxVisible, len(p.matches))
+	for i := p.top; i < end; i++ {
+		label := p.matches[i].Label
+		if p.multi {
+			mark := "[ ] "
+			if p.selected[p.indexOf(p.matches[i])] {
+				mark = "[x] "
+			}
+			label = mark + label
+		}
+		label = fit(label, width-3)
+		if i == p.cursor {
+			fmt.Fprintf(&b, "\r\n\x1b[7m> %s\x1b[0m", label)
+		} else {
+			fmt.Fprintf(&b, "\r\n  %s", label)
+		}
+	}
+	help := "↑/↓ move · Enter select · Esc cancel"
+	if ASCII {
+		help = "Up/Down move - Enter select - Esc cancel"
+	}
+	if p.multi {
+		count := 0
+		for _, on := range p.selected {
+			if on {
+				count++
+			}
+		}
+		help = fmt.Sprintf("%d selected · Space toggle · Enter confirm · Esc cancel", count)
+		if ASCII {
+			help = strings.ReplaceAll(help, "·", "-")
+		}
+	}
+	fmt.Fprintf(&b, "\r\n\x1b[90m  %d/%d  %s\x1b[0m", len(p.matches), len(p.items), help)
+
+	lines := end - p.top + 1
+	line := p.prompt + " " + string(p.query)
+	fmt.Fprintf(&b, "\x1b[%dA\r%s", lines, line)
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+// clear erases the picker from the screen
+func (p *session) clear() {
+	fmt.Fprint(os.Stderr, "\r\x1b[J")
+}
+
+// fit truncates s to at most width runes
+func fit(s string, width int) string {
+	if width < 4 {
+		width = 4
+	}
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-3]) + "..."
+}
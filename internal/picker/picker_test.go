@@ -0,0 +1,34 @@
+package picker
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		ok          bool
+	}{
+		{"", "anything", true},
+		{"lun", "Lunch tomorrow?", true},
+		{"LUN", "lunch", true},
+		{"q3p", "Q3 planning", true},
+		{"nul", "lunch", false}, // Out of order
+		{"lunchx", "lunch", false},
+	}
+	for _, tt := range tests {
+		if _, ok := Match(tt.query, tt.text); ok != tt.ok {
+			t.Errorf("Match(%q, %q) ok = %v, want %v", tt.query, tt.text, ok, tt.ok)
+		}
+	}
+}
+
+func TestFilterRanksTightMatchesFirst(t *testing.T) {
+	items := []Item{
+		{ID: "1", Label: "The Weekly: product news"},
+		{ID: "2", Label: "Budget review"},
+		{ID: "3", Label: "Lunch tomorrow?"},
+	}
+	got := Filter(items, "lun")
+	if len(got) != 2 || got[0].ID != "3" || got[1].ID != "1" {
+		t.Fatalf("Filter(lun) = %+v, want Lunch first, then the loose match", got)
+	}
+}
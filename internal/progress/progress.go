@@ -0,0 +1,70 @@
+// Package progress renders transient status lines on stderr. Output is
+// suppressed when stderr is not a terminal so scripts and logs stay clean.
+package progress
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Enabled reports whether stderr is an interactive terminal
+func Enabled() bool {
+	return term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Status overwrites the current stderr line with msg
+func Status(msg string) {
+	if !Enabled() {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s", msg)
+}
+
+// Clear erases the status line
+func Clear() {
+	if !Enabled() {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+}
+
+// Countdown blocks for d, showing the remaining seconds in format (which must
+// contain a single %s verb). Returns ctx.Err() if cancelled early.
+func Countdown(ctx context.Context, d time.Duration, format string) error {
+	deadline := time.Now().Add(d)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	defer Clear()
+
+	for {
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining < time.Second {
+			remaining = time.Second
+		}
+		Status(fmt.Sprintf(format, remaining))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Bar renders a fixed-width progress bar such as "[#####.....]"
+func Bar(done, total, width int) string {
+	if total <= 0 {
+		return ""
+	}
+	filled := min(done*width/total, width)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
@@ -0,0 +1,449 @@
+// Package qr encodes short text such as URLs as QR codes (byte mode, error
+// correction level M, versions 1-20) and renders them for the terminal.
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrTooLong is returned when text doesn't fit in the largest supported version
+var ErrTooLong = errors.New("text too long for a QR code")
+
+// Code is an encoded QR symbol
+type Code struct {
+	Size     int
+	modules  [][]bool
+	function [][]bool // Modules reserved for finder, timing, format and version patterns
+}
+
+// version describes the block structure of one version at level M
+type version struct {
+	ecPerBlock int
+	blocks1    int // Blocks in group 1
+	data1      int // Data codewords per group 1 block
+	blocks2    int
+	data2      int
+	alignment  []int
+}
+
+var versions = []version{
+	1:  {10, 1, 16, 0, 0, nil},
+	2:  {16, 1, 28, 0, 0, []int{6, 18}},
+	3:  {26, 1, 44, 0, 0, []int{6, 22}},
+	4:  {18, 2, 32, 0, 0, []int{6, 26}},
+	5:  {24, 2, 43, 0, 0, []int{6, 30}},
+	6:  {16, 4, 27, 0, 0, []int{6, 34}},
+	7:  {18, 4, 31, 0, 0, []int{6, 22, 38}},
+	8:  {22, 2, 38, 2, 39, []int{6, 24, 42}},
+	9:  {22, 3, 36, 2, 37, []int{6, 26, 46}},
+	10: {26, 4, 43, 1, 44, []int{6, 28, 50}},
+	11: {30, 1, 50, 4, 51, []int{6, 30, 54}},
+	12: {22, 6, 36, 2, 37, []int{6, 32, 58}},
+	13: {22, 8, 37, 1, 38, []int{6, 34, 62}},
+	14: {24, 4, 40, 5, 41, []int{6, 26, 46, 66}},
+	15: {24, 5, 41, 5, 42, []int{6, 26, 48, 70}},
+	16: {28, 7, 45, 3, 46, []int{6, 26, 50, 74}},
+	17: {28, 10, 46, 1, 47, []int{6, 30, 54, 78}},
+	18: {26, 9, 43, 4, 44, []int{6, 30, 56, 82}},
+	19: {26, 3, 44, 11, 45, []int{6, 30, 58, 86}},
+	20: {26, 3, 41, 13, 42, []int{6, 34, 62, 90}},
+}
+
+func (v version) dataCodewords() int {
+	return v.blocks1*v.data1 + v.blocks2*v.data2
+}
+
+// Encode returns the smallest QR code that holds text
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	for n := 1; n < len(versions); n++ {
+		v := versions[n]
+		countBits := 8
+		if n >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) > 8*v.dataCodewords() {
+			continue
+		}
+
+		c := newCode(n)
+		c.drawCodewords(interleave(v, encodeData(data, countBits, v.dataCodewords())))
+		c.applyBestMask()
+		return c, nil
+	}
+	return nil, ErrTooLong
+}
+
+// Dark reports whether the module at column x, row y is dark
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y][x]
+}
+
+// encodeData builds the data codewords: byte mode indicator, character count,
+// payload, terminator, then alternating pad bytes
+func encodeData(data []byte, countBits, capacity int) []byte {
+	var bb bitBuffer
+	bb.append(0x4, 4)
+	bb.append(len(data), countBits)
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	bb.append(0, min(4, capacity*8-len(bb)))
+	bb.append(0, (8-len(bb)%8)%8)
+
+	out := bb.bytes()
+	for pad := byte(0xEC); len(out) < capacity; pad ^= 0xEC ^ 0x11 {
+		out = append(out, pad)
+	}
+	return out
+}
+
+// interleave splits data into blocks, appends each block's error correction
+// codewords, and interleaves the result as the symbol requires
+func interleave(v version, data []byte) []byte {
+	var blocks, ecBlocks [][]byte
+	gen := generator(v.ecPerBlock)
+	for i, off := 0, 0; i < v.blocks1+v.blocks2; i++ {
+		n := v.data1
+		if i >= v.blocks1 {
+			n = v.data2
+		}
+		block := data[off : off+n]
+		off += n
+		blocks = append(blocks, block)
+		ecBlocks = append(ecBlocks, remainder(block, gen))
+	}
+
+	var out []byte
+	for i := 0; i < max(v.data1, v.data2); i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, b := range ecBlocks {
+			out = append(out, b[i])
+		}
+	}
+	return out
+}
+
+func newCode(ver int) *Code {
+	size := 17 + 4*ver
+	c := &Code{Size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(size-4, 3)
+	c.drawFinder(3, size-4)
+
+	align := versions[ver].alignment
+	last := len(align) - 1
+	for i, y := range align {
+		for j, x := range align {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	c.drawFormat(0) // Reserve the area; redrawn once the mask is chosen
+	if ver >= 7 {
+		c.drawVersion(ver)
+	}
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			c.setFunction(x, y, d != 2 && d != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// formatBits returns the 15-bit BCH-protected format word for level M and mask
+func formatBits(mask int) int {
+	data := 0<<3 | mask // Level M is encoded as 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormat(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true) // Always-dark module
+}
+
+// versionBits returns the 18-bit BCH-protected version word
+func versionBits(ver int) int {
+	rem := ver
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return ver<<12 | rem
+}
+
+func (c *Code) drawVersion(ver int) {
+	bits := versionBits(ver)
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 != 0
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places data bits in the two-column zigzag, skipping function
+// modules and the vertical timing pattern
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.function[y][x] && i < len(data)*8 {
+					c.modules[y][x] = data[i>>3]>>(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.function[y][x] && maskBit(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// applyBestMask tries all eight masks and keeps the one with the lowest penalty
+func (c *Code) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // XOR again to undo
+	}
+	c.applyMask(best)
+	c.drawFormat(best)
+}
+
+// penalty scores the symbol using the four rules from the QR specification
+func (c *Code) penalty() int {
+	n := c.Size
+	p := 0
+	line := make([]bool, n)
+
+	for pass := 0; pass < 2; pass++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if pass == 0 {
+					line[j] = c.modules[i][j]
+				} else {
+					line[j] = c.modules[j][i]
+				}
+			}
+			p += runPenalty(line) + finderPenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				v := c.modules[y][x]
+				if c.modules[y][x+1] == v && c.modules[y+1][x] == v && c.modules[y+1][x+1] == v {
+					p += 3
+				}
+			}
+		}
+	}
+	total := n * n
+	p += ((abs(dark*20-total*10)+total-1)/total - 1) * 10
+	return p
+}
+
+// runPenalty scores runs of five or more same-colored modules
+func runPenalty(line []bool) int {
+	p, run := 0, 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			p += run - 2
+		}
+		run = 1
+	}
+	return p
+}
+
+// finderPenalty scores finder-like 1:1:3:1:1 patterns flanked by four light
+// modules; the area outside the symbol counts as light
+func finderPenalty(line []bool) int {
+	pattern := []bool{true, false, true, true, true, false, true}
+	dark := func(i int) bool { return i >= 0 && i < len(line) && line[i] }
+	p := 0
+	for i := 0; i+len(pattern) <= len(line); i++ {
+		match := true
+		for k, want := range pattern {
+			if line[i+k] != want {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		before, after := true, true
+		for k := 1; k <= 4; k++ {
+			before = before && !dark(i-k)
+			after = after && !dark(i+len(pattern)-1+k)
+		}
+		if before || after {
+			p += 40
+		}
+	}
+	return p
+}
+
+// Render draws the code with Unicode half blocks, two module rows per text
+// line, surrounded by a quiet zone. Light modules are drawn as blocks so the
+// code reads correctly on dark terminal backgrounds; set invert for light ones.
+func (c *Code) Render(invert bool) string {
+	const quiet = 2
+	light := func(x, y int) bool { return c.Dark(x, y) == invert }
+
+	var b strings.Builder
+	for y := -quiet; y < c.Size+quiet; y += 2 {
+		for x := -quiet; x < c.Size+quiet; x++ {
+			top, bottom := light(x, y), light(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, val>>i&1 != 0)
+	}
+}
+
+func (bb bitBuffer) bytes() []byte {
+	out := make([]byte, (len(bb)+7)/8)
+	for i, bit := range bb {
+		if bit {
+			out[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return out
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,56 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the worked example in the QR specification
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := remainder(data, generator(10)); !bytes.Equal(got, want) {
+		t.Errorf("remainder = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatBits(0); got != 0b101010000010010 {
+		t.Errorf("formatBits(M, mask 0) = %015b", got)
+	}
+	if got := versionBits(7); got != 0b000111110010010100 {
+		t.Errorf("versionBits(7) = %018b", got)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		text string
+		size int
+	}{
+		{"https://meet.example.com/abc-defg-hij", 29},                                // Version 3
+		{"https://zoom.example.com/j/123456789?pwd=" + strings.Repeat("x", 120), 53}, // Version 9
+	}
+	for _, tt := range tests {
+		c, err := Encode(tt.text)
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", tt.text, err)
+		}
+		if c.Size != tt.size {
+			t.Errorf("Encode(%q) size = %d, want %d", tt.text, c.Size, tt.size)
+		}
+
+		// Every finder pattern has a dark center and a light separator ring
+		for _, p := range [][2]int{{3, 3}, {c.Size - 4, 3}, {3, c.Size - 4}} {
+			if !c.Dark(p[0], p[1]) || c.Dark(p[0]+2, p[1]) {
+				t.Errorf("finder pattern at %v is malformed", p)
+			}
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("x", 700)); err != ErrTooLong {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
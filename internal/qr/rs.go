@@ -0,0 +1,43 @@
+package qr
+
+// gfMul multiplies in GF(2^8) modulo the QR polynomial x^8+x^4+x^3+x^2+1
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// generator returns the coefficients of the Reed-Solomon generator polynomial
+// of the given degree, highest power first and the leading 1 omitted
+func generator(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+// remainder computes the error correction codewords for data
+func remainder(data, gen []byte) []byte {
+	result := make([]byte, len(gen))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, g := range gen {
+			result[i] ^= gfMul(g, factor)
+		}
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+// Package queue keeps requests that couldn't reach the API, such as emails
+// sent while offline, so they can be replayed later instead of being lost.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/porteden/cli/internal/cache"
+)
+
+const formatVersion = 1
+
+// Entry is one queued request
+type Entry struct {
+	ID string `json:"id"`
+	Op string `json:"op"`
+	// Target is the item the request acts on, e.g. the email replied to
+	Target   string          `json:"target,omitempty"`
+	Summary  string          `json:"summary"`
+	Request  json.RawMessage `json:"request"`
+	QueuedAt time.Time       `json:"queuedAt"`
+	Attempts int             `json:"attempts,omitempty"`
+	// LastError is why the latest replay failed
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Queue is a named queue of a profile, oldest entry first
+type Queue struct {
+	Version int     `json:"version"`
+	NextID  int     `json:"nextId"`
+	Entries []Entry `json:"entries"`
+
+	name, profile string
+}
+
+// FileName is the cache entry holding a profile's queue
+func FileName(name, profile string) string {
+	return name + "-" + profile + ".json"
+}
+
+// Load reads a profile's queue. Unlike other caches an unreadable queue is an
+// error, since it may hold messages the user still wants to send.
+func Load(name, profile string) (*Queue, error) {
+	q := &Queue{Version: formatVersion, NextID: 1, name: name, profile: profile}
+	data, err := cache.Read(FileName(name, profile))
+	if errors.Is(err, cache.ErrNotFound) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName(name, profile), err)
+	}
+	if q.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported %s version %d", name, q.Version)
+	}
+	return q, nil
+}
+
+// Save writes the queue
+func (q *Queue) Save() error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	return cache.Write(FileName(q.name, q.profile), data)
+}
+
+// Add queues a request and returns its entry
+func (q *Queue) Add(op, target, summary string, request interface{}) (*Entry, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	q.Entries = append(q.Entries, Entry{
+		ID:       strconv.Itoa(q.NextID),
+		Op:       op,
+		Target:   target,
+		Summary:  summary,
+		Request:  data,
+		QueuedAt: time.Now().UTC(),
+	})
+	q.NextID++
+	return &q.Entries[len(q.Entries)-1], nil
+}
+
+// Find returns the entry with the given ID
+func (q *Queue) Find(id string) (*Entry, bool) {
+	for i := range q.Entries {
+		if q.Entries[i].ID == id {
+			return &q.Entries[i], true
+		}
+	}
+	return nil, false
+}
+
+// Remove drops the entry with the given ID, reporting whether it was queued
+func (q *Queue) Remove(id string) bool {
+	for i, e := range q.Entries {
+		if e.ID == id {
+			q.Entries = append(q.Entries[:i], q.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
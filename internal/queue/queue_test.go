@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAddFindRemove(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	q, err := Load("outbox", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := q.Add("send", "", "Hello", map[string]string{"subject": "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Add("reply", "msg_1", "Reply to msg_1", map[string]string{"body": "Thanks"}); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != "1" || q.Entries[1].ID != "2" {
+		t.Errorf("IDs should count up from 1, got %q and %q", first.ID, q.Entries[1].ID)
+	}
+	if err := q.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load("outbox", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := loaded.Find("2")
+	if !ok || e.Op != "reply" || e.Target != "msg_1" {
+		t.Fatalf("Find(2) = %+v, %v", e, ok)
+	}
+	var req map[string]string
+	if err := json.Unmarshal(e.Request, &req); err != nil || req["body"] != "Thanks" {
+		t.Errorf("request not kept: %s", e.Request)
+	}
+
+	if !loaded.Remove("1") || loaded.Remove("1") {
+		t.Error("Remove should drop an entry exactly once")
+	}
+	if next, _ := loaded.Add("send", "", "Again", nil); next.ID != "3" {
+		t.Errorf("IDs should not be reused, got %q", next.ID)
+	}
+
+	if other, _ := Load("outbox", "work"); len(other.Entries) != 0 {
+		t.Error("queues should be separate per profile")
+	}
+}
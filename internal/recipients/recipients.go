@@ -0,0 +1,169 @@
+// Package recipients remembers the addresses the user has emailed or invited,
+// for shell completion and for catching typos in addresses never used before.
+package recipients
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/cache"
+)
+
+const (
+	formatVersion = 1
+	maxAddresses  = 500 // Least recently used addresses are forgotten first
+	maxDistance   = 2   // Largest edit distance still treated as a typo
+)
+
+// Address is a remembered recipient
+type Address struct {
+	Email    string    `json:"email"`
+	Name     string    `json:"name,omitempty"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// History holds a profile's recipients, most recently used first
+type History struct {
+	Version   int       `json:"version"`
+	Addresses []Address `json:"addresses"`
+}
+
+// FileName is the cache entry holding the history for a profile
+func FileName(profile string) string {
+	return "recipients-" + profile + ".json"
+}
+
+// Load reads the history for a profile, returning an empty history if none
+// exists or the existing one is unreadable
+func Load(profile string) (*History, error) {
+	h := &History{Version: formatVersion}
+	data, err := cache.Read(FileName(profile))
+	if errors.Is(err, cache.ErrNotFound) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+
+	var loaded History
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != formatVersion {
+		return h, nil
+	}
+	return &loaded, nil
+}
+
+// Save writes the history for a profile
+func (h *History) Save(profile string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return cache.Write(FileName(profile), data)
+}
+
+// Record counts a use of each address. A non-empty name replaces the
+// remembered one.
+func (h *History) Record(used []Address) {
+	now := time.Now()
+	for _, u := range used {
+		email := strings.ToLower(strings.TrimSpace(u.Email))
+		if !strings.Contains(email, "@") {
+			continue
+		}
+		entry := Address{Email: email}
+		if i := h.find(email); i >= 0 {
+			entry = h.Addresses[i]
+			h.Addresses = append(h.Addresses[:i], h.Addresses[i+1:]...)
+		}
+		if u.Name != "" {
+			entry.Name = u.Name
+		}
+		entry.Count++
+		entry.LastUsed = now
+		h.Addresses = append([]Address{entry}, h.Addresses...)
+	}
+	if len(h.Addresses) > maxAddresses {
+		h.Addresses = h.Addresses[:maxAddresses]
+	}
+}
+
+// Known reports whether an address has been used before
+func (h *History) Known(email string) bool {
+	return h.find(strings.ToLower(strings.TrimSpace(email))) >= 0
+}
+
+func (h *History) find(email string) int {
+	for i, a := range h.Addresses {
+		if a.Email == email {
+			return i
+		}
+	}
+	return -1
+}
+
+// Complete returns the addresses whose email or name starts with prefix,
+// ignoring case, most used first
+func (h *History) Complete(prefix string) []Address {
+	prefix = strings.ToLower(prefix)
+	var out []Address
+	for _, a := range h.Addresses {
+		if strings.HasPrefix(a.Email, prefix) || strings.HasPrefix(strings.ToLower(a.Name), prefix) {
+			out = append(out, a)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Count > out[j].Count })
+	return out
+}
+
+// Suggest returns the remembered address that email is most likely a typo
+// of. Known addresses, and ones not close to any remembered address, have no
+// suggestion.
+func (h *History) Suggest(email string) (string, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || h.Known(email) {
+		return "", false
+	}
+	best, bestDist, bestCount := "", maxDistance+1, 0
+	for _, a := range h.Addresses {
+		d := distance(email, a.Email)
+		// Short addresses are only a typo of something very close
+		if d > len(a.Email)/4 {
+			continue
+		}
+		if d < bestDist || d == bestDist && a.Count > bestCount {
+			best, bestDist, bestCount = a.Email, d, a.Count
+		}
+	}
+	return best, best != ""
+}
+
+// distance is the Damerau-Levenshtein distance between a and b (with adjacent
+// transpositions counting as one edit)
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
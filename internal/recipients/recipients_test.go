@@ -0,0 +1,67 @@
+package recipients
+
+import "testing"
+
+func TestRecordAndComplete(t *testing.T) {
+	h := &History{}
+	h.Record([]Address{{Email: "Alice@Corp.com", Name: "Alice Liddell"}, {Email: "bob@corp.com"}})
+	h.Record([]Address{{Email: "alice@corp.com"}, {Email: "not-an-address"}})
+
+	if len(h.Addresses) != 2 {
+		t.Fatalf("expected 2 addresses, got %+v", h.Addresses)
+	}
+	if a := h.Addresses[0]; a.Email != "alice@corp.com" || a.Count != 2 || a.Name != "Alice Liddell" {
+		t.Errorf("alice should be lowercased, counted twice and keep her name, got %+v", a)
+	}
+
+	if got := h.Complete("AL"); len(got) != 1 || got[0].Email != "alice@corp.com" {
+		t.Errorf("Complete(AL) = %+v", got)
+	}
+	if got := h.Complete("lid"); len(got) != 0 {
+		t.Errorf("Complete should match prefixes only, got %+v", got)
+	}
+	if got := h.Complete(""); len(got) != 2 || got[0].Email != "alice@corp.com" {
+		t.Errorf("Complete should put the most used first, got %+v", got)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	h := &History{}
+	h.Record([]Address{{Email: "alice@corp.com"}, {Email: "al@x.io"}})
+
+	tests := []struct {
+		in, want string
+	}{
+		{in: "alcie@corp.com", want: "alice@corp.com"}, // Transposition
+		{in: "alice@crop.com", want: "alice@corp.com"},
+		{in: "alice@corp.co", want: "alice@corp.com"},
+		{in: "alice@corp.com", want: ""}, // Known
+		{in: "ALICE@corp.com", want: ""}, // Known, ignoring case
+		{in: "carol@corp.com", want: ""}, // Too different
+		{in: "bo@x.io", want: ""},        // Two edits is too many for a short address
+	}
+	for _, tt := range tests {
+		got, ok := h.Suggest(tt.in)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Suggest(%q) = %q, %v; want %q", tt.in, got, ok, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"abc", "acb", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package refs remembers the IDs shown by recent listings so commands can
+// accept short forms of long provider IDs, like git accepts SHA prefixes.
+package refs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/cache"
+)
+
+// Kind separates ID namespaces
+type Kind string
+
+const (
+	KindEvent Kind = "event"
+	KindEmail Kind = "email"
+)
+
+const (
+	formatVersion = 1
+	maxRecent     = 1000 // IDs remembered per kind
+	minPrefix     = 4    // Shorter arguments are never treated as prefixes
+)
+
+// Item is a remembered ID with enough context to tell candidates apart
+type Item struct {
+	ID     string    `json:"id"`
+	Title  string    `json:"title,omitempty"`
+	SeenAt time.Time `json:"seenAt"`
+}
+
+// Store holds recently listed IDs per kind, most recent first
+type Store struct {
+	Version int             `json:"version"`
+	Recent  map[Kind][]Item `json:"recent"`
+}
+
+// FileName is the cache entry holding the store for a profile
+func FileName(profile string) string {
+	return "refs-" + profile + ".json"
+}
+
+// Load reads the store for a profile, returning an empty store if none exists
+// or the existing one is unreadable
+func Load(profile string) (*Store, error) {
+	s := &Store{Version: formatVersion, Recent: map[Kind][]Item{}}
+	data, err := cache.Read(FileName(profile))
+	if errors.Is(err, cache.ErrNotFound) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	var loaded Store
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != formatVersion {
+		return s, nil
+	}
+	if loaded.Recent == nil {
+		loaded.Recent = map[Kind][]Item{}
+	}
+	return &loaded, nil
+}
+
+// Save writes the store for a profile
+func (s *Store) Save(profile string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return cache.Write(FileName(profile), data)
+}
+
+// Remember records items as the most recently seen of their kind
+func (s *Store) Remember(kind Kind, items []Item) {
+	now := time.Now()
+	seen := make(map[string]bool, len(items))
+	merged := make([]Item, 0, len(items)+len(s.Recent[kind]))
+	for _, it := range items {
+		if it.ID == "" || seen[it.ID] {
+			continue
+		}
+		seen[it.ID] = true
+		it.SeenAt = now
+		merged = append(merged, it)
+	}
+	for _, it := range s.Recent[kind] {
+		if !seen[it.ID] {
+			merged = append(merged, it)
+		}
+	}
+	if len(merged) > maxRecent {
+		merged = merged[:maxRecent]
+	}
+	s.Recent[kind] = merged
+}
+
+// AmbiguousError reports a prefix that matches more than one remembered ID
+type AmbiguousError struct {
+	Prefix     string
+	Candidates []Item
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiguous ID prefix %q matches %d items:", e.Prefix, len(e.Candidates))
+	for i, c := range e.Candidates {
+		if i == 5 {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(e.Candidates)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s  %s", c.ID, c.Title)
+	}
+	return b.String()
+}
+
+// Resolve expands ref to a full ID when it is a unique prefix of a remembered
+// ID. Exact IDs, short arguments and unknown values are returned unchanged, so
+// full IDs that were never listed still work.
+func (s *Store) Resolve(kind Kind, ref string) (string, error) {
+	if len(ref) < minPrefix {
+		return ref, nil
+	}
+
+	var matches []Item
+	for _, it := range s.Recent[kind] {
+		if it.ID == ref {
+			return ref, nil
+		}
+		if strings.HasPrefix(it.ID, ref) {
+			matches = append(matches, it)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return ref, nil
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return "", &AmbiguousError{Prefix: ref, Candidates: matches}
+	}
+}
+
+// Known reports whether id is a remembered ID of the given kind
+func (s *Store) Known(kind Kind, id string) bool {
+	for _, it := range s.Recent[kind] {
+		if it.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package refs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	s := &Store{Recent: map[Kind][]Item{}}
+	s.Remember(KindEmail, []Item{
+		{ID: "18c2f0a1b2c3d4e5", Title: "Invoice"},
+		{ID: "18c2f0a1ffee0011", Title: "Lunch"},
+		{ID: "19aa00bb11cc22dd", Title: "Offsite"},
+	})
+
+	tests := []struct {
+		ref, want string
+		ambiguous bool
+	}{
+		{ref: "19aa", want: "19aa00bb11cc22dd"},
+		{ref: "18c2f0a1b", want: "18c2f0a1b2c3d4e5"},
+		{ref: "18c2f0a1", ambiguous: true},
+		{ref: "18c", want: "18c"},                           // Too short to be a prefix
+		{ref: "unknown-full-id", want: "unknown-full-id"},   // Never listed, passed through
+		{ref: "18c2f0a1ffee0011", want: "18c2f0a1ffee0011"}, // Exact match
+	}
+	for _, tt := range tests {
+		got, err := s.Resolve(KindEmail, tt.ref)
+		var amb *AmbiguousError
+		if tt.ambiguous {
+			if !errors.As(err, &amb) || len(amb.Candidates) != 2 {
+				t.Errorf("Resolve(%q): expected ambiguity between 2 items, got %v", tt.ref, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	if !s.Known(KindEmail, "19aa00bb11cc22dd") || s.Known(KindEmail, "19aa") || s.Known(KindEvent, "19aa00bb11cc22dd") {
+		t.Error("Known should match only full remembered IDs of the same kind")
+	}
+
+	if got, _ := s.Resolve(KindEvent, "19aa"); got != "19aa" {
+		t.Errorf("kinds should not share IDs, got %q", got)
+	}
+}
+
+func TestListingRow(t *testing.T) {
+	l := &Listing{Rows: map[Kind][]string{KindEmail: {"msg_a", "msg_b", "msg_c"}}}
+
+	if got, err := l.Row(KindEmail, "%2"); err != nil || got != "msg_b" {
+		t.Errorf("Row(%%2) = %q, %v; want msg_b", got, err)
+	}
+	for _, ref := range []string{"%0", "%4"} {
+		if _, err := l.Row(KindEmail, ref); err == nil {
+			t.Errorf("Row(%s) should be out of range", ref)
+		}
+	}
+	if _, err := l.Row(KindEvent, "%1"); err == nil {
+		t.Error("Row on a kind with no listing should fail")
+	}
+
+	for ref, want := range map[string]bool{"%1": true, "%12": true, "%": false, "%x": false, "1": false} {
+		if got := IsRowRef(ref); got != want {
+			t.Errorf("IsRowRef(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
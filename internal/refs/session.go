@@ -0,0 +1,129 @@
+package refs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/porteden/cli/internal/config"
+)
+
+// sessionTTL is how long an untouched session listing is kept before it is
+// pruned
+const sessionTTL = 24 * time.Hour
+
+// Listing holds the IDs of the rows most recently listed in a terminal
+// session, in display order
+type Listing struct {
+	Rows map[Kind][]string `json:"rows"`
+}
+
+// SessionKey identifies the terminal session: PE_SESSION when set, otherwise
+// the parent process, which is the shell for interactive use
+func SessionKey() string {
+	if key := os.Getenv("PE_SESSION"); key != "" {
+		return key
+	}
+	return strconv.Itoa(os.Getppid())
+}
+
+// sessionPath returns the listing file for a profile in the current session
+func sessionPath(profile string) (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(profile + "-" + SessionKey())
+	return filepath.Join(dir, "sessions", name+".json"), nil
+}
+
+// LoadListing reads the last listing for a profile in the current session.
+// A missing or unreadable file yields an empty listing.
+func LoadListing(profile string) (*Listing, error) {
+	l := &Listing{Rows: map[Kind][]string{}}
+	path, err := sessionPath(profile)
+	if err != nil {
+		return l, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return l, err
+	}
+	if err := json.Unmarshal(data, l); err != nil || l.Rows == nil {
+		l.Rows = map[Kind][]string{}
+	}
+	return l, nil
+}
+
+// SetRows replaces the rows of a kind with the IDs just listed
+func (l *Listing) SetRows(kind Kind, ids []string) {
+	l.Rows[kind] = ids
+}
+
+// Save writes the listing and prunes listings of sessions that have ended
+func (l *Listing) Save(profile string) error {
+	path, err := sessionPath(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	pruneSessions(filepath.Dir(path))
+	return nil
+}
+
+// pruneSessions removes listings not written to within sessionTTL
+func pruneSessions(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < sessionTTL {
+			continue
+		}
+		os.Remove(filepath.Join(dir, e.Name()))
+	}
+}
+
+// IsRowRef reports whether ref has the %N row reference form
+func IsRowRef(ref string) bool {
+	if len(ref) < 2 || ref[0] != '%' {
+		return false
+	}
+	_, err := strconv.Atoi(ref[1:])
+	return err == nil
+}
+
+// Row returns the ID at the 1-based row of a %N reference
+func (l *Listing) Row(kind Kind, ref string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(ref, "%"))
+	if err != nil {
+		return "", fmt.Errorf("invalid row reference %q", ref)
+	}
+	rows := l.Rows[kind]
+	if len(rows) == 0 {
+		return "", fmt.Errorf("no %s listing to refer to in this session; list some first", kind)
+	}
+	if n < 1 || n > len(rows) {
+		return "", fmt.Errorf("row reference %s is out of range: the last %s listing had %d row(s)", ref, kind, len(rows))
+	}
+	return rows[n-1], nil
+}
@@ -0,0 +1,189 @@
+// Package sanitize cleans untrusted email HTML for viewing in a browser.
+//
+// Tags and attributes are kept from an allow list; scripts, frames, forms,
+// event handlers and javascript: links are removed. Remote images, which
+// tell the sender when a message is opened, are blocked unless asked for.
+package sanitize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Options controls what the sanitized HTML may load
+type Options struct {
+	RemoteImages bool // Keep http(s) image sources and CSS url() references
+}
+
+var (
+	tagPattern     = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+	commentPattern = regexp.MustCompile(`(?s)<!--.*?(?:-->|$)|<![^>]*>|<\?[^>]*>`)
+	attrPattern    = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+	cssImport      = regexp.MustCompile(`(?i)@import[^;]*;?`)
+	cssURL         = regexp.MustCompile(`(?i)url\(\s*(?:"[^"]*"|'[^']*'|[^)]*)\s*\)`)
+	cssExpression  = regexp.MustCompile(`(?i)expression\s*\(|behavior\s*:|-moz-binding|javascript:`)
+)
+
+// allowedTags are kept; other tags are dropped but their text is kept
+var allowedTags = set("a", "abbr", "address", "b", "bdi", "bdo", "big", "blockquote", "br", "caption",
+	"center", "cite", "code", "col", "colgroup", "dd", "del", "dfn", "div", "dl", "dt", "em", "font",
+	"h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "ins", "kbd", "li", "mark", "ol", "p", "pre",
+	"q", "s", "samp", "small", "span", "strike", "strong", "sub", "sup", "table", "tbody", "td",
+	"tfoot", "th", "thead", "tr", "tt", "u", "ul", "wbr")
+
+// droppedTags are removed together with everything inside them
+var droppedTags = set("script", "iframe", "frame", "frameset", "object", "embed", "applet", "noscript",
+	"template", "title", "svg", "math", "textarea", "select", "button", "audio", "video", "canvas")
+
+// allowedAttrs are kept on any allowed tag; href and src are checked separately
+var allowedAttrs = set("alt", "title", "width", "height", "align", "valign", "bgcolor", "color", "face",
+	"size", "colspan", "rowspan", "cellpadding", "cellspacing", "border", "style", "class", "dir",
+	"start", "type", "lang", "name", "id")
+
+func set(items ...string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, s := range items {
+		m[s] = true
+	}
+	return m
+}
+
+// HTML returns src with everything unsafe removed, and the number of remote
+// images that were blocked
+func HTML(src string, opts Options) (string, int) {
+	src = commentPattern.ReplaceAllString(src, "")
+	var b strings.Builder
+	blocked := 0
+
+	for len(src) > 0 {
+		loc := tagPattern.FindStringSubmatchIndex(src)
+		if loc == nil {
+			b.WriteString(escapeText(src))
+			break
+		}
+		b.WriteString(escapeText(src[:loc[0]]))
+		closing := loc[3] > loc[2]
+		name := strings.ToLower(src[loc[4]:loc[5]])
+		attrs := src[loc[6]:loc[7]]
+		src = src[loc[1]:]
+
+		switch {
+		case name == "style" && !closing:
+			css, rest := untilClose(src, name)
+			src = rest
+			b.WriteString("<style>" + cleanCSS(css, opts) + "</style>")
+		case droppedTags[name]:
+			if !closing {
+				_, src = untilClose(src, name)
+			}
+		case !allowedTags[name]:
+			// Unknown or structural tags (html, body, form, ...) keep their text
+		case closing:
+			b.WriteString("</" + name + ">")
+		default:
+			tag, wasBlocked := cleanTag(name, attrs, opts)
+			if wasBlocked {
+				blocked++
+			}
+			b.WriteString(tag)
+		}
+	}
+	return b.String(), blocked
+}
+
+// untilClose splits s at the closing tag of name, returning the content
+// before it and the rest after it. Without a closing tag the content runs
+// to the end.
+func untilClose(s, name string) (string, string) {
+	lower := strings.ToLower(s)
+	end := strings.Index(lower, "</"+name)
+	if end < 0 {
+		return s, ""
+	}
+	rest := s[end:]
+	if gt := strings.IndexByte(rest, '>'); gt >= 0 {
+		return s[:end], rest[gt+1:]
+	}
+	return s[:end], ""
+}
+
+// cleanTag rebuilds an allowed tag from its safe attributes. It reports
+// whether a remote image source was removed.
+func cleanTag(name, attrs string, opts Options) (string, bool) {
+	var b strings.Builder
+	b.WriteString("<" + name)
+	blocked := false
+	hasAlt := false
+
+	for _, m := range attrPattern.FindAllStringSubmatch(attrs, -1) {
+		key := strings.ToLower(m[1])
+		value := html.UnescapeString(m[2] + m[3] + m[4])
+		switch {
+		case key == "href" && name == "a":
+			if !safeLink(value) {
+				continue
+			}
+		case key == "src" && name == "img":
+			if isRemote(value) && !opts.RemoteImages {
+				blocked = true
+				continue
+			}
+			if !isRemote(value) && !strings.HasPrefix(strings.ToLower(strings.TrimSpace(value)), "data:image/") {
+				continue // cid: and other sources can't load from a file
+			}
+		case key == "style":
+			if value = cleanCSS(value, opts); strings.TrimSpace(value) == "" {
+				continue
+			}
+		case key == "alt":
+			hasAlt = true
+		case !allowedAttrs[key]:
+			continue
+		}
+		b.WriteString(" " + key + `="` + html.EscapeString(value) + `"`)
+	}
+
+	switch {
+	case name == "a":
+		b.WriteString(` rel="noopener noreferrer" target="_blank"`)
+	case blocked && !hasAlt:
+		b.WriteString(` alt="[image]"`)
+	}
+	b.WriteString(">")
+	return b.String(), blocked
+}
+
+// cleanCSS removes imports and, unless remote images are allowed, url()
+// references from a style sheet or style attribute. Styles that run script
+// are dropped entirely.
+func cleanCSS(css string, opts Options) string {
+	if cssExpression.MatchString(css) {
+		return ""
+	}
+	css = cssImport.ReplaceAllString(css, "")
+	css = cssURL.ReplaceAllStringFunc(css, func(u string) string {
+		if opts.RemoteImages {
+			return u
+		}
+		return "none"
+	})
+	return strings.ReplaceAll(css, "<", "")
+}
+
+// safeLink reports whether a link target is web, mail or in-page
+func safeLink(u string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	return isRemote(u) || strings.HasPrefix(u, "mailto:") || strings.HasPrefix(u, "#")
+}
+
+func isRemote(u string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "//")
+}
+
+// escapeText escapes any "<" left in text, so a broken tag can't combine
+// with markup that follows
+func escapeText(s string) string {
+	return strings.ReplaceAll(s, "<", "&lt;")
+}
@@ -0,0 +1,48 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{name: "kept", in: `<p align="center">Hi <b>there</b></p>`, want: `<p align="center">Hi <b>there</b></p>`},
+		{name: "script", in: `a<script>alert(1)</script>b`, want: `ab`},
+		{name: "unclosed script", in: `a<SCRIPT>alert(1)`, want: `a`},
+		{name: "handler", in: `<div onclick="x()" class="c">t</div>`, want: `<div class="c">t</div>`},
+		{name: "structure", in: `<html><body><form action="/x">t</form></body></html>`, want: `t`},
+		{name: "comment", in: `a<!-- <script>x</script> -->b`, want: `ab`},
+		{name: "link", in: `<a href="https://x.io/?a=1&amp;b=2">x</a>`, want: `<a href="https://x.io/?a=1&amp;b=2" rel="noopener noreferrer" target="_blank">x</a>`},
+		{name: "javascript link", in: `<a href=" jav&#x61;script:alert(1)">x</a>`, want: `<a rel="noopener noreferrer" target="_blank">x</a>`},
+		{name: "data image", in: `<img src="data:image/png;base64,AA">`, want: `<img src="data:image/png;base64,AA">`},
+		{name: "cid image", in: `<img src="cid:logo" alt="Logo">`, want: `<img alt="Logo">`},
+		{name: "broken tag", in: `<3 and <img src=x onerror=alert(1)`, want: `&lt;3 and &lt;img src=x onerror=alert(1)`},
+		{name: "style block", in: `<style>@import "x.css"; p{background:url(https://t.io/p.gif)}</style>`, want: `<style> p{background:none}</style>`},
+		{name: "style attribute", in: `<p style="width:expression(alert(1))">t</p>`, want: `<p>t</p>`},
+	}
+	for _, tt := range tests {
+		if got, _ := HTML(tt.in, Options{}); got != tt.want {
+			t.Errorf("%s: HTML(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRemoteImages(t *testing.T) {
+	in := `<img src="https://t.io/pixel.gif"><img src="//t.io/logo.png" alt="Logo">`
+
+	got, blocked := HTML(in, Options{})
+	if blocked != 2 || strings.Contains(got, "t.io") {
+		t.Errorf("remote images should be blocked, got %d: %s", blocked, got)
+	}
+	if !strings.Contains(got, `alt="[image]"`) || !strings.Contains(got, `alt="Logo"`) {
+		t.Errorf("blocked images should keep or get alt text, got %s", got)
+	}
+
+	got, blocked = HTML(in, Options{RemoteImages: true})
+	if blocked != 0 || !strings.Contains(got, `src="https://t.io/pixel.gif"`) {
+		t.Errorf("remote images should load when allowed, got %d: %s", blocked, got)
+	}
+}
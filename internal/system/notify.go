@@ -0,0 +1,53 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Notify shows a native desktop notification.
+// Uses osascript on macOS, notify-send on Linux, and a PowerShell toast on Windows.
+func Notify(title, message string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptQuote(message), appleScriptQuote(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "PE_TITLE="+title, "PE_MESSAGE="+message)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return fmt.Errorf("notify-send not found; install libnotify to enable desktop notifications")
+		}
+		cmd = exec.Command("notify-send", "--app-name=PortEden", "--", title, message)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notification failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func appleScriptQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// windowsToastScript shows a toast with the text of $env:PE_TITLE and
+// $env:PE_MESSAGE. The text is passed in the environment rather than quoted
+// into the script, which PowerShell would parse.
+var windowsToastScript = strings.Join([]string{
+	"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null",
+	"$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+	"$n = $t.GetElementsByTagName('text')",
+	"$n.Item(0).AppendChild($t.CreateTextNode($env:PE_TITLE)) > $null",
+	"$n.Item(1).AppendChild($t.CreateTextNode($env:PE_MESSAGE)) > $null",
+	"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('PortEden').Show([Windows.UI.Notifications.ToastNotification]::new($t))",
+}, "; ")
@@ -33,7 +33,7 @@ func CheckForUpdate() {
 		return // Don't check for updates on dev builds
 	}
 
-	cacheFile := filepath.Join(configDir(), checkCacheFile)
+	cacheFile := CheckCacheFile()
 
 	// Check if we've checked recently
 	if stat, err := os.Stat(cacheFile); err == nil {
@@ -106,6 +106,11 @@ func FetchLatestVersion() (string, error) {
 	return version, nil
 }
 
+// CheckCacheFile returns the path of the file recording the last update check
+func CheckCacheFile() string {
+	return filepath.Join(configDir(), checkCacheFile)
+}
+
 func configDir() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".config", "porteden")
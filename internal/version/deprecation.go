@@ -0,0 +1,38 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/porteden/cli/internal/api"
+	"github.com/porteden/cli/internal/output"
+)
+
+// deprecationWarnedFile records when the deprecated API warning was last shown
+const deprecationWarnedFile = "api-deprecation-warned"
+
+// WarnDeprecatedAPI tells the user, at most once a day, that the API has
+// deprecated the version this CLI speaks. Fails silently.
+func WarnDeprecatedAPI() {
+	info := api.LastVersionInfo()
+	if info == nil || !info.Deprecated {
+		return
+	}
+
+	stamp := filepath.Join(configDir(), deprecationWarnedFile)
+	if stat, err := os.Stat(stamp); err == nil {
+		if time.Since(stat.ModTime()) < checkIntervalHours*time.Hour {
+			return
+		}
+	}
+	_ = os.MkdirAll(configDir(), 0700)
+	_ = os.WriteFile(stamp, []byte(info.Requested), 0600)
+
+	msg := fmt.Sprintf("This version of porteden uses PortEden API version %s, which is deprecated", info.Requested)
+	if info.Sunset != nil {
+		msg += " and stops working on " + info.Sunset.Format("2006-01-02")
+	}
+	fmt.Fprintf(os.Stderr, "\n%s\n", output.ColorYellow(msg+". "+updateHint()))
+}